@@ -0,0 +1,67 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kata-containers/runtime/pkg/katautils"
+	"github.com/urfave/cli"
+)
+
+var cleanupCLICommand = cli.Command{
+	Name:  "cleanup",
+	Usage: "tear down a sandbox that cannot be deleted anymore",
+	ArgsUsage: `<sandbox-id>
+
+   <sandbox-id> is the name of the sandbox, the id of its first container.
+
+   The sandbox is stopped and deleted through the agent. With --force, a
+   sandbox that cannot be deleted this way, eg. because its VM does not
+   answer anymore or its state is corrupted, is torn down from the host:
+   the hypervisor is killed, the shared directory unmounted, the volumes
+   passed to the VM as block devices mounted again on the host, the network
+   namespace and the sandbox state removed.
+
+EXAMPLE:
+   If the sandbox "ubuntu01" is stuck and cannot be deleted by containerd:
+
+       # ` + name + ` cleanup --force ubuntu01`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "tear the sandbox down from the host if it cannot be deleted",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		return cleanup(ctx, context.Args().First(), context.Bool("force"))
+	},
+}
+
+func cleanup(ctx context.Context, sandboxID string, force bool) error {
+	span, ctx := katautils.Trace(ctx, "cleanup")
+	defer span.Finish()
+
+	if sandboxID == "" {
+		return fmt.Errorf("Missing sandbox ID")
+	}
+
+	kataLog = kataLog.WithField("sandbox", sandboxID)
+	setExternalLoggers(ctx, kataLog)
+	span.SetTag("sandbox", sandboxID)
+
+	if err := vci.CleanupSandbox(ctx, sandboxID, force); err != nil {
+		return err
+	}
+
+	return katautils.DelSandboxIDMappings(ctx, sandboxID)
+}
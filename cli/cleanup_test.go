@@ -0,0 +1,49 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanup(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := createTempContainerIDMapping(testContainerID, testSandboxID)
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	var forced bool
+	testingImpl.CleanupSandboxFunc = func(ctx context.Context, sandboxID string, force bool) error {
+		forced = force
+		if sandboxID != testSandboxID {
+			return errors.New("unknown sandbox")
+		}
+		return nil
+	}
+	defer func() {
+		testingImpl.CleanupSandboxFunc = nil
+	}()
+
+	assert.Error(cleanup(context.Background(), "", true))
+	assert.Error(cleanup(context.Background(), "foo", false))
+
+	// The mappings are kept when the cleanup fails.
+	mapping := filepath.Join(ctrsMapTreePath, testContainerID, testSandboxID)
+	_, err = os.Stat(mapping)
+	assert.NoError(err)
+
+	assert.NoError(cleanup(context.Background(), testSandboxID, true))
+	assert.True(forced)
+	_, err = os.Stat(mapping)
+	assert.True(os.IsNotExist(err))
+}
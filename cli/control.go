@@ -0,0 +1,282 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/kata-containers/runtime/pkg/katautils"
+	pb "github.com/kata-containers/runtime/protocols/control"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	vcTypes "github.com/kata-containers/runtime/virtcontainers/pkg/types"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+)
+
+const defaultControlAddress = "/run/vc/control.sock"
+
+var kataControlServerCLICommand = cli.Command{
+	Name:  "kata-control-server",
+	Usage: "serve the sandbox control API on a unix socket",
+	Description: `The kata-control-server command serves the SandboxControl gRPC API,
+   described by protocols/control/control.proto, until it is interrupted.
+   It lets node agents and test harnesses list and inspect the sandboxes of
+   the host, and start, stop, delete, pause and resume the sandboxes created
+   by the command line and hotplug their network interfaces. The sandboxes
+   of containerd-shim-kata-v2 are only inspected: their shim keeps their
+   state in memory, so they must be operated through containerd.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "address",
+			Value: defaultControlAddress,
+			Usage: "path of the unix socket served on, only accessible to its owner",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		address := c.String("address")
+
+		rpc := grpc.NewServer()
+		pb.RegisterSandboxControlServer(rpc, &controlServer{})
+
+		l, err := getUnixListener(address)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+
+		signals := make(chan os.Signal, 8)
+		done := handleSignals(rpc, signals)
+		signal.Notify(signals, handledSignals...)
+
+		kataLog.WithField("address", address).Info("control server start")
+		rpc.Serve(l)
+
+		<-done
+
+		kataLog.WithField("address", address).Info("control server stop")
+		return nil
+	},
+}
+
+// controlServer implements the SandboxControl service on top of the
+// virtcontainers API, which serializes the operations on a sandbox.
+type controlServer struct{}
+
+// checkCLISandbox makes sure the sandbox was created by the command line
+// before operating on it. The shim v2 registers no container ID mapping,
+// and would not notice the changes made behind its back.
+func checkCLISandbox(sandboxID string) error {
+	id, err := katautils.FetchContainerIDMapping(sandboxID)
+	if err != nil {
+		return err
+	}
+
+	if id == "" {
+		return fmt.Errorf("Sandbox %s does not exist or is managed by its containerd shim", sandboxID)
+	}
+
+	return nil
+}
+
+func sandboxStatusToGrpc(status vc.SandboxStatus) *pb.SandboxStatus {
+	s := &pb.SandboxStatus{
+		Id:          status.ID,
+		State:       string(status.State.State),
+		Hypervisor:  string(status.Hypervisor),
+		Agent:       string(status.Agent),
+		Annotations: status.Annotations,
+		Labels:      status.Labels,
+	}
+
+	for _, c := range status.ContainersStatus {
+		s.Containers = append(s.Containers, &pb.ContainerStatus{
+			Id:          c.ID,
+			State:       string(c.State.State),
+			Pid:         int64(c.PID),
+			StartTime:   c.StartTime.Unix(),
+			Rootfs:      c.RootFs,
+			Annotations: c.Annotations,
+		})
+	}
+
+	return s
+}
+
+func interfaceToGrpc(inf *vcTypes.Interface) *pb.Interface {
+	if inf == nil {
+		return &pb.Interface{}
+	}
+
+	i := &pb.Interface{
+		Device:   inf.Device,
+		Name:     inf.Name,
+		Mtu:      inf.Mtu,
+		HwAddr:   inf.HwAddr,
+		PciAddr:  inf.PciAddr,
+		LinkType: inf.LinkType,
+	}
+
+	for _, addr := range inf.IPAddresses {
+		i.IpAddresses = append(i.IpAddresses, &pb.IPAddress{
+			Family:  int32(addr.Family),
+			Address: addr.Address,
+			Mask:    addr.Mask,
+		})
+	}
+
+	return i
+}
+
+func interfaceFromGrpc(inf *pb.Interface) *vcTypes.Interface {
+	if inf == nil {
+		return &vcTypes.Interface{}
+	}
+
+	i := &vcTypes.Interface{
+		Device:   inf.Device,
+		Name:     inf.Name,
+		Mtu:      inf.Mtu,
+		HwAddr:   inf.HwAddr,
+		PciAddr:  inf.PciAddr,
+		LinkType: inf.LinkType,
+	}
+
+	for _, addr := range inf.IpAddresses {
+		i.IPAddresses = append(i.IPAddresses, &vcTypes.IPAddress{
+			Family:  int(addr.Family),
+			Address: addr.Address,
+			Mask:    addr.Mask,
+		})
+	}
+
+	return i
+}
+
+func (s *controlServer) ListSandboxes(ctx context.Context, req *pb.ListSandboxesRequest) (*pb.ListSandboxesResponse, error) {
+	sandboxes, err := vci.ListSandbox(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListSandboxesResponse{}
+	for _, status := range sandboxes {
+		resp.Sandboxes = append(resp.Sandboxes, sandboxStatusToGrpc(status))
+	}
+
+	return resp, nil
+}
+
+func (s *controlServer) StatusSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxStatus, error) {
+	status, err := vci.StatusSandbox(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sandboxStatusToGrpc(status), nil
+}
+
+func (s *controlServer) StartSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxResponse, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := vci.StartSandbox(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxResponse{}, nil
+}
+
+func (s *controlServer) StopSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxResponse, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := vci.StopSandbox(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxResponse{}, nil
+}
+
+func (s *controlServer) DeleteSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxResponse, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := vci.DeleteSandbox(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxResponse{}, nil
+}
+
+func (s *controlServer) PauseSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxResponse, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := vci.PauseSandbox(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxResponse{}, nil
+}
+
+func (s *controlServer) ResumeSandbox(ctx context.Context, req *pb.SandboxRequest) (*pb.SandboxResponse, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := vci.ResumeSandbox(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &pb.SandboxResponse{}, nil
+}
+
+func (s *controlServer) AddInterface(ctx context.Context, req *pb.InterfaceRequest) (*pb.Interface, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	inf, err := vci.AddInterface(ctx, req.Id, interfaceFromGrpc(req.Interface))
+	if err != nil {
+		return nil, err
+	}
+
+	return interfaceToGrpc(inf), nil
+}
+
+func (s *controlServer) RemoveInterface(ctx context.Context, req *pb.InterfaceRequest) (*pb.Interface, error) {
+	if err := checkCLISandbox(req.Id); err != nil {
+		return nil, err
+	}
+
+	inf, err := vci.RemoveInterface(ctx, req.Id, interfaceFromGrpc(req.Interface))
+	if err != nil {
+		return nil, err
+	}
+
+	return interfaceToGrpc(inf), nil
+}
+
+func (s *controlServer) ListInterfaces(ctx context.Context, req *pb.SandboxRequest) (*pb.ListInterfacesResponse, error) {
+	interfaces, err := vci.ListInterfaces(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListInterfacesResponse{}
+	for _, inf := range interfaces {
+		resp.Interfaces = append(resp.Interfaces, interfaceToGrpc(inf))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/kata-containers/runtime/protocols/control"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	vcTypes "github.com/kata-containers/runtime/virtcontainers/pkg/types"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func newTestControlClient(t *testing.T) (pb.SandboxControlClient, func()) {
+	dir, err := ioutil.TempDir(testDir, "control-")
+	assert.NoError(t, err)
+
+	address := filepath.Join(dir, "control.sock")
+	l, err := getUnixListener(address)
+	assert.NoError(t, err)
+
+	rpc := grpc.NewServer()
+	pb.RegisterSandboxControlServer(rpc, &controlServer{})
+	go rpc.Serve(l)
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithTimeout(5*time.Second),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}))
+	assert.NoError(t, err)
+
+	return pb.NewSandboxControlClient(conn), func() {
+		conn.Close()
+		rpc.Stop()
+	}
+}
+
+func TestControlServerStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	client, cleanup := newTestControlClient(t)
+	defer cleanup()
+
+	start := time.Unix(1500000000, 0)
+	status := vc.SandboxStatus{
+		ID:         testSandboxID,
+		State:      types.State{State: types.StateRunning},
+		Hypervisor: vc.QemuHypervisor,
+		Agent:      vc.KataContainersAgent,
+		ContainersStatus: []vc.ContainerStatus{
+			{
+				ID:        testContainerID,
+				State:     types.State{State: types.StateRunning},
+				PID:       1234,
+				StartTime: start,
+			},
+		},
+		Labels: map[string]string{"team": "a"},
+	}
+
+	testingImpl.StatusSandboxFunc = func(ctx context.Context, sandboxID string) (vc.SandboxStatus, error) {
+		if sandboxID != testSandboxID {
+			return vc.SandboxStatus{}, errors.New("no such sandbox")
+		}
+		return status, nil
+	}
+	testingImpl.ListSandboxFunc = func(ctx context.Context) ([]vc.SandboxStatus, error) {
+		return []vc.SandboxStatus{status}, nil
+	}
+	defer func() {
+		testingImpl.StatusSandboxFunc = nil
+		testingImpl.ListSandboxFunc = nil
+	}()
+
+	ctx := context.Background()
+
+	s, err := client.StatusSandbox(ctx, &pb.SandboxRequest{Id: testSandboxID})
+	assert.NoError(err)
+	assert.Equal(testSandboxID, s.Id)
+	assert.Equal("running", s.State)
+	assert.Equal(string(vc.QemuHypervisor), s.Hypervisor)
+	assert.Equal("a", s.Labels["team"])
+	assert.Len(s.Containers, 1)
+	assert.Equal(testContainerID, s.Containers[0].Id)
+	assert.Equal(int64(1234), s.Containers[0].Pid)
+	assert.Equal(start.Unix(), s.Containers[0].StartTime)
+
+	_, err = client.StatusSandbox(ctx, &pb.SandboxRequest{Id: "foo"})
+	assert.Error(err)
+
+	list, err := client.ListSandboxes(ctx, &pb.ListSandboxesRequest{})
+	assert.NoError(err)
+	assert.Len(list.Sandboxes, 1)
+}
+
+func TestControlServerLifecycle(t *testing.T) {
+	assert := assert.New(t)
+
+	client, cleanup := newTestControlClient(t)
+	defer cleanup()
+
+	var calls []string
+	record := func(call string) func(context.Context, string) (vc.VCSandbox, error) {
+		return func(ctx context.Context, sandboxID string) (vc.VCSandbox, error) {
+			calls = append(calls, call+" "+sandboxID)
+			return &vcmock.Sandbox{MockID: sandboxID}, nil
+		}
+	}
+
+	testingImpl.StartSandboxFunc = record("start")
+	testingImpl.PauseSandboxFunc = record("pause")
+	testingImpl.ResumeSandboxFunc = record("resume")
+	testingImpl.StopSandboxFunc = record("stop")
+	testingImpl.DeleteSandboxFunc = record("delete")
+	defer func() {
+		testingImpl.StartSandboxFunc = nil
+		testingImpl.PauseSandboxFunc = nil
+		testingImpl.ResumeSandboxFunc = nil
+		testingImpl.StopSandboxFunc = nil
+		testingImpl.DeleteSandboxFunc = nil
+	}()
+
+	ctx := context.Background()
+	req := &pb.SandboxRequest{Id: testSandboxID}
+
+	// not created by the command line, eg. by the shim v2
+	path, err := createTempContainerIDMapping("other", "other")
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	_, err = client.StopSandbox(ctx, req)
+	assert.Error(err)
+	_, err = client.DeleteSandbox(ctx, req)
+	assert.Error(err)
+	assert.Empty(calls)
+
+	path, err = createTempContainerIDMapping(testSandboxID, testSandboxID)
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	_, err = client.StartSandbox(ctx, req)
+	assert.NoError(err)
+	_, err = client.PauseSandbox(ctx, req)
+	assert.NoError(err)
+	_, err = client.ResumeSandbox(ctx, req)
+	assert.NoError(err)
+	_, err = client.StopSandbox(ctx, req)
+	assert.NoError(err)
+	_, err = client.DeleteSandbox(ctx, req)
+	assert.NoError(err)
+
+	assert.Equal([]string{
+		"start " + testSandboxID,
+		"pause " + testSandboxID,
+		"resume " + testSandboxID,
+		"stop " + testSandboxID,
+		"delete " + testSandboxID,
+	}, calls)
+}
+
+func TestControlServerInterfaces(t *testing.T) {
+	assert := assert.New(t)
+
+	client, cleanup := newTestControlClient(t)
+	defer cleanup()
+
+	var added *vcTypes.Interface
+	testingImpl.AddInterfaceFunc = func(ctx context.Context, sandboxID string, inf *vcTypes.Interface) (*vcTypes.Interface, error) {
+		added = inf
+		return inf, nil
+	}
+	testingImpl.ListInterfacesFunc = func(ctx context.Context, sandboxID string) ([]*vcTypes.Interface, error) {
+		return []*vcTypes.Interface{added}, nil
+	}
+	defer func() {
+		testingImpl.AddInterfaceFunc = nil
+		testingImpl.ListInterfacesFunc = nil
+	}()
+
+	path, err := createTempContainerIDMapping(testSandboxID, testSandboxID)
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	ctx := context.Background()
+	inf := &pb.Interface{
+		Name:   "eth1",
+		HwAddr: "02:00:ca:fe:00:48",
+		Mtu:    1500,
+		IpAddresses: []*pb.IPAddress{
+			{Family: 2, Address: "10.0.0.2", Mask: "24"},
+		},
+	}
+
+	resp, err := client.AddInterface(ctx, &pb.InterfaceRequest{Id: testSandboxID, Interface: inf})
+	assert.NoError(err)
+	assert.Equal("eth1", resp.Name)
+	assert.Equal("10.0.0.2", added.IPAddresses[0].Address)
+	assert.Equal(2, added.IPAddresses[0].Family)
+
+	list, err := client.ListInterfaces(ctx, &pb.SandboxRequest{Id: testSandboxID})
+	assert.NoError(err)
+	assert.Len(list.Interfaces, 1)
+	assert.Equal(uint64(1500), list.Interfaces[0].Mtu)
+
+	// not mocked
+	_, err = client.RemoveInterface(ctx, &pb.InterfaceRequest{Id: testSandboxID, Interface: inf})
+	assert.Error(err)
+}
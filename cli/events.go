@@ -35,6 +35,8 @@ type stats struct {
 	Blkio    blkio              `json:"blkio"`
 	Hugetlb  map[string]hugetlb `json:"hugetlb"`
 	IntelRdt intelRdt           `json:"intel_rdt"`
+
+	NetworkInterfaces []*networkInterface `json:"network_interfaces,omitempty"`
 }
 
 type hugetlb struct {
@@ -107,6 +109,20 @@ type l3CacheInfo struct {
 	NumClosids uint64 `json:"num_closids,omitempty"`
 }
 
+type networkInterface struct {
+	// Name is the name of the network interface.
+	Name string
+
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
+}
+
 type intelRdt struct {
 	// The read-only L3 cache information
 	L3CacheInfo *l3CacheInfo `json:"l3_cache_info,omitempty"`
@@ -261,9 +277,29 @@ func convertVirtcontainerStats(containerStats *vc.ContainerStats) *stats {
 		s.Hugetlb[k] = convertHugtlb(v)
 	}
 
+	s.NetworkInterfaces = convertNetworkStats(containerStats.NetworkStats)
+
 	return &s
 }
 
+func convertNetworkStats(c []*vc.NetworkStats) []*networkInterface {
+	var out []*networkInterface
+	for _, n := range c {
+		out = append(out, &networkInterface{
+			Name:      n.Name,
+			RxBytes:   n.RxBytes,
+			RxPackets: n.RxPackets,
+			RxErrors:  n.RxErrors,
+			RxDropped: n.RxDropped,
+			TxBytes:   n.TxBytes,
+			TxPackets: n.TxPackets,
+			TxErrors:  n.TxErrors,
+			TxDropped: n.TxDropped,
+		})
+	}
+	return out
+}
+
 func convertHugtlb(c vc.HugetlbStats) hugetlb {
 	return hugetlb{
 		Usage:   c.Usage,
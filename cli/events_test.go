@@ -142,3 +142,17 @@ func TestEventsCLISuccessful(t *testing.T) {
 	err = actionFunc(ctx)
 	assert.NoError(err)
 }
+
+func TestConvertVirtcontainerStatsNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	s := convertVirtcontainerStats(&vc.ContainerStats{
+		CgroupStats: &vc.CgroupStats{},
+		NetworkStats: []*vc.NetworkStats{
+			{Name: "eth0", RxBytes: 1024, TxPackets: 3, RxDropped: 1},
+		},
+	})
+	assert.NotNil(s)
+	assert.Len(s.NetworkInterfaces, 1)
+	assert.Equal(&networkInterface{Name: "eth0", RxBytes: 1024, TxPackets: 3, RxDropped: 1}, s.NetworkInterfaces[0])
+}
@@ -249,14 +249,22 @@ func execute(ctx context.Context, context *cli.Context) error {
 		user = params.ociProcess.User.Username
 	}
 
+	caps, err := oci.ContainerCapabilities(oci.CompatOCISpec{Process: &params.ociProcess})
+	if err != nil {
+		return err
+	}
+
 	cmd := types.Cmd{
-		Args:        params.ociProcess.Args,
-		Envs:        envVars,
-		WorkDir:     params.ociProcess.Cwd,
-		User:        user,
-		Interactive: params.ociProcess.Terminal,
-		Console:     consolePath,
-		Detach:      noNeedForOutput(params.detach, params.ociProcess.Terminal),
+		Args:            params.ociProcess.Args,
+		Envs:            envVars,
+		WorkDir:         params.ociProcess.Cwd,
+		User:            user,
+		Interactive:     params.ociProcess.Terminal,
+		Console:         consolePath,
+		Detach:          noNeedForOutput(params.detach, params.ociProcess.Terminal),
+		Capabilities:    caps,
+		NoNewPrivileges: params.ociProcess.NoNewPrivileges,
+		ApparmorProfile: params.ociProcess.ApparmorProfile,
 	}
 
 	_, _, process, err := vci.EnterContainer(ctx, sandboxID, params.cID, cmd)
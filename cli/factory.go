@@ -62,7 +62,8 @@ func (s *cacheServer) Config(ctx context.Context, empty *types.Empty) (*pb.GrpcV
 	return jsonVMConfig, nil
 }
 
-// GetBaseVM requests a paused VM and convert it to gRPC protocol.
+// GetBaseVM requests a base VM and convert it to gRPC protocol. The VM is
+// paused when it comes from VMCache and running when it comes from the VM pool.
 func (s *cacheServer) GetBaseVM(ctx context.Context, empty *types.Empty) (*pb.GrpcVM, error) {
 	config := s.factory.Config()
 
@@ -81,7 +82,7 @@ func getUnixListener(path string) (net.Listener, error) {
 	}
 	_, err = os.Stat(path)
 	if err == nil {
-		return nil, fmt.Errorf("%s already exist.  Please stop running VMCache server and remove %s", path, path)
+		return nil, fmt.Errorf("%s already exist.  Please stop the server running on it and remove %s", path, path)
 	} else if !os.IsNotExist(err) {
 		return nil, err
 	}
@@ -102,7 +103,7 @@ var handledSignals = []os.Signal{
 	syscall.SIGPIPE,
 }
 
-func handleSignals(s *cacheServer, signals chan os.Signal) chan struct{} {
+func handleSignals(rpc *grpc.Server, signals chan os.Signal) chan struct{} {
 	done := make(chan struct{}, 1)
 	go func() {
 		for {
@@ -112,7 +113,7 @@ func handleSignals(s *cacheServer, signals chan os.Signal) chan struct{} {
 			case unix.SIGPIPE:
 				continue
 			default:
-				s.rpc.GracefulStop()
+				rpc.GracefulStop()
 				close(done)
 				return
 			}
@@ -135,10 +136,11 @@ var initFactoryCommand = cli.Command{
 			return errors.New("invalid runtime config")
 		}
 
-		if runtimeConfig.FactoryConfig.VMCacheNumber > 0 {
+		if runtimeConfig.FactoryConfig.VMCacheNumber > 0 || runtimeConfig.FactoryConfig.VMPoolSize > 0 {
 			factoryConfig := vf.Config{
 				Template: runtimeConfig.FactoryConfig.Template,
 				Cache:    runtimeConfig.FactoryConfig.VMCacheNumber,
+				Pool:     runtimeConfig.FactoryConfig.VMPoolSize,
 				VMCache:  true,
 				VMConfig: vc.VMConfig{
 					HypervisorType:   runtimeConfig.HypervisorType,
@@ -168,7 +170,7 @@ var initFactoryCommand = cli.Command{
 			defer l.Close()
 
 			signals := make(chan os.Signal, 8)
-			done := handleSignals(s, signals)
+			done := handleSignals(s.rpc, signals)
 			signal.Notify(signals, handledSignals...)
 
 			kataLog.WithField("endpoint", runtimeConfig.FactoryConfig.VMCacheEndpoint).Info("VM cache server start")
@@ -0,0 +1,140 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kata-containers/runtime/pkg/katautils"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	"github.com/urfave/cli"
+)
+
+var hypervisorCmdlineCmd = fmt.Sprintf("%s-hypervisor-cmdline", projectPrefix)
+
+// dryRunContainerID is the container ID used when none is given, the
+// sandbox is never created.
+const dryRunContainerID = "dry-run"
+
+var kataHypervisorCmdlineCLICommand = cli.Command{
+	Name:  hypervisorCmdlineCmd,
+	Usage: "print the QEMU command line a sandbox would be started with",
+	ArgsUsage: `[container-id]
+
+   [container-id] is the container ID the sandbox would be created for`,
+	Description: `Resolve the configuration file and the annotations of the
+bundle, and print the QEMU command line, including its devices and the
+guest kernel parameters, without creating the sandbox. Only the QEMU
+hypervisor is supported, the other ones are configured through their API.
+
+This is not the exact invocation of a real sandbox: the devices added for
+the agent and the network when the sandbox is created, and the devices
+hotplugged once it runs, are not shown, and the sandbox specific names and
+paths differ.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "bundle, b",
+			Value: "",
+			Usage: `path to the root of the bundle directory, defaults to the current directory`,
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "display the command line as a JSON array",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		runtimeConfig, ok := context.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+		if !ok {
+			return errors.New("invalid runtime config")
+		}
+
+		containerID := context.Args().First()
+		if containerID == "" {
+			containerID = dryRunContainerID
+		}
+
+		return hypervisorCmdline(ctx, containerID, context.String("bundle"), context.Bool("json"), runtimeConfig, defaultOutputFile)
+	},
+}
+
+func hypervisorCmdline(ctx context.Context, containerID, bundlePath string, jsonOutput bool, runtimeConfig oci.RuntimeConfig, out io.Writer) error {
+	span, ctx := katautils.Trace(ctx, "hypervisorCmdline")
+	defer span.Finish()
+
+	if bundlePath == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		bundlePath = cwd
+	}
+
+	ociSpec, err := oci.ParseConfigJSON(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	sandboxConfig, err := oci.SandboxConfig(ociSpec, runtimeConfig, bundlePath, containerID, "", true, false)
+	if err != nil {
+		return err
+	}
+
+	cmdline, err := vci.HypervisorCommandLine(ctx, sandboxConfig)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(cmdline, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintln(out, formatCommandLine(cmdline))
+
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell, if needed.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`;&|<>()*?[]#~") {
+		return s
+	}
+
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// formatCommandLine formats a command line for a shell, one option and its
+// value per line.
+func formatCommandLine(cmdline []string) string {
+	var lines []string
+
+	for _, arg := range cmdline {
+		if len(lines) == 0 || strings.HasPrefix(arg, "-") {
+			lines = append(lines, shellQuote(arg))
+			continue
+		}
+
+		lines[len(lines)-1] += " " + shellQuote(arg)
+	}
+
+	return strings.Join(lines, " \\\n    ")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCommandLine(t *testing.T) {
+	assert := assert.New(t)
+
+	cmdline := []string{"/usr/bin/qemu", "-name", "sandbox-foo", "-nographic", "-append", "console=hvc0 quiet"}
+	assert.Equal("/usr/bin/qemu \\\n    -name sandbox-foo \\\n    -nographic \\\n    -append 'console=hvc0 quiet'", formatCommandLine(cmdline))
+
+	assert.Equal("''", shellQuote(""))
+	assert.Equal(`'it'\''s'`, shellQuote("it's"))
+}
+
+func TestHypervisorCmdline(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	bundlePath := filepath.Join(tmpdir, "bundle")
+	assert.NoError(makeOCIBundle(bundlePath))
+
+	cmdline := []string{"/usr/bin/qemu", "-name", "sandbox-foo"}
+
+	testingImpl.HypervisorCommandLineFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) ([]string, error) {
+		return cmdline, nil
+	}
+	defer func() {
+		testingImpl.HypervisorCommandLineFunc = nil
+	}()
+
+	var out bytes.Buffer
+	err = hypervisorCmdline(context.Background(), dryRunContainerID, bundlePath, true, runtimeConfig, &out)
+	assert.NoError(err)
+
+	var printed []string
+	assert.NoError(json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(cmdline, printed)
+
+	// no bundle
+	err = hypervisorCmdline(context.Background(), dryRunContainerID, tmpdir, false, runtimeConfig, &out)
+	assert.Error(err)
+}
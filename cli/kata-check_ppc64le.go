@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/kata-containers/runtime/pkg/katautils"
+	vcUtils "github.com/kata-containers/runtime/virtcontainers/utils"
 	"github.com/sirupsen/logrus"
 	"regexp"
 	"strconv"
@@ -22,11 +23,17 @@ const (
 	archCPUModelField  = "model"
 )
 
+// the oldest POWER processor generation able to run Kata Containers
+const minPowerProcessor = 8
+
 var (
 	ppc64CpuCmd     = "ppc64_cpu"
 	smtStatusOption = "--smt"
 	_               = genericCPUVendorField
 	_               = genericCPUModelField
+
+	// procInterrupts is used to detect the host interrupt controller
+	procInterrupts = "/proc/interrupts"
 )
 
 // archRequiredCPUFlags maps a CPU flag value to search for and a
@@ -78,12 +85,23 @@ func hostIsVMContainerCapable(details vmContainerCapableDetails) error {
 		kataLog.WithError(err).Error("Failed to find Power Processor number from ", details.cpuInfoFile)
 	}
 
+	if powerProcessor > 0 && powerProcessor < minPowerProcessor {
+		return fmt.Errorf("POWER%d processor is not supported, POWER%d or newer is required. %s",
+			powerProcessor, minPowerProcessor, failMessage)
+	}
+
 	if powerProcessor <= 8 {
 		if !isSMTOff() {
 			return fmt.Errorf("SMT is not Off. %s", failMessage)
 		}
 	}
 
+	if ic, _ := vcUtils.PowerInterruptController(procInterrupts); ic != "" {
+		kataLog.WithField("interrupt-controller", ic).Info("Host interrupt controller")
+	} else {
+		kataLog.Warn("Cannot detect host interrupt controller, guests will use the QEMU default")
+	}
+
 	count, err := checkKernelModules(details.requiredKernelModules, archKernelParamHandler)
 	if err != nil {
 		return err
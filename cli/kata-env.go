@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -27,7 +28,7 @@ import (
 //
 // XXX: Increment for every change to the output format
 // (meaning any change to the EnvInfo type).
-const formatVersion = "1.0.21"
+const formatVersion = "1.0.22"
 
 // MetaInfo stores information on the format of the output itself
 type MetaInfo struct {
@@ -39,6 +40,10 @@ type MetaInfo struct {
 type KernelInfo struct {
 	Path       string
 	Parameters string
+
+	// ResolvedParameters is the complete guest kernel command line,
+	// including the parameters the runtime adds to Parameters.
+	ResolvedParameters string
 }
 
 // InitrdInfo stores initrd image details
@@ -92,6 +97,12 @@ type HypervisorInfo struct {
 	MemorySlots       uint32
 	Debug             bool
 	UseVSock          bool
+
+	// MachineOptions and Devices are the machine options and the
+	// devices the VM is started with, before the devices added for the
+	// agent and the network.
+	MachineOptions string
+	Devices        []string
 }
 
 // ProxyInfo stores proxy details
@@ -337,6 +348,52 @@ func getHypervisorInfo(config oci.RuntimeConfig) HypervisorInfo {
 	}
 }
 
+// resolvedHypervisorDetails holds the VM details only known once the
+// hypervisor command line has been built.
+type resolvedHypervisorDetails struct {
+	kernelParams   string
+	machineOptions string
+	devices        []string
+}
+
+func parseHypervisorCommandLine(cmdline []string) resolvedHypervisorDetails {
+	var details resolvedHypervisorDetails
+
+	for i := 0; i+1 < len(cmdline); i++ {
+		switch cmdline[i] {
+		case "-append":
+			details.kernelParams = cmdline[i+1]
+		case "-machine":
+			details.machineOptions = cmdline[i+1]
+		case "-device":
+			details.devices = append(details.devices, cmdline[i+1])
+		default:
+			continue
+		}
+		i++
+	}
+
+	return details
+}
+
+// getResolvedHypervisorDetails builds the command line of a VM using the
+// configuration, without starting it. Not all hypervisors have a command
+// line, and building it requires the permissions to create a sandbox.
+func getResolvedHypervisorDetails(config oci.RuntimeConfig) resolvedHypervisorDetails {
+	sandboxConfig := vc.SandboxConfig{
+		HypervisorType:   config.HypervisorType,
+		HypervisorConfig: config.HypervisorConfig,
+	}
+
+	cmdline, err := vci.HypervisorCommandLine(context.Background(), sandboxConfig)
+	if err != nil {
+		kataLog.WithError(err).Debug("cannot resolve the hypervisor command line")
+		return resolvedHypervisorDetails{}
+	}
+
+	return parseHypervisorCommandLine(cmdline)
+}
+
 func getEnvInfo(configFile string, config oci.RuntimeConfig) (env EnvInfo, err error) {
 	err = setCPUtype()
 	if err != nil {
@@ -365,6 +422,10 @@ func getEnvInfo(configFile string, config oci.RuntimeConfig) (env EnvInfo, err e
 
 	hypervisor := getHypervisorInfo(config)
 
+	resolved := getResolvedHypervisorDetails(config)
+	hypervisor.MachineOptions = resolved.machineOptions
+	hypervisor.Devices = resolved.devices
+
 	image := ImageInfo{
 		Path: config.HypervisorConfig.ImagePath,
 	}
@@ -372,6 +433,8 @@ func getEnvInfo(configFile string, config oci.RuntimeConfig) (env EnvInfo, err e
 	kernel := KernelInfo{
 		Path:       config.HypervisorConfig.KernelPath,
 		Parameters: strings.Join(vc.SerializeParams(config.HypervisorConfig.KernelParams, "="), " "),
+
+		ResolvedParameters: resolved.kernelParams,
 	}
 
 	initrd := InitrdInfo{
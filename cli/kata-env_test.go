@@ -1235,3 +1235,28 @@ func TestGetHypervisorInfo(t *testing.T) {
 	info = getHypervisorInfo(config)
 	assert.Equal(info.Version, unknown)
 }
+
+func TestParseHypervisorCommandLine(t *testing.T) {
+	assert := assert.New(t)
+
+	details := parseHypervisorCommandLine([]string{
+		"/usr/bin/qemu-system-x86_64",
+		"-name", "sandbox-foo",
+		"-machine", "pc,accel=kvm",
+		"-device", "pci-bridge,bus=pci.0,id=pci-bridge-0",
+		"-append", "tsc=reliable quiet agent.log=debug",
+		"-device", "virtio-serial-pci,id=serial0",
+		"-nographic",
+	})
+
+	assert.Equal("tsc=reliable quiet agent.log=debug", details.kernelParams)
+	assert.Equal("pc,accel=kvm", details.machineOptions)
+	assert.Equal([]string{
+		"pci-bridge,bus=pci.0,id=pci-bridge-0",
+		"virtio-serial-pci,id=serial0",
+	}, details.devices)
+
+	// a trailing option without a value is ignored
+	details = parseHypervisorCommandLine([]string{"qemu", "-append"})
+	assert.Equal(resolvedHypervisorDetails{}, details)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/kata-containers/runtime/pkg/katautils"
 	vc "github.com/kata-containers/runtime/virtcontainers"
 	oci "github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/runtime/virtcontainers/types"
 )
 
 const formatOptions = `table or json`
@@ -69,6 +70,7 @@ type fullContainerState struct {
 	CurrentHypervisorDetails hypervisorDetails `json:"currentHypervisor"`
 	LatestHypervisorDetails  hypervisorDetails `json:"latestHypervisor"`
 	StaleAssets              []string
+	SandboxLabels            map[string]string `json:"sandboxLabels,omitempty"`
 }
 
 type formatState interface {
@@ -93,7 +95,11 @@ To list containers created via the default "--root":
 
 EXAMPLE 2:
 To list containers created using a non-default value for "--root":
-       # ` + name + ` --root value list`,
+       # ` + name + ` --root value list
+
+EXAMPLE 3:
+To list the containers of the sandboxes labelled as the payments team ones:
+       # ` + name + ` list --label team=payments`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "format, f",
@@ -108,6 +114,10 @@ To list containers created using a non-default value for "--root":
 			Name:  "kata-all",
 			Usage: "display all available " + project + " information",
 		},
+		cli.StringFlag{
+			Name:  "label, l",
+			Usage: "only list the containers of the sandboxes matching the given labels, eg. team=payments,env=prod",
+		},
 	},
 	Action: func(context *cli.Context) error {
 		ctx, err := cliContextToContext(context)
@@ -123,6 +133,12 @@ To list containers created using a non-default value for "--root":
 			return err
 		}
 
+		selector, err := types.ParseLabelSelector(context.String("label"))
+		if err != nil {
+			return err
+		}
+		s = filterContainers(s, selector)
+
 		file := defaultOutputFile
 		showAll := context.Bool("kata-all")
 
@@ -351,6 +367,7 @@ func getContainers(ctx context.Context, context *cli.Context) ([]fullContainerSt
 				CurrentHypervisorDetails: currentHypervisorDetails,
 				LatestHypervisorDetails:  latestHypervisorDetails,
 				StaleAssets:              staleAssets,
+				SandboxLabels:            sandbox.Labels,
 			})
 		}
 	}
@@ -358,6 +375,23 @@ func getContainers(ctx context.Context, context *cli.Context) ([]fullContainerSt
 	return s, nil
 }
 
+// filterContainers only keeps the containers of the sandboxes carrying the
+// selector labels.
+func filterContainers(states []fullContainerState, selector map[string]string) []fullContainerState {
+	if len(selector) == 0 {
+		return states
+	}
+
+	var filtered []fullContainerState
+	for _, state := range states {
+		if types.MatchLabels(state.SandboxLabels, selector) {
+			filtered = append(filtered, state)
+		}
+	}
+
+	return filtered
+}
+
 // getHypervisorDetails returns details of the latest version of the
 // hypervisor and the associated assets.
 func getHypervisorDetails(hypervisorConfig *vc.HypervisorConfig) hypervisorDetails {
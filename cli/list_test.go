@@ -22,6 +22,7 @@ import (
 	vc "github.com/kata-containers/runtime/virtcontainers"
 	vcAnnotations "github.com/kata-containers/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli"
 )
@@ -503,6 +504,81 @@ func TestListGetContainersSandboxWithContainer(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestListGetContainersLabelSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	rootfs := filepath.Join(tmpdir, "rootfs")
+	err = os.MkdirAll(rootfs, testDirMode)
+	assert.NoError(err)
+
+	newStatus := func(id string, labels map[string]string) vc.SandboxStatus {
+		return vc.SandboxStatus{
+			ID:     id,
+			Labels: labels,
+			ContainersStatus: []vc.ContainerStatus{
+				{
+					ID:          id,
+					Annotations: map[string]string{},
+					RootFs:      rootfs,
+				},
+			},
+		}
+	}
+
+	testingImpl.ListSandboxFunc = func(ctx context.Context) ([]vc.SandboxStatus, error) {
+		return []vc.SandboxStatus{
+			newStatus("payments", map[string]string{"team": "payments", "env": "prod"}),
+			newStatus("search", map[string]string{"team": "search", "env": "prod"}),
+			newStatus("unlabelled", nil),
+		}, nil
+	}
+
+	defer func() {
+		testingImpl.ListSandboxFunc = nil
+	}()
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	ctx := createCLIContext(nil)
+	ctx.App.Metadata["runtimeConfig"] = runtimeConfig
+
+	for selector, expected := range map[string][]string{
+		"":                       {"payments", "search", "unlabelled"},
+		"env=prod":               {"payments", "search"},
+		"team=payments,env=prod": {"payments"},
+		"team=storage":           nil,
+	} {
+		labels, err := types.ParseLabelSelector(selector)
+		assert.NoError(err, selector)
+
+		states, err := getContainers(context.Background(), ctx)
+		assert.NoError(err, selector)
+
+		var ids []string
+		for _, state := range filterContainers(states, labels) {
+			ids = append(ids, state.ID)
+		}
+		assert.Equal(expected, ids, selector)
+	}
+
+	set := flag.NewFlagSet("", 0)
+	set.String("label", "team", "")
+
+	ctx = createCLIContext(set)
+	ctx.App.Metadata["runtimeConfig"] = runtimeConfig
+
+	fn, ok := listCLICommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+
+	// invalid selector
+	assert.Error(fn(ctx))
+}
+
 func TestListCLIFunctionFormatFail(t *testing.T) {
 	assert := assert.New(t)
 
@@ -132,8 +132,12 @@ var runtimeCommands = []cli.Command{
 	// Kata Containers specific extensions
 	kataCheckCLICommand,
 	kataEnvCLICommand,
+	kataHypervisorCmdlineCLICommand,
 	kataNetworkCLICommand,
+	kataTimelineCLICommand,
 	factoryCLICommand,
+	kataControlServerCLICommand,
+	cleanupCLICommand,
 }
 
 // runtimeBeforeSubcommands is the function to run before command-line
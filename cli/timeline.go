@@ -0,0 +1,124 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kata-containers/runtime/pkg/katautils"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var timelineCmd = fmt.Sprintf("%s-timeline", projectPrefix)
+
+var kataTimelineCLICommand = cli.Command{
+	Name:      timelineCmd,
+	Usage:     "display the boot timeline of the sandbox a container belongs to",
+	ArgsUsage: `<container-id>`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: "table",
+			Usage: `select one of: ` + formatOptions,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		return timeline(ctx, context.Args().First(), context.String("format"), defaultOutputFile)
+	},
+}
+
+// timelineEntry is a boot timeline phase as displayed to the user.
+type timelineEntry struct {
+	Phase types.BootPhase `json:"phase"`
+	Time  time.Time       `json:"time"`
+
+	// Elapsed is the time since the first phase.
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Delta is the time since the previous phase.
+	Delta time.Duration `json:"delta"`
+}
+
+func timelineEntries(timeline types.BootTimeline) []timelineEntry {
+	var entries []timelineEntry
+
+	durations := timeline.Durations()
+	for _, e := range timeline {
+		entries = append(entries, timelineEntry{
+			Phase:   e.Phase,
+			Time:    e.Time,
+			Elapsed: e.Time.Sub(timeline[0].Time),
+			Delta:   durations[e.Phase],
+		})
+	}
+
+	return entries
+}
+
+func timeline(ctx context.Context, containerID, format string, file io.Writer) error {
+	span, _ := katautils.Trace(ctx, "timeline")
+	defer span.Finish()
+
+	if containerID == "" {
+		return fmt.Errorf("Missing container ID")
+	}
+
+	kataLog = kataLog.WithField("container", containerID)
+	setExternalLoggers(ctx, kataLog)
+	span.SetTag("container", containerID)
+
+	status, sandboxID, err := getExistingContainerInfo(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	kataLog = kataLog.WithFields(logrus.Fields{
+		"container": status.ID,
+		"sandbox":   sandboxID,
+	})
+
+	setExternalLoggers(ctx, kataLog)
+	span.SetTag("sandbox", sandboxID)
+
+	sandboxStatus, err := vci.StatusSandbox(ctx, sandboxID)
+	if err != nil {
+		return err
+	}
+
+	entries := timelineEntries(sandboxStatus.State.BootTimeline)
+
+	switch format {
+	case "table":
+		w := tabwriter.NewWriter(file, 0, 8, 1, '\t', 0)
+		fmt.Fprint(w, "PHASE\tTIME\tELAPSED\tDELTA\n")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				e.Phase,
+				e.Time.Format(time.RFC3339Nano),
+				e.Elapsed,
+				e.Delta)
+		}
+		return w.Flush()
+
+	case "json":
+		return json.NewEncoder(file).Encode(entries)
+
+	default:
+		return fmt.Errorf("invalid format option")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	vcAnnotations "github.com/kata-containers/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineEntries(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	bootTimeline := types.BootTimeline{
+		{Phase: types.BootPhaseCreate, Time: start},
+		{Phase: types.BootPhaseNetwork, Time: start.Add(time.Second)},
+		{Phase: types.BootPhaseHypervisor, Time: start.Add(3 * time.Second)},
+	}
+
+	entries := timelineEntries(bootTimeline)
+	assert.Len(entries, 3)
+	assert.Equal(time.Duration(0), entries[0].Elapsed)
+	assert.Equal(3*time.Second, entries[2].Elapsed)
+	assert.Equal(2*time.Second, entries[2].Delta)
+
+	assert.Empty(timelineEntries(types.BootTimeline{}))
+}
+
+func TestTimeline(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	path, err := createTempContainerIDMapping(sandbox.ID(), sandbox.ID())
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	start := time.Now()
+
+	testingImpl.StatusContainerFunc = func(ctx context.Context, sandboxID, containerID string) (vc.ContainerStatus, error) {
+		return vc.ContainerStatus{
+			ID: sandbox.ID(),
+			Annotations: map[string]string{
+				vcAnnotations.ContainerTypeKey: string(vc.PodSandbox),
+			},
+		}, nil
+	}
+
+	testingImpl.StatusSandboxFunc = func(ctx context.Context, sandboxID string) (vc.SandboxStatus, error) {
+		return vc.SandboxStatus{
+			ID: sandbox.ID(),
+			State: types.State{
+				BootTimeline: types.BootTimeline{
+					{Phase: types.BootPhaseCreate, Time: start},
+					{Phase: types.BootPhaseAgent, Time: start.Add(time.Second)},
+				},
+			},
+		}, nil
+	}
+
+	defer func() {
+		testingImpl.StatusContainerFunc = nil
+		testingImpl.StatusSandboxFunc = nil
+	}()
+
+	err = timeline(context.Background(), "", "json", &bytes.Buffer{})
+	assert.Error(err)
+
+	err = timeline(context.Background(), sandbox.ID(), "foo", &bytes.Buffer{})
+	assert.Error(err)
+
+	buf := &bytes.Buffer{}
+	err = timeline(context.Background(), sandbox.ID(), "table", buf)
+	assert.NoError(err)
+	assert.Contains(buf.String(), string(types.BootPhaseAgent))
+
+	buf.Reset()
+	err = timeline(context.Background(), sandbox.ID(), "json", buf)
+	assert.NoError(err)
+
+	var entries []timelineEntry
+	err = json.Unmarshal(buf.Bytes(), &entries)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal(time.Second, entries[1].Delta)
+}
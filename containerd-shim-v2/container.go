@@ -21,6 +21,7 @@ type container struct {
 	s        *service
 	ttyio    *ttyIO
 	spec     *oci.CompatOCISpec
+	rootFs   vc.RootFs
 	time     time.Time
 	execs    map[string]*exec
 	exitIOch chan struct{}
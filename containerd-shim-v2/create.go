@@ -86,6 +86,10 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest, netns
 		}
 		s.sandbox = sandbox
 
+		if err := s.startDebugServer(sandbox.ID()); err != nil {
+			logrus.WithError(err).Warn("failed to start the debug server")
+		}
+
 	case vc.PodContainer:
 		if s.sandbox == nil {
 			return nil, fmt.Errorf("BUG: Cannot start the container, since the sandbox hasn't been created")
@@ -115,6 +119,7 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest, netns
 	if err != nil {
 		return nil, err
 	}
+	container.rootFs = rootFs
 
 	return container, nil
 }
@@ -148,14 +153,6 @@ func loadSpec(r *taskAPI.CreateTaskRequest, netns string) (*oci.CompatOCISpec, s
 		}
 	}
 
-	// Todo:
-	// Since there is a bug in kata for sharedPidNs, here to
-	// remove the pidns to disable the sharePidNs temporarily,
-	// once kata fixed this issue, we can remove this line.
-	// For the bug, please see:
-	// https://github.com/kata-containers/runtime/issues/930
-	removeNamespace(&ociSpec, specs.PIDNamespace)
-
 	return &ociSpec, bundlePath, nil
 }
 
@@ -0,0 +1,221 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/sirupsen/logrus"
+)
+
+// debugSocket is the Unix socket, in the sandbox runtime directory, the shim
+// serves its debug endpoints on: /sandbox, /labels and /metrics. Query it
+// with eg.
+// curl --unix-socket /run/vc/sbs/<sandbox>/shim-debug.sock http://shim/sandbox
+const debugSocket = "shim-debug.sock"
+
+// debugLockTimeout is how long the debug endpoints wait for the service lock.
+// The lock is held by a stuck RPC when the endpoints are the most needed, the
+// goroutine stacks are served instead.
+var debugLockTimeout = 5 * time.Second
+
+func debugSocketPath(sandboxID string) string {
+	return filepath.Join(store.SandboxRuntimeRootPath(sandboxID), debugSocket)
+}
+
+// startDebugServer serves the debug endpoints of the shim until it exits.
+// The socket is only accessible by root, the endpoints expose host paths
+// and, when enabled, the pprof profiles of the shim.
+func (s *service) startDebugServer(sandboxID string) error {
+	path := debugSocketPath(sandboxID)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return err
+	}
+
+	go func() {
+		if err := http.Serve(l, s.debugMux()); err != nil {
+			logrus.WithError(err).Warn("debug server stopped")
+		}
+	}()
+
+	return nil
+}
+
+func (s *service) debugMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sandbox", s.serveSandboxDump)
+	mux.HandleFunc("/labels", s.serveSandboxLabels)
+	mux.HandleFunc("/metrics", s.serveMetrics)
+
+	if s.config != nil && s.config.EnablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return mux
+}
+
+// lockForDebug takes the service lock, giving up after debugLockTimeout. The
+// lock is then released as soon as the goroutine holding it releases it.
+func (s *service) lockForDebug() bool {
+	locked := make(chan struct{})
+	go func() {
+		s.mu.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		return true
+	case <-time.After(debugLockTimeout):
+		go func() {
+			<-locked
+			s.mu.Unlock()
+		}()
+		return false
+	}
+}
+
+// serveBusy answers a debug request the service lock could not be taken for
+// with the stacks of all the shim goroutines, the one holding the lock among
+// them.
+func serveBusy(w http.ResponseWriter) {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	fmt.Fprintf(w, "shim busy, the service lock is held for more than %v\n\n", debugLockTimeout)
+	w.Write(buf)
+}
+
+// serveSandboxDump writes the live sandbox state as JSON.
+func (s *service) serveSandboxDump(w http.ResponseWriter, r *http.Request) {
+	if !s.lockForDebug() {
+		serveBusy(w)
+		return
+	}
+	if s.sandbox == nil {
+		s.mu.Unlock()
+		http.Error(w, "sandbox not created", http.StatusNotFound)
+		return
+	}
+
+	// marshal while holding the lock, the dump shares the sandbox slices
+	data, err := json.MarshalIndent(s.sandbox.Dump(), "", "  ")
+	s.mu.Unlock()
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// sandboxLabels is the sandbox description served by the labels endpoint.
+type sandboxLabels struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels"`
+}
+
+// serveSandboxLabels writes the sandbox labels as JSON. With a selector, eg.
+// /labels?selector=team=payments,env=prod, a sandbox missing one of the
+// selected labels is not found. Tools looking for the sandboxes of a tenant
+// query the debug socket of every sandbox this way.
+func (s *service) serveSandboxLabels(w http.ResponseWriter, r *http.Request) {
+	selector, err := types.ParseLabelSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !s.lockForDebug() {
+		serveBusy(w)
+		return
+	}
+	if s.sandbox == nil {
+		s.mu.Unlock()
+		http.Error(w, "sandbox not created", http.StatusNotFound)
+		return
+	}
+
+	labels := sandboxLabels{
+		ID:     s.sandbox.ID(),
+		Labels: s.sandbox.Status().Labels,
+	}
+	s.mu.Unlock()
+
+	if !types.MatchLabels(labels.Labels, selector) {
+		http.Error(w, "sandbox labels not matching the selector", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.Marshal(labels)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// serveMetrics writes the sandbox boot timeline in the Prometheus text format,
+// for a node agent to collect from the debug sockets of the sandboxes: the
+// time each startup phase took since the previous one, and the whole boot.
+func (s *service) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	if !s.lockForDebug() {
+		serveBusy(w)
+		return
+	}
+	if s.sandbox == nil {
+		s.mu.Unlock()
+		http.Error(w, "sandbox not created", http.StatusNotFound)
+		return
+	}
+
+	id := s.sandbox.ID()
+	timeline := s.sandbox.Status().State.BootTimeline
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP kata_sandbox_boot_phase_seconds Time the sandbox took to reach a startup phase from the previous one.")
+	fmt.Fprintln(w, "# TYPE kata_sandbox_boot_phase_seconds gauge")
+	durations := timeline.Durations()
+	for _, e := range timeline {
+		fmt.Fprintf(w, "kata_sandbox_boot_phase_seconds{sandbox=%q,phase=%q} %g\n", id, e.Phase, durations[e.Phase].Seconds())
+	}
+
+	fmt.Fprintln(w, "# HELP kata_sandbox_boot_seconds Time the sandbox took to reach its last startup phase.")
+	fmt.Fprintln(w, "# TYPE kata_sandbox_boot_seconds gauge")
+	fmt.Fprintf(w, "kata_sandbox_boot_seconds{sandbox=%q} %g\n", id, timeline.Total().Seconds())
+}
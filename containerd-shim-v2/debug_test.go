@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeSandboxDump(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:         testSandboxID,
+		containers: make(map[string]*container),
+	}
+
+	w := httptest.NewRecorder()
+	s.serveSandboxDump(w, httptest.NewRequest("GET", "/sandbox", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+
+	s.sandbox = &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	w = httptest.NewRecorder()
+	s.serveSandboxDump(w, httptest.NewRequest("GET", "/sandbox", nil))
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("application/json", w.Header().Get("Content-Type"))
+
+	var dump vc.SandboxDump
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &dump))
+}
+
+func TestServeSandboxDumpBusy(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(timeout time.Duration) {
+		debugLockTimeout = timeout
+	}(debugLockTimeout)
+	debugLockTimeout = 10 * time.Millisecond
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+	}
+
+	s.mu.Lock()
+	w := httptest.NewRecorder()
+	s.serveSandboxDump(w, httptest.NewRequest("GET", "/sandbox", nil))
+	assert.Equal(http.StatusServiceUnavailable, w.Code)
+	assert.Contains(w.Body.String(), "goroutine")
+
+	w = httptest.NewRecorder()
+	s.serveSandboxLabels(w, httptest.NewRequest("GET", "/labels", nil))
+	assert.Equal(http.StatusServiceUnavailable, w.Code)
+	s.mu.Unlock()
+
+	// the lock is released by the abandoned waiters
+	s.mu.Lock()
+	s.mu.Unlock()
+
+	w = httptest.NewRecorder()
+	s.serveSandboxDump(w, httptest.NewRequest("GET", "/sandbox", nil))
+	assert.Equal(http.StatusOK, w.Code)
+}
+
+func TestDebugMuxPprof(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:     testSandboxID,
+		config: &oci.RuntimeConfig{},
+	}
+
+	w := httptest.NewRecorder()
+	s.debugMux().ServeHTTP(w, httptest.NewRequest("GET", "/debug/pprof/goroutine", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+
+	s.config.EnablePprof = true
+
+	w = httptest.NewRecorder()
+	s.debugMux().ServeHTTP(w, httptest.NewRequest("GET", "/debug/pprof/goroutine", nil))
+	assert.Equal(http.StatusOK, w.Code)
+}
+
+type labelsSandbox struct {
+	*vcmock.Sandbox
+	labels map[string]string
+}
+
+func (s *labelsSandbox) Status() vc.SandboxStatus {
+	return vc.SandboxStatus{
+		ID:     s.MockID,
+		Labels: s.labels,
+	}
+}
+
+func TestServeSandboxLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:         testSandboxID,
+		containers: make(map[string]*container),
+	}
+
+	w := httptest.NewRecorder()
+	s.serveSandboxLabels(w, httptest.NewRequest("GET", "/labels", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+
+	s.sandbox = &labelsSandbox{
+		Sandbox: &vcmock.Sandbox{
+			MockID: testSandboxID,
+		},
+		labels: map[string]string{
+			"team": "payments",
+			"env":  "prod",
+		},
+	}
+
+	for _, d := range []struct {
+		url  string
+		code int
+	}{
+		{"/labels", http.StatusOK},
+		{"/labels?selector=team=payments", http.StatusOK},
+		{"/labels?selector=team=payments,env=prod", http.StatusOK},
+		{"/labels?selector=team=search", http.StatusNotFound},
+		{"/labels?selector=team", http.StatusBadRequest},
+	} {
+		w = httptest.NewRecorder()
+		s.debugMux().ServeHTTP(w, httptest.NewRequest("GET", d.url, nil))
+		assert.Equal(d.code, w.Code, d.url)
+	}
+
+	w = httptest.NewRecorder()
+	s.serveSandboxLabels(w, httptest.NewRequest("GET", "/labels", nil))
+
+	var labels sandboxLabels
+	assert.NoError(json.Unmarshal(w.Body.Bytes(), &labels))
+	assert.Equal(testSandboxID, labels.ID)
+	assert.Equal("payments", labels.Labels["team"])
+}
+
+type timelineSandbox struct {
+	*vcmock.Sandbox
+	timeline types.BootTimeline
+}
+
+func (s *timelineSandbox) Status() vc.SandboxStatus {
+	return vc.SandboxStatus{
+		ID:    s.MockID,
+		State: types.State{BootTimeline: s.timeline},
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id: testSandboxID,
+	}
+
+	w := httptest.NewRecorder()
+	s.serveMetrics(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+
+	start := time.Now()
+	s.sandbox = &timelineSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+		timeline: types.BootTimeline{
+			{Phase: types.BootPhaseCreate, Time: start},
+			{Phase: types.BootPhaseHypervisor, Time: start.Add(1500 * time.Millisecond)},
+			{Phase: types.BootPhaseAgent, Time: start.Add(2 * time.Second)},
+		},
+	}
+
+	w = httptest.NewRecorder()
+	s.debugMux().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(body, `kata_sandbox_boot_phase_seconds{sandbox="`+testSandboxID+`",phase="hypervisor-started"} 1.5`)
+	assert.Contains(body, `kata_sandbox_boot_phase_seconds{sandbox="`+testSandboxID+`",phase="agent-ready"} 0.5`)
+	assert.Contains(body, `kata_sandbox_boot_seconds{sandbox="`+testSandboxID+`"} 2`)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"github.com/containerd/containerd/errdefs"
+	"github.com/kata-containers/runtime/protocols/errdetails"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errorClassCodes = map[vc.ErrorClass]codes.Code{
+	vc.ErrorClassHypervisorLaunch: codes.Unavailable,
+	vc.ErrorClassAgentTimeout:     codes.DeadlineExceeded,
+	vc.ErrorClassHotplug:          codes.Internal,
+	vc.ErrorClassMount:            codes.Internal,
+}
+
+// toGRPC maps err to a gRPC error. The errors of a known class carry an
+// errdetails.ErrorInfo naming that class in their status details.
+func toGRPC(err error) error {
+	class := vc.ErrorClassOf(err)
+	if class == "" {
+		return errdefs.ToGRPC(err)
+	}
+
+	code, ok := errorClassCodes[class]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: string(class),
+		Domain: errdetails.Domain,
+	})
+	if detailErr != nil {
+		logrus.WithError(detailErr).Warn("failed to attach the error details")
+		return status.Error(code, err.Error())
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/gogo/protobuf/proto"
+	"github.com/kata-containers/runtime/protocols/errdetails"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+func TestToGRPCUnclassified(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(toGRPC(nil))
+
+	err := toGRPC(errdefs.ErrNotFound)
+	assert.Equal(codes.NotFound, grpcStatus.Code(err))
+
+	err = toGRPC(fmt.Errorf("foo"))
+	_, ok := grpcStatus.FromError(err)
+	assert.False(ok)
+}
+
+func TestErrorDetailsEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	st, err := grpcStatus.New(codes.DeadlineExceeded, "timed out connecting to vsock").WithDetails(&errdetails.ErrorInfo{
+		Reason: string(vc.ErrorClassAgentTimeout),
+		Domain: errdetails.Domain,
+	})
+	assert.NoError(err)
+
+	// the details survive the encoding of the shim responses
+	data, err := proto.Marshal(st.Proto())
+	assert.NoError(err)
+
+	decoded := &status.Status{}
+	assert.NoError(proto.Unmarshal(data, decoded))
+
+	details := grpcStatus.FromProto(decoded).Details()
+	assert.Len(details, 1)
+
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	assert.True(ok)
+	assert.Equal(string(vc.ErrorClassAgentTimeout), info.Reason)
+	assert.Equal(errdetails.Domain, info.Domain)
+}
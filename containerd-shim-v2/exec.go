@@ -105,6 +105,28 @@ func newExec(c *container, stdin, stdout, stderr string, terminal bool, jspec *g
 		Interactive:     terminal,
 		Detach:          !terminal,
 		NoNewPrivileges: spec.NoNewPrivileges,
+		ApparmorProfile: spec.ApparmorProfile,
+	}
+
+	if spec.Capabilities != nil {
+		cmds.Capabilities = types.LinuxCapabilities{
+			Bounding:    spec.Capabilities.Bounding,
+			Effective:   spec.Capabilities.Effective,
+			Inheritable: spec.Capabilities.Inheritable,
+			Permitted:   spec.Capabilities.Permitted,
+			Ambient:     spec.Capabilities.Ambient,
+		}
+	}
+
+	// A user given by name is resolved in the guest, against the
+	// container passwd and group files.
+	if spec.User.Username != "" {
+		cmds.User = spec.User.Username
+		cmds.PrimaryGroup = ""
+	}
+
+	for _, gid := range spec.User.AdditionalGids {
+		cmds.SupplementaryGroups = append(cmds.SupplementaryGroups, fmt.Sprintf("%d", gid))
 	}
 
 	exec := &exec{
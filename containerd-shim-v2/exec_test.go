@@ -11,9 +11,11 @@ import (
 	"testing"
 
 	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/typeurl"
 
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,3 +50,38 @@ func TestExecNoSpecFail(t *testing.T) {
 	_, err = s.Exec(ctx, reqExec)
 	assert.Error(err)
 }
+
+func TestNewExecUser(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &container{
+		id: testContainerID,
+	}
+
+	spec, err := typeurl.MarshalAny(&specs.Process{
+		User: specs.User{
+			UID:            1000,
+			GID:            2000,
+			AdditionalGids: []uint32{3000},
+		},
+	})
+	assert.NoError(err)
+
+	execs, err := newExec(c, "", "", "", false, spec)
+	assert.NoError(err)
+	assert.Equal("1000", execs.cmds.User)
+	assert.Equal("2000", execs.cmds.PrimaryGroup)
+	assert.Equal([]string{"3000"}, execs.cmds.SupplementaryGroups)
+
+	spec, err = typeurl.MarshalAny(&specs.Process{
+		User: specs.User{
+			Username: "nobody",
+		},
+	})
+	assert.NoError(err)
+
+	execs, err = newExec(c, "", "", "", false, spec)
+	assert.NoError(err)
+	assert.Equal("nobody", execs.cmds.User)
+	assert.Empty(execs.cmds.PrimaryGroup)
+}
@@ -13,13 +13,33 @@ import (
 	vc "github.com/kata-containers/runtime/virtcontainers"
 )
 
+// SandboxMetrics are the metrics of the sandbox container: the metrics of
+// its guest cgroup, and apart the host resources used by the hypervisor of
+// the sandbox, which the guest cgroups do not see.
+type SandboxMetrics struct {
+	Metrics    *cgroups.Metrics    `json:"metrics"`
+	Hypervisor *vc.HypervisorStats `json:"hypervisor,omitempty"`
+}
+
+func init() {
+	typeurl.Register(&SandboxMetrics{}, "io.containerd.kata.v2", "SandboxMetrics")
+}
+
 func marshalMetrics(s *service, containerID string) (*google_protobuf.Any, error) {
 	stats, err := s.sandbox.StatsContainer(containerID)
 	if err != nil {
 		return nil, err
 	}
 
-	metrics := statsToMetrics(stats.CgroupStats)
+	var metrics interface{} = statsToMetrics(stats.CgroupStats)
+
+	// The hypervisor stats are only reported for the sandbox container.
+	if stats.HypervisorStats != nil {
+		metrics = &SandboxMetrics{
+			Metrics:    metrics.(*cgroups.Metrics),
+			Hypervisor: stats.HypervisorStats,
+		}
+	}
 
 	data, err := typeurl.MarshalAny(metrics)
 	if err != nil {
@@ -53,17 +73,112 @@ func statsToMetrics(cgStats *vc.CgroupStats) *cgroups.Metrics {
 		CPU: &cgroups.CPUStat{
 			Usage: &cgroups.CPUUsage{
 				Total:  cgStats.CPUStats.CPUUsage.TotalUsage,
+				Kernel: cgStats.CPUStats.CPUUsage.UsageInKernelmode,
+				User:   cgStats.CPUStats.CPUUsage.UsageInUsermode,
 				PerCPU: perCPU,
 			},
+			Throttling: &cgroups.Throttle{
+				Periods:          cgStats.CPUStats.ThrottlingData.Periods,
+				ThrottledPeriods: cgStats.CPUStats.ThrottlingData.ThrottledPeriods,
+				ThrottledTime:    cgStats.CPUStats.ThrottlingData.ThrottledTime,
+			},
 		},
 		Memory: &cgroups.MemoryStat{
-			Cache: cgStats.MemoryStats.Cache,
-			Usage: &cgroups.MemoryEntry{
-				Limit: cgStats.MemoryStats.Usage.Limit,
-				Usage: cgStats.MemoryStats.Usage.Usage,
-			},
+			Cache:     cgStats.MemoryStats.Cache,
+			Usage:     memoryEntry(cgStats.MemoryStats.Usage),
+			Swap:      memoryEntry(cgStats.MemoryStats.SwapUsage),
+			Kernel:    memoryEntry(cgStats.MemoryStats.KernelUsage),
+			KernelTCP: memoryEntry(cgStats.MemoryStats.KernelTCPUsage),
+		},
+		Blkio: &cgroups.BlkIOStat{
+			IoServiceBytesRecursive: blkioEntries(cgStats.BlkioStats.IoServiceBytesRecursive),
+			IoServicedRecursive:     blkioEntries(cgStats.BlkioStats.IoServicedRecursive),
+			IoQueuedRecursive:       blkioEntries(cgStats.BlkioStats.IoQueuedRecursive),
+			IoServiceTimeRecursive:  blkioEntries(cgStats.BlkioStats.IoServiceTimeRecursive),
+			IoWaitTimeRecursive:     blkioEntries(cgStats.BlkioStats.IoWaitTimeRecursive),
+			IoMergedRecursive:       blkioEntries(cgStats.BlkioStats.IoMergedRecursive),
+			IoTimeRecursive:         blkioEntries(cgStats.BlkioStats.IoTimeRecursive),
+			SectorsRecursive:        blkioEntries(cgStats.BlkioStats.SectorsRecursive),
 		},
 	}
 
+	memoryStatToMetrics(metrics.Memory, cgStats.MemoryStats.Stats)
+
 	return metrics
 }
+
+func memoryEntry(data vc.MemoryData) *cgroups.MemoryEntry {
+	return &cgroups.MemoryEntry{
+		Limit:   data.Limit,
+		Usage:   data.Usage,
+		Max:     data.MaxUsage,
+		Failcnt: data.Failcnt,
+	}
+}
+
+func blkioEntries(entries []vc.BlkioStatEntry) []*cgroups.BlkIOEntry {
+	var blkio []*cgroups.BlkIOEntry
+	for _, e := range entries {
+		blkio = append(
+			blkio,
+			&cgroups.BlkIOEntry{
+				Op:    e.Op,
+				Major: e.Major,
+				Minor: e.Minor,
+				Value: e.Value,
+			})
+	}
+
+	return blkio
+}
+
+// memoryStatToMetrics fills the memory metrics from the guest memory.stat.
+// Each field is read from its cgroup v1 key, or else from the matching
+// cgroup v2 one. The v2 memory.stat being hierarchical, the total fields
+// are read from the same v2 keys.
+func memoryStatToMetrics(m *cgroups.MemoryStat, raw map[string]uint64) {
+	for _, f := range []struct {
+		field *uint64
+		keys  []string
+	}{
+		{&m.Cache, []string{"cache", "file"}},
+		{&m.RSS, []string{"rss", "anon"}},
+		{&m.RSSHuge, []string{"rss_huge", "anon_thp"}},
+		{&m.MappedFile, []string{"mapped_file", "file_mapped"}},
+		{&m.Dirty, []string{"dirty", "file_dirty"}},
+		{&m.Writeback, []string{"writeback", "file_writeback"}},
+		{&m.PgPgIn, []string{"pgpgin"}},
+		{&m.PgPgOut, []string{"pgpgout"}},
+		{&m.PgFault, []string{"pgfault"}},
+		{&m.PgMajFault, []string{"pgmajfault"}},
+		{&m.InactiveAnon, []string{"inactive_anon"}},
+		{&m.ActiveAnon, []string{"active_anon"}},
+		{&m.InactiveFile, []string{"inactive_file"}},
+		{&m.ActiveFile, []string{"active_file"}},
+		{&m.Unevictable, []string{"unevictable"}},
+		{&m.HierarchicalMemoryLimit, []string{"hierarchical_memory_limit"}},
+		{&m.HierarchicalSwapLimit, []string{"hierarchical_memsw_limit"}},
+		{&m.TotalCache, []string{"total_cache", "file"}},
+		{&m.TotalRSS, []string{"total_rss", "anon"}},
+		{&m.TotalRSSHuge, []string{"total_rss_huge", "anon_thp"}},
+		{&m.TotalMappedFile, []string{"total_mapped_file", "file_mapped"}},
+		{&m.TotalDirty, []string{"total_dirty", "file_dirty"}},
+		{&m.TotalWriteback, []string{"total_writeback", "file_writeback"}},
+		{&m.TotalPgPgIn, []string{"total_pgpgin"}},
+		{&m.TotalPgPgOut, []string{"total_pgpgout"}},
+		{&m.TotalPgFault, []string{"total_pgfault", "pgfault"}},
+		{&m.TotalPgMajFault, []string{"total_pgmajfault", "pgmajfault"}},
+		{&m.TotalInactiveAnon, []string{"total_inactive_anon", "inactive_anon"}},
+		{&m.TotalActiveAnon, []string{"total_active_anon", "active_anon"}},
+		{&m.TotalInactiveFile, []string{"total_inactive_file", "inactive_file"}},
+		{&m.TotalActiveFile, []string{"total_active_file", "active_file"}},
+		{&m.TotalUnevictable, []string{"total_unevictable", "unevictable"}},
+	} {
+		for _, key := range f.keys {
+			if v, ok := raw[key]; ok {
+				*f.field = v
+				break
+			}
+		}
+	}
+}
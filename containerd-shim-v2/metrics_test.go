@@ -0,0 +1,121 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"testing"
+
+	"github.com/containerd/cgroups"
+	"github.com/containerd/typeurl"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// statsSandbox is a mock sandbox returning the same stats for all its
+// containers.
+type statsSandbox struct {
+	*vcmock.Sandbox
+	stats vc.ContainerStats
+}
+
+func (s *statsSandbox) StatsContainer(contID string) (vc.ContainerStats, error) {
+	return s.stats, nil
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	cgStats := &vc.CgroupStats{}
+	cgStats.CPUStats.CPUUsage.TotalUsage = 1000
+	cgStats.MemoryStats.Usage.Usage = 4096
+
+	sandbox := &statsSandbox{
+		Sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+		stats: vc.ContainerStats{
+			CgroupStats: cgStats,
+		},
+	}
+	s := &service{
+		sandbox: sandbox,
+	}
+
+	// The containers report their guest cgroup metrics.
+	data, err := marshalMetrics(s, testContainerID)
+	assert.NoError(err)
+	v, err := typeurl.UnmarshalAny(data)
+	assert.NoError(err)
+	metrics, ok := v.(*cgroups.Metrics)
+	assert.True(ok)
+	assert.Equal(uint64(1000), metrics.CPU.Usage.Total)
+
+	// The sandbox container reports the hypervisor apart, its guest
+	// cgroup metrics are left as they are.
+	sandbox.stats.HypervisorStats = &vc.HypervisorStats{
+		RSS:      1 << 20,
+		CPUTime:  5000,
+		VCPUTime: 3000,
+	}
+	data, err = marshalMetrics(s, testSandboxID)
+	assert.NoError(err)
+	v, err = typeurl.UnmarshalAny(data)
+	assert.NoError(err)
+	sandboxMetrics, ok := v.(*SandboxMetrics)
+	assert.True(ok)
+	assert.Equal(uint64(1000), sandboxMetrics.Metrics.CPU.Usage.Total)
+	assert.Equal(uint64(4096), sandboxMetrics.Metrics.Memory.Usage.Usage)
+	assert.Zero(sandboxMetrics.Metrics.Memory.RSS)
+	assert.Equal(sandbox.stats.HypervisorStats, sandboxMetrics.Hypervisor)
+}
+
+func TestStatsToMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	cgStats := &vc.CgroupStats{}
+	cgStats.CPUStats.CPUUsage.UsageInKernelmode = 10
+	cgStats.CPUStats.CPUUsage.UsageInUsermode = 20
+	cgStats.CPUStats.ThrottlingData.ThrottledPeriods = 3
+	cgStats.MemoryStats.Usage = vc.MemoryData{Usage: 4096, MaxUsage: 8192, Limit: 1 << 20}
+	cgStats.BlkioStats.IoServiceBytesRecursive = []vc.BlkioStatEntry{
+		{Major: 254, Minor: 1, Op: "Read", Value: 512},
+	}
+
+	// cgroup v1 memory.stat
+	cgStats.MemoryStats.Stats = map[string]uint64{
+		"rss":                 100,
+		"total_rss":           200,
+		"inactive_file":       300,
+		"total_inactive_file": 400,
+	}
+
+	metrics := statsToMetrics(cgStats)
+	assert.Equal(uint64(10), metrics.CPU.Usage.Kernel)
+	assert.Equal(uint64(20), metrics.CPU.Usage.User)
+	assert.Equal(uint64(3), metrics.CPU.Throttling.ThrottledPeriods)
+	assert.Equal(uint64(8192), metrics.Memory.Usage.Max)
+	assert.Equal(uint64(1<<20), metrics.Memory.Usage.Limit)
+	assert.Equal(uint64(100), metrics.Memory.RSS)
+	assert.Equal(uint64(200), metrics.Memory.TotalRSS)
+	assert.Equal(uint64(300), metrics.Memory.InactiveFile)
+	assert.Equal(uint64(400), metrics.Memory.TotalInactiveFile)
+	assert.Len(metrics.Blkio.IoServiceBytesRecursive, 1)
+	assert.Equal(uint64(512), metrics.Blkio.IoServiceBytesRecursive[0].Value)
+	assert.Equal("Read", metrics.Blkio.IoServiceBytesRecursive[0].Op)
+
+	// cgroup v2 memory.stat
+	cgStats.MemoryStats.Stats = map[string]uint64{
+		"anon":          100,
+		"file":          500,
+		"inactive_file": 300,
+	}
+
+	metrics = statsToMetrics(cgStats)
+	assert.Equal(uint64(100), metrics.Memory.RSS)
+	assert.Equal(uint64(100), metrics.Memory.TotalRSS)
+	assert.Equal(uint64(500), metrics.Memory.Cache)
+	assert.Equal(uint64(500), metrics.Memory.TotalCache)
+	assert.Equal(uint64(300), metrics.Memory.TotalInactiveFile)
+}
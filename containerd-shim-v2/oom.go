@@ -0,0 +1,47 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"syscall"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/sirupsen/logrus"
+)
+
+// oomExitCode is the exit code of a container process killed by SIGKILL,
+// the signal of the guest OOM killer.
+const oomExitCode = 128 + int32(syscall.SIGKILL)
+
+// checkOOM publishes a TaskOOM event if the container process was killed by
+// the guest OOM killer. The agent has no OOM event, so a process killed by
+// SIGKILL after its memory cgroup hit its limit, as the memory failcnt the
+// agent reports tells, is taken as OOM killed. It is checked once, when the
+// process exits and before its exit is published.
+func checkOOM(s *service, sandbox vc.VCSandbox, c *container, exitCode int32) error {
+	if exitCode != oomExitCode {
+		return nil
+	}
+
+	s.mu.Lock()
+	stats, err := sandbox.StatsContainer(c.id)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if stats.CgroupStats == nil || stats.CgroupStats.MemoryStats.Usage.Failcnt == 0 {
+		return nil
+	}
+
+	logrus.WithField("container", c.id).Warn("Guest OOM killer killed the container process")
+	s.sendL(&eventstypes.TaskOOM{
+		ContainerID: c.id,
+	})
+
+	return nil
+}
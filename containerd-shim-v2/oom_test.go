@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"testing"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type oomSandbox struct {
+	*vcmock.Sandbox
+	failcnt uint64
+}
+
+func (s *oomSandbox) StatsContainer(contID string) (vc.ContainerStats, error) {
+	cgStats := &vc.CgroupStats{}
+	cgStats.MemoryStats.Usage.Failcnt = s.failcnt
+
+	return vc.ContainerStats{
+		CgroupStats: cgStats,
+	}, nil
+}
+
+func TestCheckOOM(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &oomSandbox{
+		Sandbox: &vcmock.Sandbox{
+			MockID: testSandboxID,
+		},
+	}
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+		events:  make(chan interface{}, 1),
+	}
+
+	c := &container{
+		id: testContainerID,
+	}
+
+	// SIGKILL without hitting the memory limit
+	assert.NoError(checkOOM(s, sandbox, c, oomExitCode))
+	assert.Empty(s.events)
+
+	// memory limit hit without SIGKILL
+	sandbox.failcnt = 2
+	assert.NoError(checkOOM(s, sandbox, c, 1))
+	assert.Empty(s.events)
+
+	assert.NoError(checkOOM(s, sandbox, c, oomExitCode))
+	if assert.Len(s.events, 1) {
+		evt := <-s.events
+		assert.Equal(&eventstypes.TaskOOM{ContainerID: testContainerID}, evt)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/kata-containers/runtime/pkg/katautils"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/sirupsen/logrus"
+)
+
+// restartVM recreates the sandbox after its hypervisor died, as many times
+// as max_vm_restarts allows. It is called by the waiters of the container
+// processes whose wait failed, and returns true when they must not report
+// the exit: the containers were started again in a new sandbox, or could
+// not be and their exit is already reported.
+//
+// The sandbox is recreated without holding s.mu, the requests meanwhile
+// fail on the dead sandbox. The recreations are serialized by restartMu.
+func (s *service) restartVM(failed vc.VCSandbox) bool {
+	if failed == nil {
+		return false
+	}
+
+	s.restartMu.Lock()
+	defer s.restartMu.Unlock()
+
+	s.mu.Lock()
+	// Another waiter already took care of the processes of the sandbox.
+	if s.sandbox != failed {
+		s.mu.Unlock()
+		return true
+	}
+
+	if s.config == nil || s.vmRestarts >= s.config.MaxVMRestarts || !failed.HypervisorExited() {
+		s.mu.Unlock()
+		return false
+	}
+	s.vmRestarts++
+
+	logger := logrus.WithFields(logrus.Fields{
+		"sandbox": failed.ID(),
+		"restart": s.vmRestarts,
+	})
+
+	containers := make([]*container, 0, len(s.containers))
+	for _, c := range s.containers {
+		containers = append(containers, c)
+	}
+	s.mu.Unlock()
+
+	logger.Warn("The sandbox hypervisor died, recreating the sandbox")
+
+	sandbox, err := s.recreateSandbox(s.context, failed.ID(), containers)
+
+	s.mu.Lock()
+	if err != nil {
+		// A failed recreation is not retried, the waiters report the
+		// exits.
+		s.vmRestarts = s.config.MaxVMRestarts
+		s.mu.Unlock()
+		logger.WithError(err).Error("Could not recreate the sandbox")
+		return false
+	}
+	s.sandbox = sandbox
+	s.mu.Unlock()
+
+	// hold the send lock so that the start events are sent before any exit events
+	s.eventSendMu.Lock()
+	defer s.eventSendMu.Unlock()
+
+	for _, c := range containers {
+		c.mu.Lock()
+		status := c.status
+		c.mu.Unlock()
+
+		if status != task.StatusRunning && status != task.StatusPaused {
+			continue
+		}
+
+		if err := s.restartContainer(s.context, sandbox, c); err != nil {
+			logger.WithError(err).WithField("container", c.id).Error("Could not restart the container")
+			s.reportLostContainer(c)
+		}
+	}
+
+	logger.Info("Recreated the sandbox")
+
+	return true
+}
+
+// recreateSandbox removes what is left of the sandbox on the host and creates
+// it again, from the specs, rootfs and bundles its containers were created
+// with. The network is scanned again from the same network namespace.
+func (s *service) recreateSandbox(ctx context.Context, id string, containers []*container) (sandbox vc.VCSandbox, err error) {
+	var sc *container
+	for _, c := range containers {
+		if c.cType.IsSandbox() {
+			sc = c
+			break
+		}
+	}
+	if sc == nil {
+		return nil, fmt.Errorf("the sandbox container of %s is not known", id)
+	}
+
+	if err := vci.CleanupSandbox(ctx, id, true); err != nil {
+		logrus.WithError(err).WithField("sandbox", id).Warn("Could not clean up the sandbox")
+	}
+
+	sandbox, _, err = katautils.CreateSandbox(ctx, vci, *sc.spec, *s.config, sc.rootFs, sc.id, sc.bundle, "", sc.disableOutput(), false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			if err2 := vci.CleanupSandbox(ctx, id, true); err2 != nil {
+				logrus.WithError(err2).WithField("sandbox", id).Warn("Could not clean up the recreated sandbox")
+			}
+		}
+	}()
+
+	// Starting the sandbox starts all its containers, the other ones
+	// are created once it runs.
+	if err = sandbox.Start(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range containers {
+		if c == sc {
+			continue
+		}
+
+		if _, err = katautils.CreateContainer(ctx, vci, sandbox, *c.spec, c.rootFs, c.id, c.bundle, "", c.disableOutput(), true); err != nil {
+			return nil, err
+		}
+	}
+
+	return sandbox, nil
+}
+
+// restartContainer starts the container process again in the recreated
+// sandbox, the sandbox container is already started with it. The exec
+// processes are gone with the hypervisor, their waiters report them.
+func (s *service) restartContainer(ctx context.Context, sandbox vc.VCSandbox, c *container) error {
+	if !c.cType.IsSandbox() {
+		if _, err := sandbox.StartContainer(c.id); err != nil {
+			return err
+		}
+	}
+
+	// The previous I/O exit channel is closed, or will be, by the I/O of
+	// the process that died with the hypervisor.
+	c.mu.Lock()
+	c.exitIOch = make(chan struct{})
+	c.status = task.StatusRunning
+	c.mu.Unlock()
+
+	if err := startContainerIO(ctx, s, c); err != nil {
+		return err
+	}
+
+	s.send(&eventstypes.TaskStart{
+		ContainerID: c.id,
+		Pid:         s.pid,
+	})
+
+	return nil
+}
+
+// reportLostContainer reports the exit of a container process which did not
+// survive the sandbox recreation, its waiter is gone.
+func (s *service) reportLostContainer(c *container) {
+	timeStamp := time.Now()
+
+	c.mu.Lock()
+	c.status = task.StatusStopped
+	c.exit = exitCode255
+	c.time = timeStamp
+	c.mu.Unlock()
+
+	c.exitCh <- exitCode255
+
+	go cReap(s, exitCode255, c.id, "", timeStamp)
+}
+
+// disableOutput tells whether the container process output is not
+// forwarded, as on its creation.
+func (c *container) disableOutput() bool {
+	terminal := c.spec.Process != nil && c.spec.Process.Terminal
+	return noNeedForOutput(!c.terminal, terminal)
+}
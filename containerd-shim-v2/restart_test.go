@@ -0,0 +1,161 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/containerd/namespaces"
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartVMNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	failed := &vcmock.Sandbox{
+		MockID:               testSandboxID,
+		MockHypervisorExited: true,
+	}
+
+	s := &service{
+		sandbox:    failed,
+		containers: make(map[string]*container),
+		config:     &oci.RuntimeConfig{},
+	}
+
+	// disabled
+	assert.False(s.restartVM(failed))
+
+	// hypervisor still running
+	s.config.MaxVMRestarts = 1
+	failed.MockHypervisorExited = false
+	assert.False(s.restartVM(failed))
+	assert.Zero(s.vmRestarts)
+
+	// already recreated by another waiter
+	other := &vcmock.Sandbox{MockID: testSandboxID}
+	s.sandbox = other
+	assert.True(s.restartVM(failed))
+	s.sandbox = failed
+
+	// no sandbox container to recreate the sandbox from, not retried
+	s.config.MaxVMRestarts = 2
+	failed.MockHypervisorExited = true
+	assert.False(s.restartVM(failed))
+	assert.Equal(uint32(2), s.vmRestarts)
+}
+
+func TestRestartVM(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledNeedRoot)
+	}
+
+	assert := assert.New(t)
+
+	failed := &vcmock.Sandbox{
+		MockID:               testSandboxID,
+		MockHypervisorExited: true,
+	}
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		MockContainers: []*vcmock.Container{
+			{MockID: testSandboxID},
+		},
+	}
+
+	var cleanups int
+	testingImpl.CleanupSandboxFunc = func(ctx context.Context, sandboxID string, force bool) error {
+		assert.True(force)
+		cleanups++
+		return nil
+	}
+	testingImpl.CreateSandboxFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
+		return sandbox, nil
+	}
+
+	defer func() {
+		testingImpl.CleanupSandboxFunc = nil
+		testingImpl.CreateSandboxFunc = nil
+	}()
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+	runtimeConfig.MaxVMRestarts = 1
+
+	s := &service{
+		context:    namespaces.WithNamespace(context.Background(), "UnitTest"),
+		sandbox:    failed,
+		containers: make(map[string]*container),
+		config:     &runtimeConfig,
+		ec:         make(chan exit, 4),
+	}
+
+	newTestContainer := func(id string, cType vc.ContainerType, status task.Status) *container {
+		bundlePath := filepath.Join(tmpdir, id)
+		assert.NoError(makeOCIBundle(bundlePath))
+
+		spec, err := oci.ParseConfigJSON(bundlePath)
+		assert.NoError(err)
+		containerType := testContainerTypeContainer
+		if cType.IsSandbox() {
+			containerType = testContainerTypeSandbox
+		}
+		spec.Annotations = map[string]string{
+			testContainerTypeAnnotation: containerType,
+			testSandboxIDAnnotation:     testSandboxID,
+		}
+
+		c := &container{
+			s:        s,
+			spec:     &spec,
+			id:       id,
+			bundle:   bundlePath,
+			cType:    cType,
+			execs:    make(map[string]*exec),
+			status:   status,
+			exitIOch: make(chan struct{}),
+			exitCh:   make(chan uint32, 1),
+		}
+		close(c.exitIOch)
+		s.containers[id] = c
+
+		return c
+	}
+
+	sc := newTestContainer(testSandboxID, vc.PodSandbox, task.StatusRunning)
+	running := newTestContainer(testContainerID, vc.PodContainer, task.StatusRunning)
+	stopped := newTestContainer("stopped", vc.PodContainer, task.StatusStopped)
+
+	if !assert.True(s.restartVM(failed)) {
+		return
+	}
+	assert.Equal(uint32(1), s.vmRestarts)
+	assert.Equal(sandbox, s.sandbox)
+	assert.Equal(1, cleanups)
+
+	// The restarted processes are waited for in the new sandbox, the
+	// stopped container is not started again.
+	for _, c := range []*container{sc, running} {
+		assert.Equal(uint32(0), <-c.exitCh)
+	}
+	assert.Len(stopped.exitCh, 0)
+
+	// the restarts are exhausted
+	sandbox.MockHypervisorExited = true
+	assert.False(s.restartVM(sandbox))
+}
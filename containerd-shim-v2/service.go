@@ -110,6 +110,10 @@ type service struct {
 
 	ec chan exit
 	id string
+
+	// vmRestarts counts the sandbox recreations, see restartVM.
+	vmRestarts uint32
+	restartMu  sync.Mutex
 }
 
 func newCommand(ctx context.Context, containerdBinary, id, containerdAddress string) (*sysexec.Cmd, error) {
@@ -324,7 +328,7 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 
 	c, err = create(ctx, s, r, netns)
 	if err != nil {
-		return nil, err
+		return nil, toGRPC(err)
 	}
 
 	c.status = task.StatusCreated
@@ -368,7 +372,7 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.
 	if r.ExecID == "" {
 		err = startContainer(ctx, s, c)
 		if err != nil {
-			return nil, errdefs.ToGRPC(err)
+			return nil, toGRPC(err)
 		}
 		s.send(&eventstypes.TaskStart{
 			ContainerID: c.id,
@@ -378,7 +382,7 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.
 		//start an exec
 		_, err = startExec(ctx, s, r.ID, r.ExecID)
 		if err != nil {
-			return nil, errdefs.ToGRPC(err)
+			return nil, toGRPC(err)
 		}
 		s.send(&eventstypes.TaskExecStarted{
 			ContainerID: c.id,
@@ -405,7 +409,7 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAP
 	if r.ExecID == "" {
 		err = deleteContainer(ctx, s, c)
 		if err != nil {
-			return nil, err
+			return nil, toGRPC(err)
 		}
 
 		// Take care of the use case where it is a sandbox.
@@ -415,12 +419,12 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAP
 		if c.cType.IsSandbox() {
 			if err = s.sandbox.Stop(); err != nil {
 				logrus.WithField("sandbox", s.sandbox.ID()).Error("failed to stop sandbox")
-				return nil, err
+				return nil, toGRPC(err)
 			}
 
 			if err = s.sandbox.Delete(); err != nil {
 				logrus.WithField("sandbox", s.sandbox.ID()).Error("failed to delete sandbox")
-				return nil, err
+				return nil, toGRPC(err)
 			}
 		}
 
@@ -468,7 +472,7 @@ func (s *service) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (*pty
 
 	execs, err := newExec(c, r.Stdin, r.Stdout, r.Stderr, r.Terminal, r.Spec)
 	if err != nil {
-		return nil, errdefs.ToGRPC(err)
+		return nil, toGRPC(err)
 	}
 
 	c.execs[r.ExecID] = execs
@@ -505,7 +509,7 @@ func (s *service) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (*
 	}
 	err = s.sandbox.WinsizeProcess(c.id, processID, r.Height, r.Width)
 	if err != nil {
-		return nil, err
+		return nil, toGRPC(err)
 	}
 
 	return empty, err
@@ -636,7 +640,7 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (*ptypes.Emp
 
 	err = s.sandbox.SignalProcess(c.id, processID, signum, r.All)
 	if err != nil {
-		return nil, err
+		return nil, toGRPC(err)
 	}
 
 	// Since the k8s will use the SIGTERM signal to stop a container by default, but
@@ -736,7 +740,7 @@ func (s *service) Stats(ctx context.Context, r *taskAPI.StatsRequest) (*taskAPI.
 
 	data, err := marshalMetrics(s, c.id)
 	if err != nil {
-		return nil, err
+		return nil, toGRPC(err)
 	}
 
 	return &taskAPI.StatsResponse{
@@ -761,7 +765,7 @@ func (s *service) Update(ctx context.Context, r *taskAPI.UpdateTaskRequest) (*pt
 
 	err = s.sandbox.UpdateContainer(r.ID, *resources)
 	if err != nil {
-		return nil, errdefs.ToGRPC(err)
+		return nil, toGRPC(err)
 	}
 
 	return empty, nil
@@ -47,6 +47,16 @@ func startContainer(ctx context.Context, s *service, c *container) error {
 
 	c.status = task.StatusRunning
 
+	if err := startContainerIO(ctx, s, c); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// startContainerIO routes the I/O of the started container process and
+// waits for its exit.
+func startContainerIO(ctx context.Context, s *service, c *container) error {
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, c.id)
 	if err != nil {
 		return err
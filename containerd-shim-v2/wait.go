@@ -18,6 +18,11 @@ func wait(s *service, c *container, execID string) (int32, error) {
 
 	processID := c.id
 
+	// The sandbox may be recreated while the process runs, see restartVM.
+	s.mu.Lock()
+	sandbox := s.sandbox
+	s.mu.Unlock()
+
 	if execID == "" {
 		//wait until the io closed, then wait the container
 		<-c.exitIOch
@@ -33,12 +38,22 @@ func wait(s *service, c *container, execID string) (int32, error) {
 		processID = execs.id
 	}
 
-	ret, err := s.sandbox.WaitProcess(c.id, processID)
+	ret, err := sandbox.WaitProcess(c.id, processID)
 	if err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"container": c.id,
 			"pid":       processID,
 		}).Error("Wait for process failed")
+
+		if execID == "" && s.restartVM(sandbox) {
+			return exitCode255, nil
+		}
+	}
+
+	if execID == "" && !c.cType.IsSandbox() {
+		if err := checkOOM(s, sandbox, c, ret); err != nil {
+			logrus.WithError(err).WithField("container", c.id).Debug("Could not check the container OOM kills")
+		}
 	}
 
 	if execID == "" {
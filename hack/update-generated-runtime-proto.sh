@@ -4,11 +4,20 @@
 # SPDX-License-Identifier: Apache-2.0
 #
 
-protoc \
-	-I=$GOPATH/src \
-	-I=$GOPATH/src/github.com/gogo/protobuf/protobuf \
-	--proto_path=protocols/cache \
-	--gogofast_out=\
+for proto in cache control hypervisor; do
+	protoc \
+		-I=$GOPATH/src \
+		-I=$GOPATH/src/github.com/gogo/protobuf/protobuf \
+		--proto_path=protocols/$proto \
+		--gogofast_out=\
 Mgoogle/protobuf/empty.proto=github.com/gogo/protobuf/types,\
-plugins=grpc:protocols/cache \
-	protocols/cache/cache.proto
+plugins=grpc:protocols/$proto \
+		protocols/$proto/$proto.proto
+done
+
+# The error details are decoded from the gRPC status by the golang/protobuf
+# registry, they are not generated with gogo.
+protoc \
+	--proto_path=protocols/errdetails \
+	--go_out=protocols/errdetails \
+	protocols/errdetails/errdetails.proto
@@ -45,6 +45,12 @@ const defaultGuestHookPath string = ""
 
 const defaultVMCacheEndpoint string = "/var/run/kata-containers/cache.sock"
 
+const defaultVhostUserStorePath string = "/var/run/kata-containers/vhost-user"
+
+// The kubelet configuration housekeeping_cpus = "kubelet" reads the
+// reservedSystemCPUs of.
+var kubeletConfigPath = "/var/lib/kubelet/config.yaml"
+
 // Default config file used by stateless systems.
 var defaultRuntimeConfiguration = "/usr/share/defaults/kata-containers/configuration.toml"
 
@@ -10,6 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"path/filepath"
+	"regexp"
 	goruntime "runtime"
 	"strings"
 
@@ -20,6 +23,7 @@ import (
 	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/runtime/virtcontainers/utils"
 	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
 )
 
 const (
@@ -50,6 +54,7 @@ const (
 	// supported hypervisor component types
 	firecrackerHypervisorTableType = "firecracker"
 	qemuHypervisorTableType        = "qemu"
+	remoteHypervisorTableType      = "remote"
 
 	// supported proxy component types
 	ccProxyTableType   = "cc"
@@ -77,10 +82,29 @@ type tomlConfig struct {
 	Netmon     netmon
 }
 
+// vCPUs is a number of vCPUs, which can be a fraction of a vCPU. TOML
+// integers are accepted as well as floats.
+type vCPUs float32
+
+// UnmarshalTOML implements the toml.Unmarshaler interface.
+func (v *vCPUs) UnmarshalTOML(data interface{}) error {
+	switch n := data.(type) {
+	case int64:
+		*v = vCPUs(n)
+	case float64:
+		*v = vCPUs(n)
+	default:
+		return fmt.Errorf("Invalid number of vCPUs %v", data)
+	}
+
+	return nil
+}
+
 type factory struct {
 	Template        bool   `toml:"enable_template"`
 	VMCacheNumber   uint   `toml:"vm_cache_number"`
 	VMCacheEndpoint string `toml:"vm_cache_endpoint"`
+	VMPoolSize      uint   `toml:"vm_pool_size"`
 }
 
 type hypervisor struct {
@@ -88,8 +112,21 @@ type hypervisor struct {
 	Kernel                  string `toml:"kernel"`
 	Initrd                  string `toml:"initrd"`
 	Image                   string `toml:"image"`
+	GuestBoot               string `toml:"guest_boot"`
 	Firmware                string `toml:"firmware"`
+	FirmwareVars            string `toml:"firmware_vars"`
+	FirmwareVarsReadOnly    bool   `toml:"firmware_vars_readonly"`
+	SecureBoot              bool   `toml:"enable_secure_boot"`
 	MachineAccelerators     string `toml:"machine_accelerators"`
+	KernelIRQChip           string `toml:"kernel_irqchip"`
+	DisableVMPort           bool   `toml:"disable_vmport"`
+	PCIHotplug              string `toml:"pci_hotplug"`
+	PCIeRootPorts           uint32 `toml:"pcie_root_ports"`
+	DisablePMU              bool   `toml:"disable_pmu"`
+	Accelerator             string `toml:"accelerator"`
+	AcceleratorTCGFallback  bool   `toml:"enable_tcg_fallback"`
+	CPUModel                string `toml:"cpu_model"`
+	CPUFeatures             string `toml:"cpu_features"`
 	KernelParams            string `toml:"kernel_params"`
 	MachineType             string `toml:"machine_type"`
 	BlockDeviceDriver       string `toml:"block_device_driver"`
@@ -97,14 +134,25 @@ type hypervisor struct {
 	BlockDeviceCacheSet     bool   `toml:"block_device_cache_set"`
 	BlockDeviceCacheDirect  bool   `toml:"block_device_cache_direct"`
 	BlockDeviceCacheNoflush bool   `toml:"block_device_cache_noflush"`
-	NumVCPUs                int32  `toml:"default_vcpus"`
+	NumVCPUs                vCPUs  `toml:"default_vcpus"`
 	DefaultMaxVCPUs         uint32 `toml:"default_maxvcpus"`
+	CPUCores                uint32 `toml:"cpu_cores"`
+	CPUThreads              uint32 `toml:"cpu_threads"`
+	MirrorHostSMT           bool   `toml:"mirror_host_smt"`
 	MemorySize              uint32 `toml:"default_memory"`
 	MemSlots                uint32 `toml:"memory_slots"`
+	DefaultMaxMemorySize    uint32 `toml:"default_maxmemory"`
 	MemOffset               uint32 `toml:"memory_offset"`
 	DefaultBridges          uint32 `toml:"default_bridges"`
 	Msize9p                 uint32 `toml:"msize_9p"`
+	Cache9p                 string `toml:"cache_9p"`
+	Version9p               string `toml:"version_9p"`
 	DisableBlockDeviceUse   bool   `toml:"disable_block_device_use"`
+	RBDVolumePassthrough    bool   `toml:"rbd_volume_passthrough"`
+	ISCSIVolumePassthrough  bool   `toml:"iscsi_volume_passthrough"`
+	ImageVolumePassthrough  bool   `toml:"image_volume_passthrough"`
+	EnableVhostUserStore    bool   `toml:"enable_vhost_user_store"`
+	VhostUserStorePath      string `toml:"vhost_user_store_path"`
 	MemPrealloc             bool   `toml:"enable_mem_prealloc"`
 	HugePages               bool   `toml:"enable_hugepages"`
 	Swap                    bool   `toml:"enable_swap"`
@@ -112,9 +160,30 @@ type hypervisor struct {
 	DisableNestingChecks    bool   `toml:"disable_nesting_checks"`
 	EnableIOThreads         bool   `toml:"enable_iothreads"`
 	UseVSock                bool   `toml:"use_vsock"`
+	VSockLogPort            uint32 `toml:"vsock_log_port"`
+	VSockDebugConsolePort   uint32 `toml:"vsock_debug_console_port"`
 	HotplugVFIOOnRootBus    bool   `toml:"hotplug_vfio_on_root_bus"`
 	DisableVhostNet         bool   `toml:"disable_vhost_net"`
+	EnablePTPKVM            bool   `toml:"enable_ptp_kvm"`
+	EnableIOMMU             bool   `toml:"enable_iommu"`
+	ScratchDiskSize         uint32 `toml:"scratch_disk_size"`
+	ScratchWritableLayer    bool   `toml:"scratch_writable_layer"`
 	GuestHookPath           string `toml:"guest_hook_path"`
+	KdumpCrashKernelSize    uint32 `toml:"guest_kdump_crashkernel_size"`
+	KdumpDir                string `toml:"guest_kdump_dir"`
+	RemoteHypervisorSocket  string `toml:"remote_hypervisor_socket"`
+	EnableCoreScheduling    bool   `toml:"enable_core_scheduling"`
+	VCPUsCPUPool            string `toml:"vcpu_cpu_pool"`
+	HousekeepingCPUs        string `toml:"housekeeping_cpus"`
+	EnableMemoryReclaim     bool   `toml:"enable_memory_reclaim"`
+	MemoryReclaimLowFree    uint32 `toml:"memory_reclaim_low_free"`
+	MemoryReclaimHighFree   uint32 `toml:"memory_reclaim_high_free"`
+	MemoryReclaimMinMemory  uint32 `toml:"memory_reclaim_min_memory"`
+
+	// guest assets the sandbox annotations can select
+	ValidKernelPaths []string `toml:"valid_kernel_paths"`
+	ValidImagePaths  []string `toml:"valid_image_paths"`
+	ValidInitrdPaths []string `toml:"valid_initrd_paths"`
 }
 
 type proxy struct {
@@ -127,8 +196,27 @@ type runtime struct {
 	Tracing             bool     `toml:"enable_tracing"`
 	DisableNewNetNs     bool     `toml:"disable_new_netns"`
 	DisableGuestSeccomp bool     `toml:"disable_guest_seccomp"`
+	IdlePauseTimeout    uint32   `toml:"idle_pause_timeout"`
+	KSMThrottling       bool     `toml:"enable_ksm_throttling"`
+	KSMAggressiveTime   uint32   `toml:"ksm_aggressive_duration"`
+	KSMStart            bool     `toml:"ksm_start"`
+	EnablePprof         bool     `toml:"enable_pprof"`
+	MaxVMRestarts       uint32   `toml:"max_vm_restarts"`
+	SlowOpThreshold     uint32   `toml:"slow_operation_threshold"`
+	VolumeSourceTimeout uint32   `toml:"volume_source_timeout"`
+	AuditLog            string   `toml:"audit_log"`
+	AuditAuditd         bool     `toml:"audit_auditd"`
+	PreVMStartHooks     []string `toml:"pre_vm_start_hooks"`
+	PostVMStartHooks    []string `toml:"post_vm_start_hooks"`
+	PreDeleteHooks      []string `toml:"pre_sandbox_delete_hooks"`
+	HostHooksTimeout    uint32   `toml:"host_hooks_timeout"`
+	HostHooksFailure    string   `toml:"host_hooks_failure_policy"`
+	PrivilegedNoDevices bool     `toml:"privileged_without_host_devices"`
+	StrictOCISpec       bool     `toml:"strict_oci_spec"`
+	EnableAnnotations   []string `toml:"enable_annotations"`
 	Experimental        []string `toml:"experimental"`
 	InterNetworkModel   string   `toml:"internetworking_model"`
+	HostNetworking      string   `toml:"host_networking"`
 }
 
 type shim struct {
@@ -138,6 +226,8 @@ type shim struct {
 }
 
 type agent struct {
+	RPCRecordDir     string `toml:"rpc_record_dir"`
+	DedicatedStreams bool   `toml:"dedicated_stream_connections"`
 }
 
 type netmon struct {
@@ -146,6 +236,14 @@ type netmon struct {
 	Enable bool   `toml:"enable_netmon"`
 }
 
+func newKataAgentConfig(a agent, useVSock bool) vc.KataAgentConfig {
+	return vc.KataAgentConfig{
+		UseVSock:         useVSock,
+		RPCRecordDir:     a.RPCRecordDir,
+		DedicatedStreams: a.DedicatedStreams,
+	}
+}
+
 func (h hypervisor) path() (string, error) {
 	p := h.Path
 
@@ -199,6 +297,14 @@ func (h hypervisor) firmware() (string, error) {
 	return ResolvePath(p)
 }
 
+func (h hypervisor) firmwareVars() (string, error) {
+	if h.FirmwareVars == "" {
+		return "", nil
+	}
+
+	return ResolvePath(h.FirmwareVars)
+}
+
 func (h hypervisor) machineAccelerators() string {
 	var machineAccelerators string
 	accelerators := strings.Split(h.MachineAccelerators, ",")
@@ -214,6 +320,17 @@ func (h hypervisor) machineAccelerators() string {
 	return machineAccelerators
 }
 
+func (h hypervisor) cpuFeatures() string {
+	var features []string
+	for _, f := range strings.Split(h.CPUFeatures, ",") {
+		if f = strings.Trim(f, "\r\t\n "); f != "" {
+			features = append(features, f)
+		}
+	}
+
+	return strings.Join(features, ",")
+}
+
 func (h hypervisor) kernelParams() string {
 	if h.KernelParams == "" {
 		return defaultKernelParams
@@ -241,19 +358,33 @@ func (h hypervisor) GetEntropySource() string {
 func (h hypervisor) defaultVCPUs() uint32 {
 	numCPUs := goruntime.NumCPU()
 
-	if h.NumVCPUs < 0 || h.NumVCPUs > int32(numCPUs) {
+	if h.NumVCPUs < 0 || h.NumVCPUs > vCPUs(numCPUs) {
 		return uint32(numCPUs)
 	}
 	if h.NumVCPUs == 0 { // or unspecified
 		return defaultVCPUCount
 	}
 
-	return uint32(h.NumVCPUs)
+	return uint32(math.Ceil(float64(h.NumVCPUs)))
+}
+
+// fractionalVCPUs returns the default number of vCPUs when it is a fraction,
+// and zero when it is a whole number of vCPUs.
+func (h hypervisor) fractionalVCPUs() float32 {
+	if h.NumVCPUs <= 0 || h.NumVCPUs > vCPUs(goruntime.NumCPU()) {
+		return 0
+	}
+
+	if n := float64(h.NumVCPUs); n == math.Trunc(n) {
+		return 0
+	}
+
+	return float32(h.NumVCPUs)
 }
 
 func (h hypervisor) defaultMaxVCPUs() uint32 {
 	numcpus := uint32(goruntime.NumCPU())
-	maxvcpus := vc.MaxQemuVCPUs()
+	maxvcpus := vc.MaxQemuMachineVCPUs(h.machineType())
 	reqVCPUs := h.DefaultMaxVCPUs
 
 	//don't exceed the number of physical CPUs. If a default is not provided, use the
@@ -296,6 +427,14 @@ func (h hypervisor) defaultMemOffset() uint32 {
 	return offset
 }
 
+func (h hypervisor) vhostUserStorePath() string {
+	if h.VhostUserStorePath == "" {
+		return defaultVhostUserStorePath
+	}
+
+	return h.VhostUserStorePath
+}
+
 func (h hypervisor) defaultBridges() uint32 {
 	if h.DefaultBridges == 0 {
 		return defaultBridgesCount
@@ -343,15 +482,61 @@ func (h hypervisor) guestHookPath() string {
 	return h.GuestHookPath
 }
 
+// housekeepingCPUsKubelet takes the housekeeping CPUs from the CPUs the
+// kubelet reserves for the system.
+const housekeepingCPUsKubelet = "kubelet"
+
+// cpuPools returns the cpusets of the vCPU threads and of the other VMM
+// threads, making sure they do not overlap.
+func (h hypervisor) cpuPools() (vcpus string, housekeeping string, err error) {
+	housekeeping = h.HousekeepingCPUs
+
+	if housekeeping == housekeepingCPUsKubelet {
+		data, err := ioutil.ReadFile(kubeletConfigPath)
+		if err != nil {
+			return "", "", fmt.Errorf("Could not read the kubelet reserved CPUs: %v", err)
+		}
+
+		var kubelet struct {
+			ReservedSystemCPUs string `yaml:"reservedSystemCPUs"`
+		}
+		if err := yaml.Unmarshal(data, &kubelet); err != nil {
+			return "", "", fmt.Errorf("Could not parse the kubelet configuration %s: %v", kubeletConfigPath, err)
+		}
+
+		if kubelet.ReservedSystemCPUs == "" {
+			return "", "", fmt.Errorf("No reservedSystemCPUs in the kubelet configuration %s", kubeletConfigPath)
+		}
+
+		housekeeping = kubelet.ReservedSystemCPUs
+	}
+
+	hkCPUs, err := utils.ParseCPUSet(housekeeping)
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid housekeeping_cpus: %v", err)
+	}
+
+	vcpuCPUs, err := utils.ParseCPUSet(h.VCPUsCPUPool)
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid vcpu_cpu_pool: %v", err)
+	}
+
+	for _, cpu := range vcpuCPUs {
+		for _, hk := range hkCPUs {
+			if cpu == hk {
+				return "", "", fmt.Errorf("CPU %d is both in vcpu_cpu_pool and in the housekeeping CPUs", cpu)
+			}
+		}
+	}
+
+	return h.VCPUsCPUPool, housekeeping, nil
+}
+
 func (h hypervisor) getInitrdAndImage() (initrd string, image string, err error) {
 	initrd, errInitrd := h.initrd()
 
 	image, errImage := h.image()
 
-	if image != "" && initrd != "" {
-		return "", "", errors.New("having both an image and an initrd defined in the configuration file is not supported")
-	}
-
 	if errInitrd != nil && errImage != nil {
 		return "", "", fmt.Errorf("Either initrd or image must be set to a valid path (initrd: %v) (image: %v)", errInitrd, errImage)
 	}
@@ -359,6 +544,16 @@ func (h hypervisor) getInitrdAndImage() (initrd string, image string, err error)
 	return
 }
 
+// guestBoot returns the configured guest boot, defaulting to the image when
+// both an image and an initrd are configured.
+func (h hypervisor) guestBoot(initrd, image string) string {
+	if h.GuestBoot == "" && initrd != "" && image != "" {
+		return "image"
+	}
+
+	return h.GuestBoot
+}
+
 func (p proxy) path() (string, error) {
 	path := p.Path
 	if path == "" {
@@ -434,6 +629,11 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	vcpuCPUPool, housekeepingCPUs, err := h.cpuPools()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	if !utils.SupportsVsocks() {
 		return vc.HypervisorConfig{}, errors.New("No vsock support, firecracker cannot be used")
 	}
@@ -443,9 +643,14 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		KernelPath:            kernel,
 		InitrdPath:            initrd,
 		ImagePath:             image,
+		GuestBoot:             h.guestBoot(initrd, image),
 		FirmwarePath:          firmware,
+		ValidKernelPaths:      h.ValidKernelPaths,
+		ValidImagePaths:       h.ValidImagePaths,
+		ValidInitrdPaths:      h.ValidInitrdPaths,
 		KernelParams:          vc.DeserializeParams(strings.Fields(kernelParams)),
 		NumVCPUs:              h.defaultVCPUs(),
+		DefaultVCPUs:          h.fractionalVCPUs(),
 		DefaultMaxVCPUs:       h.defaultMaxVCPUs(),
 		MemorySize:            h.defaultMemSz(),
 		MemSlots:              h.defaultMemSlots(),
@@ -459,7 +664,12 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		BlockDeviceDriver:     blockDriver,
 		EnableIOThreads:       h.EnableIOThreads,
 		UseVSock:              true,
+		VSockLogPort:          h.VSockLogPort,
+		VSockDebugConsolePort: h.VSockDebugConsolePort,
 		GuestHookPath:         h.guestHookPath(),
+		EnableCoreScheduling:  h.EnableCoreScheduling,
+		VCPUsCPUPool:          vcpuCPUPool,
+		HousekeepingCPUs:      housekeepingCPUs,
 	}, nil
 }
 
@@ -494,6 +704,11 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	firmwareVars, err := h.firmwareVars()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	machineAccelerators := h.machineAccelerators()
 	kernelParams := h.kernelParams()
 	machineType := h.machineType()
@@ -503,6 +718,11 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
+	vcpuCPUPool, housekeepingCPUs, err := h.cpuPools()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
 	useVSock := false
 	if h.useVSock() {
 		if utils.SupportsVsocks() {
@@ -518,18 +738,44 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		KernelPath:              kernel,
 		InitrdPath:              initrd,
 		ImagePath:               image,
+		GuestBoot:               h.guestBoot(initrd, image),
 		FirmwarePath:            firmware,
+		FirmwareVarsPath:        firmwareVars,
+		FirmwareVarsReadOnly:    h.FirmwareVarsReadOnly,
+		SecureBoot:              h.SecureBoot,
+		ValidKernelPaths:        h.ValidKernelPaths,
+		ValidImagePaths:         h.ValidImagePaths,
+		ValidInitrdPaths:        h.ValidInitrdPaths,
 		MachineAccelerators:     machineAccelerators,
+		KernelIRQChip:           h.KernelIRQChip,
+		DisableVMPort:           h.DisableVMPort,
+		PCIHotplug:              h.PCIHotplug,
+		PCIeRootPorts:           h.PCIeRootPorts,
+		DisablePMU:              h.DisablePMU,
+		Accelerator:             h.Accelerator,
+		AcceleratorTCGFallback:  h.AcceleratorTCGFallback,
+		CPUModel:                h.CPUModel,
+		CPUFeatures:             h.cpuFeatures(),
 		KernelParams:            vc.DeserializeParams(strings.Fields(kernelParams)),
 		HypervisorMachineType:   machineType,
 		NumVCPUs:                h.defaultVCPUs(),
+		DefaultVCPUs:            h.fractionalVCPUs(),
 		DefaultMaxVCPUs:         h.defaultMaxVCPUs(),
+		CPUCores:                h.CPUCores,
+		CPUThreads:              h.CPUThreads,
+		MirrorHostSMT:           h.MirrorHostSMT,
 		MemorySize:              h.defaultMemSz(),
 		MemSlots:                h.defaultMemSlots(),
+		DefaultMaxMemorySize:    h.DefaultMaxMemorySize,
 		MemOffset:               h.defaultMemOffset(),
 		EntropySource:           h.GetEntropySource(),
 		DefaultBridges:          h.defaultBridges(),
 		DisableBlockDeviceUse:   h.DisableBlockDeviceUse,
+		RBDVolumePassthrough:    h.RBDVolumePassthrough,
+		ISCSIVolumePassthrough:  h.ISCSIVolumePassthrough,
+		ImageVolumePassthrough:  h.ImageVolumePassthrough,
+		EnableVhostUserStore:    h.EnableVhostUserStore,
+		VhostUserStorePath:      h.vhostUserStorePath(),
 		MemPrealloc:             h.MemPrealloc,
 		HugePages:               h.HugePages,
 		Mlock:                   !h.Swap,
@@ -541,10 +787,29 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		BlockDeviceCacheNoflush: h.BlockDeviceCacheNoflush,
 		EnableIOThreads:         h.EnableIOThreads,
 		Msize9p:                 h.msize9p(),
+		Cache9p:                 h.Cache9p,
+		Version9p:               h.Version9p,
 		UseVSock:                useVSock,
+		VSockLogPort:            h.VSockLogPort,
+		VSockDebugConsolePort:   h.VSockDebugConsolePort,
 		HotplugVFIOOnRootBus:    h.HotplugVFIOOnRootBus,
 		DisableVhostNet:         h.DisableVhostNet,
+		EnablePTPKVM:            h.EnablePTPKVM,
+		EnableIOMMU:             h.EnableIOMMU,
+		ScratchDiskSize:         h.ScratchDiskSize,
+		ScratchWritableLayer:    h.ScratchWritableLayer,
 		GuestHookPath:           h.guestHookPath(),
+		KdumpCrashKernelSize:    h.KdumpCrashKernelSize,
+		KdumpDir:                h.KdumpDir,
+		EnableCoreScheduling:    h.EnableCoreScheduling,
+		VCPUsCPUPool:            vcpuCPUPool,
+		HousekeepingCPUs:        housekeepingCPUs,
+		MemoryReclaim: vc.MemoryReclaimConfig{
+			Enable:    h.EnableMemoryReclaim,
+			LowFree:   h.MemoryReclaimLowFree,
+			HighFree:  h.MemoryReclaimHighFree,
+			MinMemory: h.MemoryReclaimMinMemory,
+		},
 	}, nil
 }
 
@@ -556,6 +821,7 @@ func newFactoryConfig(f factory) (oci.FactoryConfig, error) {
 		Template:        f.Template,
 		VMCacheNumber:   f.VMCacheNumber,
 		VMCacheEndpoint: f.VMCacheEndpoint,
+		VMPoolSize:      f.VMPoolSize,
 	}, nil
 }
 
@@ -572,6 +838,61 @@ func newShimConfig(s shim) (vc.ShimConfig, error) {
 	}, nil
 }
 
+// newRemoteHypervisorConfig returns the configuration of a VM managed by an
+// out of tree driver. The runtime has no access to the VMM process, the
+// agent is reached through the hybrid vsock the driver exposes.
+func newRemoteHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
+	if h.RemoteHypervisorSocket == "" {
+		return vc.HypervisorConfig{}, errors.New("Missing remote_hypervisor_socket")
+	}
+
+	if !filepath.IsAbs(h.RemoteHypervisorSocket) {
+		return vc.HypervisorConfig{}, fmt.Errorf("Remote hypervisor socket %q must be absolute", h.RemoteHypervisorSocket)
+	}
+
+	kernel, err := h.kernel()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
+	initrd, image, err := h.getInitrdAndImage()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
+	firmware, err := h.firmware()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
+	blockDriver, err := h.blockDeviceDriver()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
+	}
+
+	return vc.HypervisorConfig{
+		RemoteHypervisorSocket: h.RemoteHypervisorSocket,
+		KernelPath:             kernel,
+		InitrdPath:             initrd,
+		ImagePath:              image,
+		GuestBoot:              h.guestBoot(initrd, image),
+		FirmwarePath:           firmware,
+		ValidKernelPaths:       h.ValidKernelPaths,
+		ValidImagePaths:        h.ValidImagePaths,
+		ValidInitrdPaths:       h.ValidInitrdPaths,
+		KernelParams:           vc.DeserializeParams(strings.Fields(h.kernelParams())),
+		NumVCPUs:               h.defaultVCPUs(),
+		DefaultVCPUs:           h.fractionalVCPUs(),
+		DefaultMaxVCPUs:        h.defaultMaxVCPUs(),
+		MemorySize:             h.defaultMemSz(),
+		DefaultMaxMemorySize:   h.DefaultMaxMemorySize,
+		BlockDeviceDriver:      blockDriver,
+		HugePages:              h.HugePages,
+		Debug:                  h.Debug,
+		GuestHookPath:          h.guestHookPath(),
+	}, nil
+}
+
 func updateRuntimeConfigHypervisor(configPath string, tomlConf tomlConfig, config *oci.RuntimeConfig) error {
 	for k, hypervisor := range tomlConf.Hypervisor {
 		var err error
@@ -584,6 +905,9 @@ func updateRuntimeConfigHypervisor(configPath string, tomlConf tomlConfig, confi
 		case qemuHypervisorTableType:
 			config.HypervisorType = vc.QemuHypervisor
 			hConfig, err = newQemuHypervisorConfig(hypervisor)
+		case remoteHypervisorTableType:
+			config.HypervisorType = vc.RemoteHypervisor
+			hConfig, err = newRemoteHypervisorConfig(hypervisor)
 		}
 
 		if err != nil {
@@ -625,16 +949,16 @@ func updateRuntimeConfigProxy(configPath string, tomlConf tomlConfig, config *oc
 
 func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oci.RuntimeConfig, builtIn bool) error {
 	if builtIn {
+		agentConfig := newKataAgentConfig(tomlConf.Agent[kataAgentTableType], config.HypervisorConfig.UseVSock)
+		agentConfig.LongLiveConn = true
+
 		config.AgentType = vc.KataContainersAgent
-		config.AgentConfig = vc.KataAgentConfig{
-			LongLiveConn: true,
-			UseVSock:     config.HypervisorConfig.UseVSock,
-		}
+		config.AgentConfig = agentConfig
 
 		return nil
 	}
 
-	for k := range tomlConf.Agent {
+	for k, agent := range tomlConf.Agent {
 		switch k {
 		case hyperstartAgentTableType:
 			config.AgentType = vc.HyperstartAgent
@@ -642,9 +966,7 @@ func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oc
 
 		case kataAgentTableType:
 			config.AgentType = vc.KataContainersAgent
-			config.AgentConfig = vc.KataAgentConfig{
-				UseVSock: config.HypervisorConfig.UseVSock,
-			}
+			config.AgentConfig = newKataAgentConfig(agent, config.HypervisorConfig.UseVSock)
 		}
 	}
 
@@ -682,6 +1004,10 @@ func updateRuntimeConfigShim(configPath string, tomlConf tomlConfig, config *oci
 func SetKernelParams(runtimeConfig *oci.RuntimeConfig) error {
 	defaultKernelParams := GetKernelParamsFunc(needSystemd(runtimeConfig.HypervisorConfig), runtimeConfig.Trace)
 
+	if agentConfig, ok := runtimeConfig.AgentConfig.(vc.KataAgentConfig); ok {
+		defaultKernelParams = append(defaultKernelParams, vc.KataAgentKernelParams(agentConfig)...)
+	}
+
 	if runtimeConfig.HypervisorConfig.Debug {
 		strParams := vc.SerializeParams(defaultKernelParams, "=")
 		formatted := strings.Join(strParams, " ")
@@ -854,6 +1180,36 @@ func LoadConfiguration(configPath string, ignoreLogging, builtIn bool) (resolved
 	}
 
 	config.DisableGuestSeccomp = tomlConf.Runtime.DisableGuestSeccomp
+	config.IdlePauseTimeout = tomlConf.Runtime.IdlePauseTimeout
+	config.KSM = vc.KSMConfig{
+		Throttle:           tomlConf.Runtime.KSMThrottling,
+		AggressiveDuration: tomlConf.Runtime.KSMAggressiveTime,
+		Start:              tomlConf.Runtime.KSMStart,
+	}
+	config.EnablePprof = tomlConf.Runtime.EnablePprof
+	config.MaxVMRestarts = tomlConf.Runtime.MaxVMRestarts
+	config.SlowOperationThreshold = tomlConf.Runtime.SlowOpThreshold
+	config.VolumeSourceTimeout = tomlConf.Runtime.VolumeSourceTimeout
+	config.Audit = vc.AuditConfig{
+		Path:   tomlConf.Runtime.AuditLog,
+		Auditd: tomlConf.Runtime.AuditAuditd,
+	}
+	config.HostHooks = vc.HostHooksConfig{
+		PreVMStart:       tomlConf.Runtime.PreVMStartHooks,
+		PostVMStart:      tomlConf.Runtime.PostVMStartHooks,
+		PreSandboxDelete: tomlConf.Runtime.PreDeleteHooks,
+		Timeout:          tomlConf.Runtime.HostHooksTimeout,
+		FailurePolicy:    vc.HostHookFailurePolicy(tomlConf.Runtime.HostHooksFailure),
+	}
+	config.PrivilegedWithoutHostDevices = tomlConf.Runtime.PrivilegedNoDevices
+	config.StrictOCISpec = tomlConf.Runtime.StrictOCISpec
+
+	for _, e := range tomlConf.Runtime.EnableAnnotations {
+		if _, err := regexp.Compile(e); err != nil {
+			return "", config, fmt.Errorf("Invalid enabled annotation %q: %v", e, err)
+		}
+	}
+	config.EnableAnnotations = tomlConf.Runtime.EnableAnnotations
 
 	// use no proxy if HypervisorConfig.UseVSock is true
 	if config.HypervisorConfig.UseVSock {
@@ -863,6 +1219,7 @@ func LoadConfiguration(configPath string, ignoreLogging, builtIn bool) (resolved
 	}
 
 	config.DisableNewNetNs = tomlConf.Runtime.DisableNewNetNs
+	config.HostNetworking = tomlConf.Runtime.HostNetworking
 	for _, f := range tomlConf.Runtime.Experimental {
 		feature := exp.Get(f)
 		if feature == nil {
@@ -914,6 +1271,18 @@ func checkConfig(config oci.RuntimeConfig) error {
 		return err
 	}
 
+	if err := checkHostNetworkingConfig(config); err != nil {
+		return err
+	}
+
+	if err := checkAuditConfig(config); err != nil {
+		return err
+	}
+
+	if err := checkHostHooksConfig(config); err != nil {
+		return err
+	}
+
 	if err := checkHypervisorConfig(config.HypervisorConfig); err != nil {
 		return err
 	}
@@ -945,12 +1314,72 @@ func checkNetNsConfig(config oci.RuntimeConfig) error {
 	return nil
 }
 
+// checkHostNetworkingConfig ensures the host networking policy is valid.
+func checkHostNetworkingConfig(config oci.RuntimeConfig) error {
+	switch config.HostNetworking {
+	case "", HostNetworkingReject, HostNetworkingIsolated:
+		return nil
+	}
+
+	return fmt.Errorf("Invalid host_networking policy %q, expecting %q or %q",
+		config.HostNetworking, HostNetworkingReject, HostNetworkingIsolated)
+}
+
+// checkAuditConfig ensures the audit log is an absolute path, the runtime
+// and the shims do not run from the same directory.
+func checkAuditConfig(config oci.RuntimeConfig) error {
+	if config.Audit.Path != "" && !filepath.IsAbs(config.Audit.Path) {
+		return fmt.Errorf("Audit log path %q must be absolute", config.Audit.Path)
+	}
+
+	if config.Audit.Auditd && config.Audit.Path == "" {
+		return errors.New("audit_auditd requires audit_log to be set")
+	}
+
+	return nil
+}
+
+// checkHostHooksConfig ensures the host hooks failure policy is known and
+// the hooks are absolute paths, not looked up in the PATH.
+func checkHostHooksConfig(config oci.RuntimeConfig) error {
+	hooks := config.HostHooks
+
+	switch hooks.FailurePolicy {
+	case "", vc.HostHookFailureAbort, vc.HostHookFailureIgnore:
+	default:
+		return fmt.Errorf("Invalid host_hooks_failure_policy %q, expecting %q or %q",
+			hooks.FailurePolicy, vc.HostHookFailureAbort, vc.HostHookFailureIgnore)
+	}
+
+	for _, list := range [][]string{hooks.PreVMStart, hooks.PostVMStart, hooks.PreSandboxDelete} {
+		for _, hook := range list {
+			if !filepath.IsAbs(hook) {
+				return fmt.Errorf("Host hook %q must be an absolute path", hook)
+			}
+		}
+	}
+
+	return nil
+}
+
 // checkFactoryConfig ensures the VM factory configuration is valid.
 func checkFactoryConfig(config oci.RuntimeConfig) error {
 	if config.FactoryConfig.Template && config.FactoryConfig.VMCacheNumber > 0 {
 		return errors.New("VM factory cannot work together with VM cache")
 	}
 
+	if config.FactoryConfig.VMPoolSize > 0 {
+		if config.FactoryConfig.Template || config.FactoryConfig.VMCacheNumber > 0 {
+			return errors.New("VM pool cannot work together with VM factory or VM cache")
+		}
+		if config.HypervisorType != vc.QemuHypervisor {
+			return errors.New("VM pool just support qemu")
+		}
+		if config.AgentType != vc.KataContainersAgent {
+			return errors.New("VM pool just support kata agent")
+		}
+	}
+
 	if config.FactoryConfig.Template {
 		if config.HypervisorConfig.InitrdPath == "" {
 			return errors.New("Factory option enable_template requires an initrd image")
@@ -998,6 +1427,11 @@ func checkHypervisorConfig(config vc.HypervisorConfig) error {
 		return errors.New("VM memory cannot be zero")
 	}
 
+	// The hooks directory is looked up by the agent, in the guest rootfs.
+	if config.GuestHookPath != "" && !filepath.IsAbs(config.GuestHookPath) {
+		return fmt.Errorf("guest_hook_path %q must be an absolute path of the guest rootfs", config.GuestHookPath)
+	}
+
 	mb := int64(1024 * 1024)
 
 	for _, image := range images {
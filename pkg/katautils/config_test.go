@@ -842,6 +842,88 @@ func TestNewQemuHypervisorConfig(t *testing.T) {
 	}
 }
 
+func TestHypervisorCPUPools(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	savedKubeletConfigPath := kubeletConfigPath
+	kubeletConfigPath = filepath.Join(tmpdir, "config.yaml")
+	defer func() {
+		kubeletConfigPath = savedKubeletConfigPath
+	}()
+
+	vcpus, housekeeping, err := hypervisor{}.cpuPools()
+	assert.NoError(err)
+	assert.Empty(vcpus)
+	assert.Empty(housekeeping)
+
+	vcpus, housekeeping, err = hypervisor{VCPUsCPUPool: "2-7", HousekeepingCPUs: "0,1"}.cpuPools()
+	assert.NoError(err)
+	assert.Equal("2-7", vcpus)
+	assert.Equal("0,1", housekeeping)
+
+	_, _, err = hypervisor{VCPUsCPUPool: "1-7", HousekeepingCPUs: "0,1"}.cpuPools()
+	assert.Error(err, "the pools overlap")
+
+	_, _, err = hypervisor{VCPUsCPUPool: "foo"}.cpuPools()
+	assert.Error(err)
+
+	h := hypervisor{HousekeepingCPUs: housekeepingCPUsKubelet}
+	_, _, err = h.cpuPools()
+	assert.Error(err, "no kubelet configuration")
+
+	err = WriteFile(kubeletConfigPath, "kind: KubeletConfiguration\nreservedSystemCPUs: \"0-1\"\n", testFileMode)
+	assert.NoError(err)
+	_, housekeeping, err = h.cpuPools()
+	assert.NoError(err)
+	assert.Equal("0-1", housekeeping)
+
+	err = WriteFile(kubeletConfigPath, "kind: KubeletConfiguration\n", testFileMode)
+	assert.NoError(err)
+	_, _, err = h.cpuPools()
+	assert.Error(err, "no reserved CPUs")
+}
+
+func TestNewRemoteHypervisorConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	kernelPath := path.Join(tmpdir, "kernel")
+	imagePath := path.Join(tmpdir, "image")
+
+	for _, file := range []string{kernelPath, imagePath} {
+		err = createEmptyFile(file)
+		assert.NoError(err)
+	}
+
+	hypervisor := hypervisor{
+		Kernel: kernelPath,
+		Image:  imagePath,
+	}
+
+	_, err = newRemoteHypervisorConfig(hypervisor)
+	assert.Error(err, "the driver socket is mandatory")
+
+	hypervisor.RemoteHypervisorSocket = "driver.sock"
+	_, err = newRemoteHypervisorConfig(hypervisor)
+	assert.Error(err, "the driver socket must be absolute")
+
+	hypervisor.RemoteHypervisorSocket = "/run/driver.sock"
+	config, err := newRemoteHypervisorConfig(hypervisor)
+	assert.NoError(err)
+
+	assert.Equal("/run/driver.sock", config.RemoteHypervisorSocket)
+	assert.Equal(kernelPath, config.KernelPath)
+	assert.Equal(imagePath, config.ImagePath)
+	assert.False(config.UseVSock)
+}
+
 func TestNewQemuHypervisorConfigImageAndInitrd(t *testing.T) {
 	assert := assert.New(t)
 
@@ -875,10 +957,13 @@ func TestNewQemuHypervisorConfigImageAndInitrd(t *testing.T) {
 		HotplugVFIOOnRootBus:  hotplugVFIOOnRootBus,
 	}
 
-	_, err = newQemuHypervisorConfig(hypervisor)
+	config, err := newQemuHypervisorConfig(hypervisor)
+	assert.NoError(err)
 
-	// specifying both an image+initrd is invalid
-	assert.Error(err)
+	// both are kept, the guest boot selects one of them per sandbox
+	assert.Equal(imagePath, config.ImagePath)
+	assert.Equal(initrdPath, config.InitrdPath)
+	assert.Equal("image", config.GuestBoot)
 }
 
 func TestNewShimConfig(t *testing.T) {
@@ -937,7 +1022,7 @@ func TestHypervisorDefaults(t *testing.T) {
 	h.NumVCPUs = 2
 	assert.Equal(h.defaultVCPUs(), uint32(2), "default vCPU number is wrong")
 
-	h.NumVCPUs = int32(numCPUs) + 1
+	h.NumVCPUs = vCPUs(numCPUs) + 1
 	assert.Equal(h.defaultVCPUs(), uint32(numCPUs), "default vCPU number is wrong")
 
 	h.DefaultMaxVCPUs = 2
@@ -1397,7 +1482,7 @@ func TestUpdateRuntimeConfigurationVMConfig(t *testing.T) {
 	tomlConf := tomlConfig{
 		Hypervisor: map[string]hypervisor{
 			qemuHypervisorTableType: {
-				NumVCPUs:   int32(vcpus),
+				NumVCPUs:   vCPUs(vcpus),
 				MemorySize: mem,
 				Path:       "/",
 				Kernel:     "/",
@@ -1559,6 +1644,16 @@ func TestCheckHypervisorConfig(t *testing.T) {
 		// reset logger
 		kataUtilsLogger.Logger.Out = savedOut
 	}
+
+	config := vc.HypervisorConfig{
+		ImagePath:     image,
+		MemorySize:    fileSizeMB + 2,
+		GuestHookPath: "/usr/share/oci/hooks",
+	}
+	assert.NoError(checkHypervisorConfig(config))
+
+	config.GuestHookPath = "usr/share/oci/hooks"
+	assert.Error(checkHypervisorConfig(config))
 }
 
 func TestCheckNetNsConfig(t *testing.T) {
@@ -1581,6 +1676,60 @@ func TestCheckNetNsConfig(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestCheckHostNetworkingConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, policy := range []string{"", HostNetworkingReject, HostNetworkingIsolated} {
+		err := checkHostNetworkingConfig(oci.RuntimeConfig{HostNetworking: policy})
+		assert.NoError(err, policy)
+	}
+
+	err := checkHostNetworkingConfig(oci.RuntimeConfig{HostNetworking: "host"})
+	assert.Error(err)
+}
+
+func TestCheckAuditConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, audit := range []vc.AuditConfig{
+		{},
+		{Path: "/var/log/kata-audit.log"},
+		{Path: "/var/log/kata-audit.log", Auditd: true},
+	} {
+		err := checkAuditConfig(oci.RuntimeConfig{Audit: audit})
+		assert.NoError(err, audit)
+	}
+
+	for _, audit := range []vc.AuditConfig{
+		{Path: "kata-audit.log"},
+		{Auditd: true},
+	} {
+		err := checkAuditConfig(oci.RuntimeConfig{Audit: audit})
+		assert.Error(err, audit)
+	}
+}
+
+func TestCheckHostHooksConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, hooks := range []vc.HostHooksConfig{
+		{},
+		{PreVMStart: []string{"/usr/bin/register"}, FailurePolicy: vc.HostHookFailureIgnore},
+		{PreSandboxDelete: []string{"/usr/bin/unregister"}, FailurePolicy: vc.HostHookFailureAbort},
+	} {
+		err := checkHostHooksConfig(oci.RuntimeConfig{HostHooks: hooks})
+		assert.NoError(err, hooks)
+	}
+
+	for _, hooks := range []vc.HostHooksConfig{
+		{FailurePolicy: "retry"},
+		{PostVMStart: []string{"register"}},
+	} {
+		err := checkHostHooksConfig(oci.RuntimeConfig{HostHooks: hooks})
+		assert.Error(err, hooks)
+	}
+}
+
 func TestCheckFactoryConfig(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1622,6 +1771,43 @@ func TestCheckFactoryConfig(t *testing.T) {
 	}
 }
 
+func TestCheckFactoryConfigVMPool(t *testing.T) {
+	assert := assert.New(t)
+
+	config := oci.RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		AgentType:      vc.KataContainersAgent,
+		HypervisorConfig: vc.HypervisorConfig{
+			InitrdPath: "initrd",
+		},
+		FactoryConfig: oci.FactoryConfig{
+			VMPoolSize: 2,
+		},
+	}
+
+	err := checkFactoryConfig(config)
+	assert.NoError(err)
+
+	config.FactoryConfig.VMCacheNumber = 1
+	err = checkFactoryConfig(config)
+	assert.Error(err)
+
+	config.FactoryConfig.VMCacheNumber = 0
+	config.FactoryConfig.Template = true
+	err = checkFactoryConfig(config)
+	assert.Error(err)
+
+	config.FactoryConfig.Template = false
+	config.HypervisorType = vc.FirecrackerHypervisor
+	err = checkFactoryConfig(config)
+	assert.Error(err)
+
+	config.HypervisorType = vc.QemuHypervisor
+	config.AgentType = vc.HyperstartAgent
+	err = checkFactoryConfig(config)
+	assert.Error(err)
+}
+
 func TestCheckNetNsConfigShimTrace(t *testing.T) {
 	assert := assert.New(t)
 
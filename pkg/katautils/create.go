@@ -113,19 +113,25 @@ func getKernelParams(needSystemd, trace bool) []vc.Param {
 	return p
 }
 
+// needSystemd is true as soon as an image is configured, even if sandboxes
+// may boot from an initrd too: the kernel ignores the systemd parameters
+// when the initrd provides its own init.
 func needSystemd(config vc.HypervisorConfig) bool {
 	return config.ImagePath != ""
 }
 
 // HandleFactory  set the factory
 func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeConfig) {
-	if !runtimeConfig.FactoryConfig.Template && runtimeConfig.FactoryConfig.VMCacheNumber == 0 {
+	// The VM pool server is reached through the same endpoint as VMCache.
+	vmCache := runtimeConfig.FactoryConfig.VMCacheNumber > 0 || runtimeConfig.FactoryConfig.VMPoolSize > 0
+
+	if !runtimeConfig.FactoryConfig.Template && !vmCache {
 		return
 	}
 
 	factoryConfig := vf.Config{
 		Template:        runtimeConfig.FactoryConfig.Template,
-		VMCache:         runtimeConfig.FactoryConfig.VMCacheNumber > 0,
+		VMCache:         vmCache,
 		VMCacheEndpoint: runtimeConfig.FactoryConfig.VMCacheEndpoint,
 		VMConfig: vc.VMConfig{
 			HypervisorType:   runtimeConfig.HypervisorType,
@@ -134,7 +140,7 @@ func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeCon
 			AgentConfig:      runtimeConfig.AgentConfig,
 		},
 	}
-	if runtimeConfig.FactoryConfig.VMCacheNumber > 0 {
+	if vmCache {
 		factoryConfig.VMConfig.ProxyType = runtimeConfig.ProxyType
 		factoryConfig.VMConfig.ProxyConfig = runtimeConfig.ProxyConfig
 	}
@@ -194,6 +200,10 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec oci.CompatOCISpec, ru
 		sandboxConfig.Containers[0].RootFs = rootFs
 	}
 
+	if err := applyHostNetworkingPolicy(ociSpec, runtimeConfig.HostNetworking, &sandboxConfig.NetworkConfig); err != nil {
+		return nil, vc.Process{}, err
+	}
+
 	// Important to create the network namespace before the sandbox is
 	// created, because it is not responsible for the creation of the
 	// netns if it does not exist.
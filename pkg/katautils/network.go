@@ -16,11 +16,29 @@ import (
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/sys/unix"
 )
 
 const procMountInfoFile = "/proc/self/mountinfo"
 
+const (
+	// HostNetworkingReject is the host networking policy failing the
+	// creation of the sandboxes requesting the host networking.
+	HostNetworkingReject = "reject"
+
+	// HostNetworkingIsolated is the host networking policy running the
+	// sandboxes requesting the host networking in a new network namespace,
+	// without any pod network. This is meant for the VM-only workloads
+	// bringing their own networking, eg. a nic passed through with VFIO.
+	HostNetworkingIsolated = "isolated"
+
+	// crioHostNetworkAnnotation is set to "true" by CRI-O for the
+	// hostNetwork pods.
+	crioHostNetworkAnnotation = "io.kubernetes.cri-o.HostNetwork"
+)
+
 // EnterNetNS is free from any call to a go routine, and it calls
 // into runtime.LockOSThread(), meaning it won't be executed in a
 // different thread than the one expected by the caller.
@@ -81,6 +99,65 @@ func SetupNetworkNamespace(config *vc.NetworkConfig) error {
 	return nil
 }
 
+// hostNetworkRequested checks if a sandbox requests the host networking. A
+// CRI sandbox without any network namespace in its spec, as created by
+// containerd for a hostNetwork pod, shares the host network namespace.
+func hostNetworkRequested(ociSpec oci.CompatOCISpec, netNSPath string) (bool, error) {
+	if ociSpec.Annotations[crioHostNetworkAnnotation] == "true" {
+		return true, nil
+	}
+
+	if netNSPath != "" {
+		return hostNetworkingRequested(netNSPath)
+	}
+
+	if ociSpec.Linux == nil || !isCRISandbox(ociSpec) {
+		return false, nil
+	}
+
+	for _, n := range ociSpec.Linux.Namespaces {
+		if n.Type == specs.NetworkNamespace {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func isCRISandbox(ociSpec oci.CompatOCISpec) bool {
+	for _, key := range oci.CRIContainerTypeKeyList {
+		if _, ok := ociSpec.Annotations[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyHostNetworkingPolicy applies the host networking policy to a sandbox
+// requesting the host networking, that a VM cannot be part of. The sandbox
+// creation fails early, with an explicit error, or the sandbox runs in a new
+// network namespace.
+func applyHostNetworkingPolicy(ociSpec oci.CompatOCISpec, policy string, config *vc.NetworkConfig) error {
+	if config.DisableNewNetNs {
+		return nil
+	}
+
+	requested, err := hostNetworkRequested(ociSpec, config.NetNSPath)
+	if err != nil || !requested {
+		return err
+	}
+
+	if policy != HostNetworkingIsolated {
+		return fmt.Errorf("Host networking requested, not supported by runtime: the sandbox VM cannot join the host network namespace. Use a pod network, or the %q host_networking policy to run the sandbox without any network", HostNetworkingIsolated)
+	}
+
+	kataUtilsLogger.Info("Host networking requested, running the sandbox in a new network namespace")
+	config.NetNSPath = ""
+
+	return nil
+}
+
 // getNetNsFromBindMount returns the network namespace for the bind-mounted path
 func getNetNsFromBindMount(nsPath string, procMountFile string) (string, error) {
 	netNsMountType := "nsfs"
@@ -15,6 +15,8 @@ import (
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/oci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/sys/unix"
 )
@@ -148,3 +150,71 @@ func TestSetupNetworkNamespace(t *testing.T) {
 	err = SetupNetworkNamespace(config)
 	assert.NoError(err)
 }
+
+func TestApplyHostNetworkingPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	criSandbox := oci.CompatOCISpec{
+		Spec: specs.Spec{
+			Annotations: map[string]string{
+				oci.CRIContainerTypeKeyList[0]: "sandbox",
+			},
+			Linux: &specs.Linux{},
+		},
+	}
+
+	// A CRI sandbox without network namespace shares the host one.
+	config := &vc.NetworkConfig{}
+	err := applyHostNetworkingPolicy(criSandbox, "", config)
+	assert.Error(err)
+
+	err = applyHostNetworkingPolicy(criSandbox, HostNetworkingReject, config)
+	assert.Error(err)
+
+	err = applyHostNetworkingPolicy(criSandbox, HostNetworkingIsolated, config)
+	assert.NoError(err)
+	assert.Empty(config.NetNSPath)
+
+	// The host networking shares the shim network namespace with
+	// disable_new_netns.
+	config = &vc.NetworkConfig{DisableNewNetNs: true}
+	err = applyHostNetworkingPolicy(criSandbox, HostNetworkingReject, config)
+	assert.NoError(err)
+
+	// A pod network namespace is requested.
+	criSandbox.Linux.Namespaces = []specs.LinuxNamespace{
+		{Type: specs.NetworkNamespace},
+	}
+	config = &vc.NetworkConfig{}
+	err = applyHostNetworkingPolicy(criSandbox, HostNetworkingReject, config)
+	assert.NoError(err)
+
+	// CRI-O hostNetwork pod
+	criSandbox.Annotations[crioHostNetworkAnnotation] = "true"
+	err = applyHostNetworkingPolicy(criSandbox, HostNetworkingReject, config)
+	assert.Error(err)
+
+	// Standalone container, sharing the host network namespace or not
+	// depending on the configured path only.
+	standalone := oci.CompatOCISpec{
+		Spec: specs.Spec{
+			Linux: &specs.Linux{},
+		},
+	}
+	err = applyHostNetworkingPolicy(standalone, HostNetworkingReject, config)
+	assert.NoError(err)
+
+	if os.Geteuid() != 0 {
+		return
+	}
+
+	config = &vc.NetworkConfig{
+		NetNSPath: "/proc/self/ns/net",
+	}
+	err = applyHostNetworkingPolicy(standalone, HostNetworkingReject, config)
+	assert.Error(err)
+
+	err = applyHostNetworkingPolicy(standalone, HostNetworkingIsolated, config)
+	assert.NoError(err)
+	assert.Empty(config.NetNSPath)
+}
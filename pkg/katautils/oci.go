@@ -90,3 +90,36 @@ func DelContainerIDMapping(ctx context.Context, containerID string) error {
 
 	return os.RemoveAll(path)
 }
+
+// DelSandboxIDMappings deletes the id mappings of all the containers of a
+// sandbox, eg. of a sandbox that cannot be fetched anymore.
+func DelSandboxIDMappings(ctx context.Context, sandboxID string) error {
+	span, _ := Trace(ctx, "delSandboxIDMappings")
+	defer span.Finish()
+
+	if sandboxID == "" {
+		return fmt.Errorf("Missing sandbox ID")
+	}
+
+	dirs, err := ioutil.ReadDir(ctrsMapTreePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(ctrsMapTreePath, dir.Name())
+		if _, err := os.Stat(filepath.Join(path, sandboxID)); err != nil {
+			continue
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -133,3 +133,26 @@ func TestDelContainerIDMappingSuccess(t *testing.T) {
 	_, err = os.Stat(filepath.Join(ctrsMapTreePath, testContainerID, testSandboxID))
 	assert.True(os.IsNotExist(err))
 }
+
+func TestDelSandboxIDMappings(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Error(DelSandboxIDMappings(context.Background(), ""))
+
+	path, err := createTempContainerIDMapping(testContainerID, testSandboxID)
+	assert.NoError(err)
+	defer os.RemoveAll(path)
+
+	assert.NoError(os.MkdirAll(filepath.Join(ctrsMapTreePath, testSandboxID, testSandboxID), 0750))
+	assert.NoError(os.MkdirAll(filepath.Join(ctrsMapTreePath, "other", "other-sandbox"), 0750))
+
+	assert.NoError(DelSandboxIDMappings(context.Background(), testSandboxID))
+
+	for _, id := range []string{testContainerID, testSandboxID} {
+		_, err = os.Stat(filepath.Join(ctrsMapTreePath, id))
+		assert.True(os.IsNotExist(err), id)
+	}
+
+	_, err = os.Stat(filepath.Join(ctrsMapTreePath, "other", "other-sandbox"))
+	assert.NoError(err)
+}
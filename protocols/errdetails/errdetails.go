@@ -0,0 +1,11 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package errdetails defines the details the shim attaches to the gRPC status
+// of its errors, generated from errdetails.proto.
+package errdetails
+
+// Domain is the domain of the error reasons defined by the runtime.
+const Domain = "katacontainers.io"
@@ -0,0 +1,5633 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: hypervisor.proto
+
+/*
+Package hypervisor is a generated protocol buffer package.
+
+It is generated from these files:
+
+	hypervisor.proto
+
+It has these top-level messages:
+
+	CapabilitiesRequest
+	CapabilitiesResponse
+	VMRequest
+	VMResponse
+	VMConfig
+	CreateVMRequest
+	StartVMRequest
+	StartVMResponse
+	BlockDevice
+	VFIODevice
+	NetDevice
+	SharedFS
+	SerialPort
+	MemoryDevice
+	Device
+	DeviceRequest
+	HotplugResponse
+	ResizeMemoryRequest
+	ResizeMemoryResponse
+	ResizeVCPUsRequest
+	ResizeVCPUsResponse
+	GetConsoleResponse
+	GetThreadIDsResponse
+*/
+package hypervisor
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type CapabilitiesRequest struct {
+}
+
+func (m *CapabilitiesRequest) Reset()                    { *m = CapabilitiesRequest{} }
+func (m *CapabilitiesRequest) String() string            { return proto.CompactTextString(m) }
+func (*CapabilitiesRequest) ProtoMessage()               {}
+func (*CapabilitiesRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{0} }
+
+type CapabilitiesResponse struct {
+	// version is the version of the protocol implemented by the driver.
+	Version            uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	BlockDevice        bool   `protobuf:"varint,2,opt,name=block_device,json=blockDevice,proto3" json:"block_device,omitempty"`
+	BlockDeviceHotplug bool   `protobuf:"varint,3,opt,name=block_device_hotplug,json=blockDeviceHotplug,proto3" json:"block_device_hotplug,omitempty"`
+	MultiQueue         bool   `protobuf:"varint,4,opt,name=multi_queue,json=multiQueue,proto3" json:"multi_queue,omitempty"`
+	FsSharing          bool   `protobuf:"varint,5,opt,name=fs_sharing,json=fsSharing,proto3" json:"fs_sharing,omitempty"`
+}
+
+func (m *CapabilitiesResponse) Reset()                    { *m = CapabilitiesResponse{} }
+func (m *CapabilitiesResponse) String() string            { return proto.CompactTextString(m) }
+func (*CapabilitiesResponse) ProtoMessage()               {}
+func (*CapabilitiesResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{1} }
+
+func (m *CapabilitiesResponse) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *CapabilitiesResponse) GetBlockDevice() bool {
+	if m != nil {
+		return m.BlockDevice
+	}
+	return false
+}
+
+func (m *CapabilitiesResponse) GetBlockDeviceHotplug() bool {
+	if m != nil {
+		return m.BlockDeviceHotplug
+	}
+	return false
+}
+
+func (m *CapabilitiesResponse) GetMultiQueue() bool {
+	if m != nil {
+		return m.MultiQueue
+	}
+	return false
+}
+
+func (m *CapabilitiesResponse) GetFsSharing() bool {
+	if m != nil {
+		return m.FsSharing
+	}
+	return false
+}
+
+type VMRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *VMRequest) Reset()                    { *m = VMRequest{} }
+func (m *VMRequest) String() string            { return proto.CompactTextString(m) }
+func (*VMRequest) ProtoMessage()               {}
+func (*VMRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{2} }
+
+func (m *VMRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type VMResponse struct {
+}
+
+func (m *VMResponse) Reset()                    { *m = VMResponse{} }
+func (m *VMResponse) String() string            { return proto.CompactTextString(m) }
+func (*VMResponse) ProtoMessage()               {}
+func (*VMResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{3} }
+
+type VMConfig struct {
+	Kernel       string `protobuf:"bytes,1,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	KernelParams string `protobuf:"bytes,2,opt,name=kernel_params,json=kernelParams,proto3" json:"kernel_params,omitempty"`
+	Image        string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Initrd       string `protobuf:"bytes,4,opt,name=initrd,proto3" json:"initrd,omitempty"`
+	Firmware     string `protobuf:"bytes,5,opt,name=firmware,proto3" json:"firmware,omitempty"`
+	Vcpus        uint32 `protobuf:"varint,6,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+	MaxVcpus     uint32 `protobuf:"varint,7,opt,name=max_vcpus,json=maxVcpus,proto3" json:"max_vcpus,omitempty"`
+	MemoryMb     uint32 `protobuf:"varint,8,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	MaxMemoryMb  uint32 `protobuf:"varint,9,opt,name=max_memory_mb,json=maxMemoryMb,proto3" json:"max_memory_mb,omitempty"`
+	// block_device_driver is "virtio-scsi", "virtio-blk" or
+	// "virtio-mmio".
+	BlockDeviceDriver string `protobuf:"bytes,10,opt,name=block_device_driver,json=blockDeviceDriver,proto3" json:"block_device_driver,omitempty"`
+	Hugepages         bool   `protobuf:"varint,11,opt,name=hugepages,proto3" json:"hugepages,omitempty"`
+	Debug             bool   `protobuf:"varint,12,opt,name=debug,proto3" json:"debug,omitempty"`
+}
+
+func (m *VMConfig) Reset()                    { *m = VMConfig{} }
+func (m *VMConfig) String() string            { return proto.CompactTextString(m) }
+func (*VMConfig) ProtoMessage()               {}
+func (*VMConfig) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{4} }
+
+func (m *VMConfig) GetKernel() string {
+	if m != nil {
+		return m.Kernel
+	}
+	return ""
+}
+
+func (m *VMConfig) GetKernelParams() string {
+	if m != nil {
+		return m.KernelParams
+	}
+	return ""
+}
+
+func (m *VMConfig) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *VMConfig) GetInitrd() string {
+	if m != nil {
+		return m.Initrd
+	}
+	return ""
+}
+
+func (m *VMConfig) GetFirmware() string {
+	if m != nil {
+		return m.Firmware
+	}
+	return ""
+}
+
+func (m *VMConfig) GetVcpus() uint32 {
+	if m != nil {
+		return m.Vcpus
+	}
+	return 0
+}
+
+func (m *VMConfig) GetMaxVcpus() uint32 {
+	if m != nil {
+		return m.MaxVcpus
+	}
+	return 0
+}
+
+func (m *VMConfig) GetMemoryMb() uint32 {
+	if m != nil {
+		return m.MemoryMb
+	}
+	return 0
+}
+
+func (m *VMConfig) GetMaxMemoryMb() uint32 {
+	if m != nil {
+		return m.MaxMemoryMb
+	}
+	return 0
+}
+
+func (m *VMConfig) GetBlockDeviceDriver() string {
+	if m != nil {
+		return m.BlockDeviceDriver
+	}
+	return ""
+}
+
+func (m *VMConfig) GetHugepages() bool {
+	if m != nil {
+		return m.Hugepages
+	}
+	return false
+}
+
+func (m *VMConfig) GetDebug() bool {
+	if m != nil {
+		return m.Debug
+	}
+	return false
+}
+
+type CreateVMRequest struct {
+	Id     string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Config *VMConfig `protobuf:"bytes,2,opt,name=config" json:"config,omitempty"`
+}
+
+func (m *CreateVMRequest) Reset()                    { *m = CreateVMRequest{} }
+func (m *CreateVMRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateVMRequest) ProtoMessage()               {}
+func (*CreateVMRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{5} }
+
+func (m *CreateVMRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CreateVMRequest) GetConfig() *VMConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type StartVMRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// timeout is the number of seconds the VM has to boot in.
+	Timeout uint32 `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// netns is the path of the network namespace the VM has to be
+	// started in. It is only valid during the call.
+	Netns string `protobuf:"bytes,3,opt,name=netns,proto3" json:"netns,omitempty"`
+}
+
+func (m *StartVMRequest) Reset()                    { *m = StartVMRequest{} }
+func (m *StartVMRequest) String() string            { return proto.CompactTextString(m) }
+func (*StartVMRequest) ProtoMessage()               {}
+func (*StartVMRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{6} }
+
+func (m *StartVMRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *StartVMRequest) GetTimeout() uint32 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *StartVMRequest) GetNetns() string {
+	if m != nil {
+		return m.Netns
+	}
+	return ""
+}
+
+type StartVMResponse struct {
+	// pid is the pid of the VMM process, 0 if there is none on the host.
+	Pid int64 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+}
+
+func (m *StartVMResponse) Reset()                    { *m = StartVMResponse{} }
+func (m *StartVMResponse) String() string            { return proto.CompactTextString(m) }
+func (*StartVMResponse) ProtoMessage()               {}
+func (*StartVMResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{7} }
+
+func (m *StartVMResponse) GetPid() int64 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+type BlockDevice struct {
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	File   string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Format string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Index  int64  `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (m *BlockDevice) Reset()                    { *m = BlockDevice{} }
+func (m *BlockDevice) String() string            { return proto.CompactTextString(m) }
+func (*BlockDevice) ProtoMessage()               {}
+func (*BlockDevice) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{8} }
+
+func (m *BlockDevice) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *BlockDevice) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *BlockDevice) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *BlockDevice) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type VFIODevice struct {
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bdf      string `protobuf:"bytes,2,opt,name=bdf,proto3" json:"bdf,omitempty"`
+	SysfsDev string `protobuf:"bytes,3,opt,name=sysfs_dev,json=sysfsDev,proto3" json:"sysfs_dev,omitempty"`
+}
+
+func (m *VFIODevice) Reset()                    { *m = VFIODevice{} }
+func (m *VFIODevice) String() string            { return proto.CompactTextString(m) }
+func (*VFIODevice) ProtoMessage()               {}
+func (*VFIODevice) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{9} }
+
+func (m *VFIODevice) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *VFIODevice) GetBdf() string {
+	if m != nil {
+		return m.Bdf
+	}
+	return ""
+}
+
+func (m *VFIODevice) GetSysfsDev() string {
+	if m != nil {
+		return m.SysfsDev
+	}
+	return ""
+}
+
+// NetDevice is the TAP interface of a network endpoint, created by the
+// runtime in the network namespace of the DeviceRequest.
+type NetDevice struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	HwAddr  string `protobuf:"bytes,2,opt,name=hw_addr,json=hwAddr,proto3" json:"hw_addr,omitempty"`
+	TapName string `protobuf:"bytes,3,opt,name=tap_name,json=tapName,proto3" json:"tap_name,omitempty"`
+	Type    string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (m *NetDevice) Reset()                    { *m = NetDevice{} }
+func (m *NetDevice) String() string            { return proto.CompactTextString(m) }
+func (*NetDevice) ProtoMessage()               {}
+func (*NetDevice) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{10} }
+
+func (m *NetDevice) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NetDevice) GetHwAddr() string {
+	if m != nil {
+		return m.HwAddr
+	}
+	return ""
+}
+
+func (m *NetDevice) GetTapName() string {
+	if m != nil {
+		return m.TapName
+	}
+	return ""
+}
+
+func (m *NetDevice) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+type SharedFS struct {
+	MountTag string `protobuf:"bytes,1,opt,name=mount_tag,json=mountTag,proto3" json:"mount_tag,omitempty"`
+	HostPath string `protobuf:"bytes,2,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+}
+
+func (m *SharedFS) Reset()                    { *m = SharedFS{} }
+func (m *SharedFS) String() string            { return proto.CompactTextString(m) }
+func (*SharedFS) ProtoMessage()               {}
+func (*SharedFS) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{11} }
+
+func (m *SharedFS) GetMountTag() string {
+	if m != nil {
+		return m.MountTag
+	}
+	return ""
+}
+
+func (m *SharedFS) GetHostPath() string {
+	if m != nil {
+		return m.HostPath
+	}
+	return ""
+}
+
+type SerialPort struct {
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	HostPath string `protobuf:"bytes,3,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+}
+
+func (m *SerialPort) Reset()                    { *m = SerialPort{} }
+func (m *SerialPort) String() string            { return proto.CompactTextString(m) }
+func (*SerialPort) ProtoMessage()               {}
+func (*SerialPort) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{12} }
+
+func (m *SerialPort) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SerialPort) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SerialPort) GetHostPath() string {
+	if m != nil {
+		return m.HostPath
+	}
+	return ""
+}
+
+type MemoryDevice struct {
+	Slot   int64 `protobuf:"varint,1,opt,name=slot,proto3" json:"slot,omitempty"`
+	SizeMb int64 `protobuf:"varint,2,opt,name=size_mb,json=sizeMb,proto3" json:"size_mb,omitempty"`
+}
+
+func (m *MemoryDevice) Reset()                    { *m = MemoryDevice{} }
+func (m *MemoryDevice) String() string            { return proto.CompactTextString(m) }
+func (*MemoryDevice) ProtoMessage()               {}
+func (*MemoryDevice) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{13} }
+
+func (m *MemoryDevice) GetSlot() int64 {
+	if m != nil {
+		return m.Slot
+	}
+	return 0
+}
+
+func (m *MemoryDevice) GetSizeMb() int64 {
+	if m != nil {
+		return m.SizeMb
+	}
+	return 0
+}
+
+// Device describes a single device, only one of the fields is set.
+type Device struct {
+	Block  *BlockDevice  `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+	Vfio   *VFIODevice   `protobuf:"bytes,2,opt,name=vfio" json:"vfio,omitempty"`
+	Net    *NetDevice    `protobuf:"bytes,3,opt,name=net" json:"net,omitempty"`
+	Fs     *SharedFS     `protobuf:"bytes,4,opt,name=fs" json:"fs,omitempty"`
+	Serial *SerialPort   `protobuf:"bytes,5,opt,name=serial" json:"serial,omitempty"`
+	Memory *MemoryDevice `protobuf:"bytes,7,opt,name=memory" json:"memory,omitempty"`
+	// vcpus is the number of vCPUs to add or remove.
+	Vcpus uint32 `protobuf:"varint,8,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+}
+
+func (m *Device) Reset()                    { *m = Device{} }
+func (m *Device) String() string            { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()               {}
+func (*Device) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{14} }
+
+func (m *Device) GetBlock() *BlockDevice {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *Device) GetVfio() *VFIODevice {
+	if m != nil {
+		return m.Vfio
+	}
+	return nil
+}
+
+func (m *Device) GetNet() *NetDevice {
+	if m != nil {
+		return m.Net
+	}
+	return nil
+}
+
+func (m *Device) GetFs() *SharedFS {
+	if m != nil {
+		return m.Fs
+	}
+	return nil
+}
+
+func (m *Device) GetSerial() *SerialPort {
+	if m != nil {
+		return m.Serial
+	}
+	return nil
+}
+
+func (m *Device) GetMemory() *MemoryDevice {
+	if m != nil {
+		return m.Memory
+	}
+	return nil
+}
+
+func (m *Device) GetVcpus() uint32 {
+	if m != nil {
+		return m.Vcpus
+	}
+	return 0
+}
+
+type DeviceRequest struct {
+	Id     string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Device *Device `protobuf:"bytes,2,opt,name=device" json:"device,omitempty"`
+	// netns is the path of the network namespace of the network devices.
+	// It is only valid during the call.
+	Netns string `protobuf:"bytes,3,opt,name=netns,proto3" json:"netns,omitempty"`
+}
+
+func (m *DeviceRequest) Reset()                    { *m = DeviceRequest{} }
+func (m *DeviceRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeviceRequest) ProtoMessage()               {}
+func (*DeviceRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{15} }
+
+func (m *DeviceRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeviceRequest) GetDevice() *Device {
+	if m != nil {
+		return m.Device
+	}
+	return nil
+}
+
+func (m *DeviceRequest) GetNetns() string {
+	if m != nil {
+		return m.Netns
+	}
+	return ""
+}
+
+type HotplugResponse struct {
+	// vcpus is the number of vCPUs added or removed.
+	Vcpus uint32 `protobuf:"varint,1,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+	// memory_mb is the size of the memory added.
+	MemoryMb int64 `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+}
+
+func (m *HotplugResponse) Reset()                    { *m = HotplugResponse{} }
+func (m *HotplugResponse) String() string            { return proto.CompactTextString(m) }
+func (*HotplugResponse) ProtoMessage()               {}
+func (*HotplugResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{16} }
+
+func (m *HotplugResponse) GetVcpus() uint32 {
+	if m != nil {
+		return m.Vcpus
+	}
+	return 0
+}
+
+func (m *HotplugResponse) GetMemoryMb() int64 {
+	if m != nil {
+		return m.MemoryMb
+	}
+	return 0
+}
+
+type ResizeMemoryRequest struct {
+	Id                string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MemoryMb          uint32 `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	MemoryBlockSizeMb uint32 `protobuf:"varint,3,opt,name=memory_block_size_mb,json=memoryBlockSizeMb,proto3" json:"memory_block_size_mb,omitempty"`
+}
+
+func (m *ResizeMemoryRequest) Reset()                    { *m = ResizeMemoryRequest{} }
+func (m *ResizeMemoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResizeMemoryRequest) ProtoMessage()               {}
+func (*ResizeMemoryRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{17} }
+
+func (m *ResizeMemoryRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ResizeMemoryRequest) GetMemoryMb() uint32 {
+	if m != nil {
+		return m.MemoryMb
+	}
+	return 0
+}
+
+func (m *ResizeMemoryRequest) GetMemoryBlockSizeMb() uint32 {
+	if m != nil {
+		return m.MemoryBlockSizeMb
+	}
+	return 0
+}
+
+type ResizeMemoryResponse struct {
+	MemoryMb uint32 `protobuf:"varint,1,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+}
+
+func (m *ResizeMemoryResponse) Reset()                    { *m = ResizeMemoryResponse{} }
+func (m *ResizeMemoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResizeMemoryResponse) ProtoMessage()               {}
+func (*ResizeMemoryResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{18} }
+
+func (m *ResizeMemoryResponse) GetMemoryMb() uint32 {
+	if m != nil {
+		return m.MemoryMb
+	}
+	return 0
+}
+
+type ResizeVCPUsRequest struct {
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vcpus uint32 `protobuf:"varint,2,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+}
+
+func (m *ResizeVCPUsRequest) Reset()                    { *m = ResizeVCPUsRequest{} }
+func (m *ResizeVCPUsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResizeVCPUsRequest) ProtoMessage()               {}
+func (*ResizeVCPUsRequest) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{19} }
+
+func (m *ResizeVCPUsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ResizeVCPUsRequest) GetVcpus() uint32 {
+	if m != nil {
+		return m.Vcpus
+	}
+	return 0
+}
+
+type ResizeVCPUsResponse struct {
+	OldVcpus uint32 `protobuf:"varint,1,opt,name=old_vcpus,json=oldVcpus,proto3" json:"old_vcpus,omitempty"`
+	NewVcpus uint32 `protobuf:"varint,2,opt,name=new_vcpus,json=newVcpus,proto3" json:"new_vcpus,omitempty"`
+}
+
+func (m *ResizeVCPUsResponse) Reset()                    { *m = ResizeVCPUsResponse{} }
+func (m *ResizeVCPUsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResizeVCPUsResponse) ProtoMessage()               {}
+func (*ResizeVCPUsResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{20} }
+
+func (m *ResizeVCPUsResponse) GetOldVcpus() uint32 {
+	if m != nil {
+		return m.OldVcpus
+	}
+	return 0
+}
+
+func (m *ResizeVCPUsResponse) GetNewVcpus() uint32 {
+	if m != nil {
+		return m.NewVcpus
+	}
+	return 0
+}
+
+type GetConsoleResponse struct {
+	// url is the console of the VM, eg. "unix:///run/vc/vm/<id>/console.sock".
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+func (m *GetConsoleResponse) Reset()                    { *m = GetConsoleResponse{} }
+func (m *GetConsoleResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetConsoleResponse) ProtoMessage()               {}
+func (*GetConsoleResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{21} }
+
+func (m *GetConsoleResponse) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+type GetThreadIDsResponse struct {
+	// vcpus maps the vCPU indexes to the host thread IDs running them.
+	Vcpus map[uint32]int64 `protobuf:"bytes,1,rep,name=vcpus" json:"vcpus,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (m *GetThreadIDsResponse) Reset()                    { *m = GetThreadIDsResponse{} }
+func (m *GetThreadIDsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetThreadIDsResponse) ProtoMessage()               {}
+func (*GetThreadIDsResponse) Descriptor() ([]byte, []int) { return fileDescriptorHypervisor, []int{22} }
+
+func (m *GetThreadIDsResponse) GetVcpus() map[uint32]int64 {
+	if m != nil {
+		return m.Vcpus
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*CapabilitiesRequest)(nil), "hypervisor.CapabilitiesRequest")
+	proto.RegisterType((*CapabilitiesResponse)(nil), "hypervisor.CapabilitiesResponse")
+	proto.RegisterType((*VMRequest)(nil), "hypervisor.VMRequest")
+	proto.RegisterType((*VMResponse)(nil), "hypervisor.VMResponse")
+	proto.RegisterType((*VMConfig)(nil), "hypervisor.VMConfig")
+	proto.RegisterType((*CreateVMRequest)(nil), "hypervisor.CreateVMRequest")
+	proto.RegisterType((*StartVMRequest)(nil), "hypervisor.StartVMRequest")
+	proto.RegisterType((*StartVMResponse)(nil), "hypervisor.StartVMResponse")
+	proto.RegisterType((*BlockDevice)(nil), "hypervisor.BlockDevice")
+	proto.RegisterType((*VFIODevice)(nil), "hypervisor.VFIODevice")
+	proto.RegisterType((*NetDevice)(nil), "hypervisor.NetDevice")
+	proto.RegisterType((*SharedFS)(nil), "hypervisor.SharedFS")
+	proto.RegisterType((*SerialPort)(nil), "hypervisor.SerialPort")
+	proto.RegisterType((*MemoryDevice)(nil), "hypervisor.MemoryDevice")
+	proto.RegisterType((*Device)(nil), "hypervisor.Device")
+	proto.RegisterType((*DeviceRequest)(nil), "hypervisor.DeviceRequest")
+	proto.RegisterType((*HotplugResponse)(nil), "hypervisor.HotplugResponse")
+	proto.RegisterType((*ResizeMemoryRequest)(nil), "hypervisor.ResizeMemoryRequest")
+	proto.RegisterType((*ResizeMemoryResponse)(nil), "hypervisor.ResizeMemoryResponse")
+	proto.RegisterType((*ResizeVCPUsRequest)(nil), "hypervisor.ResizeVCPUsRequest")
+	proto.RegisterType((*ResizeVCPUsResponse)(nil), "hypervisor.ResizeVCPUsResponse")
+	proto.RegisterType((*GetConsoleResponse)(nil), "hypervisor.GetConsoleResponse")
+	proto.RegisterType((*GetThreadIDsResponse)(nil), "hypervisor.GetThreadIDsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for HypervisorDriver service
+
+type HypervisorDriverClient interface {
+	// Capabilities is called first, before CreateVM.
+	Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	// CreateVM prepares the VM, it is only started by StartVM. A VM
+	// created by a previous runtime process must be accepted again.
+	CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	StartVM(ctx context.Context, in *StartVMRequest, opts ...grpc.CallOption) (*StartVMResponse, error)
+	StopVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	PauseVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	ResumeVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	SaveVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	// AddDevice adds a device to a VM not started yet.
+	AddDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*VMResponse, error)
+	HotplugAddDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*HotplugResponse, error)
+	HotplugRemoveDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*HotplugResponse, error)
+	ResizeMemory(ctx context.Context, in *ResizeMemoryRequest, opts ...grpc.CallOption) (*ResizeMemoryResponse, error)
+	ResizeVCPUs(ctx context.Context, in *ResizeVCPUsRequest, opts ...grpc.CallOption) (*ResizeVCPUsResponse, error)
+	GetConsole(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*GetConsoleResponse, error)
+	GetThreadIDs(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*GetThreadIDsResponse, error)
+	// Cleanup releases what the driver holds for a stopped VM.
+	Cleanup(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error)
+}
+
+type hypervisorDriverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHypervisorDriverClient(cc *grpc.ClientConn) HypervisorDriverClient {
+	return &hypervisorDriverClient{cc}
+}
+
+func (c *hypervisorDriverClient) Capabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	out := new(CapabilitiesResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/Capabilities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) CreateVM(ctx context.Context, in *CreateVMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/CreateVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) StartVM(ctx context.Context, in *StartVMRequest, opts ...grpc.CallOption) (*StartVMResponse, error) {
+	out := new(StartVMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/StartVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) StopVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/StopVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) PauseVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/PauseVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) ResumeVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/ResumeVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) SaveVM(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/SaveVM", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) AddDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/AddDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) HotplugAddDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*HotplugResponse, error) {
+	out := new(HotplugResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/HotplugAddDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) HotplugRemoveDevice(ctx context.Context, in *DeviceRequest, opts ...grpc.CallOption) (*HotplugResponse, error) {
+	out := new(HotplugResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/HotplugRemoveDevice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) ResizeMemory(ctx context.Context, in *ResizeMemoryRequest, opts ...grpc.CallOption) (*ResizeMemoryResponse, error) {
+	out := new(ResizeMemoryResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/ResizeMemory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) ResizeVCPUs(ctx context.Context, in *ResizeVCPUsRequest, opts ...grpc.CallOption) (*ResizeVCPUsResponse, error) {
+	out := new(ResizeVCPUsResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/ResizeVCPUs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) GetConsole(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*GetConsoleResponse, error) {
+	out := new(GetConsoleResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/GetConsole", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) GetThreadIDs(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*GetThreadIDsResponse, error) {
+	out := new(GetThreadIDsResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/GetThreadIDs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hypervisorDriverClient) Cleanup(ctx context.Context, in *VMRequest, opts ...grpc.CallOption) (*VMResponse, error) {
+	out := new(VMResponse)
+	err := grpc.Invoke(ctx, "/hypervisor.HypervisorDriver/Cleanup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for HypervisorDriver service
+
+type HypervisorDriverServer interface {
+	// Capabilities is called first, before CreateVM.
+	Capabilities(context.Context, *CapabilitiesRequest) (*CapabilitiesResponse, error)
+	// CreateVM prepares the VM, it is only started by StartVM. A VM
+	// created by a previous runtime process must be accepted again.
+	CreateVM(context.Context, *CreateVMRequest) (*VMResponse, error)
+	StartVM(context.Context, *StartVMRequest) (*StartVMResponse, error)
+	StopVM(context.Context, *VMRequest) (*VMResponse, error)
+	PauseVM(context.Context, *VMRequest) (*VMResponse, error)
+	ResumeVM(context.Context, *VMRequest) (*VMResponse, error)
+	SaveVM(context.Context, *VMRequest) (*VMResponse, error)
+	// AddDevice adds a device to a VM not started yet.
+	AddDevice(context.Context, *DeviceRequest) (*VMResponse, error)
+	HotplugAddDevice(context.Context, *DeviceRequest) (*HotplugResponse, error)
+	HotplugRemoveDevice(context.Context, *DeviceRequest) (*HotplugResponse, error)
+	ResizeMemory(context.Context, *ResizeMemoryRequest) (*ResizeMemoryResponse, error)
+	ResizeVCPUs(context.Context, *ResizeVCPUsRequest) (*ResizeVCPUsResponse, error)
+	GetConsole(context.Context, *VMRequest) (*GetConsoleResponse, error)
+	GetThreadIDs(context.Context, *VMRequest) (*GetThreadIDsResponse, error)
+	// Cleanup releases what the driver holds for a stopped VM.
+	Cleanup(context.Context, *VMRequest) (*VMResponse, error)
+}
+
+func RegisterHypervisorDriverServer(s *grpc.Server, srv HypervisorDriverServer) {
+	s.RegisterService(&_HypervisorDriver_serviceDesc, srv)
+}
+
+func _HypervisorDriver_Capabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).Capabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/Capabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).Capabilities(ctx, req.(*CapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_CreateVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).CreateVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/CreateVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).CreateVM(ctx, req.(*CreateVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_StartVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartVMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).StartVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/StartVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).StartVM(ctx, req.(*StartVMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_StopVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).StopVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/StopVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).StopVM(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_PauseVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).PauseVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/PauseVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).PauseVM(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_ResumeVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).ResumeVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/ResumeVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).ResumeVM(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_SaveVM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).SaveVM(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/SaveVM",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).SaveVM(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_AddDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).AddDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/AddDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).AddDevice(ctx, req.(*DeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_HotplugAddDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).HotplugAddDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/HotplugAddDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).HotplugAddDevice(ctx, req.(*DeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_HotplugRemoveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).HotplugRemoveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/HotplugRemoveDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).HotplugRemoveDevice(ctx, req.(*DeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_ResizeMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).ResizeMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/ResizeMemory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).ResizeMemory(ctx, req.(*ResizeMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_ResizeVCPUs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeVCPUsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).ResizeVCPUs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/ResizeVCPUs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).ResizeVCPUs(ctx, req.(*ResizeVCPUsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_GetConsole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).GetConsole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/GetConsole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).GetConsole(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_GetThreadIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).GetThreadIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/GetThreadIDs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).GetThreadIDs(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HypervisorDriver_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VMRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HypervisorDriverServer).Cleanup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hypervisor.HypervisorDriver/Cleanup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HypervisorDriverServer).Cleanup(ctx, req.(*VMRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HypervisorDriver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hypervisor.HypervisorDriver",
+	HandlerType: (*HypervisorDriverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Capabilities",
+			Handler:    _HypervisorDriver_Capabilities_Handler,
+		},
+		{
+			MethodName: "CreateVM",
+			Handler:    _HypervisorDriver_CreateVM_Handler,
+		},
+		{
+			MethodName: "StartVM",
+			Handler:    _HypervisorDriver_StartVM_Handler,
+		},
+		{
+			MethodName: "StopVM",
+			Handler:    _HypervisorDriver_StopVM_Handler,
+		},
+		{
+			MethodName: "PauseVM",
+			Handler:    _HypervisorDriver_PauseVM_Handler,
+		},
+		{
+			MethodName: "ResumeVM",
+			Handler:    _HypervisorDriver_ResumeVM_Handler,
+		},
+		{
+			MethodName: "SaveVM",
+			Handler:    _HypervisorDriver_SaveVM_Handler,
+		},
+		{
+			MethodName: "AddDevice",
+			Handler:    _HypervisorDriver_AddDevice_Handler,
+		},
+		{
+			MethodName: "HotplugAddDevice",
+			Handler:    _HypervisorDriver_HotplugAddDevice_Handler,
+		},
+		{
+			MethodName: "HotplugRemoveDevice",
+			Handler:    _HypervisorDriver_HotplugRemoveDevice_Handler,
+		},
+		{
+			MethodName: "ResizeMemory",
+			Handler:    _HypervisorDriver_ResizeMemory_Handler,
+		},
+		{
+			MethodName: "ResizeVCPUs",
+			Handler:    _HypervisorDriver_ResizeVCPUs_Handler,
+		},
+		{
+			MethodName: "GetConsole",
+			Handler:    _HypervisorDriver_GetConsole_Handler,
+		},
+		{
+			MethodName: "GetThreadIDs",
+			Handler:    _HypervisorDriver_GetThreadIDs_Handler,
+		},
+		{
+			MethodName: "Cleanup",
+			Handler:    _HypervisorDriver_Cleanup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hypervisor.proto",
+}
+
+func (m *CapabilitiesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CapabilitiesRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *CapabilitiesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CapabilitiesResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Version))
+	}
+	if m.BlockDevice {
+		dAtA[i] = 0x10
+		i++
+		if m.BlockDevice {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.BlockDeviceHotplug {
+		dAtA[i] = 0x18
+		i++
+		if m.BlockDeviceHotplug {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MultiQueue {
+		dAtA[i] = 0x20
+		i++
+		if m.MultiQueue {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.FsSharing {
+		dAtA[i] = 0x28
+		i++
+		if m.FsSharing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *VMRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VMRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	return i, nil
+}
+
+func (m *VMResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VMResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *VMConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VMConfig) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Kernel) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Kernel)))
+		i += copy(dAtA[i:], m.Kernel)
+	}
+	if len(m.KernelParams) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.KernelParams)))
+		i += copy(dAtA[i:], m.KernelParams)
+	}
+	if len(m.Image) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Image)))
+		i += copy(dAtA[i:], m.Image)
+	}
+	if len(m.Initrd) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Initrd)))
+		i += copy(dAtA[i:], m.Initrd)
+	}
+	if len(m.Firmware) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Firmware)))
+		i += copy(dAtA[i:], m.Firmware)
+	}
+	if m.Vcpus != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Vcpus))
+	}
+	if m.MaxVcpus != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MaxVcpus))
+	}
+	if m.MemoryMb != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MemoryMb))
+	}
+	if m.MaxMemoryMb != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MaxMemoryMb))
+	}
+	if len(m.BlockDeviceDriver) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.BlockDeviceDriver)))
+		i += copy(dAtA[i:], m.BlockDeviceDriver)
+	}
+	if m.Hugepages {
+		dAtA[i] = 0x58
+		i++
+		if m.Hugepages {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Debug {
+		dAtA[i] = 0x60
+		i++
+		if m.Debug {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *CreateVMRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateVMRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Config != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Config.Size()))
+		n1, err := m.Config.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *StartVMRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StartVMRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Timeout))
+	}
+	if len(m.Netns) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Netns)))
+		i += copy(dAtA[i:], m.Netns)
+	}
+	return i, nil
+}
+
+func (m *StartVMResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StartVMResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Pid != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Pid))
+	}
+	return i, nil
+}
+
+func (m *BlockDevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockDevice) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.File) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.File)))
+		i += copy(dAtA[i:], m.File)
+	}
+	if len(m.Format) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Format)))
+		i += copy(dAtA[i:], m.Format)
+	}
+	if m.Index != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Index))
+	}
+	return i, nil
+}
+
+func (m *VFIODevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VFIODevice) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Bdf) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Bdf)))
+		i += copy(dAtA[i:], m.Bdf)
+	}
+	if len(m.SysfsDev) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.SysfsDev)))
+		i += copy(dAtA[i:], m.SysfsDev)
+	}
+	return i, nil
+}
+
+func (m *NetDevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetDevice) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.HwAddr) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.HwAddr)))
+		i += copy(dAtA[i:], m.HwAddr)
+	}
+	if len(m.TapName) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.TapName)))
+		i += copy(dAtA[i:], m.TapName)
+	}
+	if len(m.Type) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Type)))
+		i += copy(dAtA[i:], m.Type)
+	}
+	return i, nil
+}
+
+func (m *SharedFS) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SharedFS) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.MountTag) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.MountTag)))
+		i += copy(dAtA[i:], m.MountTag)
+	}
+	if len(m.HostPath) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.HostPath)))
+		i += copy(dAtA[i:], m.HostPath)
+	}
+	return i, nil
+}
+
+func (m *SerialPort) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SerialPort) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Name) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.HostPath) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.HostPath)))
+		i += copy(dAtA[i:], m.HostPath)
+	}
+	return i, nil
+}
+
+func (m *MemoryDevice) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MemoryDevice) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Slot != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Slot))
+	}
+	if m.SizeMb != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.SizeMb))
+	}
+	return i, nil
+}
+
+func (m *Device) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Device) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Block != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Block.Size()))
+		n2, err := m.Block.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Vfio != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Vfio.Size()))
+		n3, err := m.Vfio.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Net != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Net.Size()))
+		n4, err := m.Net.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Fs != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Fs.Size()))
+		n5, err := m.Fs.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if m.Serial != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Serial.Size()))
+		n6, err := m.Serial.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if m.Memory != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Memory.Size()))
+		n7, err := m.Memory.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if m.Vcpus != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Vcpus))
+	}
+	return i, nil
+}
+
+func (m *DeviceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeviceRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Device != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Device.Size()))
+		n8, err := m.Device.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if len(m.Netns) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Netns)))
+		i += copy(dAtA[i:], m.Netns)
+	}
+	return i, nil
+}
+
+func (m *HotplugResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HotplugResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Vcpus != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Vcpus))
+	}
+	if m.MemoryMb != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MemoryMb))
+	}
+	return i, nil
+}
+
+func (m *ResizeMemoryRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResizeMemoryRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.MemoryMb != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MemoryMb))
+	}
+	if m.MemoryBlockSizeMb != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MemoryBlockSizeMb))
+	}
+	return i, nil
+}
+
+func (m *ResizeMemoryResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResizeMemoryResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MemoryMb != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.MemoryMb))
+	}
+	return i, nil
+}
+
+func (m *ResizeVCPUsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResizeVCPUsRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Vcpus != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.Vcpus))
+	}
+	return i, nil
+}
+
+func (m *ResizeVCPUsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResizeVCPUsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.OldVcpus != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.OldVcpus))
+	}
+	if m.NewVcpus != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(m.NewVcpus))
+	}
+	return i, nil
+}
+
+func (m *GetConsoleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetConsoleResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Url) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintHypervisor(dAtA, i, uint64(len(m.Url)))
+		i += copy(dAtA[i:], m.Url)
+	}
+	return i, nil
+}
+
+func (m *GetThreadIDsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetThreadIDsResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Vcpus) > 0 {
+		for k, _ := range m.Vcpus {
+			dAtA[i] = 0xa
+			i++
+			v := m.Vcpus[k]
+			mapSize := 1 + sovHypervisor(uint64(k)) + 1 + sovHypervisor(uint64(v))
+			i = encodeVarintHypervisor(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0x8
+			i++
+			i = encodeVarintHypervisor(dAtA, i, uint64(k))
+			dAtA[i] = 0x10
+			i++
+			i = encodeVarintHypervisor(dAtA, i, uint64(v))
+		}
+	}
+	return i, nil
+}
+
+func encodeVarintHypervisor(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *CapabilitiesRequest) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *CapabilitiesResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Version != 0 {
+		n += 1 + sovHypervisor(uint64(m.Version))
+	}
+	if m.BlockDevice {
+		n += 2
+	}
+	if m.BlockDeviceHotplug {
+		n += 2
+	}
+	if m.MultiQueue {
+		n += 2
+	}
+	if m.FsSharing {
+		n += 2
+	}
+	return n
+}
+
+func (m *VMRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *VMResponse) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *VMConfig) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Kernel)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.KernelParams)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Image)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Initrd)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Firmware)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Vcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.Vcpus))
+	}
+	if m.MaxVcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.MaxVcpus))
+	}
+	if m.MemoryMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MemoryMb))
+	}
+	if m.MaxMemoryMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MaxMemoryMb))
+	}
+	l = len(m.BlockDeviceDriver)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Hugepages {
+		n += 2
+	}
+	if m.Debug {
+		n += 2
+	}
+	return n
+}
+
+func (m *CreateVMRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Config != nil {
+		l = m.Config.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *StartVMRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovHypervisor(uint64(m.Timeout))
+	}
+	l = len(m.Netns)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *StartVMResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Pid != 0 {
+		n += 1 + sovHypervisor(uint64(m.Pid))
+	}
+	return n
+}
+
+func (m *BlockDevice) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Format)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Index != 0 {
+		n += 1 + sovHypervisor(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *VFIODevice) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Bdf)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.SysfsDev)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *NetDevice) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.HwAddr)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.TapName)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *SharedFS) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.MountTag)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.HostPath)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *SerialPort) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.HostPath)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *MemoryDevice) Size() (n int) {
+	var l int
+	_ = l
+	if m.Slot != 0 {
+		n += 1 + sovHypervisor(uint64(m.Slot))
+	}
+	if m.SizeMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.SizeMb))
+	}
+	return n
+}
+
+func (m *Device) Size() (n int) {
+	var l int
+	_ = l
+	if m.Block != nil {
+		l = m.Block.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Vfio != nil {
+		l = m.Vfio.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Net != nil {
+		l = m.Net.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Fs != nil {
+		l = m.Fs.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Serial != nil {
+		l = m.Serial.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Memory != nil {
+		l = m.Memory.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Vcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.Vcpus))
+	}
+	return n
+}
+
+func (m *DeviceRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Device != nil {
+		l = m.Device.Size()
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	l = len(m.Netns)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *HotplugResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Vcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.Vcpus))
+	}
+	if m.MemoryMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MemoryMb))
+	}
+	return n
+}
+
+func (m *ResizeMemoryRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.MemoryMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MemoryMb))
+	}
+	if m.MemoryBlockSizeMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MemoryBlockSizeMb))
+	}
+	return n
+}
+
+func (m *ResizeMemoryResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.MemoryMb != 0 {
+		n += 1 + sovHypervisor(uint64(m.MemoryMb))
+	}
+	return n
+}
+
+func (m *ResizeVCPUsRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	if m.Vcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.Vcpus))
+	}
+	return n
+}
+
+func (m *ResizeVCPUsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.OldVcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.OldVcpus))
+	}
+	if m.NewVcpus != 0 {
+		n += 1 + sovHypervisor(uint64(m.NewVcpus))
+	}
+	return n
+}
+
+func (m *GetConsoleResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Url)
+	if l > 0 {
+		n += 1 + l + sovHypervisor(uint64(l))
+	}
+	return n
+}
+
+func (m *GetThreadIDsResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Vcpus) > 0 {
+		for k, v := range m.Vcpus {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + sovHypervisor(uint64(k)) + 1 + sovHypervisor(uint64(v))
+			n += mapEntrySize + 1 + sovHypervisor(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func sovHypervisor(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozHypervisor(x uint64) (n int) {
+	return sovHypervisor(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *CapabilitiesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CapabilitiesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CapabilitiesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CapabilitiesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CapabilitiesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CapabilitiesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockDevice", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BlockDevice = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockDeviceHotplug", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BlockDeviceHotplug = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MultiQueue", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MultiQueue = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FsSharing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FsSharing = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VMRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VMRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VMRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VMResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VMResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VMResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VMConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VMConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VMConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Kernel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Kernel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KernelParams", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KernelParams = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Image", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Image = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Initrd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Initrd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Firmware", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Firmware = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vcpus", wireType)
+			}
+			m.Vcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Vcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxVcpus", wireType)
+			}
+			m.MaxVcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxVcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryMb", wireType)
+			}
+			m.MemoryMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryMb |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxMemoryMb", wireType)
+			}
+			m.MaxMemoryMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxMemoryMb |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockDeviceDriver", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockDeviceDriver = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hugepages", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Hugepages = bool(v != 0)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Debug", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Debug = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateVMRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateVMRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateVMRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Config == nil {
+				m.Config = &VMConfig{}
+			}
+			if err := m.Config.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StartVMRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StartVMRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StartVMRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Netns", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Netns = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StartVMResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StartVMResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StartVMResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pid", wireType)
+			}
+			m.Pid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Pid |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlockDevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockDevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockDevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Format = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VFIODevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VFIODevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VFIODevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bdf", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bdf = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SysfsDev", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SysfsDev = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetDevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetDevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetDevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HwAddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HwAddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TapName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TapName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SharedFS) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SharedFS: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SharedFS: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MountTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SerialPort) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SerialPort: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SerialPort: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HostPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MemoryDevice) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MemoryDevice: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MemoryDevice: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Slot", wireType)
+			}
+			m.Slot = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Slot |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SizeMb", wireType)
+			}
+			m.SizeMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SizeMb |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Device) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Device: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Device: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Block == nil {
+				m.Block = &BlockDevice{}
+			}
+			if err := m.Block.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vfio", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Vfio == nil {
+				m.Vfio = &VFIODevice{}
+			}
+			if err := m.Vfio.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Net", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Net == nil {
+				m.Net = &NetDevice{}
+			}
+			if err := m.Net.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fs == nil {
+				m.Fs = &SharedFS{}
+			}
+			if err := m.Fs.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Serial", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Serial == nil {
+				m.Serial = &SerialPort{}
+			}
+			if err := m.Serial.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Memory == nil {
+				m.Memory = &MemoryDevice{}
+			}
+			if err := m.Memory.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vcpus", wireType)
+			}
+			m.Vcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Vcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeviceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeviceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeviceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Device", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Device == nil {
+				m.Device = &Device{}
+			}
+			if err := m.Device.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Netns", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Netns = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HotplugResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HotplugResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HotplugResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vcpus", wireType)
+			}
+			m.Vcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Vcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryMb", wireType)
+			}
+			m.MemoryMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryMb |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResizeMemoryRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResizeMemoryRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResizeMemoryRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryMb", wireType)
+			}
+			m.MemoryMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryMb |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryBlockSizeMb", wireType)
+			}
+			m.MemoryBlockSizeMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryBlockSizeMb |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResizeMemoryResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResizeMemoryResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResizeMemoryResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryMb", wireType)
+			}
+			m.MemoryMb = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MemoryMb |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResizeVCPUsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResizeVCPUsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResizeVCPUsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vcpus", wireType)
+			}
+			m.Vcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Vcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResizeVCPUsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResizeVCPUsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResizeVCPUsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OldVcpus", wireType)
+			}
+			m.OldVcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OldVcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewVcpus", wireType)
+			}
+			m.NewVcpus = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NewVcpus |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetConsoleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetConsoleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetConsoleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Url", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Url = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetThreadIDsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetThreadIDsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetThreadIDsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vcpus", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Vcpus == nil {
+				m.Vcpus = make(map[uint32]int64)
+			}
+			var mapkey uint32
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowHypervisor
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowHypervisor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapkey |= (uint32(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowHypervisor
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= (int64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipHypervisor(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthHypervisor
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Vcpus[mapkey] = mapvalue
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHypervisor(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthHypervisor
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipHypervisor(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowHypervisor
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowHypervisor
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthHypervisor
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowHypervisor
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipHypervisor(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthHypervisor = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowHypervisor   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("hypervisor.proto", fileDescriptorHypervisor) }
+
+var fileDescriptorHypervisor = []byte{
+	// 1278 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xdb, 0x72, 0x1b, 0x45,
+	0x13, 0x2e, 0x1d, 0x2c, 0xad, 0x5a, 0x72, 0xe2, 0x8c, 0x95, 0x64, 0x23, 0xff, 0xbf, 0x63, 0x36,
+	0x14, 0xa4, 0x02, 0x98, 0x94, 0x72, 0x41, 0x08, 0x55, 0x50, 0x89, 0x4c, 0x4e, 0x94, 0x12, 0x67,
+	0x1d, 0x74, 0xbb, 0x35, 0xf2, 0x8e, 0xa4, 0x2d, 0xef, 0x29, 0x3b, 0xb3, 0xb2, 0xc5, 0x6b, 0x70,
+	0xc1, 0xdb, 0x70, 0x47, 0x15, 0x97, 0x3c, 0x02, 0x95, 0x3b, 0xde, 0x82, 0x9a, 0xde, 0xd1, 0x6a,
+	0x56, 0xf6, 0x12, 0x12, 0xee, 0xb6, 0x4f, 0x5f, 0xf7, 0x74, 0xf7, 0x7c, 0x23, 0xc1, 0xd6, 0x6c,
+	0x11, 0xb3, 0x64, 0xee, 0xf1, 0x28, 0xd9, 0x8f, 0x93, 0x48, 0x44, 0x04, 0x56, 0x1a, 0xeb, 0x2a,
+	0x6c, 0x0f, 0x68, 0x4c, 0xc7, 0x9e, 0xef, 0x09, 0x8f, 0x71, 0x9b, 0xbd, 0x49, 0x19, 0x17, 0xd6,
+	0x6f, 0x15, 0xe8, 0x16, 0xf5, 0x3c, 0x8e, 0x42, 0xce, 0x88, 0x09, 0xcd, 0x39, 0x4b, 0xb8, 0x17,
+	0x85, 0x66, 0x65, 0xaf, 0x72, 0x7b, 0xd3, 0x5e, 0x8a, 0xe4, 0x23, 0xe8, 0x8c, 0xfd, 0xe8, 0xf8,
+	0xc4, 0x71, 0xd9, 0xdc, 0x3b, 0x66, 0x66, 0x75, 0xaf, 0x72, 0xdb, 0xb0, 0xdb, 0xa8, 0x3b, 0x40,
+	0x15, 0xb9, 0x0b, 0x5d, 0xdd, 0xc5, 0x99, 0x45, 0x22, 0xf6, 0xd3, 0xa9, 0x59, 0x43, 0x57, 0xa2,
+	0xb9, 0x3e, 0xcd, 0x2c, 0xe4, 0x26, 0xb4, 0x83, 0xd4, 0x17, 0x9e, 0xf3, 0x26, 0x65, 0x29, 0x33,
+	0xeb, 0xe8, 0x08, 0xa8, 0x7a, 0x25, 0x35, 0xe4, 0xff, 0x00, 0x13, 0xee, 0xf0, 0x19, 0x4d, 0xbc,
+	0x70, 0x6a, 0x6e, 0xa0, 0xbd, 0x35, 0xe1, 0x47, 0x99, 0xc2, 0xda, 0x81, 0xd6, 0x68, 0xa8, 0x0e,
+	0x45, 0x2e, 0x41, 0xd5, 0x73, 0xb1, 0xec, 0x96, 0x5d, 0xf5, 0x5c, 0xab, 0x03, 0x30, 0x1a, 0x2e,
+	0x4f, 0x66, 0xfd, 0x55, 0x05, 0x63, 0x34, 0x1c, 0x44, 0xe1, 0xc4, 0x9b, 0x92, 0x6b, 0xd0, 0x38,
+	0x61, 0x49, 0xc8, 0x7c, 0xe5, 0xae, 0x24, 0x72, 0x0b, 0x36, 0xb3, 0x2f, 0x27, 0xa6, 0x09, 0x0d,
+	0x38, 0x9e, 0xb2, 0x65, 0x77, 0x32, 0xe5, 0x21, 0xea, 0x48, 0x17, 0x36, 0xbc, 0x80, 0x4e, 0x19,
+	0x9e, 0xab, 0x65, 0x67, 0x82, 0x84, 0xf4, 0x42, 0x4f, 0x24, 0x2e, 0x9e, 0xa2, 0x65, 0x2b, 0x89,
+	0xf4, 0xc0, 0x98, 0x78, 0x49, 0x70, 0x4a, 0x13, 0x86, 0xf5, 0xb7, 0xec, 0x5c, 0x96, 0x48, 0xf3,
+	0xe3, 0x38, 0xe5, 0x66, 0x03, 0x7b, 0x9d, 0x09, 0x64, 0x07, 0x5a, 0x01, 0x3d, 0x73, 0x32, 0x4b,
+	0x13, 0x2d, 0x46, 0x40, 0xcf, 0x46, 0xb9, 0x91, 0x05, 0x51, 0xb2, 0x70, 0x82, 0xb1, 0x69, 0x28,
+	0x23, 0x2a, 0x86, 0x63, 0x62, 0xc1, 0xa6, 0x8c, 0x5c, 0x39, 0xb4, 0xd0, 0xa1, 0x1d, 0xd0, 0xb3,
+	0xe1, 0xd2, 0x67, 0x1f, 0xb6, 0x0b, 0x43, 0x72, 0x13, 0x6f, 0xce, 0x12, 0x13, 0xb0, 0xb4, 0x2b,
+	0xda, 0x8c, 0x0e, 0xd0, 0x40, 0xfe, 0x07, 0xad, 0x59, 0x3a, 0x65, 0x31, 0x9d, 0x32, 0x6e, 0xb6,
+	0xb3, 0x01, 0xe4, 0x0a, 0x79, 0x02, 0x97, 0x8d, 0xd3, 0xa9, 0xd9, 0x41, 0x4b, 0x26, 0x58, 0x2f,
+	0xe1, 0xf2, 0x20, 0x61, 0x54, 0xb0, 0xd2, 0xe1, 0x90, 0xcf, 0xa1, 0x71, 0x8c, 0xb3, 0xc0, 0x16,
+	0xb7, 0xfb, 0xdd, 0x7d, 0x6d, 0x8f, 0x97, 0x73, 0xb2, 0x95, 0x8f, 0x75, 0x08, 0x97, 0x8e, 0x04,
+	0x4d, 0x44, 0x39, 0x9e, 0x09, 0x4d, 0xe1, 0x05, 0x2c, 0x4a, 0x05, 0x02, 0x6e, 0xda, 0x4b, 0x51,
+	0x96, 0x18, 0x32, 0x11, 0xf2, 0xe5, 0xb8, 0x50, 0xb0, 0x6e, 0xc1, 0xe5, 0x1c, 0x51, 0xed, 0xfe,
+	0x16, 0xd4, 0x62, 0x85, 0x59, 0xb3, 0xe5, 0xa7, 0xe5, 0x40, 0xfb, 0x91, 0xb6, 0xdf, 0xeb, 0x39,
+	0x09, 0xd4, 0x27, 0x9e, 0xcf, 0xd4, 0x92, 0xe0, 0xb7, 0x5c, 0x83, 0x49, 0x94, 0x04, 0x54, 0xa8,
+	0x74, 0x4a, 0xc2, 0xa5, 0x09, 0x5d, 0x76, 0x86, 0xdb, 0x51, 0xb3, 0x33, 0xc1, 0xfa, 0x01, 0x60,
+	0xf4, 0xf8, 0xd9, 0xcb, 0x12, 0xfc, 0x2d, 0xa8, 0x8d, 0xdd, 0x89, 0x82, 0x97, 0x9f, 0x72, 0xfa,
+	0x7c, 0xc1, 0x27, 0x5c, 0x0e, 0x4f, 0x25, 0x30, 0x50, 0x71, 0xc0, 0xe6, 0xd6, 0x14, 0x5a, 0x2f,
+	0x98, 0x50, 0x58, 0x04, 0xea, 0x21, 0x0d, 0x98, 0x42, 0xc3, 0x6f, 0x72, 0x1d, 0x9a, 0xb3, 0x53,
+	0x87, 0xba, 0x6e, 0xa2, 0x30, 0x1b, 0xb3, 0xd3, 0x87, 0xae, 0x9b, 0x90, 0x1b, 0x60, 0x08, 0x1a,
+	0x3b, 0x18, 0x90, 0xa1, 0x36, 0x05, 0x8d, 0x5f, 0xc8, 0x18, 0x02, 0x75, 0xb1, 0x88, 0x99, 0x5a,
+	0x6a, 0xfc, 0xb6, 0x0e, 0xc0, 0x90, 0x17, 0x90, 0xb9, 0x8f, 0x8f, 0x70, 0x1f, 0xa3, 0x34, 0x14,
+	0x8e, 0xa0, 0x53, 0x95, 0xcc, 0x40, 0xc5, 0x6b, 0x3a, 0x95, 0xc6, 0x59, 0xc4, 0x85, 0x13, 0x53,
+	0x31, 0x53, 0x29, 0x0d, 0xa9, 0x38, 0xa4, 0x62, 0x66, 0x0d, 0x01, 0x8e, 0x58, 0xe2, 0x51, 0xff,
+	0x30, 0x4a, 0xc4, 0x45, 0xbd, 0xc5, 0x72, 0xaa, 0x5a, 0xfd, 0x05, 0xb8, 0xda, 0x1a, 0xdc, 0x37,
+	0xd0, 0xc9, 0x76, 0x7c, 0xd5, 0x00, 0xee, 0x47, 0x42, 0x8d, 0x13, 0xbf, 0x65, 0x03, 0xb8, 0xf7,
+	0x13, 0x93, 0x37, 0xa3, 0x8a, 0xea, 0x86, 0x14, 0x87, 0x63, 0xeb, 0xd7, 0x2a, 0x34, 0x54, 0xdc,
+	0x17, 0xb0, 0x81, 0x97, 0x00, 0x03, 0xdb, 0xfd, 0xeb, 0xfa, 0x5e, 0x6a, 0xcb, 0x60, 0x67, 0x5e,
+	0xe4, 0x0e, 0xd4, 0xe7, 0x13, 0x2f, 0x52, 0x5b, 0x7c, 0xad, 0xb0, 0xc5, 0xf9, 0x64, 0x6d, 0xf4,
+	0x21, 0x9f, 0x42, 0x2d, 0x64, 0xd9, 0x62, 0xb4, 0xfb, 0x57, 0x75, 0xd7, 0x7c, 0x6e, 0xb6, 0xf4,
+	0x20, 0x1f, 0x43, 0x75, 0xc2, 0xcd, 0xfa, 0xf9, 0x8b, 0xb1, 0x6c, 0xbb, 0x5d, 0x9d, 0x70, 0xb2,
+	0x0f, 0x0d, 0x8e, 0x0d, 0x34, 0x37, 0xce, 0x27, 0x5f, 0xb5, 0xd6, 0x56, 0x5e, 0xe4, 0x2e, 0x34,
+	0x32, 0x66, 0x40, 0x52, 0x69, 0xf7, 0x4d, 0xdd, 0x5f, 0xef, 0x9d, 0xad, 0xfc, 0x56, 0xfc, 0x64,
+	0x68, 0xfc, 0xf4, 0xbc, 0x6e, 0x34, 0xb6, 0x9a, 0x76, 0x67, 0xb6, 0x18, 0x27, 0x9e, 0xeb, 0xcc,
+	0x79, 0x74, 0x7c, 0x62, 0x51, 0xd8, 0x54, 0xb1, 0x25, 0xf7, 0xf3, 0x0e, 0x34, 0xb4, 0x87, 0xa3,
+	0xdd, 0x27, 0x7a, 0xf2, 0x65, 0xda, 0xcc, 0xa3, 0xe4, 0xc6, 0x1e, 0xc0, 0x65, 0xf5, 0x6c, 0xe4,
+	0x37, 0x36, 0xaf, 0xaf, 0xb2, 0xce, 0x9f, 0x39, 0x03, 0x66, 0x73, 0xce, 0x29, 0xd2, 0xe2, 0xb0,
+	0x6d, 0x33, 0x9c, 0x3a, 0x6a, 0xca, 0xca, 0x3d, 0x87, 0xa1, 0xd3, 0xec, 0x97, 0xd0, 0x55, 0xc6,
+	0x8c, 0x49, 0x97, 0x3b, 0x55, 0x43, 0xbf, 0x2b, 0x99, 0x0d, 0x77, 0xe5, 0x28, 0x5b, 0xaf, 0x7b,
+	0xd0, 0x2d, 0x26, 0x55, 0xf5, 0x17, 0xb2, 0x54, 0x8a, 0x59, 0xac, 0x07, 0x40, 0xb2, 0xa0, 0xd1,
+	0xe0, 0xf0, 0x47, 0x5e, 0x56, 0x68, 0xde, 0x82, 0xaa, 0xd6, 0x02, 0xeb, 0x25, 0x6c, 0x17, 0x62,
+	0x57, 0xf9, 0x22, 0xdf, 0x75, 0xf4, 0x9e, 0x19, 0x91, 0xef, 0xe6, 0x2f, 0x4b, 0xc8, 0x4e, 0x1d,
+	0x1d, 0xcd, 0x08, 0xd9, 0x29, 0x1a, 0xad, 0x4f, 0x80, 0x3c, 0x61, 0x62, 0x10, 0x85, 0x3c, 0xf2,
+	0x99, 0xce, 0x98, 0x69, 0xb2, 0x7c, 0x43, 0xe5, 0xa7, 0xf5, 0x73, 0x05, 0xba, 0x4f, 0x98, 0x78,
+	0x3d, 0x4b, 0x18, 0x75, 0x9f, 0x1d, 0xac, 0x52, 0x3f, 0x5c, 0x8d, 0xaa, 0x76, 0xbb, 0xdd, 0xff,
+	0x4c, 0x1f, 0xff, 0x45, 0x01, 0xfb, 0x98, 0xf7, 0xfb, 0x50, 0x24, 0x0b, 0x75, 0xa8, 0xde, 0x7d,
+	0x80, 0x95, 0x52, 0xe6, 0x3e, 0x61, 0x0b, 0x75, 0x0a, 0xf9, 0x89, 0xad, 0xa0, 0x7e, 0xca, 0xd4,
+	0xcc, 0x33, 0xe1, 0x41, 0xf5, 0x7e, 0xa5, 0xff, 0x8b, 0x01, 0x5b, 0x4f, 0xf3, 0x7c, 0xea, 0x61,
+	0x7b, 0x05, 0x1d, 0xfd, 0x27, 0x10, 0xb9, 0xa9, 0x97, 0x74, 0xc1, 0x8f, 0xa6, 0xde, 0x5e, 0xb9,
+	0x83, 0x3a, 0xe4, 0x77, 0x60, 0x2c, 0xdf, 0x3d, 0xb2, 0x53, 0xf0, 0x2e, 0xbe, 0x86, 0xbd, 0x22,
+	0x4f, 0xac, 0x9e, 0xa0, 0x47, 0xd0, 0x54, 0xaf, 0x12, 0xe9, 0x15, 0x6e, 0x73, 0xe1, 0xf1, 0xeb,
+	0xed, 0x5c, 0x68, 0x53, 0x18, 0x5f, 0x41, 0xe3, 0x48, 0x44, 0xf1, 0x68, 0x48, 0xae, 0xae, 0x67,
+	0xf9, 0xe7, 0xe4, 0xf7, 0xa1, 0x79, 0x48, 0x53, 0xce, 0xde, 0x3f, 0xf2, 0x6b, 0x30, 0x6c, 0xc6,
+	0xd3, 0xe0, 0x03, 0x42, 0x65, 0xb5, 0x74, 0xfe, 0x01, 0x81, 0xdf, 0x42, 0xeb, 0xa1, 0xeb, 0x2a,
+	0xd2, 0xbe, 0x71, 0x01, 0x9b, 0xbc, 0x23, 0xfe, 0x39, 0x6c, 0x29, 0x3a, 0xf9, 0x57, 0x30, 0x85,
+	0x96, 0xaf, 0xf3, 0xd0, 0x10, 0xb6, 0x73, 0x55, 0x10, 0xcd, 0xd9, 0x7f, 0x84, 0x7b, 0x05, 0x1d,
+	0x9d, 0x2e, 0x8a, 0x9b, 0x79, 0x01, 0x7b, 0xf5, 0xf6, 0xca, 0x1d, 0x14, 0xe4, 0x0b, 0x68, 0x6b,
+	0x84, 0x40, 0x76, 0xcf, 0x07, 0xe8, 0x2c, 0xd3, 0xbb, 0x59, 0x6a, 0x57, 0x78, 0x03, 0x80, 0x15,
+	0x1f, 0x94, 0x8d, 0x6e, 0x77, 0xed, 0x92, 0xaf, 0xd3, 0xc7, 0x13, 0xe8, 0xe8, 0x57, 0xbf, 0x0c,
+	0x66, 0xef, 0x5d, 0x5c, 0x21, 0x37, 0x77, 0xe0, 0x33, 0x1a, 0xa6, 0xf1, 0x7b, 0x6e, 0xd1, 0xa3,
+	0xce, 0xef, 0x6f, 0x77, 0x2b, 0x7f, 0xbc, 0xdd, 0xad, 0xfc, 0xf9, 0x76, 0xb7, 0x32, 0x6e, 0xe0,
+	0x1f, 0xa8, 0x7b, 0x7f, 0x0f, 0x00, 0x28, 0x92, 0x4c, 0x60, 0x54, 0x0d, 0x00, 0x00,
+}
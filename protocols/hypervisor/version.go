@@ -0,0 +1,10 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package hypervisor
+
+// Version is the version of the protocol. Drivers reporting another
+// version are refused.
+const Version = 1
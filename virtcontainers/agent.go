@@ -243,6 +243,9 @@ type agent interface {
 	// updateInterface will tell the agent to update a nic for an existed Sandbox.
 	updateInterface(inf *vcTypes.Interface) (*vcTypes.Interface, error)
 
+	// removeInterface will tell the agent to remove a nic from an existed Sandbox.
+	removeInterface(inf *vcTypes.Interface) error
+
 	// listInterfaces will tell the agent to list interfaces of an existed Sandbox
 	listInterfaces() ([]*vcTypes.Interface, error)
 
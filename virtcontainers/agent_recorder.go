@@ -0,0 +1,164 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AgentRPCRecordMaxPayload is the size in bytes above which the request or
+// response of a recorded agent RPC is truncated.
+const AgentRPCRecordMaxPayload = 4096
+
+const redactedValue = "REDACTED"
+
+// rpcRedactedFields are the request and response fields never recorded, as
+// they hold container environments or process and file contents.
+var rpcRedactedFields = map[string]bool{
+	"env":  true,
+	"data": true,
+}
+
+// AgentRPCRecord is an agent RPC as recorded, one JSON object per line, in
+// the record file of the sandbox.
+type AgentRPCRecord struct {
+	Time     time.Time       `json:"time"`
+	Name     string          `json:"name"`
+	Duration time.Duration   `json:"duration"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+
+	// Truncated is true if the request or the response has been
+	// truncated. A truncated payload is recorded as a JSON string.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// agentRPCRecorder appends the agent RPCs of a sandbox to its record file.
+type agentRPCRecorder struct {
+	sync.Mutex
+	file *os.File
+}
+
+// AgentRPCRecordPath returns the path of the file the agent RPCs of a
+// sandbox are recorded to.
+func AgentRPCRecordPath(dir, sandboxID string) string {
+	return filepath.Join(dir, sandboxID+".jsonl")
+}
+
+func newAgentRPCRecorder(dir, sandboxID string) (*agentRPCRecorder, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	// Several runtime processes can manage the same sandbox.
+	f, err := os.OpenFile(AgentRPCRecordPath(dir, sandboxID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &agentRPCRecorder{file: f}, nil
+}
+
+// redactRPCPayload replaces the values of the redacted fields found in a
+// JSON payload.
+func redactRPCPayload(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			if rpcRedactedFields[strings.ToLower(k)] {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = redactRPCPayload(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactRPCPayload(e)
+		}
+	}
+
+	return v
+}
+
+// rpcPayload returns the redacted JSON encoding of a request or response,
+// and whether it had to be truncated.
+func rpcPayload(msg interface{}) (json.RawMessage, bool) {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		raw, _ = json.Marshal(err.Error())
+		return raw, false
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err == nil {
+		if redacted, err := json.Marshal(redactRPCPayload(generic)); err == nil {
+			raw = redacted
+		}
+	}
+
+	if len(raw) <= AgentRPCRecordMaxPayload {
+		return raw, false
+	}
+
+	truncated, _ := json.Marshal(string(raw[:AgentRPCRecordMaxPayload]))
+	return truncated, true
+}
+
+func (r *agentRPCRecorder) record(name string, start time.Time, request, response interface{}, rpcErr error) {
+	if r == nil {
+		return
+	}
+
+	rec := AgentRPCRecord{
+		Time:     start,
+		Name:     name,
+		Duration: time.Since(start),
+	}
+
+	var truncated bool
+
+	rec.Request, truncated = rpcPayload(request)
+	rec.Truncated = truncated
+
+	if response != nil {
+		rec.Response, truncated = rpcPayload(response)
+		rec.Truncated = rec.Truncated || truncated
+	}
+
+	if rpcErr != nil {
+		rec.Error = rpcErr.Error()
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		virtLog.WithError(err).Warn("failed to encode agent RPC record")
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		virtLog.WithError(err).Warn("failed to record agent RPC")
+	}
+}
+
+func (r *agentRPCRecorder) close() {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.file.Close()
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/agent/protocols/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCPayloadRedaction(t *testing.T) {
+	assert := assert.New(t)
+
+	payload, truncated := rpcPayload(&grpc.ExecProcessRequest{
+		ContainerId: "foo",
+		Process: &grpc.Process{
+			Args: []string{"sh"},
+			Env:  []string{"PASSWORD=secret"},
+		},
+	})
+	assert.False(truncated)
+	assert.NotContains(string(payload), "secret")
+	assert.Contains(string(payload), redactedValue)
+	assert.Contains(string(payload), "foo")
+
+	payload, _ = rpcPayload(&grpc.WriteStreamRequest{Data: []byte("secret")})
+	assert.NotContains(string(payload), "c2VjcmV0")
+}
+
+func TestRPCPayloadTruncation(t *testing.T) {
+	assert := assert.New(t)
+
+	payload, truncated := rpcPayload(&grpc.CreateContainerRequest{
+		ContainerId: strings.Repeat("a", 2*AgentRPCRecordMaxPayload),
+	})
+	assert.True(truncated)
+
+	var s string
+	assert.NoError(json.Unmarshal(payload, &s))
+	assert.Len(s, AgentRPCRecordMaxPayload)
+}
+
+func TestAgentRPCRecorder(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "agent-rpc")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	r, err := newAgentRPCRecorder(dir, testSandboxID)
+	assert.NoError(err)
+
+	r.record("grpc.CheckRequest", time.Now(), &grpc.CheckRequest{}, &grpc.HealthCheckResponse{}, nil)
+	r.record("grpc.StartContainerRequest", time.Now(), &grpc.StartContainerRequest{ContainerId: "foo"}, nil, errors.New("failed"))
+	r.close()
+
+	f, err := os.Open(AgentRPCRecordPath(dir, testSandboxID))
+	assert.NoError(err)
+	defer f.Close()
+
+	var records []AgentRPCRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec AgentRPCRecord
+		assert.NoError(json.Unmarshal(scanner.Bytes(), &rec))
+		records = append(records, rec)
+	}
+
+	assert.Len(records, 2)
+	assert.Equal("grpc.CheckRequest", records[0].Name)
+	assert.Empty(records[0].Error)
+	assert.Equal("grpc.StartContainerRequest", records[1].Name)
+	assert.Equal("failed", records[1].Error)
+	assert.Empty(records[1].Response)
+
+	// recording is disabled
+	var disabled *agentRPCRecorder
+	disabled.record("grpc.CheckRequest", time.Now(), &grpc.CheckRequest{}, nil, nil)
+	disabled.close()
+}
@@ -7,6 +7,7 @@ package virtcontainers
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"runtime"
 	"syscall"
@@ -14,6 +15,7 @@ import (
 	deviceApi "github.com/kata-containers/runtime/virtcontainers/device/api"
 	deviceConfig "github.com/kata-containers/runtime/virtcontainers/device/config"
 	vcTypes "github.com/kata-containers/runtime/virtcontainers/pkg/types"
+	"github.com/kata-containers/runtime/virtcontainers/pkg/uuid"
 	"github.com/kata-containers/runtime/virtcontainers/store"
 	"github.com/kata-containers/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -89,6 +91,8 @@ func createSandboxFromConfig(ctx context.Context, sandboxConfig SandboxConfig, f
 		return nil, err
 	}
 
+	s.recordBootPhase(types.BootPhaseNetwork)
+
 	// network rollback
 	defer func() {
 		if err != nil && s.networkNS.NetNsCreated {
@@ -913,3 +917,37 @@ func ListRoutes(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error)
 
 	return s.ListRoutes()
 }
+
+// HypervisorCommandLine is the virtcontainers entry point returning the
+// command line the hypervisor of a sandbox created from sandboxConfig would
+// be started with, without creating the sandbox. Only QEMU is supported. The
+// devices only added for the agent and the network when the VM is started
+// are not part of it.
+func HypervisorCommandLine(ctx context.Context, sandboxConfig SandboxConfig) ([]string, error) {
+	span, ctx := trace(ctx, "HypervisorCommandLine")
+	defer span.Finish()
+
+	if sandboxConfig.HypervisorType != QemuHypervisor {
+		return nil, fmt.Errorf("Cannot build the command line of the %s hypervisor, it is configured through its API", sandboxConfig.HypervisorType)
+	}
+
+	if err := createAssets(ctx, &sandboxConfig); err != nil {
+		return nil, err
+	}
+
+	// The hypervisor keeps its state in the sandbox store, use a
+	// throw-away one so that an existing sandbox is never touched.
+	id := "dry-run-" + uuid.Generate().String()
+	vcStore, err := store.NewVCSandboxStore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer vcStore.Delete()
+
+	q := &qemu{}
+	if err := q.createSandbox(ctx, id, &sandboxConfig.HypervisorConfig, vcStore); err != nil {
+		return nil, err
+	}
+
+	return q.commandLine()
+}
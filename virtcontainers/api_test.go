@@ -198,6 +198,10 @@ func newTestSandboxConfigKataAgent() SandboxConfig {
 	return sandboxConfig
 }
 
+func TestAPIVersion(t *testing.T) {
+	assert.Regexp(t, `^[0-9]+\.[0-9]+\.[0-9]+$`, APIVersion)
+}
+
 func TestCreateSandboxNoopAgentSuccessful(t *testing.T) {
 	defer cleanUp()
 
@@ -873,6 +877,8 @@ func TestStatusSandboxSuccessfulStateReady(t *testing.T) {
 		BlockDeviceDriver: defaultBlockDriver,
 		DefaultMaxVCPUs:   defaultMaxQemuVCPUs,
 		Msize9p:           defaultMsize9p,
+		Cache9p:           defaultCache9p,
+		Version9p:         defaultVersion9p,
 	}
 
 	expectedStatus := SandboxStatus{
@@ -913,6 +919,15 @@ func TestStatusSandboxSuccessfulStateReady(t *testing.T) {
 	// value will be.
 	expectedStatus.ContainersStatus[0].StartTime = status.ContainersStatus[0].StartTime
 
+	// Same for the boot timeline, but check the phases reached so far.
+	if _, ok := status.State.BootTimeline.Get(types.BootPhaseAgent); !ok {
+		t.Fatalf("Missing %s phase in boot timeline %v", types.BootPhaseAgent, status.State.BootTimeline)
+	}
+	if _, ok := status.State.BootTimeline.Get(types.BootPhaseContainer); ok {
+		t.Fatalf("Unexpected %s phase in boot timeline %v", types.BootPhaseContainer, status.State.BootTimeline)
+	}
+	expectedStatus.State.BootTimeline = status.State.BootTimeline
+
 	if reflect.DeepEqual(status, expectedStatus) == false {
 		t.Fatalf("Got sandbox status %v\n expecting %v", status, expectedStatus)
 	}
@@ -932,6 +947,8 @@ func TestStatusSandboxSuccessfulStateRunning(t *testing.T) {
 		BlockDeviceDriver: defaultBlockDriver,
 		DefaultMaxVCPUs:   defaultMaxQemuVCPUs,
 		Msize9p:           defaultMsize9p,
+		Cache9p:           defaultCache9p,
+		Version9p:         defaultVersion9p,
 	}
 
 	expectedStatus := SandboxStatus{
@@ -977,6 +994,12 @@ func TestStatusSandboxSuccessfulStateRunning(t *testing.T) {
 	// value will be.
 	expectedStatus.ContainersStatus[0].StartTime = status.ContainersStatus[0].StartTime
 
+	// Same for the boot timeline, but check all phases were reached.
+	if len(status.State.BootTimeline) != 5 {
+		t.Fatalf("Incomplete boot timeline %v", status.State.BootTimeline)
+	}
+	expectedStatus.State.BootTimeline = status.State.BootTimeline
+
 	if reflect.DeepEqual(status, expectedStatus) == false {
 		t.Fatalf("Got sandbox status %v\n expecting %v", status, expectedStatus)
 	}
@@ -0,0 +1,193 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// auditUserMsg is the AUDIT_USER_MSG netlink message type, used to send
+// user space records to the kernel audit subsystem.
+const auditUserMsg = 1107
+
+const auditLogMode = 0600
+
+// AuditConfig describes where the privileged operations a sandbox performs
+// on the host, the mounts, the device hotplugs and the network changes, are
+// recorded.
+type AuditConfig struct {
+	// Path is the file the records are appended to, one JSON object per
+	// line. Nothing is recorded when it is empty.
+	Path string
+
+	// Auditd also sends the records to the kernel audit subsystem, for
+	// auditd to pick them up.
+	Auditd bool
+}
+
+type auditRecord struct {
+	Time      time.Time         `json:"time"`
+	Sandbox   string            `json:"sandbox"`
+	Operation string            `json:"operation"`
+	Args      map[string]string `json:"args,omitempty"`
+	Result    string            `json:"result"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// auditLog records the privileged host operations of a sandbox. The file is
+// opened in append mode for each record since several runtime processes
+// share it.
+type auditLog struct {
+	sandboxID string
+	config    AuditConfig
+	logger    *logrus.Entry
+	lock      sync.Mutex
+}
+
+func newAuditLog(s *Sandbox, config AuditConfig) *auditLog {
+	if config.Path == "" {
+		return nil
+	}
+
+	return &auditLog{
+		sandboxID: s.id,
+		config:    config,
+		logger:    s.Logger().WithField("audit-log", config.Path),
+	}
+}
+
+// record records an operation and its outcome. Failing to record it is only
+// logged, the operation itself has already been performed.
+func (a *auditLog) record(operation string, args map[string]string, opErr error) {
+	if a == nil {
+		return
+	}
+
+	r := auditRecord{
+		Time:      time.Now().UTC(),
+		Sandbox:   a.sandboxID,
+		Operation: operation,
+		Args:      args,
+		Result:    "success",
+	}
+
+	if opErr != nil {
+		r.Result = "failed"
+		r.Error = opErr.Error()
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.write(r); err != nil {
+		a.logger.WithError(err).WithField("operation", operation).Warn("Could not write the audit record")
+	}
+
+	if a.config.Auditd {
+		if err := sendAuditd(auditdMessage(r)); err != nil {
+			a.logger.WithError(err).WithField("operation", operation).Warn("Could not send the audit record to auditd")
+		}
+	}
+}
+
+func (a *auditLog) write(r auditRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(a.config.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, auditLogMode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// A single write keeps the records of concurrent processes apart.
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// auditdMessage formats a record the way the user space audit messages
+// are, a list of key=value pairs.
+func auditdMessage(r auditRecord) string {
+	fields := []string{
+		"op=" + r.Operation,
+		"sandbox=" + r.Sandbox,
+	}
+
+	keys := make([]string, 0, len(r.Args))
+	for k := range r.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%q", k, r.Args[k]))
+	}
+
+	if r.Error != "" {
+		fields = append(fields, fmt.Sprintf("error=%q", r.Error))
+	}
+
+	return strings.Join(append(fields, "res="+r.Result), " ")
+}
+
+func sendAuditd(msg string) error {
+	req := nl.NewNetlinkRequest(auditUserMsg, unix.NLM_F_ACK)
+	req.AddRawData(nl.ZeroTerminated(msg))
+
+	_, err := req.Execute(unix.NETLINK_AUDIT, 0)
+	return err
+}
+
+// auditEndpoints records the endpoints attached to or detached from the
+// sandbox, the network namespace alone if there are none.
+func (s *Sandbox) auditEndpoints(operation string, endpoints []Endpoint, err error) {
+	if len(endpoints) == 0 {
+		s.audit.record(operation, map[string]string{
+			"netns": s.networkNS.NetNsPath,
+		}, err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		s.audit.record(operation, map[string]string{
+			"netns":  s.networkNS.NetNsPath,
+			"name":   endpoint.Name(),
+			"type":   string(endpoint.Type()),
+			"hwaddr": endpoint.HardwareAddr(),
+		}, err)
+	}
+}
+
+func vfioAuditArgs(dev *config.VFIODev) map[string]string {
+	return map[string]string{
+		"type":     "vfio",
+		"id":       dev.ID,
+		"bdf":      dev.BDF,
+		"sysfsdev": dev.SysfsDev,
+	}
+}
+
+func blockAuditArgs(drive *config.BlockDrive) map[string]string {
+	return map[string]string{
+		"type":   "block",
+		"id":     drive.ID,
+		"file":   drive.File,
+		"format": drive.Format,
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/device/drivers"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAuditRecords(t *testing.T, path string) []auditRecord {
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r auditRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	assert.NoError(t, scanner.Err())
+
+	return records
+}
+
+func TestAuditLogRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{id: testSandboxID}
+
+	// Auditing is disabled without a path.
+	a := newAuditLog(s, AuditConfig{})
+	assert.Nil(a)
+	a.record("mount", nil, nil)
+
+	path := filepath.Join(dir, "audit.log")
+	a = newAuditLog(s, AuditConfig{Path: path})
+	assert.NotNil(a)
+
+	a.record("mount", map[string]string{"source": "/a", "destination": "/b"}, nil)
+	a.record("umount", map[string]string{"target": "/b"}, errors.New("busy"))
+
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(auditLogMode), info.Mode().Perm())
+
+	records := readAuditRecords(t, path)
+	assert.Len(records, 2)
+
+	assert.Equal(testSandboxID, records[0].Sandbox)
+	assert.Equal("mount", records[0].Operation)
+	assert.Equal("/a", records[0].Args["source"])
+	assert.Equal("success", records[0].Result)
+	assert.Empty(records[0].Error)
+	assert.False(records[0].Time.IsZero())
+
+	assert.Equal("umount", records[1].Operation)
+	assert.Equal("failed", records[1].Result)
+	assert.Equal("busy", records[1].Error)
+
+	// The records are appended to an existing log.
+	newAuditLog(s, AuditConfig{Path: path}).record("resize-vcpus", nil, nil)
+	assert.Len(readAuditRecords(t, path), 3)
+}
+
+func TestAuditdMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	msg := auditdMessage(auditRecord{
+		Sandbox:   "foo",
+		Operation: "mount",
+		Args: map[string]string{
+			"source":      "/with space",
+			"destination": "/b",
+		},
+		Result: "success",
+	})
+	assert.Equal(`op=mount sandbox=foo destination="/b" source="/with space" res=success`, msg)
+
+	msg = auditdMessage(auditRecord{
+		Sandbox:   "foo",
+		Operation: "umount",
+		Result:    "failed",
+		Error:     "device busy",
+	})
+	assert.Equal(`op=umount sandbox=foo error="device busy" res=failed`, msg)
+}
+
+func TestSandboxHotplugAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{},
+		config:     &SandboxConfig{},
+		ctx:        context.Background(),
+	}
+	s.audit = newAuditLog(s, AuditConfig{Path: path})
+
+	drive := &config.BlockDrive{
+		File: "/dev/loop0",
+		ID:   "drive-1",
+	}
+	device := &drivers.BlockDevice{BlockDrive: drive}
+
+	assert.NoError(s.HotplugAddDevice(device, config.DeviceBlock))
+	assert.NoError(s.HotplugRemoveDevice(device, config.DeviceBlock))
+
+	records := readAuditRecords(t, path)
+	assert.Len(records, 2)
+	assert.Equal("hotplug-device", records[0].Operation)
+	assert.Equal("hotunplug-device", records[1].Operation)
+	for _, r := range records {
+		assert.Equal("/dev/loop0", r.Args["file"])
+		assert.Equal("block", r.Args["type"])
+	}
+}
@@ -138,6 +138,16 @@ func parentCgroup(hierarchy cgroups.Hierarchy, path string) (cgroups.Cgroup, err
 	return parentCgroup, nil
 }
 
+const (
+	// defaultCPUPeriod is the CFS period in microseconds of the default
+	// constraint of sandboxes without cpu constraints.
+	defaultCPUPeriod = 100000
+
+	// minCPUQuota is the smallest CFS quota in microseconds the kernel
+	// accepts.
+	minCPUQuota = 1000
+)
+
 func (s *Sandbox) updateCgroups() error {
 	if s.state.CgroupPath == "" {
 		s.Logger().Warn("sandbox's cgroup won't be updated: cgroup path is empty")
@@ -155,7 +165,7 @@ func (s *Sandbox) updateCgroups() error {
 
 	if len(s.containers) <= 1 {
 		// nothing to update
-		return nil
+		return s.placeVMMThreads()
 	}
 
 	resources, err := s.resources()
@@ -167,7 +177,13 @@ func (s *Sandbox) updateCgroups() error {
 		return fmt.Errorf("Could not update cgroup %v: %v", s.state.CgroupPath, err)
 	}
 
-	return nil
+	// Moving the vCPU threads to the cgroup and updating its cpuset reset
+	// their CPU affinity.
+	if err := s.placeVMMThreads(); err != nil {
+		return err
+	}
+
+	return s.pinVCPUs()
 }
 
 func (s *Sandbox) deleteCgroups() error {
@@ -319,8 +335,12 @@ func (s *Sandbox) cpuResources() *specs.LinuxCPU {
 	// use a default constraint for sandboxes without cpu constraints
 	if period == uint64(0) && quota == int64(0) {
 		// set a quota and period equal to the default number of vcpus
-		quota = int64(s.config.HypervisorConfig.NumVCPUs) * 100000
-		period = 100000
+		vcpus := float64(s.config.HypervisorConfig.NumVCPUs)
+		if s.config.HypervisorConfig.DefaultVCPUs > 0 {
+			vcpus = float64(s.config.HypervisorConfig.DefaultVCPUs)
+		}
+		quota = int64(math.Max(vcpus*defaultCPUPeriod, minCPUQuota))
+		period = defaultCPUPeriod
 	}
 
 	return validCPUResources(cpu)
@@ -191,3 +191,34 @@ func TestUpdateCgroups(t *testing.T) {
 	err = s.deleteCgroups()
 	assert.NoError(err)
 }
+
+func TestCPUResourcesDefaultVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		config: &SandboxConfig{},
+		containers: map[string]*Container{
+			"abc": {
+				config: &ContainerConfig{
+					Annotations: containerAnnotations,
+				},
+			},
+		},
+	}
+	s.config.HypervisorConfig.NumVCPUs = 2
+
+	cpu := s.cpuResources()
+	assert.Equal(int64(200000), *cpu.Quota)
+	assert.Equal(uint64(defaultCPUPeriod), *cpu.Period)
+
+	// a fraction of a vCPU
+	s.config.HypervisorConfig.NumVCPUs = 1
+	s.config.HypervisorConfig.DefaultVCPUs = 0.25
+	cpu = s.cpuResources()
+	assert.Equal(int64(25000), *cpu.Quota)
+
+	// the quota is not lower than the kernel minimum
+	s.config.HypervisorConfig.DefaultVCPUs = 0.001
+	cpu = s.cpuResources()
+	assert.Equal(int64(minCPUQuota), *cpu.Quota)
+}
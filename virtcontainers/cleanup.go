@@ -0,0 +1,358 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/kata-containers/runtime/virtcontainers/utils"
+	"golang.org/x/sys/unix"
+)
+
+// CleanupSandbox is the virtcontainers entry point tearing down a sandbox
+// left behind, eg. by a runtime or a shim that died while deleting it.
+//
+// The sandbox is first stopped and deleted the regular way. When force is
+// set and this fails, everything the sandbox holds on the host is torn
+// down without the help of the agent: the hypervisor is killed, the shared
+// directory is unmounted, the volumes passed to the VM as block devices are
+// mounted again on the host, the network namespace and the sandbox storage
+// are removed. The errors of the forced steps are logged and the cleanup goes
+// on, only the first one is returned.
+func CleanupSandbox(ctx context.Context, sandboxID string, force bool) error {
+	span, ctx := trace(ctx, "CleanupSandbox")
+	defer span.Finish()
+
+	if sandboxID == "" {
+		return errNeedSandboxID
+	}
+
+	lockFile, err := rwLockSandbox(ctx, sandboxID)
+	if err != nil {
+		if !force {
+			return err
+		}
+		virtLog.WithError(err).WithField("sandbox", sandboxID).Warn("Could not lock the sandbox, cleaning it up anyway")
+	} else {
+		defer unlockSandbox(ctx, sandboxID, lockFile)
+	}
+
+	s, err := fetchSandbox(ctx, sandboxID)
+	if err == nil {
+		if err = s.stopAndDelete(); err == nil {
+			s.Release()
+			return nil
+		}
+	}
+
+	if !force {
+		return err
+	}
+
+	virtLog.WithError(err).WithField("sandbox", sandboxID).Warn("Could not delete the sandbox, forcing its cleanup")
+
+	return forceCleanupSandbox(ctx, sandboxID, s)
+}
+
+func (s *Sandbox) stopAndDelete() error {
+	if s.state.State != types.StateStopped {
+		if err := s.Stop(); err != nil {
+			return err
+		}
+	}
+
+	return s.Delete()
+}
+
+// forceCleanupSandbox removes what a sandbox holds on the host, from the
+// sandbox if it could be fetched, from its storage otherwise.
+func forceCleanupSandbox(ctx context.Context, sandboxID string, s *Sandbox) error {
+	logger := virtLog.WithField("sandbox", sandboxID)
+
+	var firstErr error
+	step := func(name string, err error) {
+		if err == nil {
+			return
+		}
+		logger.WithError(err).Warnf("Could not %s", name)
+		if firstErr == nil {
+			firstErr = fmt.Errorf("Could not %s: %v", name, err)
+		}
+	}
+
+	var networkNS NetworkNamespace
+	pid := 0
+
+	if s != nil {
+		if q, ok := s.hypervisor.(*qemu); ok {
+			pid = hypervisorPidFromFile(q.pidFile())
+		} else {
+			pid = s.hypervisor.pid()
+		}
+		networkNS = s.networkNS
+		globalSandboxList.removeSandbox(sandboxID)
+		if s.monitor != nil {
+			s.monitor.stop()
+		}
+	} else {
+		pid = hypervisorPidFromFile(filepath.Join(store.RunVMStoragePath, sandboxID, "pid"))
+		if vcStore, err := store.NewVCSandboxStore(ctx, sandboxID); err == nil {
+			if err := vcStore.Load(store.Network, &networkNS); err != nil && !os.IsNotExist(err) {
+				step("load the sandbox network", err)
+			}
+		}
+	}
+
+	step("kill the hypervisor", killProcess(pid))
+	step("stop the network monitor", stopNetmon(networkNS.NetmonPID))
+	// The guest is gone, the volumes passed to it as block devices can be
+	// mounted on the host again.
+	step("restore the volume host mounts", restoreVolumesHostMounts(ctx, sandboxID, s))
+
+	sharedDir := filepath.Join(kataHostSharedDir, sandboxID)
+	// Removing the directory with something still mounted below would
+	// remove the files of the container volumes.
+	if err := unmountAllUnder(sharedDir); err != nil {
+		step("unmount the shared directory", err)
+	} else {
+		step("remove the shared directory", os.RemoveAll(sharedDir))
+	}
+
+	if s != nil {
+		// The VM is gone, the endpoints are detached on the host side
+		// only.
+		step("remove the network", s.network.Remove(ctx, &networkNS, s.hypervisor, false))
+		step("delete the cgroups", s.deleteCgroups())
+		step("clean the hypervisor up", s.hypervisor.cleanup())
+		s.agent.cleanup(sandboxID)
+	} else if networkNS.NetNsCreated && networkNS.NetNsPath != "" {
+		step("delete the network namespace", deleteNetNS(networkNS.NetNsPath))
+	}
+
+	for _, dir := range []string{
+		store.SandboxConfigurationRootPath(sandboxID),
+		store.SandboxRuntimeRootPath(sandboxID),
+		filepath.Join(store.RunVMStoragePath, sandboxID),
+	} {
+		step("remove "+dir, os.RemoveAll(dir))
+	}
+
+	if s != nil {
+		s.audit.record("force-cleanup", map[string]string{
+			"hypervisor-pid": strconv.Itoa(pid),
+		}, firstErr)
+	}
+
+	if firstErr == nil {
+		logger.Info("Sandbox cleaned up")
+	}
+
+	return firstErr
+}
+
+// restoreVolumesHostMounts restores the host mounts of the volumes of the
+// sandbox containers passed to the VM as block devices, from the sandbox if
+// it could be fetched, from the stored container mounts otherwise.
+func restoreVolumesHostMounts(ctx context.Context, sandboxID string, s *Sandbox) error {
+	var mounts []Mount
+
+	if s != nil {
+		for _, c := range s.containers {
+			mounts = append(mounts, c.mounts...)
+		}
+	} else {
+		entries, err := ioutil.ReadDir(store.SandboxRuntimeRootPath(sandboxID))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+
+			ctrStore, err := store.NewVCContainerStore(ctx, sandboxID, e.Name())
+			if err != nil {
+				return err
+			}
+
+			var ctrMounts []Mount
+			if err := ctrStore.Load(store.Mounts, &ctrMounts); err == nil {
+				mounts = append(mounts, ctrMounts...)
+			}
+		}
+	}
+
+	var firstErr error
+	// The containers passing the same volume share its host mounts.
+	restored := make(map[string]bool)
+	for _, m := range mounts {
+		if len(m.BlockDeviceHostMounts) == 0 || restored[m.BlockDeviceHostMounts[0].MountPoint] {
+			continue
+		}
+		restored[m.BlockDeviceHostMounts[0].MountPoint] = true
+
+		if err := restoreLostHostMounts(m.BlockDeviceHostMounts); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// hypervisorPidFromFile returns the pid qemu wrote to pidFile, 0 if it is
+// unknown. The file outlives qemu and its pid may be reused since, so the pid
+// is only returned while it is still the one of the qemu which wrote it.
+func hypervisorPidFromFile(pidFile string) int {
+	info, err := os.Stat(pidFile)
+	if err != nil {
+		return 0
+	}
+
+	data, err := ioutil.ReadFile(pidFile)
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+
+	if err := checkHypervisorProcess(pid, pidFile, info.ModTime()); err != nil {
+		virtLog.WithError(err).WithField("pid-file", pidFile).Warn("Not killing the process of the stale pid file")
+		return 0
+	}
+
+	return pid
+}
+
+// checkHypervisorProcess makes sure pid is the hypervisor which wrote its
+// pid to pidFile at written time: it was given pidFile on its command line,
+// and was started before the file was written.
+func checkHypervisorProcess(pid int, pidFile string, written time.Time) error {
+	proc, err := utils.NewProc(pid)
+	if err != nil {
+		return err
+	}
+
+	cmdline, err := proc.CmdLine()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := 0; i+1 < len(cmdline); i++ {
+		if cmdline[i] == "-pidfile" && cmdline[i+1] == pidFile {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("Process %d is not the hypervisor of %s", pid, pidFile)
+	}
+
+	stat, err := proc.NewStat()
+	if err != nil {
+		return err
+	}
+
+	start, err := stat.StartTime()
+	if err != nil {
+		return err
+	}
+
+	// The start time is rounded to the boot time second.
+	if time.Unix(0, int64(start*float64(time.Second))).After(written.Add(time.Second)) {
+		return fmt.Errorf("Process %d was started after %s was written", pid, pidFile)
+	}
+
+	return nil
+}
+
+func killProcess(pid int) error {
+	if pid <= 0 {
+		return nil
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return err
+	}
+
+	return nil
+}
+
+// mountsUnder returns the mount points at or below dir, the deepest first.
+func mountsUnder(dir string) ([]string, error) {
+	file, err := os.Open(procMountInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dir = filepath.Clean(dir)
+
+	var mounts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) <= mountInfoMountPointIndex {
+			continue
+		}
+
+		mountPoint := unescapeMountInfo(fields[mountInfoMountPointIndex])
+		if mountPoint == dir || strings.HasPrefix(mountPoint, dir+"/") {
+			mounts = append(mounts, mountPoint)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// A mount point shows up once per stacked mount, each unmount of it
+	// removes the top one.
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return strings.Count(mounts[i], "/") > strings.Count(mounts[j], "/")
+	})
+
+	return mounts, nil
+}
+
+// unmountAllUnder unmounts everything mounted below dir. A mount still busy,
+// eg. held by a leftover process, is lazily detached.
+func unmountAllUnder(dir string) error {
+	mounts, err := mountsUnder(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounts {
+		err := unix.Unmount(m, 0)
+		if err == nil || err == unix.EINVAL || err == unix.ENOENT {
+			continue
+		}
+
+		virtLog.WithError(err).WithField("mount", m).Warn("Could not unmount, detaching it")
+		if err := unix.Unmount(m, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("Could not detach %s: %v", m, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountsUnder(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := ioutil.TempFile("", "mountinfo")
+	assert.NoError(err)
+	defer os.Remove(f.Name())
+
+	mountInfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+40 22 8:1 /a /run/shared/sb rw,relatime - ext4 /dev/sda1 rw
+41 40 8:1 /b /run/shared/sb/c1/rootfs rw,relatime - ext4 /dev/sda1 rw
+42 40 8:1 /c /run/shared/sb/c1-vol rw,relatime - ext4 /dev/sda1 rw
+43 40 8:1 /d /run/shared/sb/c1/rootfs rw,relatime - ext4 /dev/sda1 rw
+44 22 8:1 /e /run/shared/sb2 rw,relatime - ext4 /dev/sda1 rw
+45 40 8:1 /f /run/shared/sb/with\040space rw,relatime - ext4 /dev/sda1 rw
+`
+	_, err = f.WriteString(mountInfo)
+	assert.NoError(err)
+	f.Close()
+
+	savedMountInfo := procMountInfoFile
+	procMountInfoFile = f.Name()
+	defer func() {
+		procMountInfoFile = savedMountInfo
+	}()
+
+	mounts, err := mountsUnder("/run/shared/sb/")
+	assert.NoError(err)
+	assert.Equal([]string{
+		"/run/shared/sb/c1/rootfs",
+		"/run/shared/sb/c1/rootfs",
+		"/run/shared/sb/c1-vol",
+		"/run/shared/sb/with space",
+		"/run/shared/sb",
+	}, mounts)
+
+	mounts, err = mountsUnder("/run/none")
+	assert.NoError(err)
+	assert.Empty(mounts)
+}
+
+func TestUnmountAllUnder(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cleanup")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	nested := filepath.Join(dir, "a", "b")
+	assert.NoError(os.MkdirAll(nested, 0755))
+	assert.NoError(syscall.Mount("tmpfs", filepath.Join(dir, "a"), "tmpfs", 0, ""))
+	assert.NoError(os.MkdirAll(nested, 0755))
+	assert.NoError(syscall.Mount("tmpfs", nested, "tmpfs", 0, ""))
+
+	// A busy mount is detached.
+	busy, err := os.Create(filepath.Join(nested, "busy"))
+	assert.NoError(err)
+	defer busy.Close()
+
+	assert.NoError(unmountAllUnder(dir))
+
+	mounts, err := mountsUnder(dir)
+	assert.NoError(err)
+	assert.Empty(mounts)
+}
+
+func TestCleanupSandbox(t *testing.T) {
+	defer cleanUp()
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	assert.Error(CleanupSandbox(ctx, "", true))
+
+	p, err := CreateSandbox(ctx, newTestSandboxConfigNoop(), nil)
+	assert.NoError(err)
+
+	configDir := store.SandboxConfigurationRootPath(p.ID())
+	_, err = os.Stat(configDir)
+	assert.NoError(err)
+
+	assert.NoError(CleanupSandbox(ctx, p.ID(), false))
+	_, err = os.Stat(configDir)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestCleanupSandboxForce(t *testing.T) {
+	defer cleanUp()
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// The storage of a sandbox that cannot be fetched anymore.
+	id := "wedged-sandbox"
+	configDir := store.SandboxConfigurationRootPath(id)
+	runDir := store.SandboxRuntimeRootPath(id)
+	for _, dir := range []string{configDir, runDir} {
+		assert.NoError(os.MkdirAll(dir, store.DirMode))
+	}
+	assert.NoError(ioutil.WriteFile(filepath.Join(configDir, store.ConfigurationFile), []byte("{"), 0600))
+
+	assert.Error(CleanupSandbox(ctx, id, false))
+	_, err := os.Stat(configDir)
+	assert.NoError(err)
+
+	assert.NoError(CleanupSandbox(ctx, id, true))
+	for _, dir := range []string{configDir, runDir} {
+		_, err = os.Stat(dir)
+		assert.True(os.IsNotExist(err), dir)
+	}
+}
+
+func TestCleanupSandboxForceRestoresVolumeHostMounts(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	defer cleanUp()
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "cleanup")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// A sandbox whose runtime died while a container volume was
+	// unmounted from the host and passed to the guest.
+	id := "wedged-sandbox"
+	configDir := store.SandboxConfigurationRootPath(id)
+	assert.NoError(os.MkdirAll(configDir, store.DirMode))
+	assert.NoError(ioutil.WriteFile(filepath.Join(configDir, store.ConfigurationFile), []byte("{"), 0600))
+
+	hostMounts := []HostMount{{Device: "tmpfs", Fstype: "tmpfs", MountPoint: dir, Root: "/"}}
+	for _, cid := range []string{"c1", "c2"} {
+		ctrStore, err := store.NewVCContainerStore(ctx, id, cid)
+		assert.NoError(err)
+		assert.NoError(ctrStore.Store(store.Mounts, []Mount{{
+			Source:                dir,
+			Destination:           "/data",
+			Type:                  "bind",
+			BlockDeviceID:         "drive-1",
+			BlockDeviceHostMounts: hostMounts,
+		}}))
+	}
+
+	assert.NoError(CleanupSandbox(ctx, id, true))
+	defer unmountAllUnder(dir)
+
+	// mounted once, the containers share the volume
+	mounts, err := mountsUnder(dir)
+	assert.NoError(err)
+	assert.Equal([]string{dir}, mounts)
+
+	// a host mount already restored is left alone
+	assert.NoError(restoreLostHostMounts(hostMounts))
+	mounts, err = mountsUnder(dir)
+	assert.NoError(err)
+	assert.Equal([]string{dir}, mounts)
+}
+
+func TestHypervisorPidFromFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	pidFile := filepath.Join(dir, "pid")
+	assert.Zero(hypervisorPidFromFile(pidFile))
+
+	// A process given the pid file on its command line, as qemu is.
+	cmd := exec.Command("sh", "-c", "sleep 10; exit 0", "-pidfile", pidFile)
+	assert.NoError(cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+	pid := cmd.Process.Pid
+
+	assert.NoError(ioutil.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0600))
+	assert.Equal(pid, hypervisorPidFromFile(pidFile))
+
+	// The pid was reused by a process started after the file was written.
+	old := time.Now().Add(-time.Hour)
+	assert.NoError(os.Chtimes(pidFile, old, old))
+	assert.Zero(hypervisorPidFromFile(pidFile))
+
+	// The pid is not the one of the hypervisor.
+	assert.NoError(ioutil.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0600))
+	assert.Zero(hypervisorPidFromFile(pidFile))
+}
@@ -189,9 +189,42 @@ type CgroupStats struct {
 	HugetlbStats map[string]HugetlbStats `json:"hugetlb_stats,omitempty"`
 }
 
+// NetworkStats describes the traffic stats of a network interface.
+type NetworkStats struct {
+	Name      string `json:"name"`
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+// HypervisorStats describes the host resources used by the hypervisor of a
+// sandbox.
+type HypervisorStats struct {
+	// RSS is the resident memory of the hypervisor process, in bytes.
+	RSS uint64 `json:"rss"`
+	// CPUTime is the host CPU time of the hypervisor process, including
+	// its vCPU threads, in nanoseconds.
+	CPUTime uint64 `json:"cpu_time"`
+	// VCPUTime is the host CPU time of the vCPU threads, in nanoseconds.
+	VCPUTime uint64 `json:"vcpu_time"`
+}
+
 // ContainerStats describes a container stats.
 type ContainerStats struct {
 	CgroupStats *CgroupStats
+
+	// NetworkStats are the stats of the sandbox network interfaces,
+	// shared by all the containers of the sandbox.
+	NetworkStats []*NetworkStats
+
+	// HypervisorStats are the stats of the sandbox hypervisor, only
+	// reported for the sandbox container.
+	HypervisorStats *HypervisorStats
 }
 
 // ContainerResources describes container resources
@@ -305,6 +338,8 @@ type Container struct {
 	ctx context.Context
 
 	store *store.VCStore
+
+	portForwarder *portForwarder
 }
 
 // ID returns the container identifier string.
@@ -472,7 +507,13 @@ func (c *Container) shareFiles(m Mount, idx int, hostSharedDir, guestSharedDir s
 	} else {
 		// These mounts are created in the shared dir
 		mountDest := filepath.Join(hostSharedDir, c.sandbox.id, filename)
-		if err := bindMount(c.ctx, m.Source, mountDest, false); err != nil {
+		err := bindMountVolume(c.ctx, m.Source, mountDest)
+		c.sandbox.audit.record("mount", map[string]string{
+			"container":   c.id,
+			"source":      m.Source,
+			"destination": mountDest,
+		}, err)
+		if err != nil {
 			return "", false, err
 		}
 		// Save HostPath mount value into the mount list of the container.
@@ -562,6 +603,29 @@ func (c *Container) mountSharedDirMounts(hostSharedDir, guestSharedDir string) (
 	return sharedDirMounts, ignoredMounts, nil
 }
 
+// bindMountRootfs bind mounts the container rootfs into the directory shared
+// with the VM.
+func (c *Container) bindMountRootfs(sharedDir string) error {
+	err := bindMountContainerRootfs(c.ctx, sharedDir, c.sandbox.id, c.id, c.rootFs.Target, false)
+	c.sandbox.audit.record("mount", map[string]string{
+		"container":   c.id,
+		"source":      c.rootFs.Target,
+		"destination": filepath.Join(sharedDir, c.sandbox.id, c.id, rootfsDir),
+	}, err)
+
+	return err
+}
+
+func (c *Container) bindUnmountRootfs(sharedDir string) error {
+	err := bindUnmountContainerRootfs(c.ctx, sharedDir, c.sandbox.id, c.id)
+	c.sandbox.audit.record("umount", map[string]string{
+		"container": c.id,
+		"target":    filepath.Join(sharedDir, c.sandbox.id, c.id, rootfsDir),
+	}, err)
+
+	return err
+}
+
 func (c *Container) unmountHostMounts() error {
 	var span opentracing.Span
 	span, c.ctx = c.trace("unmountHostMounts")
@@ -572,7 +636,12 @@ func (c *Container) unmountHostMounts() error {
 			span, _ := c.trace("unmount")
 			span.SetTag("host-path", m.HostPath)
 
-			if err := syscall.Unmount(m.HostPath, syscall.MNT_DETACH); err != nil {
+			err := syscall.Unmount(m.HostPath, syscall.MNT_DETACH)
+			c.sandbox.audit.record("umount", map[string]string{
+				"container": c.id,
+				"target":    m.HostPath,
+			}, err)
+			if err != nil {
 				c.Logger().WithFields(logrus.Fields{
 					"host-path": m.HostPath,
 					"error":     err,
@@ -601,7 +670,112 @@ func filterDevices(sandbox *Sandbox, c *Container, devices []ContainerDevice) (r
 	return
 }
 
-func (c *Container) createBlockDevices() error {
+// privilegedSpec tells whether a spec is the one of a privileged container.
+// There is no such flag in the OCI spec, the engines give the privileged
+// containers CAP_SYS_ADMIN without any masked or read-only path.
+func privilegedSpec(spec *specs.Spec) bool {
+	if spec.Linux == nil || spec.Process == nil || spec.Process.Capabilities == nil {
+		return false
+	}
+
+	if len(spec.Linux.MaskedPaths) > 0 || len(spec.Linux.ReadonlyPaths) > 0 {
+		return false
+	}
+
+	for _, c := range spec.Process.Capabilities.Bounding {
+		if c == "CAP_SYS_ADMIN" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hostDevEnumeration returns the container paths of the devices the engine
+// listed from the host /dev for a privileged container, the workload did
+// not ask for them. They are listed at their host path, with the device
+// number of the host node. The devices requested on top of them, eg. with
+// --device or by a device plugin, are either mapped to another path or
+// listed a second time when requested at their host path, they are kept.
+func hostDevEnumeration(devices []specs.LinuxDevice) map[string]bool {
+	listed := make(map[string]int)
+	for _, d := range devices {
+		listed[d.Path]++
+	}
+
+	enumerated := make(map[string]bool)
+	for _, d := range devices {
+		if listed[d.Path] > 1 {
+			continue
+		}
+
+		var st unix.Stat_t
+		if err := unix.Stat(d.Path, &st); err != nil {
+			continue
+		}
+
+		var mode uint32
+		switch d.Type {
+		case "c", "u":
+			mode = unix.S_IFCHR
+		case "b":
+			mode = unix.S_IFBLK
+		case "p":
+			mode = unix.S_IFIFO
+		}
+
+		if st.Mode&unix.S_IFMT != mode {
+			continue
+		}
+
+		if mode != unix.S_IFIFO && (int64(unix.Major(uint64(st.Rdev))) != d.Major || int64(unix.Minor(uint64(st.Rdev))) != d.Minor) {
+			continue
+		}
+
+		enumerated[d.Path] = true
+	}
+
+	return enumerated
+}
+
+// withoutHostDevices returns the container paths of the host devices of the
+// spec of a privileged container which are left out, see
+// hostDevEnumeration.
+func withoutHostDevices(sandbox *Sandbox, spec *specs.Spec) map[string]bool {
+	if !sandbox.config.PrivilegedWithoutHostDevices || !privilegedSpec(spec) {
+		return nil
+	}
+
+	return hostDevEnumeration(spec.Linux.Devices)
+}
+
+// withoutHostDevices returns the container paths of the host devices of the
+// container which are left out.
+func (c *Container) withoutHostDevices() map[string]bool {
+	ociSpecJSON, ok := c.config.Annotations[annotations.ConfigJSONKey]
+	if !ok {
+		return nil
+	}
+
+	var spec specs.Spec
+	if err := json.Unmarshal([]byte(ociSpecJSON), &spec); err != nil {
+		return nil
+	}
+
+	return withoutHostDevices(c.sandbox, &spec)
+}
+
+func (c *Container) createBlockDevices() (err error) {
+	var created []int
+
+	// Do not leave behind the devices of the volumes handled before the
+	// failing one.
+	defer func() {
+		if err != nil {
+			c.removeVolumeDevices(created)
+		}
+	}()
+
 	// iterate all mounts and create block device if it's block based.
 	for i, m := range c.mounts {
 		if len(m.BlockDeviceID) > 0 || m.Type != "bind" {
@@ -611,32 +785,306 @@ func (c *Container) createBlockDevices() error {
 			continue
 		}
 
-		var stat unix.Stat_t
-		if err := unix.Stat(m.Source, &stat); err != nil {
-			return fmt.Errorf("stat %q failed: %v", m.Source, err)
+		if err := c.createMountDevice(i); err != nil {
+			return err
 		}
 
-		// Check if mount is a block device file. If it is, the block device will be attached to the host
-		// instead of passing this as a shared mount.
-		if c.checkBlockDeviceSupport() && stat.Mode&unix.S_IFBLK == unix.S_IFBLK {
+		if c.mounts[i].BlockDeviceID != "" {
+			created = append(created, i)
+		}
+	}
+
+	return nil
+}
+
+// createMountDevice creates the block device of a bind mount, when the block
+// device, the block device backing the volume or the disk image it points to
+// has to be attached to the VM rather than shared through the shared
+// directory.
+func (c *Container) createMountDevice(i int) error {
+	m := c.mounts[i]
+
+	var stat unix.Stat_t
+	if err := c.statVolumeSource(m.Source, &stat); err != nil {
+		return fmt.Errorf("stat %q failed: %v", m.Source, err)
+	}
+
+	// Check if mount is a block device file. If it is, the block device will be attached to the host
+	// instead of passing this as a shared mount.
+	if c.checkBlockDeviceSupport() && stat.Mode&unix.S_IFBLK == unix.S_IFBLK {
+		b, err := c.sandbox.devManager.NewDevice(config.DeviceInfo{
+			HostPath:      m.Source,
+			ContainerPath: m.Destination,
+			DevType:       "b",
+			Major:         int64(unix.Major(stat.Rdev)),
+			Minor:         int64(unix.Minor(stat.Rdev)),
+		})
+		if err != nil {
+			return fmt.Errorf("device manager failed to create new device for %q: %v", m.Source, err)
+		}
+
+		c.mounts[i].BlockDeviceID = b.DeviceID()
+		return nil
+	}
+
+	if stat.Mode&unix.S_IFMT == unix.S_IFDIR && c.volumePassthrough() {
+		return c.createVolumeDevice(i)
+	}
+
+	if stat.Mode&unix.S_IFMT == unix.S_IFREG && c.imageVolumePassthrough() {
+		return c.createImageVolumeDevice(i)
+	}
+
+	return nil
+}
+
+// volumeSourcePollInterval is the interval between the checks of a missing
+// volume source.
+var volumeSourcePollInterval = 100 * time.Millisecond
+
+// statVolumeSource stats the source of a container mount. CSI ephemeral
+// volumes are published by the kubelet concurrently with the container
+// creation, so a missing source is polled for up to the sandbox
+// VolumeSourceTimeout before giving up.
+func (c *Container) statVolumeSource(source string, stat *unix.Stat_t) error {
+	deadline := time.Now().Add(time.Duration(c.sandbox.config.VolumeSourceTimeout) * time.Second)
+
+	for {
+		err := unix.Stat(source, stat)
+		if err != unix.ENOENT || !time.Now().Before(deadline) {
+			return err
+		}
+
+		c.Logger().WithField("source", source).Debug("waiting for the volume source")
+		time.Sleep(volumeSourcePollInterval)
+	}
+}
+
+// removeVolumeDevices removes the devices created for the mounts at the
+// indexes, none of them being attached yet.
+func (c *Container) removeVolumeDevices(indexes []int) {
+	for _, i := range indexes {
+		id := c.mounts[i].BlockDeviceID
+		if err := c.sandbox.devManager.RemoveDevice(id); err != nil && err != manager.ErrDeviceNotExist {
+			c.Logger().WithField("device-id", id).WithError(err).Error("remove device failed")
+		}
+		c.restoreVolumeHostMounts(i)
+		c.mounts[i].BlockDeviceID = ""
+		c.mounts[i].BlockDeviceFstype = ""
+	}
+}
+
+func (c *Container) imageVolumePassthrough() bool {
+	return c.sandbox.config.HypervisorConfig.ImageVolumePassthrough && c.checkBlockDeviceSupport()
+}
+
+// createImageVolumeDevice creates the block device of a volume whose source
+// is a disk image file, so that the image is attached to the VM as a drive
+// rather than through a host loop device.
+func (c *Container) createImageVolumeDevice(index int) error {
+	m := c.mounts[index]
+
+	format, err := diskImageFormat(m.Source)
+	if err != nil {
+		return fmt.Errorf("failed to read the volume %q: %v", m.Source, err)
+	}
+
+	if format == "" {
+		return nil
+	}
+
+	// A qcow2 header names the backing file of the image, which could be
+	// any host file: only raw images are attached.
+	if format != diskImageRaw {
+		c.Logger().WithField("volume", m.Source).Warnf("%s disk image cannot be attached, sharing it", format)
+		return nil
+	}
+
+	b, err := c.sandbox.devManager.NewDevice(config.DeviceInfo{
+		HostPath:      m.Source,
+		ContainerPath: m.Destination,
+		DevType:       "b",
+		DriverOptions: map[string]string{config.ImageFormatOption: format},
+	})
+	if err != nil {
+		return fmt.Errorf("device manager failed to create new device for %q: %v", m.Source, err)
+	}
+
+	c.Logger().WithFields(logrus.Fields{
+		"volume": m.Source,
+		"format": format,
+	}).Info("passing volume as a block device")
+
+	c.mounts[index].BlockDeviceID = b.DeviceID()
+
+	return nil
+}
+
+func (c *Container) volumePassthrough() bool {
+	conf := c.sandbox.config.HypervisorConfig
+	return (conf.RBDVolumePassthrough || conf.ISCSIVolumePassthrough) && c.checkBlockDeviceSupport()
+}
+
+// sharedVolumeDevice returns the mount of another container of the sandbox
+// passing the volume at source as a block device, nil if there is none.
+func (c *Container) sharedVolumeDevice(source string) *Mount {
+	for _, other := range c.sandbox.containers {
+		if other == c {
+			continue
+		}
+
+		for i, m := range other.mounts {
+			if m.Source == source && m.BlockDeviceID != "" && len(m.BlockDeviceHostMounts) > 0 {
+				return &other.mounts[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// createVolumeDevice creates the block device of a volume backed by a kernel
+// RBD device or an iSCSI LUN, so that the guest mounts the device itself
+// instead of going through the shared filesystem. The filesystem is
+// unmounted from the host as long as the guest mounts it.
+func (c *Container) createVolumeDevice(index int) error {
+	m := c.mounts[index]
+	conf := c.sandbox.config.HypervisorConfig
+
+	// The volume is already unmounted from the host if another container
+	// passes it.
+	if shared := c.sharedVolumeDevice(m.Source); shared != nil {
+		if d := c.sandbox.devManager.GetDeviceByID(shared.BlockDeviceID); d != nil {
+			major, minor := d.GetMajorMinor()
 			b, err := c.sandbox.devManager.NewDevice(config.DeviceInfo{
-				HostPath:      m.Source,
 				ContainerPath: m.Destination,
 				DevType:       "b",
-				Major:         int64(unix.Major(stat.Rdev)),
-				Minor:         int64(unix.Minor(stat.Rdev)),
+				Major:         major,
+				Minor:         minor,
 			})
 			if err != nil {
 				return fmt.Errorf("device manager failed to create new device for %q: %v", m.Source, err)
 			}
 
-			c.mounts[i].BlockDeviceID = b.DeviceID()
+			c.mounts[index].BlockDeviceID = b.DeviceID()
+			c.mounts[index].BlockDeviceFstype = shared.BlockDeviceFstype
+			c.mounts[index].BlockDeviceHostMounts = shared.BlockDeviceHostMounts
+
+			return c.storeVolumeHostMounts(index)
 		}
 	}
 
+	dev, devicePath, fsType, err := volumeBackingDevice(m.Source)
+	if err != nil {
+		return fmt.Errorf("failed to find the device backing %q: %v", m.Source, err)
+	}
+
+	if devicePath == "" {
+		return nil
+	}
+
+	var backing string
+
+	if conf.RBDVolumePassthrough {
+		isRBD, err := checkRBDDevice(dev.major, dev.minor)
+		if err != nil {
+			return err
+		}
+		if isRBD {
+			backing = "RBD"
+		}
+	}
+
+	if backing == "" && conf.ISCSIVolumePassthrough {
+		isISCSI, err := checkISCSIDevice(dev.major, dev.minor)
+		if err != nil {
+			return err
+		}
+		if isISCSI {
+			backing = "iSCSI"
+		}
+	}
+
+	if backing == "" {
+		return nil
+	}
+
+	b, err := c.sandbox.devManager.NewDevice(config.DeviceInfo{
+		HostPath:      devicePath,
+		ContainerPath: m.Destination,
+		DevType:       "b",
+		Major:         int64(dev.major),
+		Minor:         int64(dev.minor),
+	})
+	if err != nil {
+		return fmt.Errorf("device manager failed to create new device for %q: %v", devicePath, err)
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"volume":  m.Source,
+		"device":  devicePath,
+		"backing": backing,
+	})
+
+	hostMounts, err := deviceHostMounts(dev.major, dev.minor)
+	if err == nil {
+		err = unmountHostMounts(hostMounts)
+	}
+	if err != nil {
+		// The filesystem is in use on the host, the guest cannot
+		// mount it as well.
+		logger.WithError(err).Warn("cannot unmount the volume from the host, sharing it")
+		if err := c.sandbox.devManager.RemoveDevice(b.DeviceID()); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	logger.Info("passing volume as a block device")
+
+	c.mounts[index].BlockDeviceID = b.DeviceID()
+	c.mounts[index].BlockDeviceFstype = fsType
+	c.mounts[index].BlockDeviceHostMounts = hostMounts
+
+	return c.storeVolumeHostMounts(index)
+}
+
+// storeVolumeHostMounts stores the container mounts as soon as the host
+// mounts of the volume at index are unmounted, so that a forced cleanup of
+// the sandbox restores them if the runtime dies before the container is
+// created. The host mounts are restored right away if they cannot be
+// stored.
+func (c *Container) storeVolumeHostMounts(index int) error {
+	if err := c.storeMounts(); err != nil {
+		c.removeVolumeDevices([]int{index})
+		return err
+	}
+
 	return nil
 }
 
+// restoreVolumeHostMounts mounts again on the host the filesystem of the
+// device of the volume at index, once no container of the sandbox uses the
+// device anymore.
+func (c *Container) restoreVolumeHostMounts(index int) {
+	m := c.mounts[index]
+	if len(m.BlockDeviceHostMounts) == 0 {
+		return
+	}
+
+	if c.sandbox.devManager.GetDeviceByID(m.BlockDeviceID) != nil {
+		return
+	}
+
+	if err := restoreHostMounts(m.BlockDeviceHostMounts); err != nil {
+		c.Logger().WithError(err).WithField("volume", m.Source).Error("failed to mount the volume again on the host")
+	}
+	// The container storage may be removed already, the mounts are not
+	// stored again: the host mounts still recorded there are not mounted
+	// twice by a forced cleanup.
+	c.mounts[index].BlockDeviceHostMounts = nil
+}
+
 // newContainer creates a Container structure from a sandbox and a container configuration.
 func newContainer(sandbox *Sandbox, contConfig ContainerConfig) (*Container, error) {
 	span, _ := sandbox.trace("newContainer")
@@ -698,7 +1146,16 @@ func newContainer(sandbox *Sandbox, contConfig ContainerConfig) (*Container, err
 	} else {
 		// If devices were not found in storage, create Device implementations
 		// from the configuration. This should happen at create.
+		hostDevices := c.withoutHostDevices()
+		if len(hostDevices) > 0 {
+			c.Logger().WithField("devices", len(hostDevices)).Info("Not passing the host devices of the privileged container")
+		}
+
 		for _, info := range contConfig.DeviceInfos {
+			if hostDevices[info.ContainerPath] {
+				continue
+			}
+
 			dev, err := sandbox.devManager.NewDevice(info)
 			if err != nil {
 				return &Container{}, err
@@ -719,10 +1176,14 @@ func newContainer(sandbox *Sandbox, contConfig ContainerConfig) (*Container, err
 // been performed before the container creation failed.
 // - Unplug CPU and memory resources from the VM.
 // - Unplug devices from the VM.
+// - Unplug volume devices from the VM.
 func (c *Container) rollbackFailingContainerCreation() {
 	if err := c.detachDevices(); err != nil {
 		c.Logger().WithError(err).Error("rollback failed detachDevices()")
 	}
+	if err := c.detachVolumeDevices(); err != nil {
+		c.Logger().WithError(err).Error("rollback failed detachVolumeDevices()")
+	}
 	if err := c.removeDrive(); err != nil {
 		c.Logger().WithError(err).Error("rollback failed removeDrive()")
 	}
@@ -864,6 +1325,15 @@ func (c *Container) start() error {
 		return err
 	}
 
+	if err := c.startPortForwards(); err != nil {
+		c.Logger().WithError(err).Error("Failed to forward the container ports")
+
+		if err := c.stop(); err != nil {
+			c.Logger().WithError(err).Warn("Failed to stop container")
+		}
+		return err
+	}
+
 	return c.setContainerState(types.StateRunning)
 }
 
@@ -871,6 +1341,10 @@ func (c *Container) stop() error {
 	span, _ := c.trace("stop")
 	defer span.Finish()
 
+	// The host ports go away with the container process, whatever its
+	// state.
+	c.stopPortForwards()
+
 	// In case the container status has been updated implicitly because
 	// the container process has terminated, it might be possible that
 	// someone try to stop the container, and we don't want to issue an
@@ -1045,7 +1519,27 @@ func (c *Container) stats() (*ContainerStats, error) {
 	if err := c.checkSandboxRunning("stats"); err != nil {
 		return nil, err
 	}
-	return c.sandbox.agent.statsContainer(c.sandbox, *c)
+
+	stats, err := c.sandbox.agent.statsContainer(c.sandbox, *c)
+	if err != nil || stats == nil {
+		return stats, err
+	}
+
+	// Failing to read the network stats does not prevent reporting the
+	// cgroup stats.
+	stats.NetworkStats, err = endpointsStats(c.sandbox.networkNS.NetNsPath, c.sandbox.networkNS.Endpoints)
+	if err != nil {
+		c.Logger().WithError(err).Warn("Could not get the network stats")
+	}
+
+	if c.GetAnnotations()[annotations.ContainerTypeKey] == string(PodSandbox) {
+		stats.HypervisorStats, err = c.sandbox.hypervisorStats()
+		if err != nil {
+			c.Logger().WithError(err).Warn("Could not get the hypervisor stats")
+		}
+	}
+
+	return stats, nil
 }
 
 func (c *Container) update(resources specs.LinuxResources) error {
@@ -1259,7 +1753,10 @@ func (c *Container) attachDevices() error {
 	// because if attachDevices fails, container creation will fail too,
 	// and rollbackFailingContainerCreation could do all the rollbacks
 	for _, dev := range c.devices {
-		if err := c.sandbox.devManager.AttachDevice(dev.ID, c.sandbox); err != nil {
+		done := c.sandbox.watchdog.watch("AttachDevice "+dev.ID, nil)
+		err := c.sandbox.devManager.AttachDevice(dev.ID, c.sandbox)
+		done()
+		if err != nil {
 			return err
 		}
 	}
@@ -1290,12 +1787,43 @@ func (c *Container) detachDevices() error {
 		}
 	}
 
+	for i := range c.mounts {
+		c.restoreVolumeHostMounts(i)
+	}
+
 	if err := c.sandbox.storeSandboxDevices(); err != nil {
 		return err
 	}
 	return nil
 }
 
+// detachVolumeDevices detaches the block devices of the container volumes
+// which are not container devices yet. The volume devices are attached when
+// mounting the container volumes, but only added to the container devices
+// once passed to the agent.
+func (c *Container) detachVolumeDevices() error {
+	tracked := make(map[string]bool)
+	for _, dev := range c.devices {
+		tracked[dev.ID] = true
+	}
+
+	for i, m := range c.mounts {
+		id := m.BlockDeviceID
+		if id == "" || tracked[id] {
+			continue
+		}
+
+		err := c.sandbox.devManager.DetachDevice(id, c.sandbox)
+		if err != nil && err != manager.ErrDeviceNotAttached {
+			return err
+		}
+
+		c.removeVolumeDevices([]int{i})
+	}
+
+	return c.sandbox.storeSandboxDevices()
+}
+
 // creates a new cgroup and return the cgroups path
 func (c *Container) newCgroups() error {
 	ann := c.GetAnnotations()
@@ -7,6 +7,7 @@ package virtcontainers
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -15,14 +16,18 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/kata-containers/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/runtime/virtcontainers/device/drivers"
 	"github.com/kata-containers/runtime/virtcontainers/device/manager"
+	vcAnnotations "github.com/kata-containers/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/runtime/virtcontainers/store"
 	"github.com/kata-containers/runtime/virtcontainers/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 )
 
 func TestGetAnnotations(t *testing.T) {
@@ -474,3 +479,116 @@ func TestProcessIOStream(t *testing.T) {
 	_, _, _, err = c.ioStream(processID)
 	assert.Error(err)
 }
+
+func TestStatVolumeSource(t *testing.T) {
+	assert := assert.New(t)
+
+	savedInterval := volumeSourcePollInterval
+	volumeSourcePollInterval = 10 * time.Millisecond
+	defer func() {
+		volumeSourcePollInterval = savedInterval
+	}()
+
+	dir, err := ioutil.TempDir("", "volume-source")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	c := &Container{
+		sandbox: &Sandbox{
+			config: &SandboxConfig{},
+		},
+	}
+
+	var stat unix.Stat_t
+	source := filepath.Join(dir, "csi", "mount")
+
+	// Not waited for by default.
+	err = c.statVolumeSource(source, &stat)
+	assert.Equal(unix.ENOENT, err)
+
+	// Published while waiting.
+	c.sandbox.config.VolumeSourceTimeout = 5
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		os.MkdirAll(source, mountPerm)
+	}()
+
+	err = c.statVolumeSource(source, &stat)
+	assert.NoError(err)
+	assert.Equal(uint32(unix.S_IFDIR), stat.Mode&unix.S_IFMT)
+}
+
+func TestPrivilegedSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := &specs.Spec{
+		Process: &specs.Process{
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding: []string{"CAP_CHOWN", "CAP_SYS_ADMIN"},
+			},
+		},
+		Linux: &specs.Linux{},
+	}
+	assert.True(privilegedSpec(spec))
+
+	spec.Linux.MaskedPaths = []string{"/proc/kcore"}
+	assert.False(privilegedSpec(spec))
+
+	spec.Linux.MaskedPaths = nil
+	spec.Process.Capabilities.Bounding = []string{"CAP_CHOWN"}
+	assert.False(privilegedSpec(spec))
+
+	assert.False(privilegedSpec(&specs.Spec{}))
+}
+
+func TestHostDevEnumeration(t *testing.T) {
+	assert := assert.New(t)
+
+	null := specs.LinuxDevice{Path: "/dev/null", Type: "c", Major: 1, Minor: 3}
+	requested := specs.LinuxDevice{Path: "/dev/kata-test-requested", Type: "c", Major: 1, Minor: 3}
+	other := specs.LinuxDevice{Path: "/dev/null", Type: "c", Major: 1, Minor: 5}
+
+	// The host node as it is
+	assert.Equal(map[string]bool{"/dev/null": true}, hostDevEnumeration([]specs.LinuxDevice{null, requested}))
+
+	// Requested at its host path
+	assert.Empty(hostDevEnumeration([]specs.LinuxDevice{null, null}))
+
+	// Not the host node
+	assert.Empty(hostDevEnumeration([]specs.LinuxDevice{other}))
+}
+
+func TestContainerWithoutHostDevices(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := specs.Spec{
+		Process: &specs.Process{
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding: []string{"CAP_SYS_ADMIN"},
+			},
+		},
+		Linux: &specs.Linux{
+			Devices: []specs.LinuxDevice{
+				{Path: "/dev/null", Type: "c", Major: 1, Minor: 3},
+			},
+		},
+	}
+	data, err := json.Marshal(spec)
+	assert.NoError(err)
+
+	c := &Container{
+		sandbox: &Sandbox{config: &SandboxConfig{}},
+		config: &ContainerConfig{
+			Annotations: map[string]string{
+				vcAnnotations.ConfigJSONKey: string(data),
+			},
+		},
+	}
+	assert.Empty(c.withoutHostDevices())
+
+	c.sandbox.config.PrivilegedWithoutHostDevices = true
+	assert.Equal(map[string]bool{"/dev/null": true}, c.withoutHostDevices())
+
+	delete(c.config.Annotations, vcAnnotations.ConfigJSONKey)
+	assert.Empty(c.withoutHostDevices())
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+
+	"github.com/kata-containers/runtime/virtcontainers/utils"
+)
+
+var createCoreSchedCookie = utils.CreateCoreSchedCookie
+
+// setupCoreScheduling gives the VMM process its own core scheduling cookie.
+// It is created right after the VM is started: the I/O threads and the
+// hot added vCPUs are created later by the VMM and inherit it.
+func (s *Sandbox) setupCoreScheduling() error {
+	if !s.config.HypervisorConfig.EnableCoreScheduling {
+		return nil
+	}
+
+	pid := s.hypervisor.pid()
+	if pid <= 0 {
+		return fmt.Errorf("Core scheduling requires the VMM to run on the host, %s has no pid", s.config.HypervisorType)
+	}
+
+	if err := createCoreSchedCookie(pid); err != nil {
+		return fmt.Errorf("Could not create the core scheduling cookie of VMM %d: %v", pid, err)
+	}
+
+	s.Logger().WithField("vmm-pid", pid).Info("VMM threads given their own core scheduling cookie")
+
+	return nil
+}
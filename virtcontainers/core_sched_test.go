@@ -0,0 +1,52 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupCoreScheduling(t *testing.T) {
+	assert := assert.New(t)
+
+	var cookies []int
+	savedCreateCoreSchedCookie := createCoreSchedCookie
+	createCoreSchedCookie = func(pid int) error {
+		cookies = append(cookies, pid)
+		return nil
+	}
+	defer func() {
+		createCoreSchedCookie = savedCreateCoreSchedCookie
+	}()
+
+	h := &mockHypervisor{mockPid: 1234}
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: h,
+		config:     &SandboxConfig{HypervisorType: MockHypervisor},
+	}
+
+	// Disabled by default.
+	assert.NoError(s.setupCoreScheduling())
+	assert.Empty(cookies)
+
+	s.config.HypervisorConfig.EnableCoreScheduling = true
+	assert.NoError(s.setupCoreScheduling())
+	assert.Equal([]int{1234}, cookies)
+
+	// The kernel lacks core scheduling support.
+	createCoreSchedCookie = func(pid int) error {
+		return errors.New("invalid argument")
+	}
+	assert.Error(s.setupCoreScheduling())
+
+	// No VMM process on the host.
+	h.mockPid = 0
+	assert.Error(s.setupCoreScheduling())
+}
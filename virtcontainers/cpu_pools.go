@@ -0,0 +1,143 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/runtime/virtcontainers/utils"
+)
+
+var (
+	onlineCPUsPath = "/sys/devices/system/cpu/online"
+	procTaskPath   = "/proc/%d/task"
+)
+
+// cpuPools returns the host CPUs the vCPU threads and the other VMM threads
+// are placed on, either being empty when they are left alone.
+func cpuPools(config HypervisorConfig) (vcpus []int, housekeeping []int, err error) {
+	if housekeeping, err = utils.ParseCPUSet(config.HousekeepingCPUs); err != nil {
+		return nil, nil, err
+	}
+
+	if config.VCPUsCPUPool != "" {
+		vcpus, err = utils.ParseCPUSet(config.VCPUsCPUPool)
+		return vcpus, housekeeping, err
+	}
+
+	if len(housekeeping) == 0 {
+		return nil, nil, nil
+	}
+
+	data, err := ioutil.ReadFile(onlineCPUsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	online, err := utils.ParseCPUSet(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserved := make(map[int]bool)
+	for _, cpu := range housekeeping {
+		reserved[cpu] = true
+	}
+
+	for _, cpu := range online {
+		if !reserved[cpu] {
+			vcpus = append(vcpus, cpu)
+		}
+	}
+
+	if len(vcpus) == 0 {
+		return nil, nil, fmt.Errorf("No CPU left for the vCPUs out of the housekeeping CPUs %s", config.HousekeepingCPUs)
+	}
+
+	return vcpus, housekeeping, nil
+}
+
+// vmmThreads returns the IDs of all the threads of process pid.
+func vmmThreads(pid int) ([]int, error) {
+	entries, err := ioutil.ReadDir(fmt.Sprintf(procTaskPath, pid))
+	if err != nil {
+		return nil, err
+	}
+
+	var tids []int
+	for _, e := range entries {
+		if tid, err := strconv.Atoi(filepath.Base(e.Name())); err == nil {
+			tids = append(tids, tid)
+		}
+	}
+
+	return tids, nil
+}
+
+// placeVMMThreads places the vCPU threads on the vCPUs CPU pool and the
+// other VMM threads on the housekeeping CPUs. It is run every time the
+// sandbox cgroup is updated, which resets the affinity of the vCPU threads
+// and follows the vCPU hotplugs. The vCPUs dedicated to the containers with
+// exclusive CPUs are pinned afterwards.
+func (s *Sandbox) placeVMMThreads() error {
+	if s.config == nil {
+		return nil
+	}
+
+	vcpuPool, housekeeping, err := cpuPools(s.config.HypervisorConfig)
+	if err != nil || (len(vcpuPool) == 0 && len(housekeeping) == 0) {
+		return err
+	}
+
+	tids, err := s.hypervisor.getThreadIDs()
+	if err != nil {
+		return fmt.Errorf("failed to get thread ids from hypervisor: %v", err)
+	}
+
+	vcpuThreads := make(map[int]bool)
+	for vcpu, tid := range tids.vcpus {
+		vcpuThreads[tid] = true
+
+		if len(vcpuPool) == 0 {
+			continue
+		}
+
+		if err := setThreadAffinity(tid, vcpuPool); err != nil {
+			return fmt.Errorf("Could not place vCPU %d on CPUs %v: %v", vcpu, vcpuPool, err)
+		}
+	}
+
+	if len(housekeeping) == 0 {
+		return nil
+	}
+
+	pid := s.hypervisor.pid()
+	if pid <= 0 {
+		return nil
+	}
+
+	threads, err := vmmThreads(pid)
+	if err != nil {
+		return err
+	}
+
+	for _, tid := range threads {
+		if vcpuThreads[tid] {
+			continue
+		}
+
+		// The short lived threads may be gone already.
+		if err := setThreadAffinity(tid, housekeeping); err != nil {
+			s.Logger().WithError(err).WithField("tid", tid).Warn("Could not place VMM thread on the housekeeping CPUs")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPUPools(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cpu-pools")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedOnlineCPUsPath := onlineCPUsPath
+	onlineCPUsPath = filepath.Join(dir, "online")
+	defer func() {
+		onlineCPUsPath = savedOnlineCPUsPath
+	}()
+	assert.NoError(ioutil.WriteFile(onlineCPUsPath, []byte("0-7\n"), 0644))
+
+	vcpus, housekeeping, err := cpuPools(HypervisorConfig{})
+	assert.NoError(err)
+	assert.Empty(vcpus)
+	assert.Empty(housekeeping)
+
+	vcpus, housekeeping, err = cpuPools(HypervisorConfig{HousekeepingCPUs: "0,1"})
+	assert.NoError(err)
+	assert.Equal([]int{2, 3, 4, 5, 6, 7}, vcpus)
+	assert.Equal([]int{0, 1}, housekeeping)
+
+	vcpus, housekeeping, err = cpuPools(HypervisorConfig{VCPUsCPUPool: "4-5"})
+	assert.NoError(err)
+	assert.Equal([]int{4, 5}, vcpus)
+	assert.Empty(housekeeping)
+
+	_, _, err = cpuPools(HypervisorConfig{HousekeepingCPUs: "0-7"})
+	assert.Error(err)
+}
+
+func TestPlaceVMMThreads(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cpu-pools")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcTaskPath := procTaskPath
+	procTaskPath = filepath.Join(dir, "%d")
+	defer func() {
+		procTaskPath = savedProcTaskPath
+	}()
+	for _, tid := range []string{"10", "100", "101", "102"} {
+		assert.NoError(os.MkdirAll(filepath.Join(dir, "10", tid), 0755))
+	}
+
+	placed := make(map[int][]int)
+	savedSetThreadAffinity := setThreadAffinity
+	setThreadAffinity = func(tid int, cpus []int) error {
+		placed[tid] = cpus
+		return nil
+	}
+	defer func() {
+		setThreadAffinity = savedSetThreadAffinity
+	}()
+
+	h := &vcpuThreadsHypervisor{
+		mockHypervisor: mockHypervisor{mockPid: 10},
+		vcpus:          map[int]int{0: 100, 1: 101},
+	}
+	s := &Sandbox{
+		hypervisor: h,
+		config:     &SandboxConfig{},
+	}
+
+	// Nothing is placed without pools.
+	assert.NoError(s.placeVMMThreads())
+	assert.Empty(placed)
+
+	s.config.HypervisorConfig = HypervisorConfig{
+		VCPUsCPUPool:     "2-3",
+		HousekeepingCPUs: "0",
+	}
+	assert.NoError(s.placeVMMThreads())
+	assert.Equal(map[int][]int{
+		100: {2, 3},
+		101: {2, 3},
+		10:  {0},
+		102: {0},
+	}, placed)
+}
@@ -52,6 +52,11 @@ const (
 	Nvdimm = "nvdimm"
 )
 
+// ImageFormatOption is the block device driver option giving the format of
+// the disk image file backing a block device, eg. raw or qcow2. Such a
+// device has no major and minor numbers, its host path is the image file.
+const ImageFormatOption = "image-format"
+
 // Defining these as a variable instead of a const, to allow
 // overriding this in the tests.
 
@@ -127,6 +132,10 @@ type BlockDrive struct {
 
 	// VirtPath at which the device appears inside the VM, outside of the container mount namespace
 	VirtPath string
+
+	// Serial is the serial number the guest sees for this drive, it is only
+	// set for the drives the guest looks up by their serial.
+	Serial string
 }
 
 // VFIODeviceType indicates VFIO device type
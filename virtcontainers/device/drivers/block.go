@@ -67,6 +67,9 @@ func (device *BlockDevice) Attach(devReceiver api.DeviceReceiver) (err error) {
 	}
 
 	customOptions := device.DeviceInfo.DriverOptions
+	if format := customOptions[config.ImageFormatOption]; format != "" {
+		drive.Format = format
+	}
 	if customOptions == nil ||
 		customOptions["block-driver"] == "virtio-scsi" {
 		// User has not chosen a specific block device type
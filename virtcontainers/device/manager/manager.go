@@ -87,11 +87,15 @@ func (dm *deviceManager) findDeviceByMajorMinor(major, minor int64) api.Device {
 
 // createDevice creates one device based on DeviceInfo
 func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device, err error) {
-	path, err := config.GetHostPathFunc(devInfo)
-	if err != nil {
-		return nil, err
+	image := isDiskImage(devInfo)
+	if !image {
+		path, err := config.GetHostPathFunc(devInfo)
+		if err != nil {
+			return nil, err
+		}
+		devInfo.HostPath = path
 	}
-	devInfo.HostPath = path
+	path := devInfo.HostPath
 
 	defer func() {
 		if err == nil {
@@ -99,8 +103,10 @@ func (dm *deviceManager) createDevice(devInfo config.DeviceInfo) (dev api.Device
 		}
 	}()
 
-	if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
-		return existingDev, nil
+	if !image {
+		if existingDev := dm.findDeviceByMajorMinor(devInfo.Major, devInfo.Minor); existingDev != nil {
+			return existingDev, nil
+		}
 	}
 
 	// device ID must be generated by manager instead of device itself
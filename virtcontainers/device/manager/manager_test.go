@@ -202,6 +202,42 @@ func TestAttachBlockDevice(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAttachDiskImageDevice(t *testing.T) {
+	dm := &deviceManager{
+		blockDriver: VirtioBlock,
+		devices:     make(map[string]api.Device),
+	}
+
+	deviceInfo := config.DeviceInfo{
+		HostPath:      "/var/lib/images/a.qcow2",
+		ContainerPath: "/data/a.qcow2",
+		DevType:       "b",
+		DriverOptions: map[string]string{config.ImageFormatOption: "qcow2"},
+	}
+
+	devReceiver := &api.MockDeviceReceiver{}
+	device, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+
+	blockDevice, ok := device.(*drivers.BlockDevice)
+	assert.True(t, ok)
+
+	err = device.Attach(devReceiver)
+	assert.Nil(t, err)
+	assert.Equal(t, deviceInfo.HostPath, blockDevice.BlockDrive.File)
+	assert.Equal(t, "qcow2", blockDevice.BlockDrive.Format)
+
+	// Disk images have no major and minor numbers to be shared by.
+	deviceInfo.HostPath = "/var/lib/images/b.raw"
+	deviceInfo.DriverOptions = map[string]string{config.ImageFormatOption: "raw"}
+	other, err := dm.NewDevice(deviceInfo)
+	assert.Nil(t, err)
+	assert.NotEqual(t, device.DeviceID(), other.DeviceID())
+
+	err = device.Detach(devReceiver)
+	assert.Nil(t, err)
+}
+
 func TestAttachDetachDevice(t *testing.T) {
 	dm := NewDeviceManager(VirtioSCSI, nil)
 
@@ -35,3 +35,9 @@ func isVFIO(hostPath string) bool {
 func isBlock(devInfo config.DeviceInfo) bool {
 	return devInfo.DevType == "b"
 }
+
+// isDiskImage checks if the device is backed by a disk image file rather
+// than a host device.
+func isDiskImage(devInfo config.DeviceInfo) bool {
+	return isBlock(devInfo) && devInfo.DriverOptions[config.ImageFormatOption] != ""
+}
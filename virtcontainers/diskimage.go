@@ -0,0 +1,52 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	diskImageRaw   = "raw"
+	diskImageQcow2 = "qcow2"
+)
+
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// rawImageExtensions are the extensions of the files handled as raw disk
+// images, which have no signature.
+var rawImageExtensions = map[string]bool{
+	".raw": true,
+	".img": true,
+}
+
+// diskImageFormat returns the format of the disk image file at path, or an
+// empty string if it is not a disk image.
+func diskImageFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, len(qcow2Magic))
+	if _, err := io.ReadFull(f, header); err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	if bytes.Equal(header, qcow2Magic) {
+		return diskImageQcow2, nil
+	}
+
+	if rawImageExtensions[filepath.Ext(path)] {
+		return diskImageRaw, nil
+	}
+
+	return "", nil
+}
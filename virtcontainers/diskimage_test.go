@@ -0,0 +1,46 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskImageFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "diskimage")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	for _, d := range []struct {
+		name    string
+		content []byte
+		format  string
+	}{
+		{"disk", append(append([]byte{}, qcow2Magic...), 0, 0, 0, 3), diskImageQcow2},
+		{"disk.img", []byte("data"), diskImageRaw},
+		{"disk.raw", nil, diskImageRaw},
+		{"hosts", []byte("127.0.0.1 localhost\n"), ""},
+		{"empty", nil, ""},
+		{"short", []byte("QF"), ""},
+	} {
+		path := filepath.Join(dir, d.name)
+		assert.NoError(ioutil.WriteFile(path, d.content, 0600))
+
+		format, err := diskImageFormat(path)
+		assert.NoError(err, d.name)
+		assert.Equal(d.format, format, d.name)
+	}
+
+	_, err = diskImageFormat(filepath.Join(dir, "missing"))
+	assert.Error(err)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+)
+
+// SandboxDump is a snapshot of the live state of a sandbox, as held by the
+// process managing it. It is meant for debugging only, its content is not
+// a stable API.
+type SandboxDump struct {
+	ID         string
+	State      types.State
+	Hypervisor HypervisorType
+
+	// HypervisorPID is the PID of the hypervisor process, or zero if the
+	// hypervisor does not run as a separate process.
+	HypervisorPID int
+
+	Agent AgentType
+
+	// AgentURL is the address the agent is reached at.
+	AgentURL string
+
+	// AgentConnected is true if a connection to the agent is currently
+	// open.
+	AgentConnected bool
+
+	Network    NetworkNamespace
+	Devices    []DeviceDump
+	Containers []ContainerDump
+}
+
+// DeviceDump describes a device of the sandbox device manager.
+type DeviceDump struct {
+	ID          string
+	Type        config.DeviceType
+	AttachCount uint
+
+	// Info is the device specific data, eg. the block drive of a block
+	// device.
+	Info interface{}
+}
+
+// ContainerDump is a snapshot of the live state of a container.
+type ContainerDump struct {
+	ID      string
+	State   types.State
+	Process Process
+	RootFs  RootFs
+	Mounts  []Mount
+	Devices []ContainerDevice
+}
+
+// Dump returns a snapshot of the live state of the sandbox.
+func (s *Sandbox) Dump() SandboxDump {
+	dump := SandboxDump{
+		ID:         s.id,
+		State:      s.state,
+		Hypervisor: s.config.HypervisorType,
+		Agent:      s.config.AgentType,
+		Network:    s.networkNS,
+	}
+
+	if s.hypervisor != nil {
+		dump.HypervisorPID = s.hypervisor.pid()
+	}
+
+	if k, ok := s.agent.(*kataAgent); ok {
+		dump.AgentURL = k.state.URL
+		dump.AgentConnected = k.connected()
+	}
+
+	if s.devManager != nil {
+		for _, d := range s.devManager.GetAllDevices() {
+			dump.Devices = append(dump.Devices, DeviceDump{
+				ID:          d.DeviceID(),
+				Type:        d.DeviceType(),
+				AttachCount: d.GetAttachCount(),
+				Info:        d.GetDeviceInfo(),
+			})
+		}
+	}
+
+	for _, c := range s.containers {
+		dump.Containers = append(dump.Containers, ContainerDump{
+			ID:      c.id,
+			State:   c.state,
+			Process: c.process,
+			RootFs:  c.rootFs,
+			Mounts:  c.mounts,
+			Devices: c.devices,
+		})
+	}
+
+	return dump
+}
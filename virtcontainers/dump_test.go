@@ -0,0 +1,36 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxDump(t *testing.T) {
+	assert := assert.New(t)
+
+	contID := "100"
+	config := newTestSandboxConfigNoop()
+	config.Containers = []ContainerConfig{newTestContainerConfigNoop(contID)}
+
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NoopAgentType, NetworkConfig{}, config.Containers, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	dump := s.Dump()
+	assert.Equal(testSandboxID, dump.ID)
+	assert.Equal(MockHypervisor, dump.Hypervisor)
+	assert.Equal(NoopAgentType, dump.Agent)
+	assert.False(dump.AgentConnected)
+	assert.Len(dump.Containers, 1)
+	assert.Equal(contID, dump.Containers[0].ID)
+
+	_, err = json.Marshal(dump)
+	assert.NoError(err)
+}
@@ -17,3 +17,69 @@ var (
 	errNeedState       = errors.New("State cannot be empty")
 	errNoSuchContainer = errors.New("Container does not exist")
 )
+
+// ErrorClass identifies a class of failures, so that the callers of the
+// runtime can handle them without parsing error messages. The values are
+// part of the API and do not change across releases.
+type ErrorClass string
+
+const (
+	// ErrorClassHypervisorLaunch is a failure to start the VM.
+	ErrorClassHypervisorLaunch ErrorClass = "HYPERVISOR_LAUNCH_FAILURE"
+
+	// ErrorClassAgentTimeout is a connection or a request to the agent
+	// timing out.
+	ErrorClassAgentTimeout ErrorClass = "AGENT_TIMEOUT"
+
+	// ErrorClassHotplug is a failure to hot plug or hot unplug a device.
+	ErrorClassHotplug ErrorClass = "HOTPLUG_FAILURE"
+
+	// ErrorClassMount is a failure to mount the rootfs or the volumes of
+	// a container on the host.
+	ErrorClassMount ErrorClass = "MOUNT_FAILURE"
+)
+
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the underlying error, as github.com/pkg/errors does.
+func (e *classifiedError) Cause() error {
+	return e.err
+}
+
+// classifyError attaches a class to err, unless it already has one.
+func classifyError(class ErrorClass, err error) error {
+	if err == nil || ErrorClassOf(err) != "" {
+		return err
+	}
+
+	return &classifiedError{class: class, err: err}
+}
+
+// ErrorClassOf returns the class of err, or an empty class if err is not of
+// a known class. Errors wrapped with github.com/pkg/errors keep their class.
+func ErrorClassOf(err error) ErrorClass {
+	type causer interface {
+		Cause() error
+	}
+
+	for err != nil {
+		if e, ok := err.(*classifiedError); ok {
+			return e.class
+		}
+
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+
+	return ""
+}
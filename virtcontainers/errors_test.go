@@ -0,0 +1,55 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+)
+
+func TestErrorClassOf(t *testing.T) {
+	assert := assert.New(t)
+
+	err := errors.New("foo")
+	assert.Equal(ErrorClass(""), ErrorClassOf(err))
+	assert.Equal(ErrorClass(""), ErrorClassOf(nil))
+
+	assert.Nil(classifyError(ErrorClassMount, nil))
+
+	classified := classifyError(ErrorClassMount, err)
+	assert.Equal(ErrorClassMount, ErrorClassOf(classified))
+	assert.Equal(err.Error(), classified.Error())
+
+	// wrapping keeps the class, classifying again does not change it
+	wrapped := pkgerrors.Wrap(classified, "bar")
+	assert.Equal(ErrorClassMount, ErrorClassOf(wrapped))
+	assert.Equal(ErrorClassMount, ErrorClassOf(classifyError(ErrorClassHotplug, wrapped)))
+}
+
+func TestAgentTimeoutError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(agentTimeoutError(nil))
+
+	err := errors.New("foo")
+	assert.Equal(err, agentTimeoutError(err))
+
+	for _, err := range []error{
+		context.DeadlineExceeded,
+		grpcStatus.Errorf(codes.DeadlineExceeded, "timed out connecting to unix socket"),
+	} {
+		assert.Equal(ErrorClassAgentTimeout, ErrorClassOf(agentTimeoutError(err)))
+	}
+
+	err = grpcStatus.Errorf(codes.NotFound, "foo")
+	assert.Equal(ErrorClass(""), ErrorClassOf(agentTimeoutError(err)))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/runtime/virtcontainers/utils"
+	"github.com/sirupsen/logrus"
+)
+
+var setThreadAffinity = utils.SetThreadAffinity
+
+// exclusiveCPUs returns the host CPUs a container has the exclusive use of.
+// This is how the Kubernetes static CPU manager places the containers
+// requesting an integer number of CPUs: their cpuset holds as many CPUs as
+// their CPU quota.
+func exclusiveCPUs(config *ContainerConfig) ([]int, error) {
+	cpu := config.Resources.CPU
+	if cpu == nil || cpu.Cpus == "" || cpu.Quota == nil || cpu.Period == nil {
+		return nil, nil
+	}
+
+	mCPU := utils.CalculateMilliCPUs(*cpu.Quota, *cpu.Period)
+	if mCPU == 0 || mCPU%1000 != 0 {
+		return nil, nil
+	}
+
+	cpus, err := utils.ParseCPUSet(cpu.Cpus)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint32(len(cpus)) != mCPU/1000 {
+		return nil, nil
+	}
+
+	return cpus, nil
+}
+
+// dedicateVCPUs dedicates to a container with exclusive CPUs the guest vCPUs
+// hot added for it, the VM going from oldCPUs to newCPUs vCPUs. The guest
+// CPU numbers come from the hypervisor, the vCPUs are hot removed with the
+// container.
+func (c *Container) dedicateVCPUs(oldCPUs, newCPUs uint32) error {
+	cpus, err := exclusiveCPUs(c.config)
+	if err != nil || len(cpus) == 0 {
+		return err
+	}
+
+	n := uint32(len(cpus))
+	if newCPUs < oldCPUs+n {
+		return fmt.Errorf("Could not hot add the %d vCPUs of container %s exclusive CPUs", n, c.id)
+	}
+
+	guestCPUs, err := c.sandbox.hypervisor.dedicateVCPUs(c.id, n)
+	if err != nil {
+		return fmt.Errorf("Could not dedicate vCPUs to container %s: %v", c.id, err)
+	}
+
+	ids := make([]string, 0, len(guestCPUs))
+	for _, cpu := range guestCPUs {
+		ids = append(ids, strconv.Itoa(cpu))
+	}
+	c.state.GuestCPUs = strings.Join(ids, ",")
+
+	c.Logger().WithFields(logrus.Fields{
+		"host-cpus":  c.config.Resources.CPU.Cpus,
+		"guest-cpus": c.state.GuestCPUs,
+	}).Info("Dedicating vCPUs to the container")
+
+	return nil
+}
+
+// pinVCPUs pins the vCPUs dedicated to the containers with exclusive CPUs to
+// their host CPUs, one vCPU per host CPU.
+func (s *Sandbox) pinVCPUs() error {
+	var tids vcpuThreadIDs
+
+	for _, c := range s.containers {
+		if c.state.GuestCPUs == "" {
+			continue
+		}
+
+		hostCPUs, err := exclusiveCPUs(c.config)
+		if err != nil {
+			return err
+		}
+
+		guestCPUs, err := utils.ParseCPUSet(c.state.GuestCPUs)
+		if err != nil {
+			return err
+		}
+
+		if len(hostCPUs) != len(guestCPUs) {
+			c.Logger().WithField("guest-cpus", c.state.GuestCPUs).Warn("Container CPUs changed, not pinning its vCPUs")
+			continue
+		}
+
+		if tids.vcpus == nil {
+			if tids, err = s.hypervisor.getThreadIDs(); err != nil {
+				return fmt.Errorf("failed to get thread ids from hypervisor: %v", err)
+			}
+		}
+
+		for i, vcpu := range guestCPUs {
+			tid, ok := tids.vcpus[vcpu]
+			if !ok {
+				return fmt.Errorf("Could not find the thread of vCPU %d", vcpu)
+			}
+
+			if err := setThreadAffinity(tid, []int{hostCPUs[i]}); err != nil {
+				return fmt.Errorf("Could not pin vCPU %d to CPU %d: %v", vcpu, hostCPUs[i], err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// releaseVCPUs hot removes the guest vCPUs dedicated to the container.
+func (c *Container) releaseVCPUs() {
+	if c.state.GuestCPUs == "" {
+		return
+	}
+
+	if _, err := c.sandbox.hypervisor.releaseVCPUs(c.id); err != nil {
+		c.Logger().WithError(err).WithField("guest-cpus", c.state.GuestCPUs).Warn("Could not hot remove the vCPUs dedicated to the container")
+		return
+	}
+	c.state.GuestCPUs = ""
+}
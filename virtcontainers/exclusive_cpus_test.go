@@ -0,0 +1,179 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type vcpuThreadsHypervisor struct {
+	mockHypervisor
+	vcpus map[int]int
+
+	// guestCPUs are the guest CPU numbers of the vCPUs not dedicated
+	// yet, dedicated are the ones of each owner.
+	guestCPUs []int
+	dedicated map[string][]int
+}
+
+func (h *vcpuThreadsHypervisor) getThreadIDs() (vcpuThreadIDs, error) {
+	return vcpuThreadIDs{h.vcpus}, nil
+}
+
+func (h *vcpuThreadsHypervisor) dedicateVCPUs(owner string, count uint32) ([]int, error) {
+	if uint32(len(h.guestCPUs)) < count {
+		return nil, fmt.Errorf("not enough vCPUs")
+	}
+
+	cpus := h.guestCPUs[uint32(len(h.guestCPUs))-count:]
+	h.guestCPUs = h.guestCPUs[:uint32(len(h.guestCPUs))-count]
+	if h.dedicated == nil {
+		h.dedicated = make(map[string][]int)
+	}
+	h.dedicated[owner] = cpus
+
+	return cpus, nil
+}
+
+func (h *vcpuThreadsHypervisor) releaseVCPUs(owner string) (uint32, error) {
+	n := uint32(len(h.dedicated[owner]))
+	delete(h.dedicated, owner)
+	return n, nil
+}
+
+func exclusiveCPUsConfig(cpus string, quota int64) *ContainerConfig {
+	period := uint64(100000)
+	return &ContainerConfig{
+		ID: "ctr",
+		Resources: specs.LinuxResources{
+			CPU: &specs.LinuxCPU{
+				Cpus:   cpus,
+				Quota:  &quota,
+				Period: &period,
+			},
+		},
+	}
+}
+
+func TestExclusiveCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	cpus, err := exclusiveCPUs(&ContainerConfig{})
+	assert.NoError(err)
+	assert.Empty(cpus)
+
+	for _, d := range []struct {
+		cpus     string
+		quota    int64
+		expected []int
+	}{
+		{"4-5", 200000, []int{4, 5}},
+		{"4,6", 200000, []int{4, 6}},
+		{"4-5", 150000, nil},
+		{"4-7", 200000, nil},
+		{"", 200000, nil},
+		{"4-5", -1, nil},
+	} {
+		cpus, err := exclusiveCPUs(exclusiveCPUsConfig(d.cpus, d.quota))
+		assert.NoError(err, "%+v", d)
+		assert.Equal(d.expected, cpus, "%+v", d)
+	}
+
+	_, err = exclusiveCPUs(exclusiveCPUsConfig("4-a", 100000))
+	assert.Error(err)
+}
+
+func TestDedicateVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	// The guest numbers of the hot added vCPUs do not follow their hot
+	// add order.
+	h := &vcpuThreadsHypervisor{
+		guestCPUs: []int{1, 3, 2},
+	}
+	s := &Sandbox{
+		hypervisor: h,
+	}
+
+	c := &Container{
+		id:      "ctr",
+		config:  exclusiveCPUsConfig("4-5", 200000),
+		sandbox: s,
+	}
+
+	// Not enough vCPUs hot added.
+	assert.Error(c.dedicateVCPUs(1, 2))
+	assert.Empty(c.state.GuestCPUs)
+
+	assert.NoError(c.dedicateVCPUs(1, 3))
+	assert.Equal("3,2", c.state.GuestCPUs)
+	assert.Equal(map[string][]int{"ctr": {3, 2}}, h.dedicated)
+
+	// The hypervisor could not dedicate the vCPUs.
+	other := &Container{
+		id:      "other",
+		config:  exclusiveCPUsConfig("6-7", 200000),
+		sandbox: s,
+	}
+	assert.Error(other.dedicateVCPUs(3, 5))
+	assert.Empty(other.state.GuestCPUs)
+
+	c.releaseVCPUs()
+	assert.Empty(c.state.GuestCPUs)
+	assert.Empty(h.dedicated)
+
+	c = &Container{
+		id:      "ctr",
+		config:  exclusiveCPUsConfig("4-7", 200000),
+		sandbox: s,
+	}
+	assert.NoError(c.dedicateVCPUs(1, 3))
+	assert.Empty(c.state.GuestCPUs)
+}
+
+func TestPinVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	pinned := make(map[int][]int)
+	savedSetThreadAffinity := setThreadAffinity
+	setThreadAffinity = func(tid int, cpus []int) error {
+		pinned[tid] = cpus
+		return nil
+	}
+	defer func() {
+		setThreadAffinity = savedSetThreadAffinity
+	}()
+
+	h := &vcpuThreadsHypervisor{
+		vcpus: map[int]int{0: 100, 1: 101, 2: 102},
+	}
+	s := &Sandbox{
+		hypervisor: h,
+		containers: map[string]*Container{
+			"shared": {
+				id:     "shared",
+				config: &ContainerConfig{},
+			},
+			"exclusive": {
+				id:     "exclusive",
+				config: exclusiveCPUsConfig("4,6", 200000),
+				state:  types.State{GuestCPUs: "1-2"},
+			},
+		},
+	}
+
+	assert.NoError(s.pinVCPUs())
+	assert.Equal(map[int][]int{101: {4}, 102: {6}}, pinned)
+
+	// The dedicated vCPUs are gone.
+	h.vcpus = map[int]int{0: 100}
+	assert.Error(s.pinVCPUs())
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/kata-containers/runtime/virtcontainers/factory/cache"
 	"github.com/kata-containers/runtime/virtcontainers/factory/direct"
 	"github.com/kata-containers/runtime/virtcontainers/factory/grpccache"
+	"github.com/kata-containers/runtime/virtcontainers/factory/pool"
 	"github.com/kata-containers/runtime/virtcontainers/factory/template"
 	"github.com/kata-containers/runtime/virtcontainers/utils"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -30,6 +31,10 @@ type Config struct {
 	Cache           uint
 	VMCacheEndpoint string
 
+	// Pool is the number of booted, agent-ready VMs kept by the
+	// warm pool factory.
+	Pool uint
+
 	VMConfig vc.VMConfig
 }
 
@@ -59,6 +64,14 @@ func NewFactory(ctx context.Context, config Config, fetchOnly bool) (vc.Factory,
 		return nil, fmt.Errorf("cache factory does not support fetch")
 	}
 
+	if fetchOnly && config.Pool > 0 {
+		return nil, fmt.Errorf("pool factory does not support fetch")
+	}
+
+	if config.Pool > 0 && (config.Template || config.Cache > 0) {
+		return nil, fmt.Errorf("pool factory cannot work together with template or cache factory")
+	}
+
 	var b base.FactoryBase
 	if config.Template {
 		if fetchOnly {
@@ -72,6 +85,8 @@ func NewFactory(ctx context.Context, config Config, fetchOnly bool) (vc.Factory,
 				return nil, err
 			}
 		}
+	} else if config.Pool > 0 {
+		b = pool.New(ctx, config.Pool, config.VMConfig)
 	} else if config.VMCache && config.Cache == 0 {
 		b, err = grpccache.New(ctx, config.VMCacheEndpoint)
 		if err != nil {
@@ -108,6 +123,9 @@ func resetHypervisorConfig(config *vc.VMConfig) {
 	config.HypervisorConfig.BootFromTemplate = false
 	config.HypervisorConfig.MemoryPath = ""
 	config.HypervisorConfig.DevicesStatePath = ""
+	// the scratch disk is hotplugged once the VM is assigned to a sandbox
+	config.HypervisorConfig.ScratchDiskSize = 0
+	config.HypervisorConfig.ScratchWritableLayer = false
 	config.ProxyType = vc.NoopProxyType
 	config.ProxyConfig = vc.ProxyConfig{}
 }
@@ -55,6 +55,19 @@ func TestNewFactory(t *testing.T) {
 	assert.Nil(err)
 	f.CloseFactory(ctx)
 
+	// pool
+	config.Pool = 2
+	f, err = NewFactory(ctx, config, false)
+	assert.Nil(err)
+	f.CloseFactory(ctx)
+	_, err = NewFactory(ctx, config, true)
+	assert.Error(err)
+	config.Cache = 1
+	_, err = NewFactory(ctx, config, false)
+	assert.Error(err)
+	config.Cache = 0
+	config.Pool = 0
+
 	// template
 	if os.Geteuid() != 0 {
 		t.Skip(testDisabledAsNonRoot)
@@ -171,6 +184,11 @@ func TestCheckVMConfig(t *testing.T) {
 	}
 	err = checkVMConfig(config1, config2)
 	assert.Nil(err)
+
+	// neither does the scratch disk size
+	config1.HypervisorConfig.ScratchDiskSize = 1024
+	err = checkVMConfig(config1, config2)
+	assert.Nil(err)
 }
 
 func TestFactoryGetVM(t *testing.T) {
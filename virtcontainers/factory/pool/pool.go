@@ -0,0 +1,42 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// pool implements base vm factory that keeps a number of booted,
+// agent-ready VMs around and replenishes them in the background.
+
+package pool
+
+import (
+	"context"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+	"github.com/kata-containers/runtime/virtcontainers/factory/base"
+	"github.com/kata-containers/runtime/virtcontainers/factory/cache"
+)
+
+// running boots the VMs of the pool, and leaves them running.
+type running struct {
+	config vc.VMConfig
+}
+
+// New creates a new warm pool vm factory. Unlike the cache factory on top
+// of the direct factory, VMs kept in the pool are not paused so that they
+// can be handed out without resuming them or waiting for the agent.
+func New(ctx context.Context, count uint, config vc.VMConfig) base.FactoryBase {
+	return cache.New(ctx, count, &running{config})
+}
+
+// Config returns the pool factory's configuration.
+func (r *running) Config() vc.VMConfig {
+	return r.config
+}
+
+// GetBaseVM boots a new VM.
+func (r *running) GetBaseVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error) {
+	return vc.NewVM(ctx, config)
+}
+
+// CloseFactory closes the pool factory.
+func (r *running) CloseFactory(ctx context.Context) {
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package pool
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+)
+
+func TestPoolFactory(t *testing.T) {
+	assert := assert.New(t)
+
+	testDir, _ := ioutil.TempDir("", "vmfactory-tmp-")
+	hyperConfig := vc.HypervisorConfig{
+		KernelPath: testDir,
+		ImagePath:  testDir,
+	}
+	vmConfig := vc.VMConfig{
+		HypervisorType:   vc.MockHypervisor,
+		AgentType:        vc.NoopAgentType,
+		ProxyType:        vc.NoopProxyType,
+		HypervisorConfig: hyperConfig,
+	}
+
+	ctx := context.Background()
+
+	// New
+	f := New(ctx, 2, vmConfig)
+
+	// Config
+	assert.Equal(f.Config(), vmConfig)
+
+	// GetBaseVM, more times than the pool size to exercise replenishment
+	for i := 0; i < 3; i++ {
+		vm, err := f.GetBaseVM(ctx, vmConfig)
+		assert.Nil(err)
+
+		err = vm.Stop()
+		assert.Nil(err)
+	}
+
+	// CloseFactory
+	f.CloseFactory(ctx)
+
+	_, err := f.GetBaseVM(ctx, vmConfig)
+	assert.Error(err)
+}
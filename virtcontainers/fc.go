@@ -144,6 +144,10 @@ func (fc *firecracker) createSandbox(ctx context.Context, id string, hypervisorC
 
 	//TODO: check validity of the hypervisor config provided
 	//https://github.com/kata-containers/runtime/issues/1065
+	if err := hypervisorConfig.selectGuestBoot(); err != nil {
+		return err
+	}
+
 	fc.id = id
 	fc.socketPath = filepath.Join(store.SandboxRuntimeRootPath(fc.id), fireSocket)
 	fc.store = vcStore
@@ -357,6 +361,7 @@ func (fc *firecracker) startSandbox(timeout int) error {
 	}
 
 	kernelParams := append(fc.config.KernelParams, fcKernelParams...)
+	kernelParams = append(kernelParams, fc.config.vsockKernelParams()...)
 	strParams := SerializeParams(kernelParams, "=")
 	formattedParams := strings.Join(strParams, " ")
 
@@ -691,6 +696,14 @@ func (fc *firecracker) resizeVCPUs(reqVCPUs uint32) (currentVCPUs uint32, newVCP
 	return 0, 0, nil
 }
 
+func (fc *firecracker) dedicateVCPUs(owner string, count uint32) ([]int, error) {
+	return nil, fmt.Errorf("firecracker does not support dedicating vCPUs")
+}
+
+func (fc *firecracker) releaseVCPUs(owner string) (uint32, error) {
+	return 0, nil
+}
+
 // This is used to apply cgroup information on the host.
 //
 // As suggested by https://github.com/firecracker-microvm/firecracker/issues/718,
@@ -0,0 +1,96 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// agent-rpc-dump pretty-prints the agent RPCs recorded by the runtime when
+// the rpc_record_dir agent option is set.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	vc "github.com/kata-containers/runtime/virtcontainers"
+)
+
+func printPayload(w io.Writer, label string, payload json.RawMessage) {
+	if len(payload) == 0 {
+		return
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, payload, "    ", "  "); err != nil {
+		out.Reset()
+		out.Write(payload)
+	}
+
+	fmt.Fprintf(w, "  %s: %s\n", label, out.String())
+}
+
+func dump(r io.Reader, w io.Writer, name string, errorsOnly bool) error {
+	scanner := bufio.NewScanner(r)
+	// Payloads are truncated, but the JSON escaping of a truncated
+	// payload can take up to 6 bytes per byte.
+	scanner.Buffer(make([]byte, 0, 64*1024), 12*vc.AgentRPCRecordMaxPayload+64*1024)
+
+	for scanner.Scan() {
+		var rec vc.AgentRPCRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return err
+		}
+
+		if name != "" && !strings.Contains(rec.Name, name) {
+			continue
+		}
+
+		if errorsOnly && rec.Error == "" {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s %s (%v)\n", rec.Time.Format("15:04:05.000000"), rec.Name, rec.Duration)
+		printPayload(w, "request", rec.Request)
+		printPayload(w, "response", rec.Response)
+		if rec.Error != "" {
+			fmt.Fprintf(w, "  error: %s\n", rec.Error)
+		}
+		if rec.Truncated {
+			fmt.Fprintf(w, "  (truncated)\n")
+		}
+	}
+
+	return scanner.Err()
+}
+
+func main() {
+	name := flag.String("name", "", "only print the RPCs whose name contains this string, eg. CreateContainer")
+	errorsOnly := flag.Bool("errors", false, "only print the failed RPCs")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] <record file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := dump(f, os.Stdout, *name, *errorsOnly); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
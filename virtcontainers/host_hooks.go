@@ -0,0 +1,185 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HostHookPoint is a point of the sandbox lifecycle host hooks are run at.
+type HostHookPoint string
+
+const (
+	// HostHookPreVMStart hooks run before the sandbox VM is started.
+	HostHookPreVMStart HostHookPoint = "pre-vm-start"
+
+	// HostHookPostVMStart hooks run once the VM is started and the agent
+	// is reachable.
+	HostHookPostVMStart HostHookPoint = "post-vm-start"
+
+	// HostHookPreSandboxDelete hooks run before the sandbox resources are
+	// released. Their failures are logged and never keep the sandbox from
+	// being deleted, whatever the failure policy.
+	HostHookPreSandboxDelete HostHookPoint = "pre-sandbox-delete"
+)
+
+// HostHookFailurePolicy is what happens to the sandbox operation when one of
+// its host hooks fails.
+type HostHookFailurePolicy string
+
+const (
+	// HostHookFailureAbort fails the operation, the remaining hooks are
+	// not run.
+	HostHookFailureAbort HostHookFailurePolicy = "abort"
+
+	// HostHookFailureIgnore logs the failure and runs the remaining hooks.
+	HostHookFailureIgnore HostHookFailurePolicy = "ignore"
+)
+
+// HostHooksConfig lists the host binaries run at the points of the sandbox
+// lifecycle, eg. to register the sandboxes with an inventory.
+//
+// The hooks are run with an empty stdin and the sandbox metadata in their
+// environment: KATA_HOOK_POINT, KATA_SANDBOX_ID, KATA_HYPERVISOR,
+// KATA_NETNS, KATA_VMM_PID (0 before the VM is started) and
+// KATA_SANDBOX_LABELS, a comma separated list of key=value pairs.
+type HostHooksConfig struct {
+	PreVMStart       []string
+	PostVMStart      []string
+	PreSandboxDelete []string
+
+	// Timeout is the number of seconds a hook is given before being
+	// killed, none when it is 0.
+	Timeout uint32
+
+	// FailurePolicy defaults to HostHookFailureAbort. It does not apply
+	// to the HostHookPreSandboxDelete hooks.
+	FailurePolicy HostHookFailurePolicy
+}
+
+func (c HostHooksConfig) failurePolicy(point HostHookPoint) HostHookFailurePolicy {
+	// Nothing would clean up a sandbox left behind by a failing
+	// delete hook.
+	if point == HostHookPreSandboxDelete {
+		return HostHookFailureIgnore
+	}
+
+	return c.FailurePolicy
+}
+
+func (c HostHooksConfig) hooks(point HostHookPoint) []string {
+	switch point {
+	case HostHookPreVMStart:
+		return c.PreVMStart
+	case HostHookPostVMStart:
+		return c.PostVMStart
+	case HostHookPreSandboxDelete:
+		return c.PreSandboxDelete
+	}
+
+	return nil
+}
+
+func (s *Sandbox) hostHookEnv(point HostHookPoint) []string {
+	labels := make([]string, 0, len(s.config.Labels))
+	for k, v := range s.config.Labels {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+
+	pid := 0
+	if s.hypervisor != nil {
+		pid = s.hypervisor.pid()
+	}
+
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"KATA_HOOK_POINT=" + string(point),
+		"KATA_SANDBOX_ID=" + s.id,
+		"KATA_HYPERVISOR=" + string(s.config.HypervisorType),
+		"KATA_NETNS=" + s.networkNS.NetNsPath,
+		"KATA_VMM_PID=" + strconv.Itoa(pid),
+		"KATA_SANDBOX_LABELS=" + strings.Join(labels, ","),
+	}
+}
+
+func runHostHook(path string, env []string, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		return fmt.Errorf("%v: stdout: %s, stderr: %s", err, stdout.String(), stderr.String())
+	}
+
+	return nil
+}
+
+// runHostHooks runs the hooks of a lifecycle point in turn, applying the
+// failure policy to their errors.
+func (s *Sandbox) runHostHooks(point HostHookPoint) error {
+	if s.config == nil {
+		return nil
+	}
+	config := s.config.HostHooks
+
+	hooks := config.hooks(point)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	span, _ := s.trace("runHostHooks")
+	span.SetTag("hook-point", string(point))
+	defer span.Finish()
+
+	env := s.hostHookEnv(point)
+	timeout := time.Duration(config.Timeout) * time.Second
+
+	for _, hook := range hooks {
+		logger := s.Logger().WithFields(logrus.Fields{
+			"hook-point": point,
+			"hook":       hook,
+		})
+
+		err := runHostHook(hook, env, timeout)
+		if err == nil {
+			logger.Debug("Host hook run")
+			continue
+		}
+
+		if config.failurePolicy(point) == HostHookFailureIgnore {
+			logger.WithError(err).Warn("Host hook failed, ignoring")
+			continue
+		}
+
+		logger.WithError(err).Error("Host hook failed")
+		return fmt.Errorf("%s hook %s failed: %v", point, hook, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeHostHook(t *testing.T, dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0700)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestRunHostHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "host-hooks")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "env")
+	dump := writeHostHook(t, dir, "dump", "env > "+out)
+	fail := writeHostHook(t, dir, "fail", "echo failing >&2; exit 1")
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		ctx:        context.Background(),
+		hypervisor: &mockHypervisor{},
+		networkNS:  NetworkNamespace{NetNsPath: "/var/run/netns/foo"},
+		config: &SandboxConfig{
+			HypervisorType: MockHypervisor,
+			Labels:         map[string]string{"team": "a", "env": "prod"},
+			HostHooks: HostHooksConfig{
+				PreVMStart: []string{dump},
+			},
+		},
+	}
+
+	// No hooks is not an error.
+	assert.NoError(s.runHostHooks(HostHookPostVMStart))
+
+	assert.NoError(s.runHostHooks(HostHookPreVMStart))
+	data, err := ioutil.ReadFile(out)
+	assert.NoError(err)
+	env := strings.Split(string(data), "\n")
+	assert.Contains(env, "KATA_HOOK_POINT=pre-vm-start")
+	assert.Contains(env, "KATA_SANDBOX_ID="+testSandboxID)
+	assert.Contains(env, "KATA_HYPERVISOR=mock")
+	assert.Contains(env, "KATA_NETNS=/var/run/netns/foo")
+	assert.Contains(env, "KATA_SANDBOX_LABELS=env=prod,team=a")
+
+	// A failing hook fails the operation and stops the next hooks.
+	os.Remove(out)
+	s.config.HostHooks.PostVMStart = []string{fail, dump}
+	err = s.runHostHooks(HostHookPostVMStart)
+	assert.Error(err)
+	assert.Contains(err.Error(), "failing")
+	_, err = os.Stat(out)
+	assert.True(os.IsNotExist(err))
+
+	// Except for the delete hooks.
+	s.config.HostHooks.PreSandboxDelete = []string{fail, dump}
+	assert.NoError(s.runHostHooks(HostHookPreSandboxDelete))
+	_, err = os.Stat(out)
+	assert.NoError(err)
+
+	// Or when the failures are ignored.
+	os.Remove(out)
+	s.config.HostHooks.FailurePolicy = HostHookFailureIgnore
+	assert.NoError(s.runHostHooks(HostHookPostVMStart))
+	_, err = os.Stat(out)
+	assert.NoError(err)
+}
+
+func TestRunHostHookTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "host-hooks")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{
+		id:  testSandboxID,
+		ctx: context.Background(),
+		config: &SandboxConfig{
+			HostHooks: HostHooksConfig{
+				PostVMStart: []string{writeHostHook(t, dir, "sleep", "exec sleep 10")},
+				Timeout:     1,
+			},
+		},
+	}
+
+	err = s.runHostHooks(HostHookPostVMStart)
+	assert.Error(err)
+	assert.Contains(err.Error(), "timed out")
+}
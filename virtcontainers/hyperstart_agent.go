@@ -546,7 +546,7 @@ func (h *hyper) startOneContainer(sandbox *Sandbox, c *Container) error {
 		container.Fstype = c.state.Fstype
 	} else {
 
-		if err := bindMountContainerRootfs(c.ctx, defaultSharedDir, sandbox.id, c.id, c.rootFs.Target, false); err != nil {
+		if err := c.bindMountRootfs(defaultSharedDir); err != nil {
 			bindUnmountAllRootfs(c.ctx, defaultSharedDir, sandbox)
 			return err
 		}
@@ -642,7 +642,7 @@ func (h *hyper) stopOneContainer(sandboxID string, c Container) error {
 	}
 
 	if c.state.Fstype == "" {
-		if err := bindUnmountContainerRootfs(c.ctx, defaultSharedDir, sandboxID, c.id); err != nil {
+		if err := c.bindUnmountRootfs(defaultSharedDir); err != nil {
 			return err
 		}
 	}
@@ -901,6 +901,11 @@ func (h *hyper) updateInterface(inf *vcTypes.Interface) (*vcTypes.Interface, err
 	return nil, nil
 }
 
+func (h *hyper) removeInterface(inf *vcTypes.Interface) error {
+	// hyperstart-agent does not support remove interface
+	return nil
+}
+
 func (h *hyper) listInterfaces() ([]*vcTypes.Interface, error) {
 	// hyperstart-agent does not support list interfaces
 	return nil, nil
@@ -220,6 +220,14 @@ func TestHyperUpdateInterface(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestHyperRemoveInterface(t *testing.T) {
+	assert := assert.New(t)
+
+	h := &hyper{}
+	err := h.removeInterface(nil)
+	assert.Nil(err)
+}
+
 func TestHyperListInterfaces(t *testing.T) {
 	assert := assert.New(t)
 
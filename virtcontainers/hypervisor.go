@@ -9,7 +9,10 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"math"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -31,6 +34,10 @@ const (
 	// QemuHypervisor is the QEMU hypervisor.
 	QemuHypervisor HypervisorType = "qemu"
 
+	// RemoteHypervisor is an out of tree VMM, driven through the
+	// HypervisorDriver gRPC protocol.
+	RemoteHypervisor HypervisorType = "remote"
+
 	// MockHypervisor is a mock hypervisor for testing purposes
 	MockHypervisor HypervisorType = "mock"
 )
@@ -38,6 +45,10 @@ const (
 const (
 	procMemInfo = "/proc/meminfo"
 	procCPUInfo = "/proc/cpuinfo"
+
+	// sysThreadSiblings lists the hardware threads sharing the core of
+	// the first host CPU.
+	sysThreadSiblings = "/sys/devices/system/cpu/cpu0/topology/thread_siblings_list"
 )
 
 const (
@@ -47,9 +58,26 @@ const (
 
 	defaultBridges = 1
 
+	// The memory topology of the VM describes the number of memory
+	// slots on 8 bits.
+	maxMemSlots = 255
+
 	defaultBlockDriver = config.VirtioSCSI
 )
 
+const (
+	imageGuestBoot  = "image"
+	initrdGuestBoot = "initrd"
+)
+
+const (
+	// vsockPortAny is VMADDR_PORT_ANY, which cannot be listened on.
+	vsockPortAny = 0xFFFFFFFF
+
+	vsockLogPortKernelOption          = "agent.log_vport"
+	vsockDebugConsolePortKernelOption = "agent.debug_console_vport"
+)
+
 // In some architectures the maximum number of vCPUs depends on the number of physical cores.
 var defaultMaxQemuVCPUs = MaxQemuVCPUs()
 
@@ -102,6 +130,9 @@ func (hType *HypervisorType) Set(value string) error {
 	case "firecracker":
 		*hType = FirecrackerHypervisor
 		return nil
+	case "remote":
+		*hType = RemoteHypervisor
+		return nil
 	case "mock":
 		*hType = MockHypervisor
 		return nil
@@ -117,6 +148,8 @@ func (hType *HypervisorType) String() string {
 		return string(QemuHypervisor)
 	case FirecrackerHypervisor:
 		return string(FirecrackerHypervisor)
+	case RemoteHypervisor:
+		return string(RemoteHypervisor)
 	case MockHypervisor:
 		return string(MockHypervisor)
 	default:
@@ -131,6 +164,8 @@ func newHypervisor(hType HypervisorType) (hypervisor, error) {
 		return &qemu{}, nil
 	case FirecrackerHypervisor:
 		return &firecracker{}, nil
+	case RemoteHypervisor:
+		return &remoteHypervisor{}, nil
 	case MockHypervisor:
 		return &mockHypervisor{}, nil
 	default:
@@ -144,6 +179,27 @@ type Param struct {
 	Value string
 }
 
+// The PCI hotplug mechanisms. The guest kernel needs the matching hotplug
+// driver, CONFIG_HOTPLUG_PCI_ACPI, CONFIG_HOTPLUG_PCI_SHPC or
+// CONFIG_HOTPLUG_PCI_PCIE.
+const (
+	// PCIHotplugACPI hot plugs the devices on the PCI bridges through the
+	// ACPI hotplug of the firmware.
+	PCIHotplugACPI = "acpi"
+
+	// PCIHotplugSHPC hot plugs the devices on the PCI bridges through
+	// their standard hot plug controller.
+	PCIHotplugSHPC = "shpc"
+
+	// PCIHotplugNative hot plugs the devices on PCIe root ports through
+	// the native PCIe hotplug.
+	PCIHotplugNative = "native"
+)
+
+// defaultPCIeRootPorts is the number of PCIe root ports of the native PCIe
+// hotplug, when none is configured.
+const defaultPCIeRootPorts = 8
+
 // HypervisorConfig is the hypervisor configuration.
 type HypervisorConfig struct {
 	// NumVCPUs specifies default number of vCPUs for the VM.
@@ -162,9 +218,41 @@ type HypervisorConfig struct {
 	// Msize9p is used as the msize for 9p shares
 	Msize9p uint32
 
+	// Cache9p is the cache mode the guest mounts the 9p shares with,
+	// "mmap" by default.
+	Cache9p string
+
+	// Version9p is the 9p protocol version of the shares, "9p2000.L" by
+	// default.
+	Version9p string
+
 	// MemSlots specifies default memory slots the VM.
 	MemSlots uint32
 
+	// DefaultVCPUs is the amount of host CPU time, in vCPUs, the VM gets
+	// when its containers set no CPU constraint. It can be a fraction of
+	// a vCPU to overcommit tiny sandboxes, NumVCPUs is then rounded up.
+	// Zero means NumVCPUs.
+	DefaultVCPUs float32
+
+	// CPUCores is the number of cores per socket of the guest CPU
+	// topology. The number of sockets follows from the maximum number
+	// of vCPUs. Zero keeps the default topology of the architecture.
+	CPUCores uint32
+
+	// CPUThreads is the number of threads per core of the guest CPU
+	// topology.
+	CPUThreads uint32
+
+	// MirrorHostSMT sets the number of threads per core of the guest to
+	// the one of the host, overriding CPUThreads.
+	MirrorHostSMT bool
+
+	// DefaultMaxMemorySize specifies the maximum amount of memory in MiB
+	// the VM can reach through memory hotplug. Zero means the host memory
+	// size.
+	DefaultMaxMemorySize uint32
+
 	// MemOffset specifies memory space for nvdimm device
 	MemOffset uint32
 
@@ -181,15 +269,75 @@ type HypervisorConfig struct {
 	ImagePath string
 
 	// InitrdPath is the guest initrd image host path.
-	// ImagePath and InitrdPath cannot be set at the same time.
+	// When both ImagePath and InitrdPath are set, GuestBoot selects the
+	// one the VM boots from.
 	InitrdPath string
 
+	// GuestBoot is either "image" or "initrd". Empty leaves both paths
+	// as they are.
+	GuestBoot string
+
 	// FirmwarePath is the bios host path
 	FirmwarePath string
 
+	// FirmwareVarsPath is the UEFI variable store template of the
+	// firmware. When set, FirmwarePath is the firmware code and both are
+	// mapped as pflash devices instead of being loaded as a bios. The VM
+	// gets its own copy of the template, kept in the VM directory and
+	// removed with it when the VM is stopped.
+	FirmwareVarsPath string
+
+	// FirmwareVarsReadOnly maps FirmwareVarsPath itself, read-only, in
+	// all the VMs instead of a copy. The variables written by the guest
+	// are then lost.
+	FirmwareVarsReadOnly bool
+
+	// SecureBoot enables what the UEFI Secure Boot of the firmware relies
+	// on, i.e. the SMM mode of the q35 machine. Enforcing it depends on
+	// the keys enrolled in the variable store.
+	SecureBoot bool
+
 	// MachineAccelerators are machine specific accelerators
 	MachineAccelerators string
 
+	// KernelIRQChip sets where the interrupt controllers are emulated:
+	// "on" in KVM, "off" in QEMU, or "split" to emulate the IOAPIC and
+	// PIC in QEMU and the local APIC in KVM. Empty keeps the default of
+	// the machine.
+	KernelIRQChip string
+
+	// DisableVMPort disables the emulation of the VMware IO port.
+	DisableVMPort bool
+
+	// PCIHotplug is the mechanism the guest is told to hot plug the PCI
+	// devices through, one of the PCIHotplug constants. Empty keeps the
+	// default of the machine.
+	PCIHotplug string
+
+	// PCIeRootPorts is the number of PCIe root ports the devices are hot
+	// plugged on with the native PCIe hotplug, one device per port.
+	PCIeRootPorts uint32
+
+	// DisablePMU hides the performance monitoring unit from the guest,
+	// which saves the exits caused by the guest perf counters.
+	DisablePMU bool
+
+	// Accelerator is the accelerator the VM runs with, one of "kvm",
+	// "hvf" or "tcg". Empty means "kvm".
+	Accelerator string
+
+	// AcceleratorTCGFallback runs the VM with TCG when the configured
+	// accelerator is not available on the host, instead of failing.
+	AcceleratorTCGFallback bool
+
+	// CPUModel is the guest CPU model, replacing the default one of the
+	// architecture.
+	CPUModel string
+
+	// CPUFeatures is a comma-separated list of CPU features to enable
+	// ("+feature") or disable ("-feature") on top of the CPU model.
+	CPUFeatures string
+
 	// HypervisorPath is the hypervisor executable host path.
 	HypervisorPath string
 
@@ -213,6 +361,14 @@ type HypervisorConfig struct {
 	// entropy (/dev/random, /dev/urandom or real hardware RNG device)
 	EntropySource string
 
+	// ValidKernelPaths, ValidImagePaths and ValidInitrdPaths are the
+	// glob patterns the custom assets requested through the sandbox
+	// annotations must match. An empty list lets any asset of the type
+	// be used.
+	ValidKernelPaths []string
+	ValidImagePaths  []string
+	ValidInitrdPaths []string
+
 	// customAssets is a map of assets.
 	// Each value in that map takes precedence over the configured assets.
 	// For example, if there is a value for the "kernel" key in this map,
@@ -233,6 +389,34 @@ type HypervisorConfig struct {
 	// DisableBlockDeviceUse disallows a block device from being used.
 	DisableBlockDeviceUse bool
 
+	// RBDVolumePassthrough is used to indicate if the volumes backed by a
+	// kernel RBD device are mounted in the guest from that device, rather
+	// than shared with the guest.
+	RBDVolumePassthrough bool
+
+	// ISCSIVolumePassthrough is used to indicate if the volumes backed by
+	// an iSCSI LUN are mounted in the guest from that LUN, rather than
+	// shared with the guest.
+	ISCSIVolumePassthrough bool
+
+	// ImageVolumePassthrough is used to indicate if the volumes whose
+	// source is a raw disk image file are attached to the VM as block
+	// devices, rather than shared with the guest.
+	ImageVolumePassthrough bool
+
+	// EnableVhostUserStore is used to indicate if the sandbox can use the
+	// vhost user backends found in the vhost user store.
+	EnableVhostUserStore bool
+
+	// VhostUserStorePath is the directory holding the sockets of the vhost
+	// user backends, eg. the SPDK vhost-user-scsi controllers under its
+	// "scsi" directory.
+	VhostUserStorePath string
+
+	// VhostUserSCSIController is the name of the vhost-user-scsi controller
+	// of the vhost user store attached to the VM, if any.
+	VhostUserSCSIController string
+
 	// EnableIOThreads enables IO to be processed in a separate thread.
 	// Supported currently for virtio-scsi driver.
 	EnableIOThreads bool
@@ -262,6 +446,14 @@ type HypervisorConfig struct {
 	// UseVSock use a vsock for agent communication
 	UseVSock bool
 
+	// VSockLogPort is the vsock port the agent writes its logs to. The
+	// agent logs are written to the console when it is 0.
+	VSockLogPort uint32
+
+	// VSockDebugConsolePort is the vsock port of the guest debug
+	// console, which is disabled when it is 0.
+	VSockDebugConsolePort uint32
+
 	// HotplugVFIOOnRootBus is used to indicate if devices need to be hotplugged on the
 	// root bus instead of a bridge.
 	HotplugVFIOOnRootBus bool
@@ -275,8 +467,58 @@ type HypervisorConfig struct {
 	// DisableVhostNet is used to indicate if host supports vhost_net
 	DisableVhostNet bool
 
+	// EnablePTPKVM is used to indicate if the guest clock should be
+	// synchronized with the host through the ptp_kvm clock
+	EnablePTPKVM bool
+
+	// EnableIOMMU presents a virtio-iommu device to the guest and puts
+	// the virtio devices behind it, for guest userspace drivers such as
+	// DPDK to drive them, or the passed through devices, with VFIO.
+	EnableIOMMU bool
+
+	// ScratchDiskSize is the size in MiB of the thin provisioned block
+	// device attached to the sandbox and used by the guest as local
+	// storage for /tmp. No scratch disk is attached when it is 0.
+	ScratchDiskSize uint32
+
+	// ScratchWritableLayer is used to indicate if the containers writable
+	// layer should be kept on the scratch disk, on top of their rootfs.
+	ScratchWritableLayer bool
+
 	// GuestHookPath is the path within the VM that will be used for 'drop-in' hooks
 	GuestHookPath string
+
+	// KdumpCrashKernelSize is the size in MiB of the memory the guest
+	// kernel reserves for its crash kernel. Guest kdump is disabled when
+	// it is 0.
+	KdumpCrashKernelSize uint32
+
+	// KdumpDir is the host directory the vmcores written by the guest
+	// crash kernel are collected to.
+	KdumpDir string
+
+	// RemoteHypervisorSocket is the unix socket the out of tree VMM
+	// driver of the remote hypervisor serves on.
+	RemoteHypervisorSocket string
+
+	// EnableCoreScheduling gives the VMM threads of the sandbox their own
+	// core scheduling cookie, so that the SMT siblings of a core never
+	// run the vCPUs of two sandboxes at the same time.
+	EnableCoreScheduling bool
+
+	// VCPUsCPUPool is the cpuset of the host CPUs the vCPU threads are
+	// placed on, eg. the isolcpus of a latency sensitive node. It defaults
+	// to the online CPUs but the housekeeping ones.
+	VCPUsCPUPool string
+
+	// HousekeepingCPUs is the cpuset of the host CPUs the other VMM
+	// threads, the main loop and the I/O threads, are placed on. The VMM
+	// threads are not placed when both are empty.
+	HousekeepingCPUs string
+
+	// MemoryReclaim is the policy ballooning the guest memory the
+	// containers leave unused back to the host.
+	MemoryReclaim MemoryReclaimConfig
 }
 
 // vcpu mapping from vcpu number to thread number
@@ -311,18 +553,54 @@ func (conf *HypervisorConfig) valid() error {
 		return fmt.Errorf("Missing image and initrd path")
 	}
 
+	if err := conf.selectGuestBoot(); err != nil {
+		return err
+	}
+
 	if err := conf.checkTemplateConfig(); err != nil {
 		return err
 	}
 
+	if err := conf.checkFirmwareVars(); err != nil {
+		return err
+	}
+
+	if conf.DefaultVCPUs < 0 {
+		return fmt.Errorf("Invalid number of default vCPUs %v", conf.DefaultVCPUs)
+	}
+
 	if conf.NumVCPUs == 0 {
 		conf.NumVCPUs = defaultVCPUs
+		if conf.DefaultVCPUs > 0 {
+			conf.NumVCPUs = uint32(math.Ceil(float64(conf.DefaultVCPUs)))
+		}
 	}
 
 	if conf.MemorySize == 0 {
 		conf.MemorySize = defaultMemSzMiB
 	}
 
+	if conf.MemSlots > maxMemSlots {
+		return fmt.Errorf("Invalid number of memory slots %d, the maximum is %d", conf.MemSlots, maxMemSlots)
+	}
+
+	if conf.DefaultMaxMemorySize != 0 && conf.DefaultMaxMemorySize < conf.MemorySize {
+		return fmt.Errorf("Maximum memory size %d MiB is lower than the memory size %d MiB",
+			conf.DefaultMaxMemorySize, conf.MemorySize)
+	}
+
+	if err := conf.checkKdumpConfig(); err != nil {
+		return err
+	}
+
+	if err := conf.checkVhostUserSCSIConfig(); err != nil {
+		return err
+	}
+
+	if err := conf.MemoryReclaim.valid(); err != nil {
+		return err
+	}
+
 	if conf.DefaultBridges == 0 {
 		conf.DefaultBridges = defaultBridges
 	}
@@ -335,13 +613,199 @@ func (conf *HypervisorConfig) valid() error {
 		conf.DefaultMaxVCPUs = defaultMaxQemuVCPUs
 	}
 
+	if conf.NumVCPUs > conf.DefaultMaxVCPUs {
+		return fmt.Errorf("Number of vCPUs %d exceeds the maximum number of vCPUs %d",
+			conf.NumVCPUs, conf.DefaultMaxVCPUs)
+	}
+
 	if conf.Msize9p == 0 {
 		conf.Msize9p = defaultMsize9p
 	}
 
+	if err := conf.check9p(); err != nil {
+		return err
+	}
+
+	if err := conf.checkVSockPorts(); err != nil {
+		return err
+	}
+
+	if err := conf.checkCPUFeatures(); err != nil {
+		return err
+	}
+
+	switch conf.KernelIRQChip {
+	case "", "on", "off", "split":
+	default:
+		return fmt.Errorf("Invalid kernel irqchip %q, expecting on, off or split", conf.KernelIRQChip)
+	}
+
+	switch conf.PCIHotplug {
+	case "", PCIHotplugACPI, PCIHotplugSHPC:
+	case PCIHotplugNative:
+		if conf.PCIeRootPorts == 0 {
+			conf.PCIeRootPorts = defaultPCIeRootPorts
+		}
+	default:
+		return fmt.Errorf("Invalid PCI hotplug %q, expecting acpi, shpc or native", conf.PCIHotplug)
+	}
+
+	switch conf.Accelerator {
+	case "", "kvm", "hvf", "tcg":
+	default:
+		return fmt.Errorf("Invalid accelerator %q, expecting kvm, hvf or tcg", conf.Accelerator)
+	}
+
+	return nil
+}
+
+// selectGuestBoot keeps the guest artifact the VM boots from, image or
+// initrd, when both are configured.
+func (conf *HypervisorConfig) selectGuestBoot() error {
+	switch conf.GuestBoot {
+	case "":
+	case imageGuestBoot:
+		if conf.ImagePath == "" {
+			return fmt.Errorf("Cannot boot from an image, no guest image configured")
+		}
+		conf.InitrdPath = ""
+	case initrdGuestBoot:
+		if conf.InitrdPath == "" {
+			return fmt.Errorf("Cannot boot from an initrd, no guest initrd configured")
+		}
+		conf.ImagePath = ""
+	default:
+		return fmt.Errorf("Invalid guest boot %q, expecting %s or %s", conf.GuestBoot, imageGuestBoot, initrdGuestBoot)
+	}
+
+	return nil
+}
+
+// checkCPUFeatures makes sure that each CPU feature is either enabled,
+// disabled or set to a value.
+func (conf *HypervisorConfig) checkCPUFeatures() error {
+	if conf.CPUFeatures == "" {
+		return nil
+	}
+
+	for _, f := range strings.Split(conf.CPUFeatures, ",") {
+		if len(f) < 2 || strings.ContainsAny(f, " \t") ||
+			(f[0] != '+' && f[0] != '-' && !strings.Contains(f, "=")) {
+			return fmt.Errorf("Invalid CPU feature %q, expecting +feature, -feature or feature=value", f)
+		}
+	}
+
+	return nil
+}
+
+// checkFirmwareVars makes sure the UEFI variable store can be used.
+func (conf *HypervisorConfig) checkFirmwareVars() error {
+	if conf.FirmwareVarsPath == "" {
+		if conf.SecureBoot {
+			return fmt.Errorf("Secure Boot needs a firmware variable store")
+		}
+		return nil
+	}
+
+	// the VMs created from the template would share its copy
+	if conf.BootToBeTemplate && !conf.FirmwareVarsReadOnly {
+		return fmt.Errorf("A template VM can only use a read-only firmware variable store")
+	}
+
+	return nil
+}
+
+// check9p sets the default 9p mount options and makes sure the configured
+// ones are understood by both the guest kernel and QEMU.
+func (conf *HypervisorConfig) check9p() error {
+	if conf.Msize9p < minMsize9p {
+		return fmt.Errorf("Invalid 9p msize %d, it must be at least %d", conf.Msize9p, minMsize9p)
+	}
+
+	if conf.Cache9p == "" {
+		conf.Cache9p = defaultCache9p
+	}
+
+	switch conf.Cache9p {
+	case "none", "loose", "fscache", "mmap":
+	default:
+		return fmt.Errorf("Invalid 9p cache mode %q, expecting none, loose, fscache or mmap", conf.Cache9p)
+	}
+
+	if conf.Version9p == "" {
+		conf.Version9p = defaultVersion9p
+	}
+
+	switch conf.Version9p {
+	case "9p2000.L", "9p2000.u":
+	default:
+		return fmt.Errorf("Invalid 9p version %q, expecting 9p2000.L or 9p2000.u", conf.Version9p)
+	}
+
+	return nil
+}
+
+// checkVSockPorts makes sure that each agent channel uses its own port, the
+// agent always listening on vSockPort for the runtime requests.
+func (conf *HypervisorConfig) checkVSockPorts() error {
+	ports := map[uint32]string{uint32(vSockPort): "agent"}
+
+	for _, p := range []struct {
+		name string
+		port uint32
+	}{
+		{"log", conf.VSockLogPort},
+		{"debug console", conf.VSockDebugConsolePort},
+	} {
+		if p.port == 0 {
+			continue
+		}
+
+		if p.port == vsockPortAny {
+			return fmt.Errorf("Invalid vsock %s port %d", p.name, p.port)
+		}
+
+		if name, ok := ports[p.port]; ok {
+			return fmt.Errorf("The vsock %s port %d is already used by the %s", p.name, p.port, name)
+		}
+		ports[p.port] = p.name
+	}
+
 	return nil
 }
 
+// EnableVSockLog has the agent write its logs to a vsock port, picking
+// the first port after the agent one that no other channel uses when none
+// is configured. It does nothing when vsocks are not used.
+func (conf *HypervisorConfig) EnableVSockLog() {
+	if !conf.UseVSock || conf.VSockLogPort != 0 {
+		return
+	}
+
+	port := uint32(vSockPort) + 1
+	if port == conf.VSockDebugConsolePort {
+		port++
+	}
+
+	conf.VSockLogPort = port
+}
+
+// vsockKernelParams returns the kernel parameters telling the agent which
+// vsock ports its channels use, when they differ from the agent defaults.
+func (conf *HypervisorConfig) vsockKernelParams() []Param {
+	var params []Param
+
+	if conf.VSockLogPort != 0 {
+		params = append(params, Param{vsockLogPortKernelOption, fmt.Sprintf("%d", conf.VSockLogPort)})
+	}
+
+	if conf.VSockDebugConsolePort != 0 {
+		params = append(params, Param{vsockDebugConsolePortKernelOption, fmt.Sprintf("%d", conf.VSockDebugConsolePort)})
+	}
+
+	return params
+}
+
 // AddKernelParam allows the addition of new kernel parameters to an existing
 // hypervisor configuration.
 func (conf *HypervisorConfig) AddKernelParam(p Param) error {
@@ -364,6 +828,10 @@ func (conf *HypervisorConfig) addCustomAsset(a *types.Asset) error {
 		return fmt.Errorf("Invalid %s at %s", a.Type(), a.Path())
 	}
 
+	if err := conf.checkCustomAssetPath(a); err != nil {
+		return err
+	}
+
 	virtLog.Debugf("Using custom %v asset %s", a.Type(), a.Path())
 
 	if conf.customAssets == nil {
@@ -375,6 +843,38 @@ func (conf *HypervisorConfig) addCustomAsset(a *types.Asset) error {
 	return nil
 }
 
+// checkCustomAssetPath makes sure that the custom asset is one of the
+// permitted ones.
+func (conf *HypervisorConfig) checkCustomAssetPath(a *types.Asset) error {
+	var patterns []string
+
+	switch a.Type() {
+	case types.KernelAsset:
+		patterns = conf.ValidKernelPaths
+	case types.ImageAsset:
+		patterns = conf.ValidImagePaths
+	case types.InitrdAsset:
+		patterns = conf.ValidInitrdPaths
+	}
+
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	path := filepath.Clean(a.Path())
+	for _, pattern := range patterns {
+		match, err := filepath.Match(pattern, path)
+		if err != nil {
+			return fmt.Errorf("Invalid %s path pattern %q: %v", a.Type(), pattern, err)
+		}
+		if match {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Custom %s %s is not one of the permitted ones", a.Type(), a.Path())
+}
+
 func (conf *HypervisorConfig) assetPath(t types.AssetType) (string, error) {
 	// Custom assets take precedence over the configured ones
 	a, ok := conf.customAssets[t]
@@ -533,6 +1033,35 @@ func getHostMemorySizeKb(memInfoPath string) (uint64, error) {
 	return 0, fmt.Errorf("unable get MemTotal from %s", memInfoPath)
 }
 
+// getHostThreadsPerCore returns the number of hardware threads per core from
+// a CPU list like "0,64" or "0-1".
+func getHostThreadsPerCore(siblingsPath string) (uint32, error) {
+	data, err := ioutil.ReadFile(siblingsPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var threads uint32
+	for _, r := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse %s: %v", siblingsPath, err)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 32); err != nil || last < first {
+				return 0, fmt.Errorf("unable to parse %s: invalid range %q", siblingsPath, r)
+			}
+		}
+
+		threads += uint32(last - first + 1)
+	}
+
+	return threads, nil
+}
+
 // RunningOnVMM checks if the system is running inside a VM.
 func RunningOnVMM(cpuInfoPath string) (bool, error) {
 	if runtime.GOARCH == "arm64" || runtime.GOARCH == "ppc64le" || runtime.GOARCH == "s390x" {
@@ -594,6 +1123,8 @@ type hypervisor interface {
 	hotplugRemoveDevice(devInfo interface{}, devType deviceType) (interface{}, error)
 	resizeMemory(memMB uint32, memoryBlockSizeMB uint32) (uint32, error)
 	resizeVCPUs(vcpus uint32) (uint32, uint32, error)
+	dedicateVCPUs(owner string, count uint32) ([]int, error)
+	releaseVCPUs(owner string) (uint32, error)
 	getSandboxConsole(sandboxID string) (string, error)
 	disconnect()
 	capabilities() types.Capabilities
@@ -12,6 +12,8 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func testSetHypervisorType(t *testing.T, value string, expected HypervisorType) {
@@ -157,6 +159,70 @@ func TestHypervisorConfigIsValid(t *testing.T) {
 	testHypervisorConfigValid(t, hypervisorConfig, true)
 }
 
+func TestHypervisorConfigMemory(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		MemSlots:       maxMemSlots + 1,
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.MemSlots = maxMemSlots
+	hypervisorConfig.MemorySize = 4096
+	hypervisorConfig.DefaultMaxMemorySize = 2048
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.DefaultMaxMemorySize = 8192
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+}
+
+func TestHypervisorConfigCPUFeatures(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		CPUFeatures:    "-vmx,+avx512f,pmu=off",
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+
+	for _, features := range []string{"vmx", "+avx512f,", "+", "-vmx, +avx512f"} {
+		hypervisorConfig.CPUFeatures = features
+		testHypervisorConfigValid(t, hypervisorConfig, false)
+	}
+}
+
+func TestHypervisorConfigKernelIRQChip(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		KernelIRQChip:  "split",
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+
+	hypervisorConfig.KernelIRQChip = "userspace"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
+
+func TestHypervisorConfigPCIHotplug(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		PCIHotplug:     PCIHotplugNative,
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Equal(t, uint32(defaultPCIeRootPorts), hypervisorConfig.PCIeRootPorts)
+
+	hypervisorConfig.PCIHotplug = "acpiphp"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
+
 func TestHypervisorConfigValidTemplateConfig(t *testing.T) {
 	hypervisorConfig := &HypervisorConfig{
 		KernelPath:       fmt.Sprintf("%s/%s", testDir, testKernel),
@@ -199,6 +265,8 @@ func TestHypervisorConfigDefaults(t *testing.T) {
 		BlockDeviceDriver: defaultBlockDriver,
 		DefaultMaxVCPUs:   defaultMaxQemuVCPUs,
 		Msize9p:           defaultMsize9p,
+		Cache9p:           defaultCache9p,
+		Version9p:         defaultVersion9p,
 	}
 
 	if reflect.DeepEqual(hypervisorConfig, hypervisorConfigDefaultsExpected) == false {
@@ -206,6 +274,131 @@ func TestHypervisorConfigDefaults(t *testing.T) {
 	}
 }
 
+func TestHypervisorConfigVSockPorts(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []struct {
+		log, debug uint32
+		valid      bool
+	}{
+		{0, 0, true},
+		{2049, 2050, true},
+		{1025, 0, true},
+		{1024, 0, false},
+		{0, 1024, false},
+		{1025, 1025, false},
+		{vsockPortAny, 0, false},
+		{0, vsockPortAny, false},
+	}
+
+	for _, d := range data {
+		conf := &HypervisorConfig{
+			VSockLogPort:          d.log,
+			VSockDebugConsolePort: d.debug,
+		}
+
+		err := conf.checkVSockPorts()
+		if d.valid {
+			assert.NoError(err, "%+v", d)
+		} else {
+			assert.Error(err, "%+v", d)
+		}
+	}
+}
+
+func TestHypervisorConfigCheckFirmwareVars(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := &HypervisorConfig{}
+	assert.NoError(conf.checkFirmwareVars())
+
+	conf.SecureBoot = true
+	assert.Error(conf.checkFirmwareVars())
+
+	conf.FirmwareVarsPath = "/usr/share/OVMF/OVMF_VARS.fd"
+	assert.NoError(conf.checkFirmwareVars())
+
+	conf.BootToBeTemplate = true
+	assert.Error(conf.checkFirmwareVars())
+
+	conf.FirmwareVarsReadOnly = true
+	assert.NoError(conf.checkFirmwareVars())
+}
+
+func TestHypervisorConfigCheck9p(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []struct {
+		msize          uint32
+		cache, version string
+		valid          bool
+	}{
+		{defaultMsize9p, "", "", true},
+		{512 * 1024, "loose", "9p2000.u", true},
+		{minMsize9p - 1, "", "", false},
+		{defaultMsize9p, "writeback", "", false},
+		{defaultMsize9p, "", "9p2000", false},
+	}
+
+	for _, d := range data {
+		conf := &HypervisorConfig{
+			Msize9p:   d.msize,
+			Cache9p:   d.cache,
+			Version9p: d.version,
+		}
+
+		err := conf.check9p()
+		if !d.valid {
+			assert.Error(err, "%+v", d)
+			continue
+		}
+
+		assert.NoError(err, "%+v", d)
+		assert.NotEmpty(conf.Cache9p)
+		assert.NotEmpty(conf.Version9p)
+	}
+}
+
+func TestHypervisorConfigVSockKernelParams(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := &HypervisorConfig{}
+	assert.Empty(conf.vsockKernelParams())
+
+	conf.VSockLogPort = 2049
+	conf.VSockDebugConsolePort = 2050
+
+	expected := []Param{
+		{vsockLogPortKernelOption, "2049"},
+		{vsockDebugConsolePortKernelOption, "2050"},
+	}
+	assert.Equal(expected, conf.vsockKernelParams())
+}
+
+func TestHypervisorConfigEnableVSockLog(t *testing.T) {
+	assert := assert.New(t)
+
+	// no vsock, no log port
+	conf := &HypervisorConfig{}
+	conf.EnableVSockLog()
+	assert.Zero(conf.VSockLogPort)
+
+	conf.UseVSock = true
+	conf.EnableVSockLog()
+	assert.Equal(uint32(vSockPort+1), conf.VSockLogPort)
+
+	// the configured port is kept
+	conf.VSockLogPort = 4000
+	conf.EnableVSockLog()
+	assert.Equal(uint32(4000), conf.VSockLogPort)
+
+	// the debug console port is skipped
+	conf = &HypervisorConfig{UseVSock: true, VSockDebugConsolePort: uint32(vSockPort + 1)}
+	conf.EnableVSockLog()
+	assert.Equal(uint32(vSockPort+2), conf.VSockLogPort)
+	assert.NoError(conf.checkVSockPorts())
+}
+
 func TestAppendParams(t *testing.T) {
 	paramList := []Param{
 		{
@@ -378,6 +571,37 @@ func TestAddKernelParamInvalid(t *testing.T) {
 	}
 }
 
+func TestGetHostThreadsPerCore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "threads")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "thread_siblings_list")
+
+	for list, threads := range map[string]uint32{
+		"0\n":       1,
+		"0,64\n":    2,
+		"0-3\n":     4,
+		"0-1,8-9\n": 4,
+	} {
+		assert.NoError(ioutil.WriteFile(path, []byte(list), 0644))
+		n, err := getHostThreadsPerCore(path)
+		assert.NoError(err)
+		assert.Equal(threads, n, list)
+	}
+
+	for _, list := range []string{"", "a", "3-1"} {
+		assert.NoError(ioutil.WriteFile(path, []byte(list), 0644))
+		_, err := getHostThreadsPerCore(path)
+		assert.Error(err, list)
+	}
+
+	_, err = getHostThreadsPerCore(filepath.Join(dir, "missing"))
+	assert.Error(err)
+}
+
 func TestGetHostMemorySizeKb(t *testing.T) {
 
 	type testData struct {
@@ -474,3 +698,62 @@ func genericTestRunningOnVMM(t *testing.T, data []testNestedVMMData) {
 		}
 	}
 }
+
+func TestHypervisorConfigDefaultVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		DefaultVCPUs:   1.5,
+	}
+
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Equal(uint32(2), hypervisorConfig.NumVCPUs)
+
+	hypervisorConfig.DefaultMaxVCPUs = 1
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.DefaultMaxVCPUs = 2
+	hypervisorConfig.DefaultVCPUs = -1
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
+
+func TestHypervisorConfigGuestBoot(t *testing.T) {
+	assert := assert.New(t)
+
+	imagePath := fmt.Sprintf("%s/%s", testDir, testImage)
+	initrdPath := fmt.Sprintf("%s/%s", testDir, "initrd")
+
+	newConfig := func(guestBoot string) *HypervisorConfig {
+		return &HypervisorConfig{
+			KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+			ImagePath:      imagePath,
+			InitrdPath:     initrdPath,
+			HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+			GuestBoot:      guestBoot,
+		}
+	}
+
+	hypervisorConfig := newConfig(imageGuestBoot)
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Equal(imagePath, hypervisorConfig.ImagePath)
+	assert.Empty(hypervisorConfig.InitrdPath)
+
+	hypervisorConfig = newConfig(initrdGuestBoot)
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Empty(hypervisorConfig.ImagePath)
+	assert.Equal(initrdPath, hypervisorConfig.InitrdPath)
+
+	testHypervisorConfigValid(t, newConfig("disk"), false)
+
+	// the selected artifact must be configured
+	hypervisorConfig = newConfig(imageGuestBoot)
+	hypervisorConfig.ImagePath = ""
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig = newConfig(initrdGuestBoot)
+	hypervisorConfig.InitrdPath = ""
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
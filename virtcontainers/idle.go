@@ -0,0 +1,302 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idleCPUThreshold is the share of a host CPU the hypervisor process can use
+// while the sandbox is still considered idle. The timers of an idle guest
+// keep it well below that.
+const idleCPUThreshold = 0.05
+
+// clockTicks is USER_HZ, the unit of the process CPU times found in
+// /proc/<pid>/stat.
+const clockTicks = 100
+
+var (
+	procPidStat   = "/proc/%d/stat"
+	procPidNetDev = "/proc/%d/net/dev"
+)
+
+// idleTracker pauses the sandbox VM once it has been idle for a while, and
+// resumes it on the next agent request or on the next packet received by the
+// sandbox. The sandbox is idle when no agent request has been sent, no
+// process output has been read, no packet has gone through the sandbox
+// network and the hypervisor process, which runs the guest processes, has
+// hardly used any CPU time during the timeout. The health checks and the
+// stats and wait polls do not count as agent requests.
+type idleTracker struct {
+	sync.Mutex
+
+	sandbox *Sandbox
+	timeout time.Duration
+
+	lastActive time.Time
+	lastSample time.Time
+	lastCPU    time.Duration
+	lastNet    uint64
+	paused     bool
+
+	// stats are the last container stats read from the agent, which
+	// do not change while the VM is paused.
+	stats map[string]*ContainerStats
+}
+
+func newIdleTracker(s *Sandbox, timeout time.Duration) *idleTracker {
+	now := time.Now()
+
+	return &idleTracker{
+		sandbox:    s,
+		timeout:    timeout,
+		lastActive: now,
+		lastSample: now,
+	}
+}
+
+// activity records that the sandbox is in use, resuming the VM first if it
+// has been paused because it was idle.
+func (t *idleTracker) activity() error {
+	if t == nil {
+		return nil
+	}
+
+	t.Lock()
+	t.lastActive = time.Now()
+	resumed, err := t.resume()
+	t.Unlock()
+
+	if resumed {
+		t.resumed()
+	}
+
+	return err
+}
+
+// poll resumes the VM, if it has been paused because it was idle, for a
+// request polling the sandbox which has to reach the agent. The request does
+// not restart the idle period.
+func (t *idleTracker) poll() error {
+	if t == nil {
+		return nil
+	}
+
+	t.Lock()
+	resumed, err := t.resume()
+	t.Unlock()
+
+	if resumed {
+		t.resumed()
+	}
+
+	return err
+}
+
+// pausedStats returns the last stats of a container read from the agent if
+// the VM is paused, nil otherwise.
+func (t *idleTracker) pausedStats(containerID string) *ContainerStats {
+	if t == nil {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if !t.paused {
+		return nil
+	}
+
+	return t.stats[containerID]
+}
+
+func (t *idleTracker) recordStats(containerID string, stats *ContainerStats) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.stats == nil {
+		t.stats = make(map[string]*ContainerStats)
+	}
+	t.stats[containerID] = stats
+}
+
+// resume resumes the VM if it has been paused because it was idle. It must
+// be called with the tracker locked.
+func (t *idleTracker) resume() (bool, error) {
+	if !t.paused {
+		return false, nil
+	}
+
+	if err := t.sandbox.hypervisor.resumeSandbox(); err != nil {
+		return false, err
+	}
+	t.paused = false
+	// the stats of removed containers go away with the next pause
+	t.stats = nil
+
+	return true, nil
+}
+
+func (t *idleTracker) resumed() {
+	t.sandbox.Logger().Info("resumed idle sandbox")
+	// the guest clock did not move while the VM was paused
+	t.sandbox.syncGuestTime()
+}
+
+func (t *idleTracker) isPaused() bool {
+	if t == nil {
+		return false
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.paused
+}
+
+// check pauses the VM if the sandbox has been idle for the timeout, and
+// resumes a paused VM as soon as a packet reaches the sandbox network.
+func (t *idleTracker) check(now time.Time) {
+	if t == nil {
+		return
+	}
+
+	t.Lock()
+
+	pid := t.sandbox.hypervisor.pid()
+
+	netActive := false
+	if bytes, err := networkBytes(pid); err == nil {
+		netActive = bytes != t.lastNet
+		t.lastNet = bytes
+	}
+
+	if t.paused {
+		if !netActive {
+			t.Unlock()
+			return
+		}
+
+		t.lastActive = now
+		resumed, err := t.resume()
+		t.Unlock()
+
+		if err != nil {
+			t.sandbox.Logger().WithError(err).Warn("failed to resume idle sandbox on network activity")
+		} else if resumed {
+			t.resumed()
+		}
+		return
+	}
+
+	defer t.Unlock()
+
+	if netActive {
+		t.lastActive = now
+	}
+
+	cpu, err := processCPUTime(pid)
+	if err == nil {
+		elapsed := now.Sub(t.lastSample)
+		if elapsed > 0 && float64(cpu-t.lastCPU) > idleCPUThreshold*float64(elapsed) {
+			t.lastActive = now
+		}
+		t.lastCPU = cpu
+		t.lastSample = now
+	}
+
+	if now.Sub(t.lastActive) < t.timeout {
+		return
+	}
+
+	if err := t.sandbox.hypervisor.pauseSandbox(); err != nil {
+		t.sandbox.Logger().WithError(err).Warn("failed to pause idle sandbox")
+		return
+	}
+	t.paused = true
+
+	t.sandbox.Logger().WithField("idle", now.Sub(t.lastActive)).Info("paused idle sandbox")
+}
+
+// networkBytes returns the bytes received and sent on the interfaces of the
+// network namespace of a process, the loopback aside.
+func networkBytes(pid int) (uint64, error) {
+	if pid <= 0 {
+		return 0, fmt.Errorf("Invalid pid %d", pid)
+	}
+
+	data, err := ioutil.ReadFile(fmt.Sprintf(procPidNetDev, pid))
+	if err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	// The first two lines are headers, each interface line is
+	// "name: <8 receive fields> <8 transmit fields>".
+	for _, line := range strings.Split(string(data), "\n") {
+		sep := strings.Index(line, ":")
+		if sep < 0 || strings.TrimSpace(line[:sep]) == "lo" {
+			continue
+		}
+
+		fields := strings.Fields(line[sep+1:])
+		if len(fields) < 9 {
+			continue
+		}
+
+		// receive and transmit bytes
+		for _, f := range []string{fields[0], fields[8]} {
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			total += v
+		}
+	}
+
+	return total, nil
+}
+
+// processCPUTime returns the user and system CPU time used by a process.
+func processCPUTime(pid int) (time.Duration, error) {
+	if pid <= 0 {
+		return 0, fmt.Errorf("Invalid pid %d", pid)
+	}
+
+	data, err := ioutil.ReadFile(fmt.Sprintf(procPidStat, pid))
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name can contain spaces and parentheses, the
+	// fields of interest follow the last parenthesis.
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("Unexpected format for %s", fmt.Sprintf(procPidStat, pid))
+	}
+
+	var ticks uint64
+	// utime and stime
+	for _, f := range fields[11:13] {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		ticks += v
+	}
+
+	return time.Duration(ticks) * time.Second / clockTicks, nil
+}
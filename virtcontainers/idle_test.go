@@ -0,0 +1,165 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessCPUTime(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := processCPUTime(0)
+	assert.Error(err)
+
+	_, err = processCPUTime(os.Getpid())
+	assert.NoError(err)
+
+	dir, err := ioutil.TempDir("", "idle")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcPidStat := procPidStat
+	defer func() {
+		procPidStat = savedProcPidStat
+	}()
+	procPidStat = filepath.Join(dir, "%d")
+
+	stat := "1234 (qemu (x) y) S 1 1234 1234 0 -1 4210944 100 0 0 0 250 150 0 0 20 0 4 0 100 0 0"
+	err = ioutil.WriteFile(filepath.Join(dir, "1234"), []byte(stat), 0600)
+	assert.NoError(err)
+
+	cpu, err := processCPUTime(1234)
+	assert.NoError(err)
+	assert.Equal(4*time.Second, cpu)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "1234"), []byte("1234 (qemu) S 1"), 0600)
+	assert.NoError(err)
+	_, err = processCPUTime(1234)
+	assert.Error(err)
+}
+
+func TestIdleTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	// a nil tracker, when the feature is disabled, does nothing
+	var nilTracker *idleTracker
+	assert.NoError(nilTracker.activity())
+	assert.False(nilTracker.isPaused())
+	nilTracker.check(time.Now())
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{},
+		agent:      &noopAgent{},
+	}
+	tracker := newIdleTracker(s, time.Minute)
+
+	now := time.Now()
+	tracker.check(now)
+	assert.False(tracker.isPaused())
+
+	tracker.check(now.Add(2 * time.Minute))
+	assert.True(tracker.isPaused())
+
+	assert.NoError(tracker.activity())
+	assert.False(tracker.isPaused())
+
+	// the activity restarted the idle period
+	tracker.check(time.Now().Add(30 * time.Second))
+	assert.False(tracker.isPaused())
+}
+
+func TestNetworkBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := networkBytes(0)
+	assert.Error(err)
+
+	dir, err := ioutil.TempDir("", "idle")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcPidNetDev := procPidNetDev
+	defer func() {
+		procPidNetDev = savedProcPidNetDev
+	}()
+	procPidNetDev = filepath.Join(dir, "%d")
+
+	netDev := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0:     300       3    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+ tap0_kata: 50       1    0    0    0     0          0         0       25       1    0    0    0     0       0          0
+`
+	err = ioutil.WriteFile(filepath.Join(dir, "1234"), []byte(netDev), 0600)
+	assert.NoError(err)
+
+	bytes, err := networkBytes(1234)
+	assert.NoError(err)
+	assert.Equal(uint64(575), bytes)
+}
+
+func TestIdleTrackerNetworkActivity(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "idle")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedProcPidNetDev := procPidNetDev
+	defer func() {
+		procPidNetDev = savedProcPidNetDev
+	}()
+	procPidNetDev = filepath.Join(dir, "%d")
+
+	// only the network activity is accounted
+	savedProcPidStat := procPidStat
+	defer func() {
+		procPidStat = savedProcPidStat
+	}()
+	procPidStat = filepath.Join(dir, "stat-%d")
+
+	setBytes := func(rx string) {
+		netDev := "  eth0: " + rx + " 1 0 0 0 0 0 0 100 1 0 0 0 0 0 0\n"
+		assert.NoError(ioutil.WriteFile(filepath.Join(dir, "1234"), []byte(netDev), 0600))
+	}
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{mockPid: 1234},
+		agent:      &noopAgent{},
+	}
+	tracker := newIdleTracker(s, time.Minute)
+
+	now := time.Now()
+	setBytes("100")
+	tracker.check(now)
+	assert.False(tracker.isPaused())
+
+	// traffic keeps the sandbox active
+	setBytes("200")
+	tracker.check(now.Add(2 * time.Minute))
+	assert.False(tracker.isPaused())
+
+	tracker.check(now.Add(4 * time.Minute))
+	assert.True(tracker.isPaused())
+
+	// no traffic, the sandbox stays paused
+	tracker.check(now.Add(5 * time.Minute))
+	assert.True(tracker.isPaused())
+
+	// an inbound packet resumes it
+	setBytes("300")
+	tracker.check(now.Add(6 * time.Minute))
+	assert.False(tracker.isPaused())
+}
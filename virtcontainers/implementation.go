@@ -56,6 +56,11 @@ func (impl *VCImpl) StopSandbox(ctx context.Context, sandboxID string) (VCSandbo
 	return StopSandbox(ctx, sandboxID)
 }
 
+// CleanupSandbox implements the VC function of the same name.
+func (impl *VCImpl) CleanupSandbox(ctx context.Context, sandboxID string, force bool) error {
+	return CleanupSandbox(ctx, sandboxID, force)
+}
+
 // RunSandbox implements the VC function of the same name.
 func (impl *VCImpl) RunSandbox(ctx context.Context, sandboxConfig SandboxConfig) (VCSandbox, error) {
 	return RunSandbox(ctx, sandboxConfig, impl.factory)
@@ -176,3 +181,8 @@ func (impl *VCImpl) UpdateRoutes(ctx context.Context, sandboxID string, routes [
 func (impl *VCImpl) ListRoutes(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error) {
 	return ListRoutes(ctx, sandboxID)
 }
+
+// HypervisorCommandLine implements the VC function of the same name.
+func (impl *VCImpl) HypervisorCommandLine(ctx context.Context, sandboxConfig SandboxConfig) ([]string, error) {
+	return HypervisorCommandLine(ctx, sandboxConfig)
+}
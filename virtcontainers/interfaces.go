@@ -18,11 +18,29 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// APIVersion is the semantic version of the API embedders program against:
+// the VC, VCSandbox and VCContainer interfaces, the functions of api.go and
+// the configuration and status types they take and return.
+//
+// The minor version is bumped when functions or fields are added, the major
+// version on any change breaking existing callers or implementations,
+// including a method added to one of the interfaces. The rest of the package
+// and its sub-packages are not covered.
+const APIVersion = "2.0.0"
+
+// The supported implementations of the API.
+var (
+	_ VC          = &VCImpl{}
+	_ VCSandbox   = &Sandbox{}
+	_ VCContainer = &Container{}
+)
+
 // VC is the Virtcontainers interface
 type VC interface {
 	SetLogger(ctx context.Context, logger *logrus.Entry)
 	SetFactory(ctx context.Context, factory Factory)
 
+	CleanupSandbox(ctx context.Context, sandboxID string, force bool) error
 	CreateSandbox(ctx context.Context, sandboxConfig SandboxConfig) (VCSandbox, error)
 	DeleteSandbox(ctx context.Context, sandboxID string) (VCSandbox, error)
 	FetchSandbox(ctx context.Context, sandboxID string) (VCSandbox, error)
@@ -54,6 +72,8 @@ type VC interface {
 	ListInterfaces(ctx context.Context, sandboxID string) ([]*vcTypes.Interface, error)
 	UpdateRoutes(ctx context.Context, sandboxID string, routes []*vcTypes.Route) ([]*vcTypes.Route, error)
 	ListRoutes(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error)
+
+	HypervisorCommandLine(ctx context.Context, sandboxConfig SandboxConfig) ([]string, error)
 }
 
 // VCSandbox is the Sandbox interface
@@ -73,8 +93,10 @@ type VCSandbox interface {
 	Resume() error
 	Release() error
 	Monitor() (chan error, error)
+	HypervisorExited() bool
 	Delete() error
 	Status() SandboxStatus
+	Dump() SandboxDump
 	CreateContainer(contConfig ContainerConfig) (VCContainer, error)
 	DeleteContainer(contID string) (VCContainer, error)
 	StartContainer(containerID string) (VCContainer, error)
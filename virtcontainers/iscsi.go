@@ -0,0 +1,83 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// dmMultipathUUIDPrefix is the prefix of the device mapper UUID of the
+// multipath devices.
+const dmMultipathUUIDPrefix = "mpath-"
+
+var checkISCSIDevice = isISCSIDevice
+
+// iscsiSessionRegexp and iscsiTargetRegexp match the sysfs path components
+// of an iSCSI session and of its SCSI target.
+var (
+	iscsiSessionRegexp = regexp.MustCompile(`^session[0-9]+$`)
+	iscsiTargetRegexp  = regexp.MustCompile(`^target[0-9]+:[0-9]+:[0-9]+$`)
+)
+
+// isISCSISysPath checks if a block device sysfs path is below an iSCSI
+// session, eg. /sys/devices/platform/host2/session1/target2:0:0/2:0:0:1/block/sdb
+func isISCSISysPath(path string) bool {
+	components := strings.Split(filepath.Clean(path), "/")
+	for i := 0; i+1 < len(components); i++ {
+		if iscsiSessionRegexp.MatchString(components[i]) && iscsiTargetRegexp.MatchString(components[i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isISCSIDevice checks if the device with the major and minor numbers is an
+// iSCSI LUN, one of its partitions, or a multipath device made of iSCSI
+// LUNs.
+func isISCSIDevice(major, minor int) (bool, error) {
+	sysPath := fmt.Sprintf(sysDevBlockTemplate, major, minor)
+
+	target, err := filepath.EvalSymlinks(sysPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if isISCSISysPath(target) {
+		return true, nil
+	}
+
+	uuid, err := ioutil.ReadFile(filepath.Join(sysPath, "dm", "uuid"))
+	if err != nil || !strings.HasPrefix(string(uuid), dmMultipathUUIDPrefix) {
+		return false, nil
+	}
+
+	slaves, err := ioutil.ReadDir(filepath.Join(sysPath, "slaves"))
+	if err != nil || len(slaves) == 0 {
+		return false, err
+	}
+
+	for _, s := range slaves {
+		path, err := filepath.EvalSymlinks(filepath.Join(sysPath, "slaves", s.Name()))
+		if err != nil {
+			return false, err
+		}
+
+		if !isISCSISysPath(path) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
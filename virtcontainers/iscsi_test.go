@@ -0,0 +1,91 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsISCSIDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "sys-dev-block")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedTemplate := sysDevBlockTemplate
+	defer func() {
+		sysDevBlockTemplate = savedTemplate
+	}()
+	sysDevBlockTemplate = filepath.Join(dir, "%d:%d")
+
+	session := filepath.Join(dir, "devices/platform/host2/session1/target2:0:0")
+	local := filepath.Join(dir, "devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0")
+
+	mkdev := func(dev, target string) string {
+		assert.NoError(os.MkdirAll(target, 0755))
+		assert.NoError(os.Symlink(target, filepath.Join(dir, dev)))
+		return target
+	}
+
+	// iSCSI LUNs, and a partition of one of them
+	sdb := mkdev("8:16", filepath.Join(session, "2:0:0:1/block/sdb"))
+	mkdev("8:32", filepath.Join(session, "2:0:0:2/block/sdc"))
+	part := mkdev("8:17", filepath.Join(sdb, "sdb1"))
+	assert.NoError(ioutil.WriteFile(filepath.Join(part, "partition"), []byte("1"), 0644))
+
+	// a local disk
+	sda := mkdev("8:0", filepath.Join(local, "0:0:0:0/block/sda"))
+
+	mkMultipath := func(dev string, slaves ...string) {
+		target := mkdev(dev, filepath.Join(dir, "devices/virtual/block", dev))
+		assert.NoError(os.MkdirAll(filepath.Join(target, "dm"), 0755))
+		assert.NoError(ioutil.WriteFile(filepath.Join(target, "dm", "uuid"), []byte("mpath-3600140"+dev), 0644))
+		assert.NoError(os.MkdirAll(filepath.Join(target, "slaves"), 0755))
+		for _, s := range slaves {
+			assert.NoError(os.Symlink(s, filepath.Join(target, "slaves", filepath.Base(s))))
+		}
+	}
+
+	mkMultipath("253:0", sdb, filepath.Join(session, "2:0:0:2/block/sdc"))
+	mkMultipath("253:1", sdb, sda)
+
+	for _, d := range []struct {
+		major, minor int
+		isISCSI      bool
+	}{
+		{8, 16, true},
+		{8, 17, true},
+		{8, 0, false},
+		{253, 0, true},
+		{253, 1, false},
+		{8, 48, false},
+	} {
+		isISCSI, err := isISCSIDevice(d.major, d.minor)
+		assert.NoError(err)
+		assert.Equal(d.isISCSI, isISCSI, "%d:%d", d.major, d.minor)
+	}
+}
+
+func TestIsISCSISysPath(t *testing.T) {
+	assert := assert.New(t)
+
+	for path, isISCSI := range map[string]bool{
+		"/sys/devices/platform/host2/session1/target2:0:0/2:0:0:1/block/sdb":     true,
+		"/sys/devices/platform/host12/session10/target12:0:0/12:0:0:0/block/sdc": true,
+		"/sys/devices/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0":    false,
+		"/sys/devices/virtual/block/sessions/target0:0:0":                        false,
+		"/sys/devices/platform/host2/session1":                                   false,
+		"/sys/devices/platform/host2/session/target2:0:0":                        false,
+	} {
+		assert.Equal(isISCSI, isISCSISysPath(path), path)
+	}
+}
@@ -65,11 +65,16 @@ var (
 	kataBlkDevType       = "blk"
 	kataSCSIDevType      = "scsi"
 	kataNvdimmDevType    = "nvdimm"
-	sharedDir9pOptions   = []string{"trans=virtio,version=9p2000.L,cache=mmap", "nodev"}
 	shmDir               = "shm"
 	kataEphemeralDevType = "ephemeral"
 	ephemeralPath        = filepath.Join(kataGuestSandboxDir, kataEphemeralDevType)
+	kataLocalDevType     = "local"
+	scratchPath          = filepath.Join(kataGuestSandboxDir, scratchDiskID)
 	grpcMaxDataSize      = int64(1024 * 1024)
+	logLevelKernelOption = "agent.log"
+	// guestTmpIfacePrefix prefixes the temporary names of the guest
+	// interfaces being renamed.
+	guestTmpIfacePrefix = "kata_tmp"
 )
 
 // KataAgentConfig is a structure storing information needed
@@ -77,6 +82,29 @@ var (
 type KataAgentConfig struct {
 	LongLiveConn bool
 	UseVSock     bool
+
+	// RPCRecordDir is the directory the agent RPCs are recorded to, one
+	// file per sandbox. Empty disables the recording.
+	RPCRecordDir string
+
+	// DedicatedStreams gives the process streams agent connections of
+	// their own when the agent is reached over vsock.
+	DedicatedStreams bool
+
+	// Debug sets the log level of the agent to debug.
+	Debug bool
+}
+
+// KataAgentKernelParams returns the kernel parameters the agent reads its
+// guest side configuration from.
+func KataAgentKernelParams(config KataAgentConfig) []Param {
+	var params []Param
+
+	if config.Debug {
+		params = append(params, Param{logLevelKernelOption, "debug"})
+	}
+
+	return params
 }
 
 type kataVSOCK struct {
@@ -109,6 +137,22 @@ type kataAgent struct {
 	keepConn     bool
 	proxyBuiltIn bool
 
+	// streamConns is set when the process streams may get dedicated
+	// connections, see dedicatedStreams.
+	streamConns bool
+
+	// streamsLock protects the process stream connections
+	streamsLock   sync.Mutex
+	streamClients map[string]*kataclient.AgentClient
+
+	// idle is set when the sandbox VM is paused while idle
+	idle *idleTracker
+
+	watchdog *slowOpWatchdog
+
+	// recorder is set when the agent RPCs are recorded
+	recorder *agentRPCRecorder
+
 	vmSocket interface{}
 	ctx      context.Context
 }
@@ -177,10 +221,21 @@ func (k *kataAgent) init(ctx context.Context, sandbox *Sandbox, config interface
 			return err
 		}
 		k.keepConn = c.LongLiveConn
+		k.streamConns = c.DedicatedStreams
+		if c.RPCRecordDir != "" {
+			recorder, err := newAgentRPCRecorder(c.RPCRecordDir, sandbox.id)
+			if err != nil {
+				k.Logger().WithError(err).Warn("failed to record agent RPCs")
+			}
+			k.recorder = recorder
+		}
 	default:
 		return fmt.Errorf("Invalid config type")
 	}
 
+	k.idle = sandbox.idle
+	k.watchdog = sandbox.watchdog
+
 	k.proxy, err = newProxy(sandbox.config.ProxyType)
 	if err != nil {
 		return err
@@ -229,6 +284,7 @@ func (k *kataAgent) internalConfigure(h hypervisor, id, sharePath string, builti
 				return err
 			}
 			k.keepConn = c.LongLiveConn
+			k.streamConns = c.DedicatedStreams
 		default:
 			return fmt.Errorf("Invalid config type")
 		}
@@ -254,7 +310,9 @@ func (k *kataAgent) configure(h hypervisor, id, sharePath string, builtin bool,
 			return err
 		}
 	case kataVSOCK:
-		s.vhostFd, s.contextID, err = utils.FindContextID()
+		s.vhostFd, s.contextID, err = utils.FindContextID(func(cid uint64) error {
+			return reserveVSockCID(id, cid)
+		})
 		if err != nil {
 			return err
 		}
@@ -309,25 +367,31 @@ func cmdToKataProcess(cmd types.Cmd) (process *grpc.Process, err error) {
 
 	// User can contain only the "uid" or it can contain "uid:gid".
 	parsedUser := strings.Split(cmd.User, ":")
-	if len(parsedUser) > 2 {
+	if len(parsedUser) > 2 || parsedUser[0] == "" {
 		return nil, fmt.Errorf("cmd.User %q format is wrong", cmd.User)
 	}
 
-	i, err = strconv.ParseUint(parsedUser[0], 10, grpcUserBits)
-	if err != nil {
-		return nil, err
-	}
-
-	uid := uint32(i)
-
-	var gid uint32
-	if len(parsedUser) > 1 {
-		i, err = strconv.ParseUint(parsedUser[1], 10, grpcUserBits)
+	// A user or a group given by name, eg. "nobody:nogroup", is resolved
+	// by the agent against the passwd and group files of the container,
+	// which also gives the supplementary groups of the user.
+	var username string
+	var ids []uint32
+	for _, id := range parsedUser {
+		i, err = strconv.ParseUint(id, 10, grpcUserBits)
 		if err != nil {
-			return nil, err
+			username = cmd.User
+			break
 		}
 
-		gid = uint32(i)
+		ids = append(ids, uint32(i))
+	}
+
+	var uid, gid uint32
+	if username == "" {
+		uid = ids[0]
+		if len(ids) > 1 {
+			gid = ids[1]
+		}
 	}
 
 	if cmd.PrimaryGroup != "" {
@@ -356,10 +420,27 @@ func cmdToKataProcess(cmd types.Cmd) (process *grpc.Process, err error) {
 			UID:            uid,
 			GID:            gid,
 			AdditionalGids: extraGids,
+			Username:       username,
 		},
-		Args: cmd.Args,
-		Env:  cmdEnvsToStringSlice(cmd.Envs),
-		Cwd:  cmd.WorkDir,
+		Args:            cmd.Args,
+		Env:             cmdEnvsToStringSlice(cmd.Envs),
+		Cwd:             cmd.WorkDir,
+		NoNewPrivileges: cmd.NoNewPrivileges,
+		ApparmorProfile: cmd.ApparmorProfile,
+	}
+
+	// Only the capabilities given explicitly are sent, an empty set
+	// would drop them all.
+	caps := cmd.Capabilities
+	if len(caps.Bounding) > 0 || len(caps.Effective) > 0 || len(caps.Inheritable) > 0 ||
+		len(caps.Permitted) > 0 || len(caps.Ambient) > 0 {
+		process.Capabilities = &grpc.LinuxCapabilities{
+			Bounding:    caps.Bounding,
+			Effective:   caps.Effective,
+			Inheritable: caps.Inheritable,
+			Permitted:   caps.Permitted,
+			Ambient:     caps.Ambient,
+		}
 	}
 
 	return process, nil
@@ -430,8 +511,29 @@ func (k *kataAgent) updateInterface(ifc *vcTypes.Interface) (*vcTypes.Interface,
 	return nil, err
 }
 
+func (k *kataAgent) removeInterface(ifc *vcTypes.Interface) error {
+	// send remove interface request
+	ifcReq := &grpc.RemoveInterfaceRequest{
+		Interface: k.convertToKataAgentInterface(ifc),
+	}
+	if _, err := k.sendReq(ifcReq); err != nil {
+		k.Logger().WithField("interface-requested", fmt.Sprintf("%+v", ifc)).
+			WithError(err).Error("remove interface request failed")
+		return err
+	}
+	return nil
+}
+
 func (k *kataAgent) updateInterfaces(interfaces []*vcTypes.Interface) error {
-	for _, ifc := range interfaces {
+	var guestInterfaces []*vcTypes.Interface
+	if len(interfaces) > 1 {
+		var err error
+		if guestInterfaces, err = k.listInterfaces(); err != nil {
+			k.Logger().WithError(err).Warn("Could not list the guest interfaces")
+		}
+	}
+
+	for _, ifc := range orderInterfaceUpdates(interfaces, guestInterfaces) {
 		if _, err := k.updateInterface(ifc); err != nil {
 			return err
 		}
@@ -439,6 +541,60 @@ func (k *kataAgent) updateInterfaces(interfaces []*vcTypes.Interface) error {
 	return nil
 }
 
+// orderInterfaceUpdates orders the interface updates so that no guest
+// interface gets renamed to a name still held by another one. The guest
+// kernel names the interfaces ethN in device order, which does not match the
+// host names the interfaces are given. Interfaces swapping their names go
+// through a temporary name first.
+func orderInterfaceUpdates(interfaces, guestInterfaces []*vcTypes.Interface) []*vcTypes.Interface {
+	// Current guest name of the interfaces, by hardware address.
+	names := make(map[string]string)
+	for _, ifc := range guestInterfaces {
+		names[ifc.HwAddr] = ifc.Name
+	}
+
+	pending := append([]*vcTypes.Interface{}, interfaces...)
+	held := func(ifc *vcTypes.Interface) bool {
+		if ifc.Name == "" {
+			return false
+		}
+		for _, p := range pending {
+			if p.HwAddr != ifc.HwAddr && names[p.HwAddr] == ifc.Name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var updates []*vcTypes.Interface
+	for tmp := 0; len(pending) > 0; {
+		progress := false
+		for i := 0; i < len(pending); {
+			ifc := pending[i]
+			if held(ifc) {
+				i++
+				continue
+			}
+
+			updates = append(updates, ifc)
+			names[ifc.HwAddr] = ifc.Name
+			pending = append(pending[:i], pending[i+1:]...)
+			progress = true
+		}
+
+		if !progress {
+			ifc := *pending[0]
+			ifc.Name = fmt.Sprintf("%s%d", guestTmpIfacePrefix, tmp)
+			tmp++
+
+			updates = append(updates, &ifc)
+			names[ifc.HwAddr] = ifc.Name
+		}
+	}
+
+	return updates
+}
+
 func (k *kataAgent) updateRoutes(routes []*vcTypes.Route) ([]*vcTypes.Route, error) {
 	if routes != nil {
 		routesReq := &grpc.UpdateRoutesRequest{
@@ -605,6 +761,16 @@ func (k *kataAgent) setProxyFromGrpc(proxy proxy, pid int, url string) {
 	k.state.URL = url
 }
 
+// sharedDir9pOptions returns the options the agent mounts the 9p shared
+// directory with.
+func sharedDir9pOptions(conf HypervisorConfig) []string {
+	return []string{
+		fmt.Sprintf("trans=virtio,version=%s,cache=%s", conf.Version9p, conf.Cache9p),
+		"nodev",
+		fmt.Sprintf("msize=%d", conf.Msize9p),
+	}
+}
+
 func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 	span, _ := k.trace("startSandbox")
 	defer span.Finish()
@@ -649,8 +815,6 @@ func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 
 	// append 9p shared volume to storages only if filesystem sharing is supported
 	if caps.IsFsSharingSupported() {
-		sharedDir9pOptions = append(sharedDir9pOptions, fmt.Sprintf("msize=%d", sandbox.config.HypervisorConfig.Msize9p))
-
 		// We mount the shared directory in a predefined location
 		// in the guest.
 		// This is where at least some of the host config files
@@ -661,7 +825,7 @@ func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 			Source:     mountGuest9pTag,
 			MountPoint: kataGuestSharedDir,
 			Fstype:     type9pFs,
-			Options:    sharedDir9pOptions,
+			Options:    sharedDir9pOptions(sandbox.config.HypervisorConfig),
 		}
 
 		storages = append(storages, sharedVolume)
@@ -682,6 +846,10 @@ func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 		storages = append(storages, shmStorage)
 	}
 
+	if sandbox.scratchDisk != nil {
+		storages = append(storages, k.scratchDiskStorage(sandbox))
+	}
+
 	req := &grpc.CreateSandboxRequest{
 		Hostname:      hostname,
 		Storages:      storages,
@@ -694,6 +862,31 @@ func (k *kataAgent) startSandbox(sandbox *Sandbox) error {
 	return err
 }
 
+// scratchDiskStorage returns the storage asking the agent to mount the
+// sandbox scratch disk in the guest.
+func (k *kataAgent) scratchDiskStorage(sandbox *Sandbox) *grpc.Storage {
+	scratch := &grpc.Storage{
+		MountPoint: scratchPath,
+		Fstype:     scratchDiskFstype,
+		Options:    []string{"nodev"},
+	}
+
+	drive := sandbox.scratchDisk
+	switch sandbox.config.HypervisorConfig.BlockDeviceDriver {
+	case config.VirtioMmio:
+		scratch.Driver = kataMmioBlkDevType
+		scratch.Source = drive.VirtPath
+	case config.VirtioBlock:
+		scratch.Driver = kataBlkDevType
+		scratch.Source = drive.PCIAddr
+	default:
+		scratch.Driver = kataSCSIDevType
+		scratch.Source = drive.SCSIAddr
+	}
+
+	return scratch
+}
+
 func (k *kataAgent) stopSandbox(sandbox *Sandbox) error {
 	span, _ := k.trace("stopSandbox")
 	defer span.Finish()
@@ -708,6 +901,8 @@ func (k *kataAgent) stopSandbox(sandbox *Sandbox) error {
 		return err
 	}
 
+	k.closeStreamClients()
+
 	if err := k.proxy.stop(k.state.ProxyPid); err != nil {
 		return err
 	}
@@ -793,6 +988,83 @@ func (k *kataAgent) replaceOCIMountsForStorages(spec *specs.Spec, volumeStorages
 	return nil
 }
 
+// unsupportedSpecFields returns the fields of a spec constraintGRPCSpec
+// drops, and the guest hence does not apply. The device cgroup rules are
+// left out, they restrict the host device nodes the guest does not have.
+func unsupportedSpecFields(grpcSpec *grpc.Spec, passSeccomp bool) []string {
+	var fields []string
+
+	if grpcSpec.Linux == nil {
+		return nil
+	}
+
+	if grpcSpec.Linux.Seccomp != nil && !passSeccomp {
+		fields = append(fields, "linux.seccomp")
+	}
+
+	if r := grpcSpec.Linux.Resources; r != nil {
+		if r.Pids != nil && r.Pids.Limit > 0 {
+			fields = append(fields, "linux.resources.pids")
+		}
+		if r.BlockIO != nil {
+			fields = append(fields, unsupportedBlockIOFields(r.BlockIO)...)
+		}
+		if len(r.HugepageLimits) > 0 {
+			fields = append(fields, "linux.resources.hugepageLimits")
+		}
+		if r.Network != nil {
+			fields = append(fields, "linux.resources.network")
+		}
+	}
+
+	for _, ns := range grpcSpec.Linux.Namespaces {
+		if ns.Type == string(specs.CgroupNamespace) {
+			fields = append(fields, "linux.namespaces.cgroup")
+		}
+	}
+
+	return fields
+}
+
+// unsupportedBlockIOFields returns the block I/O settings guestBlockIO
+// drops.
+func unsupportedBlockIOFields(blockIO *grpc.LinuxBlockIO) []string {
+	var fields []string
+
+	for _, f := range []struct {
+		name    string
+		devices int
+	}{
+		{"linux.resources.blockIO.weightDevice", len(blockIO.WeightDevice)},
+		{"linux.resources.blockIO.throttleReadBpsDevice", len(blockIO.ThrottleReadBpsDevice)},
+		{"linux.resources.blockIO.throttleWriteBpsDevice", len(blockIO.ThrottleWriteBpsDevice)},
+		{"linux.resources.blockIO.throttleReadIOPSDevice", len(blockIO.ThrottleReadIOPSDevice)},
+		{"linux.resources.blockIO.throttleWriteIOPSDevice", len(blockIO.ThrottleWriteIOPSDevice)},
+	} {
+		if f.devices > 0 {
+			fields = append(fields, f.name)
+		}
+	}
+
+	return fields
+}
+
+// guestBlockIO returns the block I/O settings the guest cgroups can apply,
+// the weights of the container against the other containers of the
+// sandbox. The per-device settings are dropped: they name the devices by
+// their host major and minor numbers, which the hotplugged devices do not
+// keep in the guest.
+func guestBlockIO(blockIO *grpc.LinuxBlockIO) *grpc.LinuxBlockIO {
+	if blockIO == nil || (blockIO.Weight == 0 && blockIO.LeafWeight == 0) {
+		return nil
+	}
+
+	return &grpc.LinuxBlockIO{
+		Weight:     blockIO.Weight,
+		LeafWeight: blockIO.LeafWeight,
+	}
+}
+
 func constraintGRPCSpec(grpcSpec *grpc.Spec, systemdCgroup bool, passSeccomp bool) {
 	// Disable Hooks since they have been handled on the host and there is
 	// no reason to send them to the agent. It would make no sense to try
@@ -810,7 +1082,7 @@ func constraintGRPCSpec(grpcSpec *grpc.Spec, systemdCgroup bool, passSeccomp boo
 	// Issue: https://github.com/kata-containers/runtime/issues/204
 	grpcSpec.Linux.Resources.Devices = nil
 	grpcSpec.Linux.Resources.Pids = nil
-	grpcSpec.Linux.Resources.BlockIO = nil
+	grpcSpec.Linux.Resources.BlockIO = guestBlockIO(grpcSpec.Linux.Resources.BlockIO)
 	grpcSpec.Linux.Resources.HugepageLimits = nil
 	grpcSpec.Linux.Resources.Network = nil
 
@@ -890,30 +1162,112 @@ func (k *kataAgent) appendDevices(deviceList []*grpc.Device, c *Container) []*gr
 			continue
 		}
 
-		kataDevice := &grpc.Device{
-			ContainerPath: dev.ContainerPath,
+		deviceList = append(deviceList, blockDeviceForAgent(c.sandbox, d, dev.ContainerPath))
+	}
+
+	return deviceList
+}
+
+// blockDeviceForAgent returns the device asking the agent to create the
+// node of a block drive at containerPath in the container.
+func blockDeviceForAgent(sandbox *Sandbox, d *config.BlockDrive, containerPath string) *grpc.Device {
+	kataDevice := &grpc.Device{
+		ContainerPath: containerPath,
+	}
+
+	switch sandbox.config.HypervisorConfig.BlockDeviceDriver {
+	case config.VirtioMmio:
+		kataDevice.Type = kataMmioBlkDevType
+		kataDevice.Id = d.VirtPath
+		kataDevice.VmPath = d.VirtPath
+	case config.VirtioBlock:
+		kataDevice.Type = kataBlkDevType
+		kataDevice.Id = d.PCIAddr
+	case config.VirtioSCSI:
+		kataDevice.Type = kataSCSIDevType
+		kataDevice.Id = d.SCSIAddr
+	case config.Nvdimm:
+		kataDevice.Type = kataNvdimmDevType
+		kataDevice.VmPath = fmt.Sprintf("/dev/pmem%s", d.NvdimmID)
+	}
+
+	return kataDevice
+}
+
+// handleRawBlockVolumes handles the volumes that are block device files the
+// container gets as is, eg. the Kubernetes volumeDevices, by passing them
+// as container devices: the agent creates the device node at the path the
+// container asked for, instead of the volume being a bind mount.
+func (k *kataAgent) handleRawBlockVolumes(c *Container, spec *specs.Spec) ([]*grpc.Device, error) {
+	raw := make(map[string]Mount)
+	for _, m := range c.mounts {
+		if m.BlockDeviceID != "" && m.BlockDeviceFstype == "" {
+			raw[m.Destination] = m
+		}
+	}
+
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	if spec.Linux == nil {
+		spec.Linux = &specs.Linux{}
+	}
+
+	var devices []*grpc.Device
+	var mounts []specs.Mount
+
+	for _, ociMount := range spec.Mounts {
+		m, ok := raw[ociMount.Destination]
+		if !ok {
+			mounts = append(mounts, ociMount)
+			continue
+		}
+
+		device := c.sandbox.devManager.GetDeviceByID(m.BlockDeviceID)
+		if device == nil {
+			return nil, fmt.Errorf("failed to find device by id %q", m.BlockDeviceID)
 		}
 
-		switch c.sandbox.config.HypervisorConfig.BlockDeviceDriver {
-		case config.VirtioMmio:
-			kataDevice.Type = kataMmioBlkDevType
-			kataDevice.Id = d.VirtPath
-			kataDevice.VmPath = d.VirtPath
-		case config.VirtioBlock:
-			kataDevice.Type = kataBlkDevType
-			kataDevice.Id = d.PCIAddr
-		case config.VirtioSCSI:
-			kataDevice.Type = kataSCSIDevType
-			kataDevice.Id = d.SCSIAddr
-		case config.Nvdimm:
-			kataDevice.Type = kataNvdimmDevType
-			kataDevice.VmPath = fmt.Sprintf("/dev/pmem%s", d.NvdimmID)
+		drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+		if !ok || drive == nil {
+			return nil, fmt.Errorf("malformed block drive for device %q", m.BlockDeviceID)
 		}
 
-		deviceList = append(deviceList, kataDevice)
+		// Add the block device to the list of container devices, to make
+		// sure the device is detached with detachDevices() for a container.
+		c.devices = append(c.devices, ContainerDevice{ID: m.BlockDeviceID, ContainerPath: m.Destination})
+		if err := c.storeDevices(); err != nil {
+			return nil, err
+		}
+
+		// The agent replaces the numbers of the host device with the
+		// ones of the drive in the guest. Disk images have none.
+		fileMode := os.FileMode(0660)
+		var uid, gid uint32
+		var stat unix.Stat_t
+		if err := unix.Stat(m.Source, &stat); err == nil && stat.Mode&unix.S_IFMT == unix.S_IFBLK {
+			fileMode = os.FileMode(stat.Mode &^ unix.S_IFMT)
+			uid = stat.Uid
+			gid = stat.Gid
+		}
+
+		spec.Linux.Devices = append(spec.Linux.Devices, specs.LinuxDevice{
+			Path:     m.Destination,
+			Type:     "b",
+			Major:    int64(unix.Major(stat.Rdev)),
+			Minor:    int64(unix.Minor(stat.Rdev)),
+			FileMode: &fileMode,
+			UID:      &uid,
+			GID:      &gid,
+		})
+
+		devices = append(devices, blockDeviceForAgent(c.sandbox, drive, m.Destination))
 	}
 
-	return deviceList
+	spec.Mounts = mounts
+
+	return devices, nil
 }
 
 // rollbackFailingContainerCreation rolls back important steps that might have
@@ -926,7 +1280,7 @@ func (k *kataAgent) rollbackFailingContainerCreation(c *Container) {
 			k.Logger().WithError(err2).Error("rollback failed unmountHostMounts()")
 		}
 
-		if err2 := bindUnmountContainerRootfs(k.ctx, kataHostSharedDir, c.sandbox.id, c.id); err2 != nil {
+		if err2 := c.bindUnmountRootfs(kataHostSharedDir); err2 != nil {
 			k.Logger().WithError(err2).Error("rollback failed bindUnmountContainerRootfs()")
 		}
 	}
@@ -982,7 +1336,7 @@ func (k *kataAgent) buildContainerRootfs(sandbox *Sandbox, c *Container, rootPat
 	// (kataGuestSharedDir) is already mounted in the
 	// guest. We only need to mount the rootfs from
 	// the host and it will show up in the guest.
-	if err := bindMountContainerRootfs(k.ctx, kataHostSharedDir, sandbox.id, c.id, c.rootFs.Target, false); err != nil {
+	if err := c.bindMountRootfs(kataHostSharedDir); err != nil {
 		return nil, err
 	}
 
@@ -1015,7 +1369,7 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 	}()
 
 	if rootfs, err = k.buildContainerRootfs(sandbox, c, rootPathParent); err != nil {
-		return nil, err
+		return nil, classifyError(ErrorClassMount, err)
 	} else if rootfs != nil {
 		// Add rootfs to the list of container storage.
 		// We only need to do this for block based rootfs, as we
@@ -1029,15 +1383,33 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 		return nil, err
 	}
 
+	// The nodes of the host devices mean nothing in the guest, the
+	// privileged container gets the ones of the guest /dev instead.
+	if hostDevices := withoutHostDevices(sandbox, ociSpec); len(hostDevices) > 0 {
+		var devices []specs.LinuxDevice
+		for _, d := range ociSpec.Linux.Devices {
+			if !hostDevices[d.Path] {
+				devices = append(devices, d)
+			}
+		}
+		ociSpec.Linux.Devices = devices
+	}
+
 	// Handle container mounts
 	newMounts, ignoredMounts, err := c.mountSharedDirMounts(kataHostSharedDir, kataGuestSharedDir)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(ErrorClassMount, err)
 	}
 
 	epheStorages := k.handleEphemeralStorage(ociSpec.Mounts)
 	ctrStorages = append(ctrStorages, epheStorages...)
 
+	if sandbox.scratchDiskEnabled() {
+		if tmpStorage := k.handleScratchTmp(ociSpec, c); tmpStorage != nil {
+			ctrStorages = append(ctrStorages, tmpStorage)
+		}
+	}
+
 	// We replace all OCI mount sources that match our container mount
 	// with the right source path (The guest one).
 	if err = k.replaceOCIMountSource(ociSpec, newMounts); err != nil {
@@ -1052,6 +1424,15 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 	// Append container devices for block devices passed with --device.
 	ctrDevices = k.appendDevices(ctrDevices, c)
 
+	// Handle all the volumes that are block device files passed as is.
+	// Like handleBlockVolumes, this needs be done after the devices
+	// passed with --device are handled.
+	rawDevices, err := k.handleRawBlockVolumes(c, ociSpec)
+	if err != nil {
+		return nil, err
+	}
+	ctrDevices = append(ctrDevices, rawDevices...)
+
 	// Handle all the volumes that are block device files.
 	// Note this call modifies the list of container devices to make sure
 	// all hotplugged devices are unplugged, so this needs be done
@@ -1063,6 +1444,23 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 
 	ctrStorages = append(ctrStorages, volumeStorages...)
 
+	// Handle all the volumes backed by a vhost-user-scsi LUN.
+	lunStorages, err := k.handleVhostUserSCSIVolumes(c, ociSpec)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.replaceOCIMountsForStorages(ociSpec, lunStorages); err != nil {
+		return nil, err
+	}
+
+	ctrStorages = append(ctrStorages, lunStorages...)
+
+	if sandbox.scratchDiskEnabled() && k.useScratchWritableLayer(sandbox, ociSpec) {
+		var layerStorages []*grpc.Storage
+		layerStorages, rootPath = k.handleScratchWritableLayer(c, rootPath)
+		ctrStorages = append(ctrStorages, layerStorages...)
+	}
+
 	grpcSpec, err := grpc.OCItoGRPC(ociSpec)
 	if err != nil {
 		return nil, err
@@ -1078,10 +1476,23 @@ func (k *kataAgent) createContainer(sandbox *Sandbox, c *Container) (p *Process,
 
 	passSeccomp := !sandbox.config.DisableGuestSeccomp && sandbox.seccompSupported
 
+	if sandbox.config.StrictOCISpec {
+		if fields := unsupportedSpecFields(grpcSpec, passSeccomp); len(fields) > 0 {
+			return nil, fmt.Errorf("The guest cannot honor the spec fields %s, refused by strict_oci_spec",
+				strings.Join(fields, ", "))
+		}
+	}
+
 	// We need to constraint the spec to make sure we're not passing
 	// irrelevant information to the agent.
 	constraintGRPCSpec(grpcSpec, sandbox.config.SystemdCgroup, passSeccomp)
 
+	// The exclusive CPUs of the container are the guest vCPUs dedicated
+	// to it, not the host ones.
+	if c.state.GuestCPUs != "" && grpcSpec.Linux.Resources.CPU != nil {
+		grpcSpec.Linux.Resources.CPU.Cpus = c.state.GuestCPUs
+	}
+
 	k.handleShm(grpcSpec, sandbox)
 
 	req := &grpc.CreateContainerRequest{
@@ -1134,8 +1545,90 @@ func (k *kataAgent) handleEphemeralStorage(mounts []specs.Mount) []*grpc.Storage
 	return epheStorages
 }
 
-// handleBlockVolumes handles volumes that are block devices files
-// by passing the block devices as Storage to the agent.
+// handleScratchTmp backs the container /tmp with a directory of the
+// sandbox scratch disk, unless the spec already provides a /tmp mount.
+func (k *kataAgent) handleScratchTmp(ociSpec *specs.Spec, c *Container) *grpc.Storage {
+	for _, mnt := range ociSpec.Mounts {
+		if filepath.Clean(mnt.Destination) == "/tmp" {
+			return nil
+		}
+	}
+
+	source := filepath.Join(scratchPath, c.id, "tmp")
+
+	ociSpec.Mounts = append(ociSpec.Mounts, specs.Mount{
+		Destination: "/tmp",
+		Type:        "bind",
+		Source:      source,
+		Options:     []string{"rbind", "rw", "nodev"},
+	})
+
+	// The agent creates the directory on the scratch disk
+	return &grpc.Storage{
+		Driver:     kataLocalDevType,
+		Source:     kataLocalDevType,
+		Fstype:     kataLocalDevType,
+		MountPoint: source,
+		Options:    []string{"mode=1777"},
+	}
+}
+
+// useScratchWritableLayer returns true if the container writable layer
+// should be kept on the sandbox scratch disk.
+func (k *kataAgent) useScratchWritableLayer(sandbox *Sandbox, ociSpec *specs.Spec) bool {
+	enabled := sandbox.config.HypervisorConfig.ScratchWritableLayer
+
+	if value, ok := sandbox.containerAnnotation(ociSpec, vcAnnotations.ScratchWritableLayer); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			k.Logger().WithError(err).WithField("annotation", vcAnnotations.ScratchWritableLayer).
+				Warn("invalid annotation value, ignoring")
+			return enabled
+		}
+		enabled = b
+	}
+
+	return enabled
+}
+
+// handleScratchWritableLayer returns the storages mounting an overlay
+// of the container rootfs with an upper layer on the scratch disk, and
+// the guest path of the resulting rootfs. Storages are mounted in order,
+// so the upper and work directories exist when the overlay is mounted.
+func (k *kataAgent) handleScratchWritableLayer(c *Container, rootPath string) ([]*grpc.Storage, string) {
+	layerDir := filepath.Join(scratchPath, c.id)
+	upperDir := filepath.Join(layerDir, "upper")
+	workDir := filepath.Join(layerDir, "work")
+	mergedDir := filepath.Join(layerDir, c.rootfsSuffix)
+
+	storages := []*grpc.Storage{}
+	for _, dir := range []string{upperDir, workDir} {
+		storages = append(storages, &grpc.Storage{
+			Driver:     kataLocalDevType,
+			Source:     kataLocalDevType,
+			Fstype:     kataLocalDevType,
+			MountPoint: dir,
+			Options:    []string{"mode=0755"},
+		})
+	}
+
+	storages = append(storages, &grpc.Storage{
+		Driver:     kataEphemeralDevType,
+		Source:     "overlay",
+		Fstype:     "overlay",
+		MountPoint: mergedDir,
+		Options: []string{
+			"lowerdir=" + rootPath,
+			"upperdir=" + upperDir,
+			"workdir=" + workDir,
+		},
+	})
+
+	return storages, mergedDir
+}
+
+// handleBlockVolumes handles volumes whose block device holds a filesystem
+// mounted by the guest, by passing the block devices as Storage to the agent.
 func (k *kataAgent) handleBlockVolumes(c *Container) []*grpc.Storage {
 
 	var volumeStorages []*grpc.Storage
@@ -1143,7 +1636,9 @@ func (k *kataAgent) handleBlockVolumes(c *Container) []*grpc.Storage {
 	for _, m := range c.mounts {
 		id := m.BlockDeviceID
 
-		if len(id) == 0 {
+		// The block devices passed as is are handled by
+		// handleRawBlockVolumes.
+		if len(id) == 0 || m.BlockDeviceFstype == "" {
 			continue
 		}
 
@@ -1179,8 +1674,12 @@ func (k *kataAgent) handleBlockVolumes(c *Container) []*grpc.Storage {
 		}
 
 		vol.MountPoint = m.Destination
-		vol.Fstype = "bind"
-		vol.Options = []string{"bind"}
+		vol.Fstype = m.BlockDeviceFstype
+		for _, flag := range m.Options {
+			if flag == "ro" {
+				vol.Options = []string{"ro"}
+			}
+		}
 
 		volumeStorages = append(volumeStorages, vol)
 	}
@@ -1188,6 +1687,50 @@ func (k *kataAgent) handleBlockVolumes(c *Container) []*grpc.Storage {
 	return volumeStorages
 }
 
+// handleVhostUserSCSIVolumes handles the volumes backed by a LUN of the
+// vhost-user-scsi controller of the sandbox, by passing the LUNs as Storage
+// to the agent and bind mounting them into the container.
+func (k *kataAgent) handleVhostUserSCSIVolumes(c *Container, spec *specs.Spec) ([]*grpc.Storage, error) {
+	var volumeStorages []*grpc.Storage
+
+	for i, m := range spec.Mounts {
+		if m.Type != vhostUserSCSIMountType {
+			continue
+		}
+
+		if c.sandbox.config.HypervisorConfig.VhostUserSCSIController == "" {
+			return nil, fmt.Errorf("No vhost-user-scsi controller for the volume %s", m.Destination)
+		}
+
+		addr, err := vhostUserSCSIAddr(m.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		fstype, options := vhostUserSCSIMountOptions(m.Options)
+
+		volumeStorages = append(volumeStorages, &grpc.Storage{
+			Driver:     kataSCSIDevType,
+			Source:     addr,
+			Fstype:     fstype,
+			Options:    options,
+			MountPoint: m.Destination,
+		})
+
+		bindOptions := []string{"rbind"}
+		for _, o := range options {
+			if o == "ro" {
+				bindOptions = append(bindOptions, "ro")
+			}
+		}
+
+		spec.Mounts[i].Type = "bind"
+		spec.Mounts[i].Options = bindOptions
+	}
+
+	return volumeStorages, nil
+}
+
 // handlePidNamespace checks if Pid namespace for a container needs to be shared with its sandbox
 // pid namespace. This function also modifies the grpc spec to remove the pid namespace
 // from the list of namespaces passed to the agent.
@@ -1202,7 +1745,16 @@ func (k *kataAgent) handlePidNamespace(grpcSpec *grpc.Spec, sandbox *Sandbox) (b
 
 		pidIndex = i
 
-		if ns.Path == "" || sandbox.state.Pid == 0 {
+		if ns.Path == "" {
+			break
+		}
+
+		// The sandbox process is not known to the host when the shim is
+		// built in the runtime, the path then points to the runtime
+		// process. The only pid namespace a container of the sandbox can
+		// join in the guest is the sandbox one.
+		if sandbox.state.Pid <= 0 {
+			sharedPidNs = sandbox.sharePidNs
 			break
 		}
 
@@ -1262,7 +1814,7 @@ func (k *kataAgent) stopContainer(sandbox *Sandbox, c Container) error {
 		return err
 	}
 
-	return bindUnmountContainerRootfs(k.ctx, kataHostSharedDir, sandbox.id, c.id)
+	return c.bindUnmountRootfs(kataHostSharedDir)
 }
 
 func (k *kataAgent) signalProcess(c *Container, processID string, signal syscall.Signal, all bool) error {
@@ -1319,6 +1871,12 @@ func (k *kataAgent) updateContainer(sandbox *Sandbox, c Container, resources spe
 		return err
 	}
 
+	if c.state.GuestCPUs != "" && grpcResources.CPU != nil && grpcResources.CPU.Cpus != "" {
+		grpcResources.CPU.Cpus = c.state.GuestCPUs
+	}
+
+	grpcResources.BlockIO = guestBlockIO(grpcResources.BlockIO)
+
 	req := &grpc.UpdateContainerRequest{
 		ContainerId: c.id,
 		Resources:   grpcResources,
@@ -1358,6 +1916,11 @@ func (k *kataAgent) onlineCPUMem(cpus uint32, cpuOnly bool) error {
 }
 
 func (k *kataAgent) statsContainer(sandbox *Sandbox, c Container) (*ContainerStats, error) {
+	// The stats polls of an idle sandbox are served without resuming it.
+	if stats := k.idle.pausedStats(c.id); stats != nil {
+		return stats, nil
+	}
+
 	req := &grpc.StatsContainerRequest{
 		ContainerId: c.id,
 	}
@@ -1383,9 +1946,12 @@ func (k *kataAgent) statsContainer(sandbox *Sandbox, c Container) (*ContainerSta
 	if err != nil {
 		return nil, err
 	}
+
 	containerStats := &ContainerStats{
 		CgroupStats: &cgroupStats,
 	}
+	k.idle.recordStats(c.id, containerStats)
+
 	return containerStats, nil
 }
 
@@ -1417,6 +1983,13 @@ func (k *kataAgent) connect() error {
 	return nil
 }
 
+func (k *kataAgent) connected() bool {
+	k.Lock()
+	defer k.Unlock()
+
+	return k.client != nil
+}
+
 func (k *kataAgent) disconnect() error {
 	span, _ := k.trace("disconnect")
 	defer span.Finish()
@@ -1466,6 +2039,10 @@ func (k *kataAgent) waitProcess(c *Container, processID string) (int32, error) {
 }
 
 func (k *kataAgent) writeProcessStdin(c *Container, ProcessID string, data []byte) (int, error) {
+	if k.dedicatedStreams() {
+		return k.writeDedicatedStdin(c.id, ProcessID, data)
+	}
+
 	resp, err := k.sendReq(&grpc.WriteStreamRequest{
 		ContainerId: c.id,
 		ExecId:      ProcessID,
@@ -1485,6 +2062,8 @@ func (k *kataAgent) closeProcessStdin(c *Container, ProcessID string) error {
 		ExecId:      ProcessID,
 	})
 
+	k.closeStreamClient(c.id, ProcessID, streamStdin)
+
 	return err
 }
 
@@ -1532,6 +2111,9 @@ func (k *kataAgent) installReqFunc(c *kataclient.AgentClient) {
 	k.reqHandlers["grpc.UpdateInterfaceRequest"] = func(ctx context.Context, req interface{}, opts ...golangGrpc.CallOption) (interface{}, error) {
 		return k.client.UpdateInterface(ctx, req.(*grpc.UpdateInterfaceRequest), opts...)
 	}
+	k.reqHandlers["grpc.RemoveInterfaceRequest"] = func(ctx context.Context, req interface{}, opts ...golangGrpc.CallOption) (interface{}, error) {
+		return k.client.RemoveInterface(ctx, req.(*grpc.RemoveInterfaceRequest), opts...)
+	}
 	k.reqHandlers["grpc.ListInterfacesRequest"] = func(ctx context.Context, req interface{}, opts ...golangGrpc.CallOption) (interface{}, error) {
 		return k.client.ListInterfaces(ctx, req.(*grpc.ListInterfacesRequest), opts...)
 	}
@@ -1594,8 +2176,21 @@ func (k *kataAgent) sendReq(request interface{}) (interface{}, error) {
 		}
 	}
 
+	// The health checks and the stats and wait polls must not keep an
+	// idle sandbox running.
+	switch request.(type) {
+	case *grpc.CheckRequest, *grpc.StatsContainerRequest, *grpc.WaitProcessRequest:
+		if err := k.idle.poll(); err != nil {
+			return nil, err
+		}
+	default:
+		if err := k.idle.activity(); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := k.connect(); err != nil {
-		return nil, err
+		return nil, agentTimeoutError(err)
 	}
 	if !k.keepConn {
 		defer k.disconnect()
@@ -1609,11 +2204,31 @@ func (k *kataAgent) sendReq(request interface{}) (interface{}, error) {
 	message := request.(proto.Message)
 	k.Logger().WithField("name", msgName).WithField("req", message.String()).Debug("sending request")
 
-	return handler(k.ctx, request)
+	start := time.Now()
+	done := k.watchdog.watch(msgName, span)
+	response, err := handler(k.ctx, request)
+	done()
+	k.recorder.record(msgName, start, request, response, err)
+
+	return response, agentTimeoutError(err)
+}
+
+// agentTimeoutError classifies the errors of the agent connections and
+// requests that timed out.
+func agentTimeoutError(err error) error {
+	if err == context.DeadlineExceeded || grpcStatus.Code(err) == codes.DeadlineExceeded {
+		return classifyError(ErrorClassAgentTimeout, err)
+	}
+
+	return err
 }
 
 // readStdout and readStderr are special that we cannot differentiate them with the request types...
 func (k *kataAgent) readProcessStdout(c *Container, processID string, data []byte) (int, error) {
+	if k.dedicatedStreams() {
+		return k.readDedicatedStream(c.id, processID, streamStdout, data)
+	}
+
 	if err := k.connect(); err != nil {
 		return 0, err
 	}
@@ -1626,6 +2241,10 @@ func (k *kataAgent) readProcessStdout(c *Container, processID string, data []byt
 
 // readStdout and readStderr are special that we cannot differentiate them with the request types...
 func (k *kataAgent) readProcessStderr(c *Container, processID string, data []byte) (int, error) {
+	if k.dedicatedStreams() {
+		return k.readDedicatedStream(c.id, processID, streamStderr, data)
+	}
+
 	if err := k.connect(); err != nil {
 		return 0, err
 	}
@@ -1644,6 +2263,9 @@ func (k *kataAgent) readProcessStream(containerID, processID string, data []byte
 		ExecId:      processID,
 		Len:         uint32(len(data))})
 	if err == nil {
+		if len(resp.Data) > 0 {
+			k.idle.activity()
+		}
 		copy(data, resp.Data)
 		return len(resp.Data), nil
 	}
@@ -1868,4 +2490,10 @@ func (k *kataAgent) cleanup(id string) {
 	if err := os.RemoveAll(path); err != nil {
 		k.Logger().WithError(err).Errorf("failed to cleanup vm share path %s", path)
 	}
+
+	if err := releaseVSockCIDs(id); err != nil {
+		k.Logger().WithError(err).Error("failed to release vsock context IDs")
+	}
+
+	k.recorder.close()
 }
@@ -0,0 +1,125 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"strings"
+
+	kataclient "github.com/kata-containers/agent/protocols/client"
+	"github.com/kata-containers/agent/protocols/grpc"
+)
+
+const (
+	streamStdin  = "stdin"
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// dedicatedStreams returns true when every process stream gets a vsock
+// connection to the agent of its own, as the dedicated_stream_connections
+// agent option asks. The stream reads then neither wait behind the other
+// requests nor share the flow control window of the main connection.
+//
+// Through a proxy all the connections end up multiplexed on the same
+// channel, the main connection is kept.
+func (k *kataAgent) dedicatedStreams() bool {
+	return k.streamConns && strings.HasPrefix(k.state.URL, vsockSocketScheme+"://")
+}
+
+func streamKey(containerID, processID, stream string) string {
+	return fmt.Sprintf("%s/%s/%s", containerID, processID, stream)
+}
+
+// streamClient returns the connection dedicated to a process stream,
+// connecting it on first use.
+func (k *kataAgent) streamClient(containerID, processID, stream string) (*kataclient.AgentClient, error) {
+	key := streamKey(containerID, processID, stream)
+
+	k.streamsLock.Lock()
+	defer k.streamsLock.Unlock()
+
+	if client, ok := k.streamClients[key]; ok {
+		return client, nil
+	}
+
+	client, err := kataclient.NewAgentClient(k.ctx, k.state.URL, k.proxyBuiltIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.streamClients == nil {
+		k.streamClients = make(map[string]*kataclient.AgentClient)
+	}
+	k.streamClients[key] = client
+
+	k.Logger().WithField("stream", key).Debug("New stream connection")
+
+	return client, nil
+}
+
+func (k *kataAgent) closeStreamClient(containerID, processID, stream string) {
+	key := streamKey(containerID, processID, stream)
+
+	k.streamsLock.Lock()
+	defer k.streamsLock.Unlock()
+
+	if client, ok := k.streamClients[key]; ok {
+		client.Close()
+		delete(k.streamClients, key)
+	}
+}
+
+func (k *kataAgent) closeStreamClients() {
+	k.streamsLock.Lock()
+	defer k.streamsLock.Unlock()
+
+	for key, client := range k.streamClients {
+		client.Close()
+		delete(k.streamClients, key)
+	}
+}
+
+// readDedicatedStream reads the output of a process on its own connection.
+// The connection goes away with the first error, eg. the end of the stream
+// once the process exited.
+func (k *kataAgent) readDedicatedStream(containerID, processID, stream string, data []byte) (int, error) {
+	client, err := k.streamClient(containerID, processID, stream)
+	if err != nil {
+		return 0, err
+	}
+
+	read := client.ReadStdout
+	if stream == streamStderr {
+		read = client.ReadStderr
+	}
+
+	n, err := k.readProcessStream(containerID, processID, data, read)
+	if err != nil {
+		k.closeStreamClient(containerID, processID, stream)
+	}
+
+	return n, err
+}
+
+func (k *kataAgent) writeDedicatedStdin(containerID, processID string, data []byte) (int, error) {
+	client, err := k.streamClient(containerID, processID, streamStdin)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.WriteStdin(k.ctx, &grpc.WriteStreamRequest{
+		ContainerId: containerID,
+		ExecId:      processID,
+		Data:        data,
+	})
+	if err != nil {
+		k.closeStreamClient(containerID, processID, streamStdin)
+		return 0, err
+	}
+
+	return int(resp.Len), nil
+}
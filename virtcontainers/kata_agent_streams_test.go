@@ -0,0 +1,85 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKataAgentDedicatedStreams(t *testing.T) {
+	assert := assert.New(t)
+
+	for url, dedicated := range map[string]bool{
+		"vsock://3:1024":                  true,
+		"unix:///run/kata-proxy.sock":     false,
+		"":                                false,
+		"vsockfoo:///run/kata-proxy.sock": false,
+	} {
+		k := &kataAgent{
+			state: KataAgentState{
+				URL: url,
+			},
+		}
+
+		// disabled by default
+		assert.False(k.dedicatedStreams(), url)
+
+		k.streamConns = true
+		assert.Equal(dedicated, k.dedicatedStreams(), url)
+	}
+}
+
+func TestKataAgentStreamClients(t *testing.T) {
+	assert := assert.New(t)
+
+	proxy := mock.ProxyGRPCMock{
+		GRPCImplementer: &gRPCProxy{},
+		GRPCRegister:    gRPCRegister,
+	}
+
+	sockDir, err := testGenerateKataProxySockDir()
+	assert.NoError(err)
+	defer os.RemoveAll(sockDir)
+
+	testKataProxyURL := fmt.Sprintf(testKataProxyURLTempl, sockDir)
+	assert.NoError(proxy.Start(testKataProxyURL))
+	defer proxy.Stop()
+
+	k := &kataAgent{
+		ctx: context.Background(),
+		state: KataAgentState{
+			URL: testKataProxyURL,
+		},
+	}
+
+	data := make([]byte, 16)
+
+	_, err = k.readDedicatedStream("foo", "bar", streamStdout, data)
+	assert.NoError(err)
+	_, err = k.readDedicatedStream("foo", "bar", streamStderr, data)
+	assert.NoError(err)
+	_, err = k.writeDedicatedStdin("foo", "bar", data)
+	assert.NoError(err)
+	assert.Len(k.streamClients, 3)
+
+	// The connections are kept for the next reads.
+	client := k.streamClients[streamKey("foo", "bar", streamStdout)]
+	_, err = k.readDedicatedStream("foo", "bar", streamStdout, data)
+	assert.NoError(err)
+	assert.Equal(client, k.streamClients[streamKey("foo", "bar", streamStdout)])
+
+	k.closeStreamClient("foo", "bar", streamStdin)
+	assert.Len(k.streamClients, 2)
+
+	k.closeStreamClients()
+	assert.Empty(k.streamClients)
+}
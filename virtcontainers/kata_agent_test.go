@@ -18,6 +18,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	gpb "github.com/gogo/protobuf/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -196,7 +197,7 @@ func (p *gRPCProxy) AddInterface(ctx context.Context, req *pb.AddInterfaceReques
 }
 
 func (p *gRPCProxy) RemoveInterface(ctx context.Context, req *pb.RemoveInterfaceRequest) (*aTypes.Interface, error) {
-	return nil, nil
+	return &aTypes.Interface{}, nil
 }
 
 func (p *gRPCProxy) UpdateInterface(ctx context.Context, req *pb.UpdateInterfaceRequest) (*aTypes.Interface, error) {
@@ -359,6 +360,77 @@ func TestKataAgentSendReq(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestKataAgentIdleStats(t *testing.T) {
+	assert := assert.New(t)
+
+	impl := &gRPCProxy{}
+
+	proxy := mock.ProxyGRPCMock{
+		GRPCImplementer: impl,
+		GRPCRegister:    gRPCRegister,
+	}
+
+	sockDir, err := testGenerateKataProxySockDir()
+	assert.Nil(err)
+	defer os.RemoveAll(sockDir)
+
+	testKataProxyURL := fmt.Sprintf(testKataProxyURLTempl, sockDir)
+	err = proxy.Start(testKataProxyURL)
+	assert.Nil(err)
+	defer proxy.Stop()
+
+	resumed := 0
+	sandbox := &Sandbox{
+		id: testSandboxID,
+		hypervisor: &mockHypervisor{hooks: &MockHooks{
+			Hypervisor: func(op string) error {
+				if op == "resumeSandbox" {
+					resumed++
+				}
+				return nil
+			},
+		}},
+		agent: &noopAgent{},
+	}
+	sandbox.idle = newIdleTracker(sandbox, time.Minute)
+
+	k := &kataAgent{
+		ctx: context.Background(),
+		state: KataAgentState{
+			URL: testKataProxyURL,
+		},
+		idle: sandbox.idle,
+	}
+	container := Container{id: "foo"}
+
+	stats, err := k.statsContainer(sandbox, container)
+	assert.NoError(err)
+
+	sandbox.idle.check(time.Now().Add(2 * time.Minute))
+	assert.True(sandbox.idle.isPaused())
+
+	// the stats poll is served without resuming the VM
+	pausedStats, err := k.statsContainer(sandbox, container)
+	assert.NoError(err)
+	assert.Equal(stats, pausedStats)
+	assert.True(sandbox.idle.isPaused())
+	assert.Zero(resumed)
+
+	// the stats of a container never polled need the agent, the VM
+	// is resumed without restarting the idle period
+	_, err = k.statsContainer(sandbox, Container{id: "bar"})
+	assert.NoError(err)
+	assert.Equal(1, resumed)
+	sandbox.idle.check(time.Now().Add(3 * time.Minute))
+	assert.True(sandbox.idle.isPaused())
+
+	// the other requests resume the VM
+	_, err = k.sendReq(&pb.SignalProcessRequest{})
+	assert.NoError(err)
+	assert.False(sandbox.idle.isPaused())
+	assert.Equal(2, resumed)
+}
+
 func TestHandleEphemeralStorage(t *testing.T) {
 	k := kataAgent{}
 	var ociMounts []specs.Mount
@@ -378,6 +450,122 @@ func TestHandleEphemeralStorage(t *testing.T) {
 		"Ephemeral mount point didn't match: got %s, expecting %s", epheMountPoint, expected)
 }
 
+func TestHandleScratchTmp(t *testing.T) {
+	assert := assert.New(t)
+
+	k := kataAgent{}
+	c := &Container{id: "foo"}
+	ociSpec := &specs.Spec{}
+
+	storage := k.handleScratchTmp(ociSpec, c)
+	assert.NotNil(storage)
+
+	source := filepath.Join(scratchPath, "foo", "tmp")
+	assert.Equal(kataLocalDevType, storage.Driver)
+	assert.Equal(source, storage.MountPoint)
+	assert.Len(ociSpec.Mounts, 1)
+	assert.Equal("/tmp", ociSpec.Mounts[0].Destination)
+	assert.Equal(source, ociSpec.Mounts[0].Source)
+
+	// an existing /tmp mount is left alone
+	ociSpec.Mounts = []specs.Mount{{Destination: "/tmp/", Type: "tmpfs"}}
+	assert.Nil(k.handleScratchTmp(ociSpec, c))
+	assert.Len(ociSpec.Mounts, 1)
+}
+
+func TestUseScratchWritableLayer(t *testing.T) {
+	assert := assert.New(t)
+
+	k := kataAgent{}
+	sandbox := &Sandbox{
+		config: &SandboxConfig{},
+	}
+	ociSpec := &specs.Spec{}
+
+	assert.False(k.useScratchWritableLayer(sandbox, ociSpec))
+
+	sandbox.config.HypervisorConfig.ScratchWritableLayer = true
+	assert.True(k.useScratchWritableLayer(sandbox, ociSpec))
+
+	// the container annotation overrides the configuration, if enabled
+	ociSpec.Annotations = map[string]string{
+		vcAnnotations.ScratchWritableLayer: "false",
+	}
+	assert.True(k.useScratchWritableLayer(sandbox, ociSpec))
+
+	sandbox.config.EnableAnnotations = []string{"ScratchWritableLayer"}
+	assert.False(k.useScratchWritableLayer(sandbox, ociSpec))
+
+	// invalid values are ignored
+	ociSpec.Annotations[vcAnnotations.ScratchWritableLayer] = "foo"
+	assert.True(k.useScratchWritableLayer(sandbox, ociSpec))
+}
+
+func TestHandleScratchWritableLayer(t *testing.T) {
+	assert := assert.New(t)
+
+	k := kataAgent{}
+	c := &Container{
+		id:           "foo",
+		rootfsSuffix: "rootfs",
+	}
+	rootPath := filepath.Join(kataGuestSharedDir, "foo", "rootfs")
+
+	storages, newRootPath := k.handleScratchWritableLayer(c, rootPath)
+	assert.Equal(filepath.Join(scratchPath, "foo", "rootfs"), newRootPath)
+	assert.Len(storages, 3)
+
+	upperDir := filepath.Join(scratchPath, "foo", "upper")
+	workDir := filepath.Join(scratchPath, "foo", "work")
+	assert.Equal(kataLocalDevType, storages[0].Driver)
+	assert.Equal(upperDir, storages[0].MountPoint)
+	assert.Equal(kataLocalDevType, storages[1].Driver)
+	assert.Equal(workDir, storages[1].MountPoint)
+
+	overlay := storages[2]
+	assert.Equal("overlay", overlay.Fstype)
+	assert.Equal(newRootPath, overlay.MountPoint)
+	assert.Equal([]string{
+		"lowerdir=" + rootPath,
+		"upperdir=" + upperDir,
+		"workdir=" + workDir,
+	}, overlay.Options)
+}
+
+func TestScratchDiskStorage(t *testing.T) {
+	assert := assert.New(t)
+
+	k := kataAgent{}
+	sandbox := &Sandbox{
+		config: &SandboxConfig{},
+		scratchDisk: &config.BlockDrive{
+			SCSIAddr: "0:1",
+			PCIAddr:  "02/03",
+			VirtPath: "/dev/vdb",
+		},
+	}
+
+	data := []struct {
+		driver         string
+		expectedDriver string
+		expectedSource string
+	}{
+		{config.VirtioSCSI, kataSCSIDevType, "0:1"},
+		{config.VirtioBlock, kataBlkDevType, "02/03"},
+		{config.VirtioMmio, kataMmioBlkDevType, "/dev/vdb"},
+	}
+
+	for _, d := range data {
+		sandbox.config.HypervisorConfig.BlockDeviceDriver = d.driver
+		storage := k.scratchDiskStorage(sandbox)
+		assert.Equal(d.expectedDriver, storage.Driver)
+		assert.Equal(d.expectedSource, storage.Source)
+		assert.Equal(scratchPath, storage.MountPoint)
+		assert.Equal(scratchDiskFstype, storage.Fstype)
+		assert.Equal([]string{"nodev"}, storage.Options)
+	}
+}
+
 func TestAppendDevicesEmptyContainerDeviceList(t *testing.T) {
 	k := kataAgent{}
 
@@ -443,6 +631,96 @@ func TestAppendDevices(t *testing.T) {
 		updatedDevList, expected)
 }
 
+func TestHandleRawBlockVolumes(t *testing.T) {
+	assert := assert.New(t)
+
+	k := kataAgent{}
+
+	id := "test-raw-block"
+	devices := []api.Device{
+		&drivers.BlockDevice{
+			GenericDevice: &drivers.GenericDevice{
+				ID: id,
+			},
+			BlockDrive: &config.BlockDrive{
+				PCIAddr: testPCIAddr,
+			},
+		},
+	}
+
+	sandbox := &Sandbox{
+		ctx:        context.Background(),
+		id:         "test-raw-block-sandbox",
+		devManager: manager.NewDeviceManager("virtio-blk", devices),
+		config: &SandboxConfig{
+			HypervisorConfig: HypervisorConfig{
+				BlockDeviceDriver: config.VirtioBlock,
+			},
+		},
+	}
+
+	defer store.DeleteAll()
+
+	c := &Container{
+		id:      "test-raw-block-container",
+		sandbox: sandbox,
+		mounts: []Mount{
+			{
+				Source:        "/dev/null",
+				Destination:   "/dev/xvda",
+				Type:          "bind",
+				BlockDeviceID: id,
+			},
+			{
+				Source:      "/tmp",
+				Destination: "/tmp",
+				Type:        "bind",
+			},
+		},
+	}
+
+	containerStore, err := store.NewVCContainerStore(sandbox.ctx, sandbox.id, c.id)
+	assert.NoError(err)
+	c.store = containerStore
+
+	spec := &specs.Spec{
+		Mounts: []specs.Mount{
+			{
+				Source:      "/dev/null",
+				Destination: "/dev/xvda",
+				Type:        "bind",
+			},
+			{
+				Source:      "/tmp",
+				Destination: "/tmp",
+				Type:        "bind",
+			},
+		},
+	}
+
+	ctrDevices, err := k.handleRawBlockVolumes(c, spec)
+	assert.NoError(err)
+	assert.Equal([]*pb.Device{
+		{
+			Type:          kataBlkDevType,
+			ContainerPath: "/dev/xvda",
+			Id:            testPCIAddr,
+		},
+	}, ctrDevices)
+
+	// the volume is a device node of the container, not a mount
+	assert.Len(spec.Mounts, 1)
+	assert.Equal("/tmp", spec.Mounts[0].Destination)
+	assert.Len(spec.Linux.Devices, 1)
+	assert.Equal("/dev/xvda", spec.Linux.Devices[0].Path)
+	assert.Equal("b", spec.Linux.Devices[0].Type)
+
+	assert.Equal([]ContainerDevice{{ID: id, ContainerPath: "/dev/xvda"}}, c.devices)
+
+	// the block volumes holding a filesystem are left to handleBlockVolumes
+	assert.Empty(k.handleBlockVolumes(c))
+}
+
 func TestConstraintGRPCSpec(t *testing.T) {
 	assert := assert.New(t)
 	expectedCgroupPath := "/foo/bar"
@@ -614,6 +892,23 @@ func TestHandlePidNamespace(t *testing.T) {
 
 	_, err = k.handlePidNamespace(g, sandbox)
 	assert.NotNil(err)
+
+	// Built in shim, the path is the one of the runtime process. The pid
+	// namespace is left in the spec by the previous error.
+	sandbox.state.Pid = -1
+
+	sharedPid, err = k.handlePidNamespace(g, sandbox)
+	assert.Nil(err)
+	assert.False(sharedPid)
+	assert.False(testIsPidNamespacePresent(g))
+
+	sandbox.sharePidNs = true
+	g.Linux.Namespaces = append(g.Linux.Namespaces, pidNs)
+
+	sharedPid, err = k.handlePidNamespace(g, sandbox)
+	assert.Nil(err)
+	assert.True(sharedPid)
+	assert.False(testIsPidNamespacePresent(g))
 }
 
 func TestAgentPathAPI(t *testing.T) {
@@ -694,23 +989,43 @@ func TestCmdToKataProcess(t *testing.T) {
 
 	cmd1 := cmd
 	cmd1.User = "foobar"
+	process, err := cmdToKataProcess(cmd1)
+	assert.Nil(err)
+	assert.Equal("foobar", process.User.Username)
+
+	cmd1 = cmd
+	cmd1.User = ""
 	_, err = cmdToKataProcess(cmd1)
 	assert.Error(err)
 
 	cmd1 = cmd
-	cmd1.PrimaryGroup = "foobar"
+	cmd1.User = ":1000"
 	_, err = cmdToKataProcess(cmd1)
 	assert.Error(err)
 
 	cmd1 = cmd
-	cmd1.User = "foobar:1000"
+	cmd1.User = "foo:bar:baz"
 	_, err = cmdToKataProcess(cmd1)
 	assert.Error(err)
 
 	cmd1 = cmd
-	cmd1.User = "1000:2000"
+	cmd1.PrimaryGroup = "foobar"
 	_, err = cmdToKataProcess(cmd1)
+	assert.Error(err)
+
+	cmd1 = cmd
+	cmd1.User = "foobar:1000"
+	process, err = cmdToKataProcess(cmd1)
+	assert.Nil(err)
+	assert.Equal("foobar:1000", process.User.Username)
+
+	cmd1 = cmd
+	cmd1.User = "1000:2000"
+	process, err = cmdToKataProcess(cmd1)
 	assert.Nil(err)
+	assert.Empty(process.User.Username)
+	assert.Equal(uint32(1000), process.User.UID)
+	assert.Equal(uint32(1000), process.User.GID)
 
 	cmd1 = cmd
 	cmd1.SupplementaryGroups = []string{"foo"}
@@ -723,6 +1038,114 @@ func TestCmdToKataProcess(t *testing.T) {
 	assert.Nil(err)
 }
 
+func TestCmdToKataProcessSecurity(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := types.Cmd{
+		Args:         []string{"foo"},
+		User:         "1000",
+		PrimaryGroup: "1000",
+	}
+
+	// No capabilities are sent unless given.
+	process, err := cmdToKataProcess(cmd)
+	assert.NoError(err)
+	assert.Nil(process.Capabilities)
+	assert.False(process.NoNewPrivileges)
+
+	cmd.Capabilities = types.LinuxCapabilities{
+		Bounding: []string{"CAP_NET_BIND_SERVICE"},
+		Ambient:  []string{"CAP_NET_BIND_SERVICE"},
+	}
+	cmd.NoNewPrivileges = true
+	cmd.ApparmorProfile = "docker-default"
+
+	process, err = cmdToKataProcess(cmd)
+	assert.NoError(err)
+	assert.NotNil(process.Capabilities)
+	assert.Equal([]string{"CAP_NET_BIND_SERVICE"}, process.Capabilities.Ambient)
+	assert.Equal([]string{"CAP_NET_BIND_SERVICE"}, process.Capabilities.Bounding)
+	assert.True(process.NoNewPrivileges)
+	assert.Equal("docker-default", process.ApparmorProfile)
+}
+
+func TestMaskedPathsForwarded(t *testing.T) {
+	assert := assert.New(t)
+
+	ociSpec := &specs.Spec{
+		Linux: &specs.Linux{
+			MaskedPaths:   []string{"/proc/kcore", "/proc/keys"},
+			ReadonlyPaths: []string{"/proc/sys", "/proc/sysrq-trigger"},
+			Resources:     &specs.LinuxResources{},
+		},
+	}
+
+	g, err := pb.OCItoGRPC(ociSpec)
+	assert.NoError(err)
+
+	constraintGRPCSpec(g, false, false)
+
+	// The guest agent applies them in the container mount namespace.
+	assert.Equal(ociSpec.Linux.MaskedPaths, g.Linux.MaskedPaths)
+	assert.Equal(ociSpec.Linux.ReadonlyPaths, g.Linux.ReadonlyPaths)
+}
+
+func TestUnsupportedSpecFields(t *testing.T) {
+	assert := assert.New(t)
+
+	g := &pb.Spec{
+		Linux: &pb.Linux{
+			Seccomp: &pb.LinuxSeccomp{},
+			Resources: &pb.LinuxResources{
+				Devices: []pb.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+				Memory:  &pb.LinuxMemory{},
+			},
+		},
+	}
+
+	assert.Empty(unsupportedSpecFields(g, true))
+	assert.Equal([]string{"linux.seccomp"}, unsupportedSpecFields(g, false))
+
+	g.Linux.Resources.Pids = &pb.LinuxPids{Limit: 100}
+	g.Linux.Resources.HugepageLimits = []pb.LinuxHugepageLimit{{Pagesize: "2MB", Limit: 1}}
+	g.Linux.Namespaces = []pb.LinuxNamespace{
+		{Type: string(specs.CgroupNamespace)},
+		{Type: string(specs.MountNamespace)},
+	}
+	assert.Equal([]string{
+		"linux.resources.pids",
+		"linux.resources.hugepageLimits",
+		"linux.namespaces.cgroup",
+	}, unsupportedSpecFields(g, true))
+
+	// the weight of the container is applied in the guest
+	g.Linux.Namespaces = nil
+	g.Linux.Resources.Pids = nil
+	g.Linux.Resources.HugepageLimits = nil
+	g.Linux.Resources.BlockIO = &pb.LinuxBlockIO{
+		Weight:                500,
+		ThrottleReadBpsDevice: []pb.LinuxThrottleDevice{{Major: 8, Minor: 0, Rate: 1048576}},
+	}
+	assert.Equal([]string{"linux.resources.blockIO.throttleReadBpsDevice"}, unsupportedSpecFields(g, true))
+}
+
+func TestKataGuestBlockIO(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(guestBlockIO(nil))
+	assert.Nil(guestBlockIO(&pb.LinuxBlockIO{
+		WeightDevice: []pb.LinuxWeightDevice{{Major: 8, Minor: 0, Weight: 100}},
+	}))
+
+	blockIO := guestBlockIO(&pb.LinuxBlockIO{
+		Weight:                  500,
+		LeafWeight:              300,
+		WeightDevice:            []pb.LinuxWeightDevice{{Major: 8, Minor: 0, Weight: 100}},
+		ThrottleWriteIOPSDevice: []pb.LinuxThrottleDevice{{Major: 8, Minor: 0, Rate: 1000}},
+	})
+	assert.Equal(&pb.LinuxBlockIO{Weight: 500, LeafWeight: 300}, blockIO)
+}
+
 func TestAgentCreateContainer(t *testing.T) {
 	assert := assert.New(t)
 
@@ -828,6 +1251,9 @@ func TestAgentNetworkOperation(t *testing.T) {
 	_, err = k.updateInterface(nil)
 	assert.Nil(err)
 
+	err = k.removeInterface(nil)
+	assert.Nil(err)
+
 	_, err = k.listInterfaces()
 	assert.Nil(err)
 
@@ -836,6 +1262,52 @@ func TestAgentNetworkOperation(t *testing.T) {
 
 	_, err = k.listRoutes()
 	assert.Nil(err)
+
+	err = k.updateInterfaces([]*vcTypes.Interface{
+		{Name: "eth0", HwAddr: "02:00:ca:fe:00:01"},
+		{Name: "net1", HwAddr: "02:00:ca:fe:00:02"},
+	})
+	assert.Nil(err)
+}
+
+func TestOrderInterfaceUpdates(t *testing.T) {
+	assert := assert.New(t)
+
+	names := func(interfaces []*vcTypes.Interface) []string {
+		var n []string
+		for _, ifc := range interfaces {
+			n = append(n, ifc.Name)
+		}
+		return n
+	}
+
+	net1 := &vcTypes.Interface{Name: "net1", HwAddr: "02:00:ca:fe:00:01"}
+	eth0 := &vcTypes.Interface{Name: "eth0", HwAddr: "02:00:ca:fe:00:02"}
+	eth1 := &vcTypes.Interface{Name: "eth1", HwAddr: "02:00:ca:fe:00:03"}
+
+	// Unknown guest names, the order is kept.
+	updates := orderInterfaceUpdates([]*vcTypes.Interface{net1, eth0}, nil)
+	assert.Equal([]string{"net1", "eth0"}, names(updates))
+
+	// The guest interface of net1 holds eth0.
+	guest := []*vcTypes.Interface{
+		{Name: "eth0", HwAddr: net1.HwAddr},
+		{Name: "eth1", HwAddr: eth0.HwAddr},
+	}
+	updates = orderInterfaceUpdates([]*vcTypes.Interface{eth0, net1}, guest)
+	assert.Equal([]string{"net1", "eth0"}, names(updates))
+
+	// eth0 and eth1 swap their names.
+	guest = []*vcTypes.Interface{
+		{Name: "eth1", HwAddr: eth0.HwAddr},
+		{Name: "eth0", HwAddr: eth1.HwAddr},
+	}
+	updates = orderInterfaceUpdates([]*vcTypes.Interface{eth0, eth1}, guest)
+	assert.Equal([]string{guestTmpIfacePrefix + "0", "eth1", "eth0"}, names(updates))
+	assert.Equal(eth0.HwAddr, updates[0].HwAddr)
+	assert.Equal(eth1, updates[1])
+	assert.Equal(eth0, updates[2])
+	assert.Equal("eth0", eth0.Name)
 }
 
 func TestKataAgentSetProxy(t *testing.T) {
@@ -945,3 +1417,25 @@ func TestKataCleanupSandbox(t *testing.T) {
 		t.Fatalf("%s still exists\n", dir)
 	}
 }
+
+func TestKataAgentKernelParams(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(KataAgentKernelParams(KataAgentConfig{}))
+
+	params := KataAgentKernelParams(KataAgentConfig{Debug: true})
+	assert.Equal([]Param{{logLevelKernelOption, "debug"}}, params)
+}
+
+func TestKataSharedDir9pOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := HypervisorConfig{
+		Msize9p:   512 * 1024,
+		Cache9p:   "loose",
+		Version9p: "9p2000.u",
+	}
+
+	assert.Equal([]string{"trans=virtio,version=9p2000.u,cache=loose", "nodev", "msize=524288"},
+		sharedDir9pOptions(conf))
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/store"
+)
+
+// The guest crash kernel writes the vmcore to the raw disk seen as
+// /dev/disk/by-id/virtio-kdump in the guest, e.g. with a "raw" kdump target.
+const (
+	kdumpDiskID     = "kdump"
+	kdumpDiskFile   = "kdump.img"
+	kdumpDiskFormat = "raw"
+
+	// kdumpDiskSlack is the room in MiB left on the kdump disk for the
+	// dump headers, on top of the guest memory.
+	kdumpDiskSlack = 64
+
+	crashKernelOption = "crashkernel"
+)
+
+// vmcoreFormats are the signatures of the dump formats written by the
+// guest, and the suffix of the collected vmcores.
+var vmcoreFormats = []struct {
+	signature []byte
+	suffix    string
+}{
+	// makedumpfile -F output, rebuilt with makedumpfile -R
+	{[]byte("makedumpfile"), ".flat"},
+	// kdump-compressed format
+	{[]byte("KDUMP   "), ""},
+	{[]byte("\x7fELF"), ""},
+}
+
+func (conf *HypervisorConfig) checkKdumpConfig() error {
+	if conf.KdumpCrashKernelSize == 0 {
+		return nil
+	}
+
+	if conf.KdumpDir == "" {
+		return fmt.Errorf("Missing kdump directory")
+	}
+
+	if !filepath.IsAbs(conf.KdumpDir) {
+		return fmt.Errorf("Invalid kdump directory %s, expecting an absolute path", conf.KdumpDir)
+	}
+
+	if conf.KdumpCrashKernelSize >= conf.MemorySize {
+		return fmt.Errorf("Crash kernel size %d MiB is not lower than the memory size %d MiB",
+			conf.KdumpCrashKernelSize, conf.MemorySize)
+	}
+
+	return nil
+}
+
+func kdumpKernelParams(conf HypervisorConfig) []Param {
+	return []Param{
+		{crashKernelOption, fmt.Sprintf("%dM", conf.KdumpCrashKernelSize)},
+	}
+}
+
+func kdumpDiskPath(sandboxID string) string {
+	return filepath.Join(store.SandboxConfigurationRootPath(sandboxID), kdumpDiskFile)
+}
+
+// addKdumpDisk creates the disk the guest crash kernel writes the vmcore to,
+// and cold plugs it into the VM.
+func (s *Sandbox) addKdumpDisk() error {
+	conf := &s.config.HypervisorConfig
+	if conf.KdumpCrashKernelSize == 0 {
+		return nil
+	}
+
+	if s.factory != nil {
+		s.Logger().Warn("VM factory enabled, not attaching kdump disk")
+		return nil
+	}
+
+	// The guest memory can grow up to the maximum memory size.
+	sizeMB := conf.MemorySize
+	if conf.DefaultMaxMemorySize > sizeMB {
+		sizeMB = conf.DefaultMaxMemorySize
+	}
+	sizeMB += kdumpDiskSlack
+
+	path := kdumpDiskPath(s.id)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = f.Truncate(int64(sizeMB) << 20)
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	drive := config.BlockDrive{
+		File:   path,
+		Format: kdumpDiskFormat,
+		ID:     kdumpDiskID,
+		Serial: kdumpDiskID,
+	}
+
+	if err := s.hypervisor.addDevice(drive, blockDev); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// vmcoreSuffix returns the suffix of the vmcore found on the kdump disk, and
+// false if the guest did not write any.
+func vmcoreSuffix(path string) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 16)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	for _, format := range vmcoreFormats {
+		if bytes.HasPrefix(header[:n], format.signature) {
+			return format.suffix, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// collectKdump moves the vmcore written by the guest crash kernel, if any,
+// to the kdump directory of the sandbox.
+func (s *Sandbox) collectKdump() error {
+	conf := s.config.HypervisorConfig
+	if conf.KdumpCrashKernelSize == 0 {
+		return nil
+	}
+
+	path := kdumpDiskPath(s.id)
+	suffix, found, err := vmcoreSuffix(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !found {
+		return nil
+	}
+
+	dir := filepath.Join(conf.KdumpDir, s.id)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	vmcore := filepath.Join(dir, "vmcore-"+time.Now().Format("20060102-150405")+suffix)
+
+	// Keep the disk sparse when possible.
+	if err := os.Rename(path, vmcore); err != nil {
+		if lerr, ok := err.(*os.LinkError); !ok || lerr.Err != syscall.EXDEV {
+			return err
+		}
+
+		if err := copyFile(path, vmcore); err != nil {
+			return err
+		}
+	}
+
+	s.Logger().WithField("vmcore", vmcore).Warn("collected guest kernel crash dump")
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckKdumpConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, d := range []struct {
+		size  uint32
+		dir   string
+		valid bool
+	}{
+		{0, "", true},
+		{128, "/var/crash/kata", true},
+		{128, "", false},
+		{128, "crash", false},
+		{2048, "/var/crash/kata", false},
+	} {
+		conf := HypervisorConfig{
+			MemorySize:           2048,
+			KdumpCrashKernelSize: d.size,
+			KdumpDir:             d.dir,
+		}
+
+		err := conf.checkKdumpConfig()
+		if d.valid {
+			assert.NoError(err, "%+v", d)
+		} else {
+			assert.Error(err, "%+v", d)
+		}
+	}
+
+	params := kdumpKernelParams(HypervisorConfig{KdumpCrashKernelSize: 128})
+	assert.Equal([]Param{{"crashkernel", "128M"}}, params)
+}
+
+func TestKdumpDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		id:         "kdump-disabled",
+		config:     &SandboxConfig{},
+		hypervisor: &mockHypervisor{},
+	}
+
+	assert.NoError(s.addKdumpDisk())
+	assert.NoError(s.collectKdump())
+	_, err := os.Stat(kdumpDiskPath(s.id))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestCollectKdump(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kdump")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{
+		id: "kdump-sandbox",
+		config: &SandboxConfig{
+			HypervisorConfig: HypervisorConfig{
+				MemorySize:           256,
+				KdumpCrashKernelSize: 64,
+				KdumpDir:             dir,
+			},
+		},
+		hypervisor: &mockHypervisor{},
+	}
+
+	configDir := store.SandboxConfigurationRootPath(s.id)
+	assert.NoError(os.MkdirAll(configDir, store.DirMode))
+	defer os.RemoveAll(configDir)
+
+	assert.NoError(s.addKdumpDisk())
+
+	fi, err := os.Stat(kdumpDiskPath(s.id))
+	assert.NoError(err)
+	assert.Equal(int64(256+kdumpDiskSlack)<<20, fi.Size())
+
+	// nothing is collected until the guest writes a vmcore
+	assert.NoError(s.collectKdump())
+	_, err = os.Stat(filepath.Join(dir, s.id))
+	assert.True(os.IsNotExist(err))
+
+	f, err := os.OpenFile(kdumpDiskPath(s.id), os.O_WRONLY, 0)
+	assert.NoError(err)
+	_, err = f.Write([]byte("makedumpfile\x00\x00\x00\x00"))
+	assert.NoError(err)
+	f.Close()
+
+	assert.NoError(s.collectKdump())
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, s.id))
+	assert.NoError(err)
+	assert.Len(files, 1)
+	assert.True(strings.HasPrefix(files[0].Name(), "vmcore-"))
+	assert.True(strings.HasSuffix(files[0].Name(), ".flat"))
+
+	_, err = os.Stat(kdumpDiskPath(s.id))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestVmcoreSuffix(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kdump")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, kdumpDiskFile)
+
+	for _, d := range []struct {
+		header string
+		suffix string
+		found  bool
+	}{
+		{"", "", false},
+		{"\x00\x00\x00\x00", "", false},
+		{"\x7fELF\x02\x01\x01", "", true},
+		{"KDUMP   \x06\x00", "", true},
+		{"makedumpfile\x00\x00\x00\x00", ".flat", true},
+	} {
+		assert.NoError(ioutil.WriteFile(path, []byte(d.header), 0600))
+
+		suffix, found, err := vmcoreSuffix(path)
+		assert.NoError(err)
+		assert.Equal(d.found, found, "%q", d.header)
+		assert.Equal(d.suffix, suffix, "%q", d.header)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The configuration a kernel built with CONFIG_IKCONFIG embeds, gzipped
+// between these markers.
+var (
+	ikconfigStart = []byte("IKCFG_ST")
+	ikconfigEnd   = []byte("IKCFG_ED")
+)
+
+// pciHotplugKernelOptions are the guest kernel options of the PCI hotplug
+// mechanisms.
+var pciHotplugKernelOptions = map[string]string{
+	PCIHotplugACPI:   "CONFIG_HOTPLUG_PCI_ACPI",
+	PCIHotplugSHPC:   "CONFIG_HOTPLUG_PCI_SHPC",
+	PCIHotplugNative: "CONFIG_HOTPLUG_PCI_PCIE",
+}
+
+// kernelConfig returns the configuration embedded in the kernel image at
+// path, or nil when the image does not embed it uncompressed, as bzImage
+// kernels do.
+func kernelConfig(path string) (map[string]string, error) {
+	image, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	start := bytes.Index(image, ikconfigStart)
+	if start < 0 {
+		return nil, nil
+	}
+	image = image[start+len(ikconfigStart):]
+
+	if end := bytes.Index(image, ikconfigEnd); end >= 0 {
+		image = image[:end]
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(image))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid configuration embedded in kernel %s: %v", path, err)
+	}
+	defer r.Close()
+	r.Multistream(false)
+
+	config := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) == 2 {
+			config[fields[0]] = fields[1]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Invalid configuration embedded in kernel %s: %v", path, err)
+	}
+
+	return config, nil
+}
+
+// checkKernelPCIHotplug fails when the guest kernel at path is known not to
+// be built with the driver of the PCI hotplug mechanism. Modules do not
+// count, the guest only gets the modules the agent is asked to load.
+func checkKernelPCIHotplug(path, mode string) error {
+	option, ok := pciHotplugKernelOptions[mode]
+	if !ok {
+		return nil
+	}
+
+	config, err := kernelConfig(path)
+	if err != nil {
+		return err
+	}
+
+	if config == nil {
+		virtLog.WithField("kernel", path).Debug("The guest kernel configuration is not embedded, PCI hotplug not checked")
+		return nil
+	}
+
+	if config[option] != "y" {
+		return fmt.Errorf("The guest kernel %s does not support %s PCI hotplug, it is not built with %s",
+			path, mode, option)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestKernel(t *testing.T, dir, config string) string {
+	var image bytes.Buffer
+
+	image.WriteString("\x7fELF some kernel code")
+	image.Write(ikconfigStart)
+
+	w := gzip.NewWriter(&image)
+	_, err := w.Write([]byte(config))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	image.Write(ikconfigEnd)
+	image.WriteString("more kernel code")
+
+	path := filepath.Join(dir, "vmlinux")
+	assert.NoError(t, ioutil.WriteFile(path, image.Bytes(), 0644))
+
+	return path
+}
+
+func TestCheckKernelPCIHotplug(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kernel-config")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestKernel(t, dir, `#
+# Automatically generated file; DO NOT EDIT.
+#
+CONFIG_HOTPLUG_PCI_ACPI=y
+CONFIG_HOTPLUG_PCI_SHPC=m
+# CONFIG_HOTPLUG_PCI_PCIE is not set
+`)
+
+	config, err := kernelConfig(path)
+	assert.NoError(err)
+	assert.Equal(map[string]string{
+		"CONFIG_HOTPLUG_PCI_ACPI": "y",
+		"CONFIG_HOTPLUG_PCI_SHPC": "m",
+	}, config)
+
+	assert.NoError(checkKernelPCIHotplug(path, ""))
+	assert.NoError(checkKernelPCIHotplug(path, PCIHotplugACPI))
+	assert.Error(checkKernelPCIHotplug(path, PCIHotplugSHPC))
+	assert.Error(checkKernelPCIHotplug(path, PCIHotplugNative))
+
+	// a kernel not embedding its configuration is not checked
+	assert.NoError(ioutil.WriteFile(path, []byte("\x7fELF"), 0644))
+	assert.NoError(checkKernelPCIHotplug(path, PCIHotplugNative))
+
+	assert.Error(checkKernelPCIHotplug(filepath.Join(dir, "missing"), PCIHotplugNative))
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+)
+
+// KSMConfig is the host kernel same-page merging policy the runtime applies
+// around sandbox creations.
+type KSMConfig struct {
+	// Throttle enables the management of KSM by the runtime.
+	Throttle bool
+
+	// AggressiveDuration is the number of seconds KSM merges pages
+	// aggressively after the last sandbox creation, before being
+	// throttled back.
+	AggressiveDuration uint32
+
+	// Start starts KSM when it is tuned. KSM is otherwise left running
+	// or stopped, as the host set it.
+	Start bool
+}
+
+const defaultKSMAggressiveDuration = 30
+
+type ksmSettings struct {
+	pagesToScan    uint32
+	sleepMillisecs uint32
+}
+
+var (
+	ksmSysPath = "/sys/kernel/mm/ksm"
+
+	// ksmBoostFile is touched on each sandbox creation, and removed
+	// once KSM has been throttled back.
+	ksmBoostFile = filepath.Join("/run", store.StoragePathSuffix, "ksm-boost")
+
+	// Newly created VMs have many identical pages to merge.
+	ksmAggressive = ksmSettings{pagesToScan: 1000, sleepMillisecs: 10}
+
+	// Keep merging the pages that change over time at a low CPU cost.
+	ksmStandard = ksmSettings{pagesToScan: 100, sleepMillisecs: 500}
+)
+
+func (k KSMConfig) aggressiveDuration() time.Duration {
+	if k.AggressiveDuration == 0 {
+		return defaultKSMAggressiveDuration * time.Second
+	}
+
+	return time.Duration(k.AggressiveDuration) * time.Second
+}
+
+// apply tunes KSM, then starts it if start is set.
+func (s ksmSettings) apply(start bool) error {
+	for _, f := range []struct {
+		name  string
+		value uint32
+	}{
+		{"pages_to_scan", s.pagesToScan},
+		{"sleep_millisecs", s.sleepMillisecs},
+	} {
+		if err := writeKSMFile(f.name, f.value); err != nil {
+			return err
+		}
+	}
+
+	if start {
+		return writeKSMFile("run", 1)
+	}
+
+	return nil
+}
+
+func writeKSMFile(name string, value uint32) error {
+	return ioutil.WriteFile(filepath.Join(ksmSysPath, name), []byte(fmt.Sprintf("%d", value)), 0644)
+}
+
+func ksmAvailable() bool {
+	_, err := os.Stat(filepath.Join(ksmSysPath, "run"))
+	return err == nil
+}
+
+// boostKSM makes KSM merge pages aggressively after a sandbox creation.
+func (k KSMConfig) boostKSM() error {
+	if !k.Throttle || !ksmAvailable() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ksmBoostFile), store.DirMode); err != nil {
+		return err
+	}
+
+	// Recreate the file so that its modification time tracks the last
+	// sandbox creation.
+	f, err := os.Create(ksmBoostFile)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	return ksmAggressive.apply(k.Start)
+}
+
+// throttleKSM throttles KSM back once no sandbox has been created for the
+// aggressive duration. It is called by the sandbox monitors and on each
+// sandbox deletion, so that KSM is throttled back even without a long
+// running runtime process.
+func (k KSMConfig) throttleKSM(now time.Time) error {
+	if !k.Throttle || !ksmAvailable() {
+		return nil
+	}
+
+	fi, err := os.Stat(ksmBoostFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if now.Sub(fi.ModTime()) < k.aggressiveDuration() {
+		return nil
+	}
+
+	if err := ksmStandard.apply(k.Start); err != nil {
+		return err
+	}
+
+	if err := os.Remove(ksmBoostFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupFakeKSM(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "ksm")
+	assert.NoError(t, err)
+
+	savedSysPath, savedBoostFile := ksmSysPath, ksmBoostFile
+	ksmSysPath = filepath.Join(dir, "ksm")
+	ksmBoostFile = filepath.Join(dir, "run", "ksm-boost")
+
+	assert.NoError(t, os.MkdirAll(ksmSysPath, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(ksmSysPath, "run"), []byte("0"), 0644))
+
+	return dir, func() {
+		ksmSysPath, ksmBoostFile = savedSysPath, savedBoostFile
+		os.RemoveAll(dir)
+	}
+}
+
+func readKSMFile(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(ksmSysPath, name))
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestKSMThrottling(t *testing.T) {
+	assert := assert.New(t)
+	_, cleanup := setupFakeKSM(t)
+	defer cleanup()
+
+	k := KSMConfig{Throttle: true, AggressiveDuration: 10}
+
+	assert.NoError(k.boostKSM())
+	assert.Equal("1000", readKSMFile(t, "pages_to_scan"))
+	assert.Equal("10", readKSMFile(t, "sleep_millisecs"))
+	// KSM is tuned, not started
+	assert.Equal("0", readKSMFile(t, "run"))
+
+	// still within the aggressive duration
+	assert.NoError(k.throttleKSM(time.Now()))
+	assert.Equal("1000", readKSMFile(t, "pages_to_scan"))
+
+	assert.NoError(k.throttleKSM(time.Now().Add(11 * time.Second)))
+	assert.Equal("100", readKSMFile(t, "pages_to_scan"))
+	assert.Equal("500", readKSMFile(t, "sleep_millisecs"))
+
+	_, err := os.Stat(ksmBoostFile)
+	assert.True(os.IsNotExist(err))
+
+	// nothing to throttle back anymore
+	assert.NoError(k.throttleKSM(time.Now().Add(time.Minute)))
+}
+
+func TestKSMStart(t *testing.T) {
+	assert := assert.New(t)
+	_, cleanup := setupFakeKSM(t)
+	defer cleanup()
+
+	k := KSMConfig{Throttle: true, Start: true}
+
+	assert.NoError(k.boostKSM())
+	assert.Equal("1000", readKSMFile(t, "pages_to_scan"))
+	assert.Equal("1", readKSMFile(t, "run"))
+}
+
+func TestKSMThrottlingDisabled(t *testing.T) {
+	assert := assert.New(t)
+	_, cleanup := setupFakeKSM(t)
+	defer cleanup()
+
+	k := KSMConfig{}
+	assert.NoError(k.boostKSM())
+	assert.NoError(k.throttleKSM(time.Now()))
+
+	_, err := os.Stat(filepath.Join(ksmSysPath, "pages_to_scan"))
+	assert.True(os.IsNotExist(err))
+	assert.Equal("0", readKSMFile(t, "run"))
+}
+
+func TestKSMAggressiveDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(defaultKSMAggressiveDuration*time.Second, KSMConfig{}.aggressiveDuration())
+	assert.Equal(5*time.Second, KSMConfig{AggressiveDuration: 5}.aggressiveDuration())
+}
+
+func TestKSMThrottledOnSandboxDelete(t *testing.T) {
+	defer cleanUp()
+
+	assert := assert.New(t)
+	_, cleanup := setupFakeKSM(t)
+	defer cleanup()
+
+	config := newTestSandboxConfigNoop()
+	config.KSM = KSMConfig{Throttle: true, AggressiveDuration: 10}
+
+	ctx := context.Background()
+	p, err := CreateSandbox(ctx, config, nil)
+	assert.NoError(err)
+	assert.Equal("1000", readKSMFile(t, "pages_to_scan"))
+
+	// the sandbox was created long ago
+	old := time.Now().Add(-time.Minute)
+	assert.NoError(os.Chtimes(ksmBoostFile, old, old))
+
+	_, err = DeleteSandbox(ctx, p.ID())
+	assert.NoError(err)
+	assert.Equal("100", readKSMFile(t, "pages_to_scan"))
+}
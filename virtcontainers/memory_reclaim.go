@@ -0,0 +1,219 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryReclaimConfig is the policy returning the guest memory the sandbox
+// containers do not use to the host, by inflating a virtio-balloon device.
+//
+// The available share of the memory left to the guest, as its MemAvailable
+// tells, is kept between LowFree and HighFree: the balloon is inflated when
+// more than HighFree percent of it is available, and deflated when less
+// than LowFree percent is, to the middle of the band in both cases.
+type MemoryReclaimConfig struct {
+	// Enable adds the balloon device to the VM and runs the policy.
+	Enable bool
+
+	// LowFree is the free share, in percent, below which the balloon is
+	// deflated.
+	LowFree uint32
+
+	// HighFree is the free share, in percent, above which the balloon is
+	// inflated.
+	HighFree uint32
+
+	// MinMemory is the size in MiB the guest memory is never ballooned
+	// below, for the guest kernel and the agent.
+	MinMemory uint32
+}
+
+const (
+	defaultMemoryReclaimLowFree   = 10
+	defaultMemoryReclaimHighFree  = 30
+	defaultMemoryReclaimMinMemory = 256 // MiB
+)
+
+func (c *MemoryReclaimConfig) valid() error {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.LowFree == 0 {
+		c.LowFree = defaultMemoryReclaimLowFree
+	}
+
+	if c.HighFree == 0 {
+		c.HighFree = defaultMemoryReclaimHighFree
+	}
+
+	if c.MinMemory == 0 {
+		c.MinMemory = defaultMemoryReclaimMinMemory
+	}
+
+	if c.LowFree >= c.HighFree || c.HighFree >= 100 {
+		return fmt.Errorf("Invalid memory reclaim free memory band [%d%%, %d%%]", c.LowFree, c.HighFree)
+	}
+
+	return nil
+}
+
+// memoryBalloon is implemented by the hypervisors adding a balloon device to
+// the VM when the memory reclaim policy is enabled.
+type memoryBalloon interface {
+	// guestMemory returns the memory of the VM in MiB, the hotplugged
+	// memory included.
+	guestMemory() uint32
+
+	// setBalloonTarget inflates or deflates the balloon for the guest to
+	// be left with targetMB MiB.
+	setBalloonTarget(targetMB uint32) error
+
+	// guestMemAvailable returns the memory in MiB the guest has
+	// available, as reported by the balloon driver.
+	guestMemAvailable() (uint32, error)
+}
+
+// memoryReclaimer applies the memory reclaim policy on each monitor check.
+// The memory used in the guest is the memory left to it less its
+// MemAvailable, as reported by the balloon, without calling the agent.
+type memoryReclaimer struct {
+	sync.Mutex
+
+	sandbox *Sandbox
+	balloon memoryBalloon
+	config  MemoryReclaimConfig
+
+	// target is the memory in MiB left to the guest by the balloon, 0
+	// while the balloon is deflated.
+	target uint32
+}
+
+func newMemoryReclaimer(s *Sandbox, config MemoryReclaimConfig) *memoryReclaimer {
+	if !config.Enable {
+		return nil
+	}
+
+	balloon, ok := s.hypervisor.(memoryBalloon)
+	if !ok {
+		s.Logger().WithField("hypervisor", s.config.HypervisorType).Warn("no balloon device, memory reclaim disabled")
+		return nil
+	}
+
+	return &memoryReclaimer{
+		sandbox: s,
+		balloon: balloon,
+		config:  config,
+	}
+}
+
+// reset deflates the balloon, eg. after the VM memory has been resized for
+// a new container.
+func (r *memoryReclaimer) reset() {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.target == 0 {
+		return
+	}
+
+	if err := r.balloon.setBalloonTarget(r.balloon.guestMemory()); err != nil {
+		r.sandbox.Logger().WithError(err).Warn("failed to deflate the balloon")
+		return
+	}
+	r.target = 0
+}
+
+// check resizes the balloon once the free memory has left the band.
+func (r *memoryReclaimer) check() {
+	if r == nil {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	memory := r.balloon.guestMemory()
+	current := r.target
+	if current == 0 || current > memory {
+		current = memory
+	}
+
+	available, err := r.balloon.guestMemAvailable()
+	if err != nil {
+		r.sandbox.Logger().WithError(err).Debug("could not get the guest available memory")
+		return
+	}
+
+	var used uint32
+	if current > available {
+		used = current - available
+	}
+
+	target, resize := reclaimTarget(r.config, memory, current, used)
+	if !resize {
+		return
+	}
+
+	logger := r.sandbox.Logger().WithFields(logrus.Fields{
+		"used-mb":   used,
+		"memory-mb": memory,
+		"target-mb": target,
+	})
+
+	if err := r.balloon.setBalloonTarget(target); err != nil {
+		logger.WithError(err).Warn("failed to resize the balloon")
+		return
+	}
+	logger.Info("resized the balloon")
+
+	r.target = target
+	if target == memory {
+		r.target = 0
+	}
+}
+
+// reclaimTarget returns the memory in MiB to leave to the guest for it to
+// use usedMB out of the currentMB it is left with, and whether the balloon
+// has to be resized to it.
+func reclaimTarget(config MemoryReclaimConfig, memoryMB, currentMB, usedMB uint32) (uint32, bool) {
+	var free uint32
+	if currentMB > usedMB {
+		free = uint32(uint64(currentMB-usedMB) * 100 / uint64(currentMB))
+	}
+
+	if free >= config.LowFree && free <= config.HighFree {
+		return currentMB, false
+	}
+
+	// the containers use 100 - mid percent of the target
+	mid := (config.LowFree + config.HighFree) / 2
+	target := uint64(usedMB) * 100 / uint64(100-mid)
+
+	min := config.MinMemory
+	if min > memoryMB {
+		min = memoryMB
+	}
+
+	if target < uint64(min) {
+		target = uint64(min)
+	}
+
+	if target > uint64(memoryMB) {
+		target = uint64(memoryMB)
+	}
+
+	return uint32(target), uint32(target) != currentMB
+}
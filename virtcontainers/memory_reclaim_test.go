@@ -0,0 +1,139 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type balloonHypervisor struct {
+	mockHypervisor
+	memory    uint32
+	available uint32
+	targets   []uint32
+}
+
+func (b *balloonHypervisor) guestMemory() uint32 {
+	return b.memory
+}
+
+func (b *balloonHypervisor) setBalloonTarget(targetMB uint32) error {
+	b.targets = append(b.targets, targetMB)
+	return nil
+}
+
+func (b *balloonHypervisor) guestMemAvailable() (uint32, error) {
+	return b.available, nil
+}
+
+func TestMemoryReclaimConfigValid(t *testing.T) {
+	assert := assert.New(t)
+
+	config := MemoryReclaimConfig{}
+	assert.NoError(config.valid())
+	assert.Zero(config.LowFree)
+
+	config.Enable = true
+	assert.NoError(config.valid())
+	assert.Equal(uint32(defaultMemoryReclaimLowFree), config.LowFree)
+	assert.Equal(uint32(defaultMemoryReclaimHighFree), config.HighFree)
+	assert.Equal(uint32(defaultMemoryReclaimMinMemory), config.MinMemory)
+
+	config.LowFree = 40
+	assert.Error(config.valid())
+
+	config.LowFree = 10
+	config.HighFree = 100
+	assert.Error(config.valid())
+}
+
+func TestReclaimTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	config := MemoryReclaimConfig{
+		LowFree:   10,
+		HighFree:  30,
+		MinMemory: 256,
+	}
+
+	type testData struct {
+		memory, current, used uint32
+		target                uint32
+		resize                bool
+	}
+
+	data := []testData{
+		// 20% free, within the band
+		{2048, 2048, 1638, 2048, false},
+		// mostly free, inflated so that 20% stays free
+		{2048, 2048, 800, 1000, true},
+		// never below the minimum memory
+		{2048, 2048, 0, 256, true},
+		// the containers need more, deflated
+		{2048, 1000, 950, 1187, true},
+		// never beyond the VM memory
+		{2048, 1000, 1990, 2048, true},
+		// already deflated
+		{2048, 2048, 2048, 2048, false},
+		// a minimum above the VM memory
+		{128, 128, 0, 128, false},
+	}
+
+	for _, d := range data {
+		target, resize := reclaimTarget(config, d.memory, d.current, d.used)
+		assert.Equal(d.target, target, "%+v", d)
+		assert.Equal(d.resize, resize, "%+v", d)
+	}
+}
+
+func TestMemoryReclaimer(t *testing.T) {
+	assert := assert.New(t)
+
+	// nil when disabled
+	var nilReclaimer *memoryReclaimer
+	nilReclaimer.reset()
+	nilReclaimer.check()
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{},
+		config:     &SandboxConfig{},
+	}
+	config := MemoryReclaimConfig{Enable: true}
+	assert.NoError(config.valid())
+
+	// no balloon device
+	assert.Nil(newMemoryReclaimer(s, config))
+	assert.Nil(newMemoryReclaimer(s, MemoryReclaimConfig{}))
+
+	balloon := &balloonHypervisor{memory: 2048}
+	s.hypervisor = balloon
+
+	r := newMemoryReclaimer(s, config)
+	assert.NotNil(r)
+
+	// 800MiB used
+	balloon.available = 1248
+	r.check()
+	assert.Equal([]uint32{1000}, balloon.targets)
+
+	// within the band, left alone
+	balloon.available = 150
+	r.check()
+	assert.Len(balloon.targets, 1)
+
+	// 300MiB used
+	balloon.available = 700
+	r.check()
+	assert.Equal(uint32(375), balloon.targets[1])
+
+	r.reset()
+	assert.Equal(uint32(2048), balloon.targets[2])
+	r.reset()
+	assert.Len(balloon.targets, 3)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+// MockHooks script the mock hypervisor and the noop agent, selected with
+// the MockHypervisor and NoopAgentType sandbox configuration, for callers
+// of the API to unit test their sandbox management without KVM.
+//
+// Each hypervisor and agent operation calls the hook of its component with
+// its name, eg. "startSandbox" or "createContainer", before doing nothing.
+// The operation fails with the error returned by the hook, which can also
+// block to delay it or count the calls made.
+type MockHooks struct {
+	Hypervisor func(op string) error
+	Agent      func(op string) error
+}
+
+func (h *MockHooks) hypervisor(op string) error {
+	if h == nil || h.Hypervisor == nil {
+		return nil
+	}
+
+	return h.Hypervisor(op)
+}
+
+func (h *MockHooks) agent(op string) error {
+	if h == nil || h.Agent == nil {
+		return nil
+	}
+
+	return h.Agent(op)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockHooks(t *testing.T) {
+	defer cleanUp()
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var agentOps []string
+	config := newTestSandboxConfigNoop()
+	config.Stateful = true
+	config.MockHooks = &MockHooks{
+		Hypervisor: func(op string) error {
+			if op == "pauseSandbox" {
+				return errors.New("cannot pause")
+			}
+			return nil
+		},
+		Agent: func(op string) error {
+			agentOps = append(agentOps, op)
+			return nil
+		},
+	}
+
+	p, err := CreateSandbox(ctx, config, nil)
+	assert.NoError(err)
+	defer p.Release()
+	assert.Contains(agentOps, "createContainer")
+
+	_, err = PauseSandbox(ctx, p.ID())
+	assert.EqualError(err, "cannot pause")
+
+	// the hooks only apply to their sandbox
+	other := newTestSandboxConfigNoop()
+	other.ID = "other-sandbox"
+	other.Stateful = true
+
+	o, err := CreateSandbox(ctx, other, nil)
+	assert.NoError(err)
+	defer o.Release()
+
+	_, err = PauseSandbox(ctx, o.ID())
+	assert.NoError(err)
+
+	// no hooks
+	var hooks *MockHooks
+	assert.NoError(hooks.hypervisor("pauseSandbox"))
+	assert.NoError(hooks.agent("createContainer"))
+}
@@ -14,8 +14,20 @@ import (
 	"github.com/kata-containers/runtime/virtcontainers/types"
 )
 
+// mockHypervisor runs no VM, its operations can be scripted with MockHooks.
 type mockHypervisor struct {
 	mockPid int
+	hooks   *MockHooks
+}
+
+// hook runs the hypervisor hook of the sandbox, the unit tests use a nil
+// mockHypervisor.
+func (m *mockHypervisor) hook(op string) error {
+	if m == nil {
+		return nil
+	}
+
+	return m.hooks.hypervisor(op)
 }
 
 func (m *mockHypervisor) capabilities() types.Capabilities {
@@ -32,34 +44,38 @@ func (m *mockHypervisor) createSandbox(ctx context.Context, id string, hyperviso
 		return err
 	}
 
-	return nil
+	return m.hook("createSandbox")
 }
 
 func (m *mockHypervisor) startSandbox(timeout int) error {
-	return nil
+	return m.hook("startSandbox")
 }
 
 func (m *mockHypervisor) stopSandbox() error {
-	return nil
+	return m.hook("stopSandbox")
 }
 
 func (m *mockHypervisor) pauseSandbox() error {
-	return nil
+	return m.hook("pauseSandbox")
 }
 
 func (m *mockHypervisor) resumeSandbox() error {
-	return nil
+	return m.hook("resumeSandbox")
 }
 
 func (m *mockHypervisor) saveSandbox() error {
-	return nil
+	return m.hook("saveSandbox")
 }
 
 func (m *mockHypervisor) addDevice(devInfo interface{}, devType deviceType) error {
-	return nil
+	return m.hook("addDevice")
 }
 
 func (m *mockHypervisor) hotplugAddDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
+	if err := m.hook("hotplugAddDevice"); err != nil {
+		return nil, err
+	}
+
 	switch devType {
 	case cpuDev:
 		return devInfo.(uint32), nil
@@ -71,6 +87,10 @@ func (m *mockHypervisor) hotplugAddDevice(devInfo interface{}, devType deviceTyp
 }
 
 func (m *mockHypervisor) hotplugRemoveDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
+	if err := m.hook("hotplugRemoveDevice"); err != nil {
+		return nil, err
+	}
+
 	switch devType {
 	case cpuDev:
 		return devInfo.(uint32), nil
@@ -85,10 +105,18 @@ func (m *mockHypervisor) getSandboxConsole(sandboxID string) (string, error) {
 }
 
 func (m *mockHypervisor) resizeMemory(memMB uint32, memorySectionSizeMB uint32) (uint32, error) {
-	return 0, nil
+	return 0, m.hook("resizeMemory")
 }
 func (m *mockHypervisor) resizeVCPUs(cpus uint32) (uint32, uint32, error) {
-	return 0, 0, nil
+	return 0, 0, m.hook("resizeVCPUs")
+}
+
+func (m *mockHypervisor) dedicateVCPUs(owner string, count uint32) ([]int, error) {
+	return nil, m.hook("dedicateVCPUs")
+}
+
+func (m *mockHypervisor) releaseVCPUs(owner string) (uint32, error) {
+	return 0, m.hook("releaseVCPUs")
 }
 
 func (m *mockHypervisor) disconnect() {
@@ -12,6 +12,12 @@ import (
 
 const defaultCheckInterval = 10 * time.Second
 
+// hostSuspendThreshold is how far the wall clock can run ahead of the
+// monotonic clock between two checks before the host is considered to have
+// been suspended. Neither the monotonic clock nor the guest clock advance
+// while the host is suspended.
+const hostSuspendThreshold = 2 * time.Second
+
 type monitor struct {
 	sync.Mutex
 
@@ -45,14 +51,23 @@ func (m *monitor) newWatcher() (chan error, error) {
 		// create and start agent watcher
 		go func() {
 			tick := time.NewTicker(m.checkInterval)
+			last := time.Now()
 			for {
 				select {
 				case <-m.stopCh:
 					tick.Stop()
 					m.wg.Done()
 					return
-				case <-tick.C:
-					m.watchAgent()
+				case now := <-tick.C:
+					// a sandbox paused while idle cannot answer
+					if !m.sandbox.idle.isPaused() {
+						m.watchAgent()
+						m.watchClock(last, now)
+						m.sandbox.reclaim.check()
+					}
+					m.sandbox.idle.check(now)
+					m.watchKSM(now)
+					last = now
 				}
 			}
 		}()
@@ -118,3 +133,28 @@ func (m *monitor) watchAgent() {
 		m.notify(err)
 	}
 }
+
+// watchClock resyncs the guest clock if the host has been suspended since
+// the last check.
+func (m *monitor) watchClock(last, now time.Time) {
+	wall := now.Round(0).Sub(last.Round(0))
+	if !hostWasSuspended(wall, now.Sub(last)) {
+		return
+	}
+
+	m.sandbox.Logger().WithField("suspended", wall-now.Sub(last)).Info("host resumed from suspend")
+	m.sandbox.syncGuestTime()
+}
+
+// watchKSM throttles KSM back when sandboxes are no longer being created.
+func (m *monitor) watchKSM(now time.Time) {
+	if err := m.sandbox.config.KSM.throttleKSM(now); err != nil {
+		m.sandbox.Logger().WithError(err).Warn("failed to throttle KSM")
+	}
+}
+
+// hostWasSuspended compares the wall clock and monotonic clock time elapsed
+// over the same period.
+func hostWasSuspended(wall, monotonic time.Duration) bool {
+	return wall-monotonic > hostSuspendThreshold
+}
@@ -8,6 +8,7 @@ package virtcontainers
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -60,3 +61,25 @@ func TestMonitorClosedChannel(t *testing.T) {
 
 	m.stop()
 }
+
+func TestHostWasSuspended(t *testing.T) {
+	assert := assert.New(t)
+
+	type testData struct {
+		wall      time.Duration
+		monotonic time.Duration
+		expected  bool
+	}
+
+	data := []testData{
+		{defaultCheckInterval, defaultCheckInterval, false},
+		{defaultCheckInterval + time.Second, defaultCheckInterval, false},
+		{defaultCheckInterval - time.Minute, defaultCheckInterval, false},
+		{defaultCheckInterval + time.Minute, defaultCheckInterval, true},
+		{time.Hour, defaultCheckInterval, true},
+	}
+
+	for i, d := range data {
+		assert.Equal(d.expected, hostWasSuspended(d.wall, d.monotonic), "test %d: %+v", i, d)
+	}
+}
@@ -13,8 +13,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 // DefaultShmSize is the default shm size to be used in case host
@@ -227,6 +231,291 @@ func GetDevicePathAndFsType(mountPoint string) (devicePath, fsType string, err e
 	}
 }
 
+var procMountInfoFile = "/proc/self/mountinfo"
+
+const (
+	mountInfoDeviceIndex     = 2
+	mountInfoRootIndex       = 3
+	mountInfoMountPointIndex = 4
+	mountInfoOptionsIndex    = 5
+)
+
+// unescapeMountInfo decodes the octal escapes, eg. \040 for a space, of a
+// mountinfo path.
+func unescapeMountInfo(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// mountRoot returns the path, within its filesystem, of the root of the
+// mount at mountPoint. It is "/" unless the mount is a bind mount of a
+// subdirectory, eg. a Kubernetes subPath volume.
+func mountRoot(mountPoint string) (string, error) {
+	file, err := os.Open(procMountInfoFile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	root := ""
+
+	// The last mount wins when several mounts share the mount point.
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) <= mountInfoMountPointIndex {
+			continue
+		}
+
+		if unescapeMountInfo(fields[mountInfoMountPointIndex]) == mountPoint {
+			root = unescapeMountInfo(fields[mountInfoRootIndex])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if root == "" {
+		return "", fmt.Errorf("Mount %s not found", mountPoint)
+	}
+
+	return root, nil
+}
+
+// HostMount is a host mount of the filesystem of a block device.
+type HostMount struct {
+	// Device is the block device the filesystem is mounted from.
+	Device string
+	Fstype string
+
+	MountPoint string
+
+	// Root is the path, within the filesystem, of the root of the
+	// mount.
+	Root string
+
+	// Options are the per mount options, eg. ro or nosuid.
+	Options []string
+
+	// DeviceNumber is the "major:minor" number of Device, as the
+	// device names of iSCSI LUNs and RBD images can be reused by other
+	// devices once unmapped.
+	DeviceNumber string
+}
+
+// hostMountFlags are the mount flags of the per mount options.
+var hostMountFlags = map[string]uintptr{
+	"ro":         syscall.MS_RDONLY,
+	"nosuid":     syscall.MS_NOSUID,
+	"nodev":      syscall.MS_NODEV,
+	"noexec":     syscall.MS_NOEXEC,
+	"noatime":    syscall.MS_NOATIME,
+	"nodiratime": syscall.MS_NODIRATIME,
+	"relatime":   syscall.MS_RELATIME,
+}
+
+func (m HostMount) flags() uintptr {
+	var flags uintptr
+	for _, o := range m.Options {
+		flags |= hostMountFlags[o]
+	}
+
+	return flags
+}
+
+// deviceHostMounts returns the host mounts of the filesystem of the block
+// device with the major and minor numbers, in mount order.
+func deviceHostMounts(major, minor int) ([]HostMount, error) {
+	file, err := os.Open(procMountInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dev := fmt.Sprintf("%d:%d", major, minor)
+
+	var mounts []HostMount
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) <= mountInfoOptionsIndex || fields[mountInfoDeviceIndex] != dev {
+			continue
+		}
+
+		// The optional fields end with a "-" separator, followed by
+		// the filesystem type and the mount source.
+		sep := mountInfoOptionsIndex + 1
+		for sep < len(fields) && fields[sep] != "-" {
+			sep++
+		}
+		if sep+2 >= len(fields) {
+			return nil, fmt.Errorf("Unexpected format for %s", procMountInfoFile)
+		}
+
+		mounts = append(mounts, HostMount{
+			Device:       unescapeMountInfo(fields[sep+2]),
+			Fstype:       fields[sep+1],
+			MountPoint:   unescapeMountInfo(fields[mountInfoMountPointIndex]),
+			Root:         unescapeMountInfo(fields[mountInfoRootIndex]),
+			Options:      strings.Split(fields[mountInfoOptionsIndex], ","),
+			DeviceNumber: dev,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return mounts, nil
+}
+
+// unmountHostMounts unmounts the host mounts of a filesystem, the last
+// mounted first. The mounts are left as they were on failure.
+func unmountHostMounts(mounts []HostMount) error {
+	for i := len(mounts) - 1; i >= 0; i-- {
+		if err := syscall.Unmount(mounts[i].MountPoint, 0); err != nil {
+			if rerr := restoreHostMounts(mounts[i+1:]); rerr != nil {
+				virtLog.WithError(rerr).WithField("mount", mounts[i].MountPoint).Error("failed to restore the host mounts")
+			}
+			return fmt.Errorf("failed to unmount %s: %v", mounts[i].MountPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// restoreLostHostMounts restores the host mounts of the filesystem of a
+// block device recorded by a runtime which died before restoring them. They
+// are left alone if something is mounted at the first mount point already,
+// eg. because they were restored before the runtime died.
+func restoreLostHostMounts(mounts []HostMount) error {
+	if len(mounts) == 0 {
+		return nil
+	}
+
+	mounted, err := mountsUnder(mounts[0].MountPoint)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounted {
+		if m == filepath.Clean(mounts[0].MountPoint) {
+			return nil
+		}
+	}
+
+	// The iSCSI session or the RBD mapping may have been recreated
+	// since, another device reusing the name.
+	if number := mounts[0].DeviceNumber; number != "" {
+		var stat unix.Stat_t
+		if err := unix.Stat(mounts[0].Device, &stat); err != nil {
+			return err
+		}
+
+		if current := fmt.Sprintf("%d:%d", unix.Major(stat.Rdev), unix.Minor(stat.Rdev)); current != number {
+			return fmt.Errorf("%s is device %s, not the device %s it was mounted from", mounts[0].Device, current, number)
+		}
+	}
+
+	return restoreHostMounts(mounts)
+}
+
+// restoreHostMounts mounts again the filesystem of a block device at its
+// former host mount points, with their per mount options. The first mount of
+// the filesystem root mounts the device, the other mounts bind mount it.
+func restoreHostMounts(mounts []HostMount) error {
+	base := ""
+
+	for _, m := range mounts {
+		if base == "" {
+			if m.Root != "/" {
+				return fmt.Errorf("cannot mount %s at %s, its root is not mounted", m.Root, m.MountPoint)
+			}
+
+			if err := syscall.Mount(m.Device, m.MountPoint, m.Fstype, m.flags(), ""); err != nil {
+				return fmt.Errorf("failed to mount %s at %s: %v", m.Device, m.MountPoint, err)
+			}
+			base = m.MountPoint
+			continue
+		}
+
+		source := filepath.Join(base, m.Root)
+		if err := syscall.Mount(source, m.MountPoint, "bind", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("failed to bind mount %s at %s: %v", source, m.MountPoint, err)
+		}
+
+		// The per mount options of a bind mount are only set by a
+		// remount.
+		if flags := m.flags(); flags != 0 {
+			if err := syscall.Mount("none", m.MountPoint, "", syscall.MS_BIND|syscall.MS_REMOUNT|flags, ""); err != nil {
+				return fmt.Errorf("failed to remount %s: %v", m.MountPoint, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// volumeBackingDevice returns the device backing the filesystem mounted at
+// path, and the type of that filesystem. The device path is empty if path is
+// not the root of a filesystem, as only whole filesystems can be passed to
+// the guest: passing the device of a subdirectory bind mount would expose
+// the whole filesystem to the container.
+func volumeBackingDevice(path string) (dev device, devicePath, fsType string, err error) {
+	dev, err = getDeviceForPath(path)
+	if err == errMountPointNotFound {
+		// path is on the root filesystem
+		return device{}, "", "", nil
+	} else if err != nil {
+		return device{}, "", "", err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return device{}, "", "", err
+	}
+
+	if dev.mountPoint != path {
+		return device{}, "", "", nil
+	}
+
+	root, err := mountRoot(path)
+	if err != nil {
+		return device{}, "", "", err
+	}
+
+	if root != "/" {
+		virtLog.WithFields(logrus.Fields{
+			"volume": path,
+			"root":   root,
+		}).Info("volume is a subdirectory of its filesystem, not passing its device")
+		return device{}, "", "", nil
+	}
+
+	devicePath, fsType, err = GetDevicePathAndFsType(dev.mountPoint)
+	if err != nil {
+		return device{}, "", "", err
+	}
+
+	return dev, devicePath, fsType, nil
+}
+
 var blockFormatTemplate = "/sys/dev/block/%d:%d/dm"
 
 var checkStorageDriver = isDeviceMapper
@@ -249,6 +538,47 @@ func isDeviceMapper(major, minor int) (bool, error) {
 
 const mountPerm = os.FileMode(0755)
 
+// openNoSymlinks opens path, an absolute path without symlinks, failing if
+// any of its components is a symlink. This keeps a resolved path from being
+// redirected by a symlink swapped in place of one of its components, eg. by
+// a container writing to the volume holding the path.
+func openNoSymlinks(path string) (*os.File, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("Path %s is not absolute", path)
+	}
+
+	fd, err := unix.Open("/", unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range strings.Split(filepath.Clean(path), "/") {
+		if component == "" {
+			continue
+		}
+
+		next, err := unix.Openat(fd, component, unix.O_PATH|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		unix.Close(fd)
+		if err != nil {
+			return nil, &os.PathError{Op: "openat", Path: path, Err: err}
+		}
+		fd = next
+
+		var stat unix.Stat_t
+		if err := unix.Fstat(fd, &stat); err != nil {
+			unix.Close(fd)
+			return nil, &os.PathError{Op: "fstat", Path: path, Err: err}
+		}
+
+		if stat.Mode&unix.S_IFMT == unix.S_IFLNK {
+			unix.Close(fd)
+			return nil, fmt.Errorf("Path %s has a symlink component %s", path, component)
+		}
+	}
+
+	return os.NewFile(uintptr(fd), path), nil
+}
+
 // bindMount bind mounts a source in to a destination. This will
 // do some bookkeeping:
 // * evaluate all symlinks
@@ -258,6 +588,22 @@ func bindMount(ctx context.Context, source, destination string, readonly bool) e
 	span, _ := trace(ctx, "bindMount")
 	defer span.Finish()
 
+	return bindMountWithPropagation(source, destination, readonly, syscall.MS_PRIVATE)
+}
+
+// bindMountVolume bind mounts the source of a container volume as a slave
+// mount. A filesystem mounted on the source after the container creation,
+// eg. a CSI ephemeral volume published late by the kubelet, then shows up
+// in the destination, and goes away from it when unmounted from the source.
+func bindMountVolume(ctx context.Context, source, destination string) error {
+	span, _ := trace(ctx, "bindMountVolume")
+	defer span.Finish()
+
+	return bindMountWithPropagation(source, destination, false, syscall.MS_SLAVE)
+}
+
+func bindMountWithPropagation(source, destination string, readonly bool, propagation uintptr) error {
+
 	if source == "" {
 		return fmt.Errorf("source must be specified")
 	}
@@ -270,16 +616,26 @@ func bindMount(ctx context.Context, source, destination string, readonly bool) e
 		return fmt.Errorf("Could not resolve symlink for source %v", source)
 	}
 
+	// Mount the resolved source through a file descriptor, so that it
+	// cannot be swapped for a symlink before being mounted.
+	src, err := openNoSymlinks(absSource)
+	if err != nil {
+		return fmt.Errorf("Could not open source %v: %v", absSource, err)
+	}
+	defer src.Close()
+
 	if err := ensureDestinationExists(absSource, destination); err != nil {
 		return fmt.Errorf("Could not create destination mount point %v: %v", destination, err)
 	}
 
-	if err := syscall.Mount(absSource, destination, "bind", syscall.MS_BIND, ""); err != nil {
+	fdPath := fmt.Sprintf("/proc/self/fd/%d", src.Fd())
+	if err := syscall.Mount(fdPath, destination, "bind", syscall.MS_BIND, ""); err != nil {
 		return fmt.Errorf("Could not bind mount %v to %v: %v", absSource, destination, err)
 	}
 
-	if err := syscall.Mount("none", destination, "", syscall.MS_PRIVATE, ""); err != nil {
-		return fmt.Errorf("Could not make mount point %v private: %v", destination, err)
+	// The mount is left private by MS_SLAVE when the source is not shared.
+	if err := syscall.Mount("none", destination, "", propagation, ""); err != nil {
+		return fmt.Errorf("Could not set the propagation of mount point %v: %v", destination, err)
 	}
 
 	// For readonly bind mounts, we need to remount with the readonly flag.
@@ -323,6 +679,15 @@ type Mount struct {
 	// VM in case this mount is a block device file or a directory
 	// backed by a block device.
 	BlockDeviceID string
+
+	// BlockDeviceFstype is the type of the filesystem the guest mounts
+	// from the block device, for a directory backed by a block device.
+	BlockDeviceFstype string
+
+	// BlockDeviceHostMounts are the host mounts of the filesystem of the
+	// block device of a directory, unmounted as long as the guest mounts
+	// it so that the filesystem is never mounted twice.
+	BlockDeviceHostMounts []HostMount
 }
 
 func bindUnmountContainerRootfs(ctx context.Context, sharedDir, sandboxID, cID string) error {
@@ -344,7 +709,7 @@ func bindUnmountAllRootfs(ctx context.Context, sharedDir string, sandbox *Sandbo
 		if c.state.Fstype == "" {
 			// Need to check for error returned by this call.
 			// See: https://github.com/containers/virtcontainers/issues/295
-			bindUnmountContainerRootfs(c.ctx, sharedDir, sandbox.id, c.id)
+			c.bindUnmountRootfs(sharedDir)
 		}
 	}
 }
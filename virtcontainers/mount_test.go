@@ -9,9 +9,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"syscall"
@@ -282,3 +284,317 @@ func TestIsDeviceMapper(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestVolumeBackingDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// not the root of a filesystem
+	_, devicePath, _, err := volumeBackingDevice(dir)
+	if err != nil || devicePath != "" {
+		t.Fatalf("Unexpected backing device %q for %s: %v", devicePath, dir, err)
+	}
+
+	if _, _, err := GetDevicePathAndFsType("/"); err != nil {
+		t.Skip("root filesystem not found in mounts")
+	}
+
+	_, devicePath, fsType, err := volumeBackingDevice("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if devicePath == "" || fsType == "" {
+		t.Fatalf("Expected a backing device for /, got %q with type %q", devicePath, fsType)
+	}
+}
+
+func TestUnescapeMountInfo(t *testing.T) {
+	for _, d := range []struct {
+		escaped   string
+		unescaped string
+	}{
+		{"/var/lib/kubelet", "/var/lib/kubelet"},
+		{"/mnt/with\\040space", "/mnt/with space"},
+		{"/mnt/back\\134slash", "/mnt/back\\slash"},
+		{"/mnt/trailing\\04", "/mnt/trailing\\04"},
+	} {
+		if got := unescapeMountInfo(d.escaped); got != d.unescaped {
+			t.Fatalf("Expected %q for %q, got %q", d.unescaped, d.escaped, got)
+		}
+	}
+}
+
+func TestMountRoot(t *testing.T) {
+	f, err := ioutil.TempFile("", "mountinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	mountInfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+40 22 252:0 / /var/lib/kubelet/pods/p/volumes/v rw,relatime shared:20 - ext4 /dev/rbd0 rw
+41 22 252:0 /data/logs /var/lib/kubelet/pods/p/volume-subpaths/v/c/0 rw,relatime shared:20 - ext4 /dev/rbd0 rw
+42 22 8:1 / /mnt/over rw,relatime - ext4 /dev/sda1 rw
+43 22 8:1 /srv /mnt/over rw,relatime - ext4 /dev/sda1 rw
+44 22 8:1 /with\040space /mnt/space\040dir rw,relatime - ext4 /dev/sda1 rw
+`
+	if _, err := f.WriteString(mountInfo); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	savedMountInfo := procMountInfoFile
+	procMountInfoFile = f.Name()
+	defer func() {
+		procMountInfoFile = savedMountInfo
+	}()
+
+	for _, d := range []struct {
+		mountPoint string
+		root       string
+	}{
+		{"/", "/"},
+		{"/var/lib/kubelet/pods/p/volumes/v", "/"},
+		{"/var/lib/kubelet/pods/p/volume-subpaths/v/c/0", "/data/logs"},
+		{"/mnt/over", "/srv"},
+		{"/mnt/space dir", "/with space"},
+	} {
+		root, err := mountRoot(d.mountPoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != d.root {
+			t.Fatalf("Expected root %q for %s, got %q", d.root, d.mountPoint, root)
+		}
+	}
+
+	if _, err := mountRoot("/not/mounted"); err == nil {
+		t.Fatal("Expected an error for a path not mounted")
+	}
+}
+
+func TestDeviceHostMounts(t *testing.T) {
+	f, err := ioutil.TempFile("", "mountinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	mountInfo := `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+40 22 252:0 / /var/lib/kubelet/plugins/rbd/mounts/pool-image rw,relatime shared:20 - ext4 /dev/rbd0 rw
+41 22 252:0 / /var/lib/kubelet/pods/p/volumes/v rw,nosuid,nodev shared:20 - ext4 /dev/rbd0 rw
+42 22 252:0 /data/logs /var/lib/kubelet/pods/p/volume-subpaths/v/c/0 ro,relatime - ext4 /dev/rbd0 rw
+`
+	if _, err := f.WriteString(mountInfo); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	savedMountInfo := procMountInfoFile
+	procMountInfoFile = f.Name()
+	defer func() {
+		procMountInfoFile = savedMountInfo
+	}()
+
+	mounts, err := deviceHostMounts(252, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []HostMount{
+		{"/dev/rbd0", "ext4", "/var/lib/kubelet/plugins/rbd/mounts/pool-image", "/", []string{"rw", "relatime"}, "252:0"},
+		{"/dev/rbd0", "ext4", "/var/lib/kubelet/pods/p/volumes/v", "/", []string{"rw", "nosuid", "nodev"}, "252:0"},
+		{"/dev/rbd0", "ext4", "/var/lib/kubelet/pods/p/volume-subpaths/v/c/0", "/data/logs", []string{"ro", "relatime"}, "252:0"},
+	}
+	if !reflect.DeepEqual(mounts, expected) {
+		t.Fatalf("Expected %v, got %v", expected, mounts)
+	}
+
+	if flags := mounts[1].flags(); flags != syscall.MS_NOSUID|syscall.MS_NODEV {
+		t.Fatalf("Unexpected flags %x", flags)
+	}
+
+	if mounts, err = deviceHostMounts(252, 1); err != nil || len(mounts) != 0 {
+		t.Fatalf("Expected no mounts, got %v, %v", mounts, err)
+	}
+}
+
+func TestRestoreHostMountsNoRoot(t *testing.T) {
+	err := restoreHostMounts([]HostMount{
+		{"/dev/rbd0", "ext4", "/var/lib/kubelet/pods/p/volume-subpaths/v/c/0", "/data/logs", nil, ""},
+	})
+	if err == nil {
+		t.Fatal("Expected an error restoring a mount of a subdirectory only")
+	}
+}
+
+func TestRestoreLostHostMountsDeviceReused(t *testing.T) {
+	f, err := ioutil.TempFile("", "mountinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString("22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	savedMountInfo := procMountInfoFile
+	procMountInfoFile = f.Name()
+	defer func() {
+		procMountInfoFile = savedMountInfo
+	}()
+
+	// /dev/null is 1:3, not the device the mount was recorded from
+	err = restoreLostHostMounts([]HostMount{
+		{"/dev/null", "ext4", "/var/lib/kubelet/plugins/iscsi/iface-default/1.2.3.4:3260-iqn-lun-0", "/", nil, "8:16"},
+	})
+	if err == nil {
+		t.Fatal("Expected an error restoring a mount from a reused device name")
+	}
+}
+
+func TestOpenNoSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nosymlinks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the temporary directory itself may be behind a symlink
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := filepath.Join(dir, "volume", "sub")
+	if err := os.MkdirAll(sub, mountPerm); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink("/", link); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := openNoSymlinks(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	for _, path := range []string{link, filepath.Join(link, "etc"), "volume/sub", filepath.Join(dir, "missing")} {
+		if f, err := openNoSymlinks(path); err == nil {
+			f.Close()
+			t.Fatalf("Expected an error opening %s", path)
+		}
+	}
+}
+
+func TestVolumeBackingDeviceSubdirectory(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	dir, err := ioutil.TempDir("", "subpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "sub")
+	dest := filepath.Join(dir, "dest")
+	for _, d := range []string{sub, dest} {
+		if err := os.MkdirAll(d, mountPerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := bindMount(context.Background(), sub, dest, false); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Unmount(dest, 0)
+
+	root, err := mountRoot(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root == "/" {
+		t.Fatalf("Expected the root of %s to be a subdirectory", dest)
+	}
+
+	// the subdirectory bind mount is not passed as a device
+	_, devicePath, _, err := volumeBackingDevice(dest)
+	if err != nil || devicePath != "" {
+		t.Fatalf("Unexpected backing device %q for %s: %v", devicePath, dest, err)
+	}
+}
+
+func TestBindMountVolume(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	dir, err := ioutil.TempDir("", "late-volume")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The kubelet pods directory is a shared mount.
+	pods := filepath.Join(dir, "pods")
+	source := filepath.Join(pods, "volume")
+	dest := filepath.Join(dir, "dest")
+	for _, d := range []string{pods, dest} {
+		if err := os.MkdirAll(d, mountPerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := syscall.Mount("tmpfs", pods, "tmpfs", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Unmount(pods, syscall.MNT_DETACH)
+
+	if err := syscall.Mount("none", pods, "", syscall.MS_SHARED, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Mkdir(source, mountPerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bindMountVolume(context.Background(), source, dest); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Unmount(dest, syscall.MNT_DETACH)
+
+	// The volume is published after the bind mount.
+	if err := syscall.Mount("tmpfs", source, "tmpfs", 0, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(source, "data"), []byte("data"), 0600); err != nil {
+		syscall.Unmount(source, syscall.MNT_DETACH)
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "data")); err != nil {
+		syscall.Unmount(source, syscall.MNT_DETACH)
+		t.Fatalf("Expected the late volume mount in %s: %v", dest, err)
+	}
+
+	// The volume unmount goes through as well.
+	if err := syscall.Unmount(source, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "data")); !os.IsNotExist(err) {
+		t.Fatalf("Expected the volume to be unmounted from %s: %v", dest, err)
+	}
+}
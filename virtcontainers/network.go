@@ -1048,6 +1048,56 @@ func removeTCFiltering(endpoint Endpoint) error {
 	return nil
 }
 
+// endpointsStats returns the traffic stats of the network endpoints, read
+// from their interfaces in the network namespace. The guest traffic goes
+// through them, and they are the interfaces of a runc pod in that same
+// namespace, so that the stats are alike. The endpoints without such an
+// interface, eg. a physical nic passed through, are skipped.
+func endpointsStats(netNsPath string, endpoints []Endpoint) ([]*NetworkStats, error) {
+	if netNsPath == "" || len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	var stats []*NetworkStats
+
+	err := doNetNS(netNsPath, func(_ ns.NetNS) error {
+		netHandle, err := netlink.NewHandle()
+		if err != nil {
+			return err
+		}
+		defer netHandle.Delete()
+
+		for _, endpoint := range endpoints {
+			link, err := netHandle.LinkByName(endpoint.Name())
+			if err != nil {
+				networkLogger().WithError(err).WithField("endpoint", endpoint.Name()).Debug("No stats for endpoint")
+				continue
+			}
+
+			s := link.Attrs().Statistics
+			if s == nil {
+				continue
+			}
+
+			stats = append(stats, &NetworkStats{
+				Name:      endpoint.Name(),
+				RxBytes:   s.RxBytes,
+				RxPackets: s.RxPackets,
+				RxErrors:  s.RxErrors,
+				RxDropped: s.RxDropped,
+				TxBytes:   s.TxBytes,
+				TxPackets: s.TxPackets,
+				TxErrors:  s.TxErrors,
+				TxDropped: s.TxDropped,
+			})
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
 func createNetNS() (string, error) {
 	n, err := ns.NewNS()
 	if err != nil {
@@ -1282,6 +1332,13 @@ func createEndpointsFromScan(networkNSPath string, config *NetworkConfig) ([]End
 		return []Endpoint{}, err
 	}
 
+	// Create the endpoints in the order of the interface names, so that
+	// their indexes and the guest device order do not depend on the order
+	// the links were created in the network namespace.
+	sort.Slice(linkList, func(i, j int) bool {
+		return linkList[i].Attrs().Name < linkList[j].Attrs().Name
+	})
+
 	idx := 0
 	for _, link := range linkList {
 		var (
@@ -1320,10 +1377,6 @@ func createEndpointsFromScan(networkNSPath string, config *NetworkConfig) ([]End
 		idx++
 	}
 
-	sort.Slice(endpoints, func(i, j int) bool {
-		return endpoints[i].Name() < endpoints[j].Name()
-	})
-
 	networkLogger().WithField("endpoints", endpoints).Info("Endpoints found after scan")
 
 	return endpoints, nil
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/containernetworking/plugins/pkg/ns"
 	vcTypes "github.com/kata-containers/runtime/virtcontainers/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/vishvananda/netlink"
@@ -304,3 +305,38 @@ func TestTcRedirectNetwork(t *testing.T) {
 	err = netHandle.LinkDel(link)
 	assert.NoError(err)
 }
+
+func TestEndpointsStats(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	assert := assert.New(t)
+
+	stats, err := endpointsStats("", nil)
+	assert.NoError(err)
+	assert.Nil(stats)
+
+	netNSPath, err := createNetNS()
+	assert.NoError(err)
+	defer deleteNetNS(netNSPath)
+
+	err = doNetNS(netNSPath, func(_ ns.NetNS) error {
+		return netlink.LinkAdd(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "eth0"}, PeerName: "peer0"})
+	})
+	assert.NoError(err)
+
+	// The second endpoint has no interface in the netns.
+	var endpoints []Endpoint
+	for i, name := range []string{"eth0", "eth1"} {
+		endpoint, err := createVethNetworkEndpoint(i, name, DefaultNetInterworkingModel)
+		assert.NoError(err)
+		endpoints = append(endpoints, endpoint)
+	}
+
+	stats, err = endpointsStats(netNSPath, endpoints)
+	assert.NoError(err)
+	if assert.Len(stats, 1) {
+		assert.Equal("eth0", stats[0].Name)
+	}
+}
@@ -17,8 +17,9 @@ import (
 )
 
 // noopAgent a.k.a. NO-OP Agent is an empty Agent implementation, for testing and
-// mocking purposes.
+// mocking purposes. Its operations can be scripted with MockHooks.
 type noopAgent struct {
+	hooks *MockHooks
 }
 
 //start the proxy to watch the vm console. It does nothing.
@@ -26,14 +27,18 @@ func (n *noopAgent) startProxy(sandbox *Sandbox) error {
 	return nil
 }
 
-// init initializes the Noop agent, i.e. it does nothing.
+// init initializes the Noop agent, i.e. it only takes the hooks of the sandbox.
 func (n *noopAgent) init(ctx context.Context, sandbox *Sandbox, config interface{}) error {
+	if sandbox != nil && sandbox.config != nil {
+		n.hooks = sandbox.config.MockHooks
+	}
+
 	return nil
 }
 
 // createSandbox is the Noop agent sandbox creation implementation. It does nothing.
 func (n *noopAgent) createSandbox(sandbox *Sandbox) error {
-	return nil
+	return n.hooks.agent("createSandbox")
 }
 
 // capabilities returns empty capabilities, i.e no capabilties are supported.
@@ -48,37 +53,41 @@ func (n *noopAgent) disconnect() error {
 
 // exec is the Noop agent command execution implementation. It does nothing.
 func (n *noopAgent) exec(sandbox *Sandbox, c Container, cmd types.Cmd) (*Process, error) {
-	return nil, nil
+	return nil, n.hooks.agent("exec")
 }
 
 // startSandbox is the Noop agent Sandbox starting implementation. It does nothing.
 func (n *noopAgent) startSandbox(sandbox *Sandbox) error {
-	return nil
+	return n.hooks.agent("startSandbox")
 }
 
 // stopSandbox is the Noop agent Sandbox stopping implementation. It does nothing.
 func (n *noopAgent) stopSandbox(sandbox *Sandbox) error {
-	return nil
+	return n.hooks.agent("stopSandbox")
 }
 
 // createContainer is the Noop agent Container creation implementation. It does nothing.
 func (n *noopAgent) createContainer(sandbox *Sandbox, c *Container) (*Process, error) {
+	if err := n.hooks.agent("createContainer"); err != nil {
+		return nil, err
+	}
+
 	return &Process{}, nil
 }
 
 // startContainer is the Noop agent Container starting implementation. It does nothing.
 func (n *noopAgent) startContainer(sandbox *Sandbox, c *Container) error {
-	return nil
+	return n.hooks.agent("startContainer")
 }
 
 // stopContainer is the Noop agent Container stopping implementation. It does nothing.
 func (n *noopAgent) stopContainer(sandbox *Sandbox, c Container) error {
-	return nil
+	return n.hooks.agent("stopContainer")
 }
 
 // signalProcess is the Noop agent Container signaling implementation. It does nothing.
 func (n *noopAgent) signalProcess(c *Container, processID string, signal syscall.Signal, all bool) error {
-	return nil
+	return n.hooks.agent("signalProcess")
 }
 
 // processListContainer is the Noop agent Container ps implementation. It does nothing.
@@ -88,7 +97,7 @@ func (n *noopAgent) processListContainer(sandbox *Sandbox, c Container, options
 
 // updateContainer is the Noop agent Container update implementation. It does nothing.
 func (n *noopAgent) updateContainer(sandbox *Sandbox, c Container, resources specs.LinuxResources) error {
-	return nil
+	return n.hooks.agent("updateContainer")
 }
 
 // onlineCPUMem is the Noop agent Container online CPU and Memory implementation. It does nothing.
@@ -98,7 +107,12 @@ func (n *noopAgent) onlineCPUMem(cpus uint32, cpuOnly bool) error {
 
 // updateInterface is the Noop agent Interface update implementation. It does nothing.
 func (n *noopAgent) updateInterface(inf *vcTypes.Interface) (*vcTypes.Interface, error) {
-	return nil, nil
+	return nil, n.hooks.agent("updateInterface")
+}
+
+// removeInterface is the Noop agent Interface remove implementation. It does nothing.
+func (n *noopAgent) removeInterface(inf *vcTypes.Interface) error {
+	return n.hooks.agent("removeInterface")
 }
 
 // listInterfaces is the Noop agent Interfaces list implementation. It does nothing.
@@ -118,17 +132,21 @@ func (n *noopAgent) listRoutes() ([]*vcTypes.Route, error) {
 
 // check is the Noop agent health checker. It does nothing.
 func (n *noopAgent) check() error {
-	return nil
+	return n.hooks.agent("check")
 }
 
 // statsContainer is the Noop agent Container stats implementation. It does nothing.
 func (n *noopAgent) statsContainer(sandbox *Sandbox, c Container) (*ContainerStats, error) {
+	if err := n.hooks.agent("statsContainer"); err != nil {
+		return nil, err
+	}
+
 	return &ContainerStats{}, nil
 }
 
 // waitProcess is the Noop agent process waiter. It does nothing.
 func (n *noopAgent) waitProcess(c *Container, processID string) (int32, error) {
-	return 0, nil
+	return 0, n.hooks.agent("waitProcess")
 }
 
 // winsizeProcess is the Noop agent process tty resizer. It does nothing.
@@ -158,12 +176,12 @@ func (n *noopAgent) readProcessStderr(c *Container, processID string, data []byt
 
 // pauseContainer is the Noop agent Container pause implementation. It does nothing.
 func (n *noopAgent) pauseContainer(sandbox *Sandbox, c Container) error {
-	return nil
+	return n.hooks.agent("pauseContainer")
 }
 
 // resumeContainer is the Noop agent Container resume implementation. It does nothing.
 func (n *noopAgent) resumeContainer(sandbox *Sandbox, c Container) error {
-	return nil
+	return n.hooks.agent("resumeContainer")
 }
 
 // configHypervisor is the Noop agent hypervisor configuration implementation. It does nothing.
@@ -233,6 +233,14 @@ func TestNoopAgentUpdateInterface(t *testing.T) {
 	}
 }
 
+func TestNoopAgentRemoveInterface(t *testing.T) {
+	n := &noopAgent{}
+	err := n.removeInterface(nil)
+	if err != nil {
+		t.Fatal("removeInterface failed")
+	}
+}
+
 func TestNoopAgentListInterfaces(t *testing.T) {
 	n := &noopAgent{}
 	_, err := n.listInterfaces()
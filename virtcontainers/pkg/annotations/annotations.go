@@ -5,6 +5,11 @@
 
 package annotations
 
+import (
+	"regexp"
+	"strings"
+)
+
 const (
 	vcAnnotationsPrefix = "com.github.containers.virtcontainers."
 
@@ -23,6 +28,10 @@ const (
 	// FirmwarePath is a sandbox annotation for passing a per container path pointing at the guest firmware that will run the container VM.
 	FirmwarePath = vcAnnotationsPrefix + "FirmwarePath"
 
+	// GuestBoot is a sandbox annotation selecting the guest artifact the
+	// VM boots from, "image" or "initrd", when both are configured.
+	GuestBoot = vcAnnotationsPrefix + "GuestBoot"
+
 	// KernelHash is a sandbox annotation for passing a container kernel image SHA-512 hash value.
 	KernelHash = vcAnnotationsPrefix + "KernelHash"
 
@@ -41,6 +50,73 @@ const (
 	// AssetHashType is the hash type used for assets verification
 	AssetHashType = vcAnnotationsPrefix + "AssetHashType"
 
+	// AgentDebug is a sandbox annotation to set the log level of the agent
+	// to debug and have it send its logs to a vsock port. It is ignored
+	// unless the agent configuration allows it.
+	AgentDebug = vcAnnotationsPrefix + "AgentDebug"
+
+	// ScratchDiskSize is a sandbox annotation for the size in MiB of the
+	// scratch block device, overriding the hypervisor configuration.
+	ScratchDiskSize = vcAnnotationsPrefix + "ScratchDiskSize"
+
+	// ScratchWritableLayer is a container annotation to enable or disable
+	// keeping the container writable layer on the scratch disk.
+	ScratchWritableLayer = vcAnnotationsPrefix + "ScratchWritableLayer"
+
+	// PortForward is a container annotation for the host ports forwarded
+	// to the container, as a comma separated list of
+	// [hostIP:]hostPort:containerPort[/tcp] entries. The host IP defaults
+	// to 127.0.0.1, the host ports below 1024 are refused.
+	PortForward = vcAnnotationsPrefix + "PortForward"
+
+	// DefaultVCPUs is a sandbox annotation for the number of vCPUs of the
+	// VM, which can be a fraction of a vCPU, overriding the hypervisor
+	// configuration.
+	DefaultVCPUs = vcAnnotationsPrefix + "DefaultVCPUs"
+
+	// DefaultMaxVCPUs is a sandbox annotation for the maximum number of
+	// vCPUs of the VM, overriding the hypervisor configuration.
+	DefaultMaxVCPUs = vcAnnotationsPrefix + "DefaultMaxVCPUs"
+
+	// MemSlots is a sandbox annotation for the number of memory slots of
+	// the VM, overriding the hypervisor configuration.
+	MemSlots = vcAnnotationsPrefix + "MemSlots"
+
+	// DefaultMaxMemory is a sandbox annotation for the maximum memory in
+	// MiB the VM can reach through hotplug, overriding the hypervisor
+	// configuration.
+	DefaultMaxMemory = vcAnnotationsPrefix + "DefaultMaxMemory"
+
+	// CPUModel is a sandbox annotation for the guest CPU model, overriding
+	// the hypervisor configuration.
+	CPUModel = vcAnnotationsPrefix + "CPUModel"
+
+	// CPUFeatures is a sandbox annotation for the comma-separated list of
+	// guest CPU features, overriding the hypervisor configuration.
+	CPUFeatures = vcAnnotationsPrefix + "CPUFeatures"
+
+	// VhostUserSCSIController is a sandbox annotation for the name of the
+	// vhost-user-scsi controller of the vhost user store attached to the VM,
+	// among the controllers provisioned for the sandbox.
+	VhostUserSCSIController = vcAnnotationsPrefix + "VhostUserSCSIController"
+
+	// MemoryReclaim is a sandbox annotation set to "false" for the pod to
+	// opt out of the memory reclaim policy of the runtime configuration.
+	MemoryReclaim = vcAnnotationsPrefix + "MemoryReclaim"
+
+	// Msize9p, Cache9p and Version9p are sandbox annotations for the msize,
+	// the cache mode and the protocol version of the 9p shares, overriding
+	// the hypervisor configuration. They are ignored unless the hypervisor
+	// configuration allows them.
+	Msize9p   = vcAnnotationsPrefix + "Msize9p"
+	Cache9p   = vcAnnotationsPrefix + "Cache9p"
+	Version9p = vcAnnotationsPrefix + "Version9p"
+
+	// SandboxLabelPrefix is the prefix of the sandbox annotations setting
+	// the sandbox labels, eg. SandboxLabelPrefix + "team" for the "team"
+	// label.
+	SandboxLabelPrefix = vcAnnotationsPrefix + "label."
+
 	// ConfigJSONKey is the annotation key to fetch the OCI configuration.
 	ConfigJSONKey = vcAnnotationsPrefix + "pkg.oci.config"
 
@@ -55,3 +131,35 @@ const (
 	// SHA512 is the SHA-512 (64) hash algorithm
 	SHA512 string = "sha512"
 )
+
+// DefaultEnabled are the annotations enabled when the runtime configuration
+// does not list any, the guest asset annotations virtcontainers has always
+// honored.
+var DefaultEnabled = []string{
+	"KernelPath", "ImagePath", "InitrdPath", "HypervisorPath", "FirmwarePath",
+	"KernelHash", "ImageHash", "InitrdHash", "HypervisorHash", "FirmwareHash",
+	"AssetHashType",
+}
+
+// Enabled returns true if the annotation key is not a virtcontainers
+// annotation, or if its name, without the virtcontainers prefix, fully
+// matches one of the enabled regular expressions, eg. "DefaultVCPUs" or
+// "label\..*". A nil list enables the DefaultEnabled annotations.
+func Enabled(enabled []string, key string) bool {
+	if !strings.HasPrefix(key, vcAnnotationsPrefix) {
+		return true
+	}
+
+	if enabled == nil {
+		enabled = DefaultEnabled
+	}
+
+	name := strings.TrimPrefix(key, vcAnnotationsPrefix)
+	for _, e := range enabled {
+		if match, err := regexp.MatchString("^(?:"+e+")$", name); err == nil && match {
+			return true
+		}
+	}
+
+	return false
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -103,6 +104,10 @@ type FactoryConfig struct {
 
 	// VMCacheEndpoint specifies the endpoint of transport VM from the VM cache server to runtime.
 	VMCacheEndpoint string
+
+	// VMPoolSize specifies the number of booted, agent-ready VMs kept
+	// by the VM pool server. The pool shares VMCacheEndpoint with VMCache.
+	VMPoolSize uint
 }
 
 // RuntimeConfig aggregates all runtime specific settings
@@ -133,9 +138,45 @@ type RuntimeConfig struct {
 	//Determines if seccomp should be applied inside guest
 	DisableGuestSeccomp bool
 
+	//Number of seconds after which an idle sandbox VM is paused
+	IdlePauseTimeout uint32
+
+	//Host KSM policy
+	KSM vc.KSMConfig
+
+	//Number of seconds after which a slow sandbox operation is logged
+	SlowOperationThreshold uint32
+
+	//Number of seconds a missing container volume source is waited for
+	VolumeSourceTimeout uint32
+
+	//Where the privileged host operations of the sandboxes are recorded
+	Audit vc.AuditConfig
+
+	//Host binaries run at the points of the sandbox lifecycle
+	HostHooks vc.HostHooksConfig
+
+	//Determines if the host devices of the privileged containers are passed
+	PrivilegedWithoutHostDevices bool
+
+	//Determines if the specs with fields the guest cannot honor are refused
+	StrictOCISpec bool
+
+	//Regular expressions of the virtcontainers annotations names the pods can set
+	EnableAnnotations []string
+
+	//Determines if the shim serves pprof profiles on its debug socket
+	EnablePprof bool
+
+	//Number of times the shim recreates the sandbox VM after its hypervisor died
+	MaxVMRestarts uint32
+
 	//Determines if create a netns for hypervisor process
 	DisableNewNetNs bool
 
+	//Policy for the sandboxes requesting the host networking
+	HostNetworking string
+
 	//Experimental features enabled
 	Experimental []exp.Feature
 }
@@ -395,6 +436,35 @@ func GetContainerType(annotations map[string]string) (vc.ContainerType, error) {
 	return vc.UnknownContainerType, fmt.Errorf("Could not find container type")
 }
 
+// sharePidNs tells if the containers of a sandbox can share its pid namespace,
+// as the CRI asks for by giving them the pid namespace of the sandbox. The
+// sandbox has to be a CRI one, with a pid namespace of its own.
+func sharePidNs(ocispec CompatOCISpec) bool {
+	if ocispec.Linux == nil {
+		return false
+	}
+
+	cri := false
+	for _, key := range CRIContainerTypeKeyList {
+		if _, ok := ocispec.Annotations[key]; ok {
+			cri = true
+			break
+		}
+	}
+
+	if !cri {
+		return false
+	}
+
+	for _, ns := range ocispec.Linux.Namespaces {
+		if ns.Type == spec.PIDNamespace && ns.Path == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ContainerType returns the type of container and if the container type was
 // found from CRI servers annotations.
 func (spec *CompatOCISpec) ContainerType() (vc.ContainerType, error) {
@@ -437,6 +507,7 @@ func addAssetAnnotations(ocispec CompatOCISpec, config *vc.SandboxConfig) {
 		vcAnnotations.InitrdPath,
 		vcAnnotations.KernelHash,
 		vcAnnotations.ImageHash,
+		vcAnnotations.InitrdHash,
 		vcAnnotations.AssetHashType,
 	}
 
@@ -450,9 +521,171 @@ func addAssetAnnotations(ocispec CompatOCISpec, config *vc.SandboxConfig) {
 	}
 }
 
+func addAgentAnnotations(ocispec CompatOCISpec, config *vc.SandboxConfig) error {
+	c, ok := config.AgentConfig.(vc.KataAgentConfig)
+	if !ok {
+		return nil
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.AgentDebug]; ok {
+		debug, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Error parsing annotation for %s: %v", vcAnnotations.AgentDebug, err)
+		}
+
+		if debug && !c.Debug {
+			c.Debug = true
+
+			params := append([]vc.Param{}, config.HypervisorConfig.KernelParams...)
+			config.HypervisorConfig.KernelParams = append(params, vc.KataAgentKernelParams(vc.KataAgentConfig{
+				Debug: true,
+			})...)
+			config.HypervisorConfig.EnableVSockLog()
+		}
+	}
+
+	config.AgentConfig = c
+
+	return nil
+}
+
+func addHypervisorAnnotations(ocispec CompatOCISpec, config *vc.SandboxConfig) error {
+	for annotation, field := range map[string]*uint32{
+		vcAnnotations.ScratchDiskSize:  &config.HypervisorConfig.ScratchDiskSize,
+		vcAnnotations.DefaultMaxVCPUs:  &config.HypervisorConfig.DefaultMaxVCPUs,
+		vcAnnotations.MemSlots:         &config.HypervisorConfig.MemSlots,
+		vcAnnotations.DefaultMaxMemory: &config.HypervisorConfig.DefaultMaxMemorySize,
+	} {
+		value, ok := ocispec.Annotations[annotation]
+		if !ok {
+			continue
+		}
+
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("Error parsing annotation for %s: %v", annotation, err)
+		}
+
+		*field = uint32(v)
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.DefaultVCPUs]; ok {
+		vcpus, err := strconv.ParseFloat(value, 32)
+		if err != nil || vcpus <= 0 {
+			return fmt.Errorf("Error parsing annotation for %s: invalid number of vCPUs %q",
+				vcAnnotations.DefaultVCPUs, value)
+		}
+
+		config.HypervisorConfig.NumVCPUs = uint32(math.Ceil(vcpus))
+		config.HypervisorConfig.DefaultVCPUs = 0
+		if vcpus != math.Trunc(vcpus) {
+			config.HypervisorConfig.DefaultVCPUs = float32(vcpus)
+		}
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.GuestBoot]; ok {
+		config.HypervisorConfig.GuestBoot = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.CPUModel]; ok {
+		config.HypervisorConfig.CPUModel = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.CPUFeatures]; ok {
+		config.HypervisorConfig.CPUFeatures = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.VhostUserSCSIController]; ok {
+		config.HypervisorConfig.VhostUserSCSIController = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.MemoryReclaim]; ok {
+		reclaim, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("Error parsing annotation for %s: %v", vcAnnotations.MemoryReclaim, err)
+		}
+
+		// a pod can only opt out, the balloon device is enabled by
+		// the runtime configuration
+		if !reclaim {
+			config.HypervisorConfig.MemoryReclaim.Enable = false
+		}
+	}
+
+	return add9pAnnotations(ocispec, config)
+}
+
+func add9pAnnotations(ocispec CompatOCISpec, config *vc.SandboxConfig) error {
+	if value, ok := ocispec.Annotations[vcAnnotations.Msize9p]; ok {
+		msize, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("Error parsing annotation for %s: %v", vcAnnotations.Msize9p, err)
+		}
+
+		config.HypervisorConfig.Msize9p = uint32(msize)
+	}
+
+	// the values are checked with the rest of the hypervisor
+	// configuration when the sandbox is created
+	if value, ok := ocispec.Annotations[vcAnnotations.Cache9p]; ok {
+		config.HypervisorConfig.Cache9p = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.Version9p]; ok {
+		config.HypervisorConfig.Version9p = value
+	}
+
+	return nil
+}
+
+// enabledAnnotations returns the annotations of the spec, without the
+// virtcontainers annotations the runtime configuration does not enable.
+func enabledAnnotations(ocispec CompatOCISpec, enabled []string) map[string]string {
+	if ocispec.Annotations == nil {
+		return nil
+	}
+
+	annotations := make(map[string]string, len(ocispec.Annotations))
+	for key, value := range ocispec.Annotations {
+		if !vcAnnotations.Enabled(enabled, key) {
+			ociLog.WithField("annotation", key).Warn("annotation not enabled by the runtime configuration, ignoring it")
+			continue
+		}
+
+		annotations[key] = value
+	}
+
+	return annotations
+}
+
+// sandboxLabels returns the sandbox labels set through the annotations.
+func sandboxLabels(ocispec CompatOCISpec) map[string]string {
+	var labels map[string]string
+
+	for key, value := range ocispec.Annotations {
+		if !strings.HasPrefix(key, vcAnnotations.SandboxLabelPrefix) {
+			continue
+		}
+
+		label := strings.TrimPrefix(key, vcAnnotations.SandboxLabelPrefix)
+		if label == "" {
+			continue
+		}
+
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[label] = value
+	}
+
+	return labels
+}
+
 // SandboxConfig converts an OCI compatible runtime configuration file
 // to a virtcontainers sandbox configuration structure.
 func SandboxConfig(ocispec CompatOCISpec, runtime RuntimeConfig, bundlePath, cid, console string, detach, systemdCgroup bool) (vc.SandboxConfig, error) {
+	ocispec.Annotations = enabledAnnotations(ocispec, runtime.EnableAnnotations)
+
 	containerConfig, err := ContainerConfig(ocispec, bundlePath, cid, console, detach)
 	if err != nil {
 		return vc.SandboxConfig{}, err
@@ -499,16 +732,45 @@ func SandboxConfig(ocispec CompatOCISpec, runtime RuntimeConfig, bundlePath, cid
 			vcAnnotations.BundlePathKey: bundlePath,
 		},
 
+		Labels: sandboxLabels(ocispec),
+
 		ShmSize: shmSize,
 
+		SharePidNs: sharePidNs(ocispec),
+
 		SystemdCgroup: systemdCgroup,
 
 		DisableGuestSeccomp: runtime.DisableGuestSeccomp,
 
+		IdlePauseTimeout: runtime.IdlePauseTimeout,
+
+		KSM: runtime.KSM,
+
+		SlowOperationThreshold: runtime.SlowOperationThreshold,
+
+		VolumeSourceTimeout: runtime.VolumeSourceTimeout,
+
+		Audit: runtime.Audit,
+
+		HostHooks: runtime.HostHooks,
+
+		PrivilegedWithoutHostDevices: runtime.PrivilegedWithoutHostDevices,
+
+		StrictOCISpec: runtime.StrictOCISpec,
+
+		EnableAnnotations: runtime.EnableAnnotations,
+
 		Experimental: runtime.Experimental,
 	}
 
 	addAssetAnnotations(ocispec, &sandboxConfig)
+	if err := addAgentAnnotations(ocispec, &sandboxConfig); err != nil {
+		return vc.SandboxConfig{}, err
+	}
+
+	if err := addHypervisorAnnotations(ocispec, &sandboxConfig); err != nil {
+		return vc.SandboxConfig{}, err
+	}
 
 	return sandboxConfig, nil
 }
@@ -538,6 +800,7 @@ func ContainerConfig(ocispec CompatOCISpec, bundlePath, cid, console string, det
 		Console:         console,
 		Detach:          detach,
 		NoNewPrivileges: ocispec.Process.NoNewPrivileges,
+		ApparmorProfile: ocispec.Process.ApparmorProfile,
 	}
 
 	cmd.SupplementaryGroups = []string{}
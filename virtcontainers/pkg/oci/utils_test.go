@@ -556,6 +556,85 @@ func TestContainerTypePodSandbox(t *testing.T) {
 	testContainerTypeSuccessful(t, ociSpec, vc.PodSandbox)
 }
 
+func TestSharePidNs(t *testing.T) {
+	assert := assert.New(t)
+
+	var ociSpec CompatOCISpec
+	assert.False(sharePidNs(ociSpec))
+
+	ociSpec.Linux = &specs.Linux{
+		Namespaces: []specs.LinuxNamespace{
+			{Type: specs.PIDNamespace},
+		},
+	}
+	assert.False(sharePidNs(ociSpec))
+
+	ociSpec.Annotations = map[string]string{
+		annotations.ContainerType: annotations.ContainerTypeSandbox,
+	}
+	assert.True(sharePidNs(ociSpec))
+
+	ociSpec.Linux.Namespaces[0].Path = "/proc/1/ns/pid"
+	assert.False(sharePidNs(ociSpec))
+
+	ociSpec.Linux.Namespaces = nil
+	assert.False(sharePidNs(ociSpec))
+}
+
+func TestSandboxLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	var ociSpec CompatOCISpec
+	assert.Nil(sandboxLabels(ociSpec))
+
+	ociSpec.Annotations = map[string]string{
+		vcAnnotations.SandboxLabelPrefix + "team":   "payments",
+		vcAnnotations.SandboxLabelPrefix + "tenant": "a",
+		vcAnnotations.SandboxLabelPrefix:            "ignored",
+		vcAnnotations.KernelPath:                    "/boot/vmlinuz",
+	}
+	assert.Equal(map[string]string{
+		"team":   "payments",
+		"tenant": "a",
+	}, sandboxLabels(ociSpec))
+}
+
+func TestEnabledAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	var ociSpec CompatOCISpec
+	assert.Nil(enabledAnnotations(ociSpec, nil))
+
+	ociSpec.Annotations = map[string]string{
+		vcAnnotations.DefaultVCPUs:                "2",
+		vcAnnotations.DefaultMaxVCPUs:             "8",
+		vcAnnotations.KernelPath:                  "/boot/vmlinuz",
+		vcAnnotations.SandboxLabelPrefix + "team": "payments",
+		annotations.ContainerType:                 annotations.ContainerTypeSandbox,
+	}
+
+	// only the virtcontainers annotations are filtered, the guest asset
+	// ones are enabled by default
+	assert.Equal(map[string]string{
+		vcAnnotations.KernelPath:  "/boot/vmlinuz",
+		annotations.ContainerType: annotations.ContainerTypeSandbox,
+	}, enabledAnnotations(ociSpec, nil))
+
+	assert.Equal(map[string]string{
+		annotations.ContainerType: annotations.ContainerTypeSandbox,
+	}, enabledAnnotations(ociSpec, []string{}))
+
+	// the whole name has to match
+	assert.Equal(map[string]string{
+		vcAnnotations.DefaultVCPUs:                "2",
+		vcAnnotations.SandboxLabelPrefix + "team": "payments",
+		annotations.ContainerType:                 annotations.ContainerTypeSandbox,
+	}, enabledAnnotations(ociSpec, []string{"DefaultVCPUs", `label\..*`, "Kernel"}))
+
+	// the spec is left untouched
+	assert.Len(ociSpec.Annotations, 5)
+}
+
 func TestContainerTypePodContainer(t *testing.T) {
 	var ociSpec CompatOCISpec
 
@@ -839,6 +918,147 @@ func TestGetShmSizeBindMounted(t *testing.T) {
 	assert.Equal(t, shmSize, uint64(size))
 }
 
+func TestAddAgentDebugAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		HypervisorConfig: vc.HypervisorConfig{UseVSock: true},
+		AgentConfig:      vc.KataAgentConfig{},
+	}
+
+	ocispec := CompatOCISpec{}
+	ocispec.Annotations = map[string]string{
+		vcAnnotations.AgentDebug: "true",
+	}
+
+	assert.NoError(addAgentAnnotations(ocispec, &config))
+	assert.True(config.AgentConfig.(vc.KataAgentConfig).Debug)
+	assert.Equal([]vc.Param{{Key: "agent.log", Value: "debug"}}, config.HypervisorConfig.KernelParams)
+	assert.Equal(uint32(1025), config.HypervisorConfig.VSockLogPort)
+
+	config.AgentConfig = vc.KataAgentConfig{}
+	config.HypervisorConfig = vc.HypervisorConfig{}
+	ocispec.Annotations[vcAnnotations.AgentDebug] = "false"
+	assert.NoError(addAgentAnnotations(ocispec, &config))
+	assert.False(config.AgentConfig.(vc.KataAgentConfig).Debug)
+	assert.Empty(config.HypervisorConfig.KernelParams)
+
+	ocispec.Annotations[vcAnnotations.AgentDebug] = "verbose"
+	assert.Error(addAgentAnnotations(ocispec, &config))
+
+	// non kata agents are ignored
+	config.AgentConfig = vc.HyperConfig{}
+	assert.NoError(addAgentAnnotations(ocispec, &config))
+	assert.Equal(vc.HyperConfig{}, config.AgentConfig)
+}
+
+func TestAddHypervisorAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		HypervisorConfig: vc.HypervisorConfig{
+			ScratchDiskSize: 512,
+		},
+	}
+
+	ocispec := CompatOCISpec{}
+	ocispec.Annotations = map[string]string{}
+
+	// no annotation, the configured size is kept
+	err := addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(512), config.HypervisorConfig.ScratchDiskSize)
+
+	ocispec.Annotations[vcAnnotations.ScratchDiskSize] = "2048"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(2048), config.HypervisorConfig.ScratchDiskSize)
+
+	ocispec.Annotations[vcAnnotations.ScratchDiskSize] = "2G"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.Error(err)
+	delete(ocispec.Annotations, vcAnnotations.ScratchDiskSize)
+
+	ocispec.Annotations[vcAnnotations.MemSlots] = "64"
+	ocispec.Annotations[vcAnnotations.DefaultMaxMemory] = "65536"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(64), config.HypervisorConfig.MemSlots)
+	assert.Equal(uint32(65536), config.HypervisorConfig.DefaultMaxMemorySize)
+
+	ocispec.Annotations[vcAnnotations.DefaultVCPUs] = "0.25"
+	ocispec.Annotations[vcAnnotations.DefaultMaxVCPUs] = "4"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(1), config.HypervisorConfig.NumVCPUs)
+	assert.Equal(float32(0.25), config.HypervisorConfig.DefaultVCPUs)
+	assert.Equal(uint32(4), config.HypervisorConfig.DefaultMaxVCPUs)
+
+	ocispec.Annotations[vcAnnotations.DefaultVCPUs] = "2"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(2), config.HypervisorConfig.NumVCPUs)
+	assert.Equal(float32(0), config.HypervisorConfig.DefaultVCPUs)
+
+	ocispec.Annotations[vcAnnotations.DefaultVCPUs] = "-1"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.Error(err)
+	delete(ocispec.Annotations, vcAnnotations.DefaultVCPUs)
+
+	ocispec.Annotations[vcAnnotations.GuestBoot] = "initrd"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal("initrd", config.HypervisorConfig.GuestBoot)
+
+	ocispec.Annotations[vcAnnotations.CPUModel] = "Skylake-Server"
+	ocispec.Annotations[vcAnnotations.CPUFeatures] = "-vmx"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal("Skylake-Server", config.HypervisorConfig.CPUModel)
+	assert.Equal("-vmx", config.HypervisorConfig.CPUFeatures)
+
+	// a pod cannot opt in the memory reclaim policy
+	ocispec.Annotations[vcAnnotations.MemoryReclaim] = "true"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.False(config.HypervisorConfig.MemoryReclaim.Enable)
+
+	config.HypervisorConfig.MemoryReclaim.Enable = true
+	ocispec.Annotations[vcAnnotations.MemoryReclaim] = "false"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.False(config.HypervisorConfig.MemoryReclaim.Enable)
+
+	ocispec.Annotations[vcAnnotations.MemoryReclaim] = "no way"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.Error(err)
+}
+
+func TestAdd9pAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{}
+	ocispec := CompatOCISpec{
+		Spec: specs.Spec{
+			Annotations: map[string]string{
+				vcAnnotations.Msize9p:   "524288",
+				vcAnnotations.Cache9p:   "loose",
+				vcAnnotations.Version9p: "9p2000.u",
+			},
+		},
+	}
+
+	err := addHypervisorAnnotations(ocispec, &config)
+	assert.NoError(err)
+	assert.Equal(uint32(524288), config.HypervisorConfig.Msize9p)
+	assert.Equal("loose", config.HypervisorConfig.Cache9p)
+	assert.Equal("9p2000.u", config.HypervisorConfig.Version9p)
+
+	ocispec.Annotations[vcAnnotations.Msize9p] = "big"
+	err = addHypervisorAnnotations(ocispec, &config)
+	assert.Error(err)
+}
+
 func TestMain(m *testing.M) {
 	/* Create temp bundle directory if necessary */
 	err := os.MkdirAll(tempBundlePath, dirMode)
@@ -33,6 +33,13 @@ import (
 // implementation itself will contain as a prefix.
 const mockErrorPrefix = "vcmock forced failure"
 
+// The mock stands in for the whole virtcontainers API.
+var (
+	_ vc.VC          = &VCMock{}
+	_ vc.VCSandbox   = &Sandbox{}
+	_ vc.VCContainer = &Container{}
+)
+
 // SetLogger implements the VC function of the same name.
 func (m *VCMock) SetLogger(ctx context.Context, logger *logrus.Entry) {
 	if m.SetLoggerFunc != nil {
@@ -92,6 +99,15 @@ func (m *VCMock) StopSandbox(ctx context.Context, sandboxID string) (vc.VCSandbo
 	return nil, fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
 }
 
+// CleanupSandbox implements the VC function of the same name.
+func (m *VCMock) CleanupSandbox(ctx context.Context, sandboxID string, force bool) error {
+	if m.CleanupSandboxFunc != nil {
+		return m.CleanupSandboxFunc(ctx, sandboxID, force)
+	}
+
+	return fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
+}
+
 // RunSandbox implements the VC function of the same name.
 func (m *VCMock) RunSandbox(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
 	if m.RunSandboxFunc != nil {
@@ -298,3 +314,12 @@ func (m *VCMock) ListRoutes(ctx context.Context, sandboxID string) ([]*vcTypes.R
 
 	return nil, fmt.Errorf("%s: %s (%+v): sandboxID: %v", mockErrorPrefix, getSelf(), m, sandboxID)
 }
+
+// HypervisorCommandLine implements the VC function of the same name.
+func (m *VCMock) HypervisorCommandLine(ctx context.Context, sandboxConfig vc.SandboxConfig) ([]string, error) {
+	if m.HypervisorCommandLineFunc != nil {
+		return m.HypervisorCommandLineFunc(ctx, sandboxConfig)
+	}
+
+	return nil, fmt.Errorf("%s: %s (%+v): sandboxConfig: %v", mockErrorPrefix, getSelf(), m, sandboxConfig)
+}
@@ -332,6 +332,32 @@ func TestVCMockStatusSandbox(t *testing.T) {
 	assert.True(IsMockError(err))
 }
 
+func TestVCMockCleanupSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &VCMock{}
+	assert.Nil(m.CleanupSandboxFunc)
+
+	ctx := context.Background()
+	err := m.CleanupSandbox(ctx, testSandboxID, true)
+	assert.Error(err)
+	assert.True(IsMockError(err))
+
+	m.CleanupSandboxFunc = func(ctx context.Context, sandboxID string, force bool) error {
+		return nil
+	}
+
+	err = m.CleanupSandbox(ctx, testSandboxID, true)
+	assert.NoError(err)
+
+	// reset
+	m.CleanupSandboxFunc = nil
+
+	err = m.CleanupSandbox(ctx, testSandboxID, false)
+	assert.Error(err)
+	assert.True(IsMockError(err))
+}
+
 func TestVCMockStopSandbox(t *testing.T) {
 	assert := assert.New(t)
 
@@ -143,6 +143,11 @@ func (s *Sandbox) Status() vc.SandboxStatus {
 	return vc.SandboxStatus{}
 }
 
+// Dump implements the VCSandbox function of the same name.
+func (s *Sandbox) Dump() vc.SandboxDump {
+	return vc.SandboxDump{}
+}
+
 // EnterContainer implements the VCSandbox function of the same name.
 func (s *Sandbox) EnterContainer(containerID string, cmd types.Cmd) (vc.VCContainer, *vc.Process, error) {
 	return &Container{}, &vc.Process{}, nil
@@ -153,6 +158,11 @@ func (s *Sandbox) Monitor() (chan error, error) {
 	return nil, nil
 }
 
+// HypervisorExited implements the VCSandbox function of the same name.
+func (s *Sandbox) HypervisorExited() bool {
+	return s.MockHypervisorExited
+}
+
 // UpdateContainer implements the VCSandbox function of the same name.
 func (s *Sandbox) UpdateContainer(containerID string, resources specs.LinuxResources) error {
 	return nil
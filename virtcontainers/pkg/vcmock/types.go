@@ -25,6 +25,8 @@ type Sandbox struct {
 	MockAnnotations map[string]string
 	MockContainers  []*Container
 	MockNetNs       string
+
+	MockHypervisorExited bool
 }
 
 // Container is a fake Container type used for testing
@@ -44,6 +46,7 @@ type VCMock struct {
 	SetLoggerFunc  func(ctx context.Context, logger *logrus.Entry)
 	SetFactoryFunc func(ctx context.Context, factory vc.Factory)
 
+	CleanupSandboxFunc func(ctx context.Context, sandboxID string, force bool) error
 	CreateSandboxFunc  func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error)
 	DeleteSandboxFunc  func(ctx context.Context, sandboxID string) (vc.VCSandbox, error)
 	ListSandboxFunc    func(ctx context.Context) ([]vc.SandboxStatus, error)
@@ -75,4 +78,6 @@ type VCMock struct {
 	ListInterfacesFunc  func(ctx context.Context, sandboxID string) ([]*vcTypes.Interface, error)
 	UpdateRoutesFunc    func(ctx context.Context, sandboxID string, routes []*vcTypes.Route) ([]*vcTypes.Route, error)
 	ListRoutesFunc      func(ctx context.Context, sandboxID string) ([]*vcTypes.Route, error)
+
+	HypervisorCommandLineFunc func(ctx context.Context, sandboxConfig vc.SandboxConfig) ([]string, error)
 }
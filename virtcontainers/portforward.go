@@ -0,0 +1,272 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	vcAnnotations "github.com/kata-containers/runtime/virtcontainers/pkg/annotations"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	portForwardProtocolTCP = "tcp"
+
+	// portForwardDefaultHostIP is the host address the ports are
+	// forwarded from when the entry sets none, only reachable from the
+	// host itself.
+	portForwardDefaultHostIP = "127.0.0.1"
+
+	// portForwardMinHostPort is the lowest host port that can be
+	// forwarded, the privileged ports are left to the host services.
+	portForwardMinHostPort = 1024
+)
+
+var dialPortForward = net.Dial
+
+// portForward is a host port forwarded to a port of the sandbox guest.
+type portForward struct {
+	HostIP    string
+	HostPort  uint16
+	GuestPort uint16
+}
+
+// parsePortForwards parses the value of the port forward annotation, a comma
+// separated list of [hostIP:]hostPort:containerPort[/tcp] entries. The host
+// IP defaults to the loopback address and must not be the unspecified
+// address, the host port must not be a privileged port.
+func parsePortForwards(value string) ([]portForward, error) {
+	var forwards []portForward
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		ports := entry
+		if i := strings.LastIndex(entry, "/"); i >= 0 {
+			if protocol := entry[i+1:]; protocol != portForwardProtocolTCP {
+				return nil, fmt.Errorf("Invalid port forward %q, protocol %q not supported", entry, protocol)
+			}
+			ports = entry[:i]
+		}
+
+		f := portForward{
+			HostIP: portForwardDefaultHostIP,
+		}
+		fields := strings.Split(ports, ":")
+		switch len(fields) {
+		case 2:
+		case 3:
+			ip := net.ParseIP(fields[0])
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid port forward %q, bad host IP", entry)
+			}
+			if ip.IsUnspecified() {
+				return nil, fmt.Errorf("Invalid port forward %q, the host IP must not be the unspecified address", entry)
+			}
+			f.HostIP = fields[0]
+			fields = fields[1:]
+		default:
+			return nil, fmt.Errorf("Invalid port forward %q, expecting [hostIP:]hostPort:containerPort", entry)
+		}
+
+		hostPort, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil || hostPort == 0 {
+			return nil, fmt.Errorf("Invalid port forward %q, bad host port", entry)
+		}
+		if hostPort < portForwardMinHostPort {
+			return nil, fmt.Errorf("Invalid port forward %q, host ports below %d are privileged", entry, portForwardMinHostPort)
+		}
+
+		guestPort, err := strconv.ParseUint(fields[1], 10, 16)
+		if err != nil || guestPort == 0 {
+			return nil, fmt.Errorf("Invalid port forward %q, bad container port", entry)
+		}
+
+		f.HostPort = uint16(hostPort)
+		f.GuestPort = uint16(guestPort)
+		forwards = append(forwards, f)
+	}
+
+	return forwards, nil
+}
+
+// portForwarder proxies the TCP connections accepted on the host ports to
+// the sandbox guest.
+type portForwarder struct {
+	listeners []net.Listener
+	wg        sync.WaitGroup
+	logger    *logrus.Entry
+}
+
+func newPortForwarder(forwards []portForward, guestIP string, logger *logrus.Entry) (*portForwarder, error) {
+	p := &portForwarder{
+		logger: logger,
+	}
+
+	for _, f := range forwards {
+		l, err := net.Listen("tcp", net.JoinHostPort(f.HostIP, strconv.Itoa(int(f.HostPort))))
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+
+		target := net.JoinHostPort(guestIP, strconv.Itoa(int(f.GuestPort)))
+		p.listeners = append(p.listeners, l)
+
+		p.wg.Add(1)
+		go p.serve(l, target)
+	}
+
+	return p, nil
+}
+
+func (p *portForwarder) serve(l net.Listener, target string) {
+	defer p.wg.Done()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			// The listener is closed when the forwarder stops.
+			return
+		}
+
+		go p.forward(conn, target)
+	}
+}
+
+func (p *portForwarder) forward(conn net.Conn, target string) {
+	defer conn.Close()
+
+	guest, err := dialPortForward("tcp", target)
+	if err != nil {
+		p.logger.WithError(err).WithField("target", target).Warn("Could not forward connection")
+		return
+	}
+	defer guest.Close()
+
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		io.Copy(dst, src)
+		if c, ok := dst.(*net.TCPConn); ok {
+			c.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+
+	go copyConn(guest, conn)
+	go copyConn(conn, guest)
+
+	<-done
+	<-done
+}
+
+// stop closes the host ports. The forwarded connections are left to end
+// by themselves.
+func (p *portForwarder) stop() {
+	if p == nil {
+		return
+	}
+
+	for _, l := range p.listeners {
+		l.Close()
+	}
+
+	p.wg.Wait()
+}
+
+// guestIP returns the address of the sandbox network that the forwarded
+// ports are proxied to: the first IPv4 address, or the first global IPv6
+// address of an IPv6 only network.
+func (s *Sandbox) guestIP() (string, error) {
+	var ipv6 net.IP
+
+	for _, endpoint := range s.networkNS.Endpoints {
+		for _, addr := range endpoint.Properties().Addrs {
+			if addr.IPNet == nil || !addr.IP.IsGlobalUnicast() {
+				continue
+			}
+
+			if addr.IP.To4() != nil {
+				return addr.IP.String(), nil
+			}
+
+			if ipv6 == nil {
+				ipv6 = addr.IP
+			}
+		}
+	}
+
+	if ipv6 != nil {
+		return ipv6.String(), nil
+	}
+
+	return "", fmt.Errorf("No IP address found for sandbox %s", s.id)
+}
+
+// containerPortForwards returns the host ports to forward to the container,
+// from its OCI spec annotation.
+func (c *Container) containerPortForwards() ([]portForward, error) {
+	config, ok := c.config.Annotations[vcAnnotations.ConfigJSONKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var spec specs.Spec
+	if err := json.Unmarshal([]byte(config), &spec); err != nil {
+		return nil, err
+	}
+
+	value, ok := c.sandbox.containerAnnotation(&spec, vcAnnotations.PortForward)
+	if !ok {
+		return nil, nil
+	}
+
+	return parsePortForwards(value)
+}
+
+// startPortForwards starts forwarding the host ports requested for the
+// container to the sandbox guest. The guest address is reached from the host
+// network, eg. through the CNI bridge the pod network is plugged on.
+func (c *Container) startPortForwards() error {
+	forwards, err := c.containerPortForwards()
+	if err != nil || len(forwards) == 0 {
+		return err
+	}
+
+	// The forwarder lives in the runtime process.
+	if !c.sandbox.config.Stateful {
+		return fmt.Errorf("Port forwarding requires a long running runtime, eg. the containerd shim v2")
+	}
+
+	guestIP, err := c.sandbox.guestIP()
+	if err != nil {
+		return err
+	}
+
+	c.portForwarder, err = newPortForwarder(forwards, guestIP, c.Logger())
+	if err != nil {
+		return err
+	}
+
+	c.Logger().WithField("guest-ip", guestIP).Infof("Forwarding %d host ports", len(forwards))
+
+	return nil
+}
+
+func (c *Container) stopPortForwards() {
+	c.portForwarder.stop()
+	c.portForwarder = nil
+}
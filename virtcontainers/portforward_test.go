@@ -0,0 +1,165 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	vcAnnotations "github.com/kata-containers/runtime/virtcontainers/pkg/annotations"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func TestParsePortForwards(t *testing.T) {
+	assert := assert.New(t)
+
+	forwards, err := parsePortForwards("8080:80, 10.0.0.1:2222:22/tcp,")
+	assert.NoError(err)
+	assert.Equal([]portForward{
+		{HostIP: "127.0.0.1", HostPort: 8080, GuestPort: 80},
+		{HostIP: "10.0.0.1", HostPort: 2222, GuestPort: 22},
+	}, forwards)
+
+	for _, value := range []string{"80", "8080:80/udp", "0:80", "8080:70000", "host:8080:80", "1:2:3:4", "a:80",
+		"80:80", "127.0.0.1:1023:22", "0.0.0.0:8080:80"} {
+		_, err := parsePortForwards(value)
+		assert.Error(err, value)
+	}
+}
+
+func TestPortForwarder(t *testing.T) {
+	assert := assert.New(t)
+
+	// The guest echoes the first line received.
+	guest, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer guest.Close()
+
+	go func() {
+		conn, err := guest.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		fmt.Fprint(conn, line)
+	}()
+
+	guestPort := guest.Addr().(*net.TCPAddr).Port
+
+	// Reserve a free host port.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	hostPort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	p, err := newPortForwarder([]portForward{
+		{HostIP: "127.0.0.1", HostPort: uint16(hostPort), GuestPort: uint16(guestPort)},
+	}, "127.0.0.1", virtLog)
+	assert.NoError(err)
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", hostPort))
+	assert.NoError(err)
+	defer conn.Close()
+
+	fmt.Fprint(conn, "ping\n")
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NoError(err)
+	assert.Equal("ping\n", line)
+
+	p.stop()
+
+	_, err = net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", hostPort))
+	assert.Error(err)
+}
+
+func TestStartPortForwards(t *testing.T) {
+	assert := assert.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	hostPort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	spec := specs.Spec{
+		Annotations: map[string]string{
+			vcAnnotations.PortForward: fmt.Sprintf("127.0.0.1:%d:80", hostPort),
+		},
+	}
+	specJSON, err := json.Marshal(spec)
+	assert.NoError(err)
+
+	c := &Container{
+		config: &ContainerConfig{
+			Annotations: map[string]string{
+				vcAnnotations.ConfigJSONKey: string(specJSON),
+			},
+		},
+		sandbox: &Sandbox{
+			id:     "sandbox",
+			config: &SandboxConfig{},
+		},
+	}
+
+	// The annotation is ignored unless enabled.
+	err = c.startPortForwards()
+	assert.NoError(err)
+	assert.Nil(c.portForwarder)
+
+	c.sandbox.config.EnableAnnotations = []string{"PortForward"}
+
+	// Never forwarded by a runtime exiting right away.
+	err = c.startPortForwards()
+	assert.Error(err)
+
+	// No sandbox network
+	c.sandbox.config.Stateful = true
+	err = c.startPortForwards()
+	assert.Error(err)
+
+	c.sandbox.networkNS.Endpoints = []Endpoint{
+		&VethEndpoint{
+			EndpointProperties: NetworkInfo{
+				Addrs: []netlink.Addr{
+					{IPNet: &net.IPNet{IP: net.ParseIP("10.0.0.2"), Mask: net.CIDRMask(24, 32)}},
+				},
+			},
+		},
+	}
+
+	ip, err := c.sandbox.guestIP()
+	assert.NoError(err)
+	assert.Equal("10.0.0.2", ip)
+
+	// IPv6 only network
+	ipv6Sandbox := &Sandbox{}
+	ipv6Sandbox.networkNS.Endpoints = []Endpoint{
+		&VethEndpoint{
+			EndpointProperties: NetworkInfo{
+				Addrs: []netlink.Addr{
+					{IPNet: &net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)}},
+					{IPNet: &net.IPNet{IP: net.ParseIP("fd00::2"), Mask: net.CIDRMask(64, 128)}},
+				},
+			},
+		},
+	}
+	ip, err = ipv6Sandbox.guestIP()
+	assert.NoError(err)
+	assert.Equal("fd00::2", ip)
+
+	err = c.startPortForwards()
+	assert.NoError(err)
+	assert.NotNil(c.portForwarder)
+
+	c.stopPortForwards()
+	assert.Nil(c.portForwarder)
+}
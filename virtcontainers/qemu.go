@@ -14,6 +14,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -48,6 +50,9 @@ type qmpChannel struct {
 type CPUDevice struct {
 	// ID is used to identify this CPU in the hypervisor options.
 	ID string
+
+	// Owner is the ID of the container this CPU is dedicated to, if any.
+	Owner string `json:",omitempty"`
 }
 
 // QemuState keeps Qemu's state
@@ -83,25 +88,57 @@ type qemu struct {
 	ctx context.Context
 
 	nvdimmCount int
+
+	// accelerator is the accelerator the VM runs with.
+	accelerator string
+
+	// balloonStatsPolled is set once the balloon driver was asked to
+	// report the guest memory stats, see guestMemAvailable.
+	balloonStatsPolled bool
 }
 
 const (
-	consoleSocket = "console.sock"
-	qmpSocket     = "qmp.sock"
+	consoleSocket    = "console.sock"
+	qmpSocket        = "qmp.sock"
+	firmwareVarsFile = "efivars.fd"
 
 	qmpCapErrMsg                      = "Failed to negoatiate QMP capabilities"
 	qmpCapMigrationBypassSharedMemory = "bypass-shared-memory"
 	qmpExecCatCmd                     = "exec:cat"
 	qmpMigrationWaitTimeout           = 5 * time.Second
 
-	scsiControllerID  = "scsi0"
-	rngID             = "rng0"
-	vsockKernelOption = "agent.use_vsock"
+	// qomPeripheralPath prefixes the QOM path of the devices added with an
+	// ID, eg. the hotplugged vCPUs.
+	qomPeripheralPath = "/machine/peripheral/"
+
+	scsiControllerID   = "scsi0"
+	rngID              = "rng0"
+	balloonID          = "balloon0"
+	vsockKernelOption  = "agent.use_vsock"
+	ptpKVMKernelOption = "agent.ptp_kvm"
+
+	// The agent waits longer for the devices behind the virtio-iommu,
+	// slower to be probed by the guest.
+	hotplugTimeoutKernelOption = "agent.hotplug_timeout"
+	iommuHotplugTimeout        = "10s"
 )
 
 var qemuMajorVersion int
 var qemuMinorVersion int
 
+const (
+	kvmAccelerator = "kvm"
+	hvfAccelerator = "hvf"
+	tcgAccelerator = "tcg"
+
+	// tcgCPUModel is the CPU model exposing all the features TCG
+	// emulates, the host CPU model requiring hardware virtualization.
+	tcgCPUModel = "max"
+)
+
+// kvmDevice is opened to check that KVM can be used.
+var kvmDevice = "/dev/kvm"
+
 // agnostic list of kernel parameters
 var defaultKernelParameters = []Param{
 	{"panic", "1"},
@@ -157,6 +194,23 @@ func (q *qemu) kernelParameters() string {
 	// This will be consumed by the agent to determine if it needs to listen on
 	// a serial or vsock channel
 	params = append(params, Param{vsockKernelOption, strconv.FormatBool(q.config.UseVSock)})
+	if q.config.UseVSock {
+		params = append(params, q.config.vsockKernelParams()...)
+	}
+
+	// The agent loads the ptp_kvm module and uses the resulting PTP
+	// device as the reference clock of the guest time daemon.
+	if q.config.EnablePTPKVM {
+		params = append(params, Param{ptpKVMKernelOption, "true"})
+	}
+
+	if q.config.KdumpCrashKernelSize > 0 {
+		params = append(params, kdumpKernelParams(q.config)...)
+	}
+
+	if q.config.EnableIOMMU {
+		params = append(params, Param{hotplugTimeoutKernelOption, iommuHotplugTimeout})
+	}
 
 	// add the params specified by the provided config. As the kernel
 	// honours the last parameter value set and since the config-provided
@@ -287,7 +341,84 @@ func (q *qemu) setup(id string, hypervisorConfig *HypervisorConfig, vcStore *sto
 }
 
 func (q *qemu) cpuTopology() govmmQemu.SMP {
-	return q.arch.cpuTopology(q.config.NumVCPUs, q.config.DefaultMaxVCPUs)
+	smp := q.arch.cpuTopology(q.config.NumVCPUs, q.config.DefaultMaxVCPUs)
+
+	cores := q.config.CPUCores
+	threads := q.config.CPUThreads
+	if q.config.MirrorHostSMT {
+		hostThreads, err := getHostThreadsPerCore(sysThreadSiblings)
+		if err != nil {
+			q.Logger().WithError(err).Warn("Unable to get the host threads per core")
+		} else {
+			threads = hostThreads
+		}
+	}
+
+	if cores == 0 && threads == 0 {
+		if q.arch.largeGuest(smp.MaxCPUs) {
+			// one vCPU per socket, so that the APIC IDs of all the
+			// vCPUs, hotplugged ones included, are contiguous
+			return explicitCPUTopology(smp.CPUs, smp.MaxCPUs, 1, 1)
+		}
+		return smp
+	}
+
+	return explicitCPUTopology(smp.CPUs, smp.MaxCPUs, cores, threads)
+}
+
+// explicitCPUTopology lays the vCPUs out in sockets of the given number of
+// cores and threads. The maximum number of vCPUs is rounded down to whole
+// sockets, still holding all the boot vCPUs.
+func explicitCPUTopology(vcpus, maxvcpus, cores, threads uint32) govmmQemu.SMP {
+	if cores == 0 {
+		cores = defaultCores
+	}
+
+	if threads == 0 {
+		threads = defaultThreads
+	}
+
+	perSocket := cores * threads
+	sockets := maxvcpus / perSocket
+	if sockets*perSocket < vcpus {
+		sockets = (vcpus + perSocket - 1) / perSocket
+	}
+
+	return govmmQemu.SMP{
+		CPUs:    vcpus,
+		Sockets: sockets,
+		Cores:   cores,
+		Threads: threads,
+		MaxCPUs: sockets * perSocket,
+	}
+}
+
+// cpuModel returns the guest CPU model, made of the configured model and
+// features on top of the architecture specific options.
+func (q *qemu) cpuModel() string {
+	cpuModel := q.arch.cpuModel()
+
+	if q.accelerator == tcgAccelerator && strings.HasPrefix(cpuModel, defaultCPUModel) {
+		cpuModel = tcgCPUModel + strings.TrimPrefix(cpuModel, defaultCPUModel)
+	}
+
+	if q.config.CPUModel != "" {
+		var options string
+		if i := strings.Index(cpuModel, ","); i >= 0 {
+			options = cpuModel[i:]
+		}
+		cpuModel = q.config.CPUModel + options
+	}
+
+	if q.config.DisablePMU && !strings.Contains(cpuModel, ",pmu=off") {
+		cpuModel += ",pmu=off"
+	}
+
+	if q.config.CPUFeatures != "" {
+		cpuModel += "," + q.config.CPUFeatures
+	}
+
+	return cpuModel
 }
 
 func (q *qemu) hostMemMB() (uint64, error) {
@@ -302,27 +433,100 @@ func (q *qemu) hostMemMB() (uint64, error) {
 	return hostMemKb / 1024, nil
 }
 
-func (q *qemu) memoryTopology() (govmmQemu.Memory, error) {
+// maxMemMB returns the maximum amount of memory the VM can reach through
+// memory hotplug, which never exceeds the host memory.
+func (q *qemu) maxMemMB() (uint64, error) {
 	hostMemMb, err := q.hostMemMB()
+	if err != nil {
+		return 0, err
+	}
+
+	maxMemMb := uint64(q.config.DefaultMaxMemorySize)
+	if maxMemMb == 0 {
+		return hostMemMb, nil
+	}
+
+	if maxMemMb > hostMemMb {
+		q.Logger().WithFields(logrus.Fields{
+			"max-memory":  maxMemMb,
+			"host-memory": hostMemMb,
+		}).Warn("Maximum memory size exceeds the host memory, using the host memory size")
+		return hostMemMb, nil
+	}
+
+	return maxMemMb, nil
+}
+
+func (q *qemu) memoryTopology() (govmmQemu.Memory, error) {
+	maxMemMb, err := q.maxMemMB()
 	if err != nil {
 		return govmmQemu.Memory{}, err
 	}
 
 	memMb := uint64(q.config.MemorySize)
 
-	return q.arch.memoryTopology(memMb, hostMemMb, uint8(q.config.MemSlots)), nil
+	return q.arch.memoryTopology(memMb, maxMemMb, uint8(q.config.MemSlots)), nil
 }
 
 func (q *qemu) qmpSocketPath(id string) (string, error) {
 	return utils.BuildSocketPath(store.RunVMStoragePath, id, qmpSocket)
 }
 
+// acceleratorAvailable returns true if the accelerator can be used on this
+// host.
+func acceleratorAvailable(accelerator string) bool {
+	switch accelerator {
+	case kvmAccelerator:
+		f, err := os.OpenFile(kvmDevice, os.O_RDWR, 0)
+		if err != nil {
+			return false
+		}
+		f.Close()
+		return true
+	case hvfAccelerator:
+		return runtime.GOOS == "darwin"
+	case tcgAccelerator:
+		return true
+	}
+
+	return false
+}
+
+// selectAccelerator returns the configured accelerator, or TCG if it is
+// not available and the fallback to TCG has been enabled. Without any
+// accelerator configuration, the default machine options are kept as is.
+func (q *qemu) selectAccelerator() (string, error) {
+	accelerator := q.config.Accelerator
+	if accelerator == "" {
+		if !q.config.AcceleratorTCGFallback {
+			return "", nil
+		}
+		accelerator = kvmAccelerator
+	}
+
+	if acceleratorAvailable(accelerator) {
+		return accelerator, nil
+	}
+
+	if !q.config.AcceleratorTCGFallback {
+		return "", fmt.Errorf("Accelerator %s is not available", accelerator)
+	}
+
+	q.Logger().WithField("accelerator", accelerator).Warn("Accelerator not available, falling back to TCG")
+
+	return tcgAccelerator, nil
+}
+
 func (q *qemu) getQemuMachine() (govmmQemu.Machine, error) {
 	machine, err := q.arch.machine()
 	if err != nil {
 		return govmmQemu.Machine{}, err
 	}
 
+	if q.accelerator != "" && q.accelerator != kvmAccelerator {
+		machine.Options = strings.Replace(machine.Options, "accel="+kvmAccelerator, "accel="+q.accelerator, 1)
+	}
+
 	accelerators := q.config.MachineAccelerators
 	if accelerators != "" {
 		if !strings.HasPrefix(accelerators, ",") {
@@ -331,9 +535,69 @@ func (q *qemu) getQemuMachine() (govmmQemu.Machine, error) {
 		machine.Options += accelerators
 	}
 
+	if chip := q.config.KernelIRQChip; chip != "" {
+		if chip == "split" && machine.Type != QemuPC && machine.Type != QemuQ35 {
+			return govmmQemu.Machine{}, fmt.Errorf("Split irqchip is not supported by the %s machine", machine.Type)
+		}
+		machine.Options = setMachineOption(machine.Options, "kernel_irqchip", chip)
+	}
+
+	if q.arch.largeGuest(q.config.DefaultMaxVCPUs) {
+		if max := MaxQemuMachineVCPUs(machine.Type); q.config.DefaultMaxVCPUs > max {
+			return govmmQemu.Machine{}, fmt.Errorf("The %s machine supports up to %d vCPUs, %d requested",
+				machine.Type, max, q.config.DefaultMaxVCPUs)
+		}
+
+		// The remapped interrupts are delivered by the IOAPIC emulated
+		// in userspace.
+		if chip := q.config.KernelIRQChip; chip != "" && chip != "split" {
+			return govmmQemu.Machine{}, fmt.Errorf("%d vCPUs need a split irqchip, kernel_irqchip is %q",
+				q.config.DefaultMaxVCPUs, chip)
+		}
+		machine.Options = setMachineOption(machine.Options, "kernel_irqchip", "split")
+	}
+
+	if q.config.SecureBoot {
+		switch machine.Type {
+		case QemuQ35:
+			// The firmware keeps the variable store out of the reach
+			// of the guest OS by only writing it in SMM.
+			machine.Options = setMachineOption(machine.Options, "smm", "on")
+		case QemuVirt:
+		default:
+			return govmmQemu.Machine{}, fmt.Errorf("Secure Boot is not supported by the %s machine", machine.Type)
+		}
+	}
+
+	if q.config.DisableVMPort {
+		if machine.Type != QemuPC && machine.Type != QemuQ35 {
+			return govmmQemu.Machine{}, fmt.Errorf("vmport is not supported by the %s machine", machine.Type)
+		}
+		machine.Options = setMachineOption(machine.Options, "vmport", "off")
+	}
+
 	return machine, nil
 }
 
+// setMachineOption sets key to value in a comma-separated list of machine
+// options, replacing any previous setting of key. QEMU accepts both dashes
+// and underscores in the option names.
+func setMachineOption(options, key, value string) string {
+	normalize := func(s string) string {
+		return strings.Replace(s, "-", "_", -1)
+	}
+
+	var opts []string
+	for _, o := range strings.Split(options, ",") {
+		if o == "" || normalize(strings.SplitN(o, "=", 2)[0]) == normalize(key) {
+			continue
+		}
+		opts = append(opts, o)
+	}
+
+	return strings.Join(append(opts, key+"="+value), ",")
+}
+
 func (q *qemu) appendImage(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
 	imagePath, err := q.config.ImageAssetPath()
 	if err != nil {
@@ -350,6 +614,50 @@ func (q *qemu) appendImage(devices []govmmQemu.Device) ([]govmmQemu.Device, erro
 	return devices, nil
 }
 
+// appendFirmwareFlash appends to devices the pflash devices of the UEFI
+// firmware code and of its variable store.
+func (q *qemu) appendFirmwareFlash(devices []govmmQemu.Device, machine govmmQemu.Machine, code string) ([]govmmQemu.Device, error) {
+	if code == "" {
+		return nil, fmt.Errorf("The firmware variable store %s needs a firmware", q.config.FirmwareVarsPath)
+	}
+
+	devices = append(devices,
+		pflash{Unit: 0, Path: code, ReadOnly: true},
+		pflash{Unit: 1, Path: q.firmwareVars(), ReadOnly: q.config.FirmwareVarsReadOnly})
+
+	if q.config.SecureBoot && machine.Type == QemuQ35 {
+		devices = append(devices, qemuGlobal("cfi.pflash01.secure=on"))
+	}
+
+	return devices, nil
+}
+
+// firmwareVars returns the UEFI variable store of the VM. Unless it is
+// read-only, it is a copy of the template in the VM directory, which keeps
+// the variables the guest writes until the VM is cleaned up.
+func (q *qemu) firmwareVars() string {
+	if q.config.FirmwareVarsReadOnly {
+		return q.config.FirmwareVarsPath
+	}
+
+	return filepath.Join(store.RunVMStoragePath, q.id, firmwareVarsFile)
+}
+
+// copyFirmwareVars copies the UEFI variable store template to the VM
+// directory. The copy of a factory VM follows the VM directory when it is
+// linked to the sandbox.
+func (q *qemu) copyFirmwareVars() error {
+	if q.config.FirmwareVarsPath == "" || q.config.FirmwareVarsReadOnly {
+		return nil
+	}
+
+	if err := copyFile(q.config.FirmwareVarsPath, q.firmwareVars()); err != nil {
+		return fmt.Errorf("Could not copy the firmware variable store %s: %v", q.config.FirmwareVarsPath, err)
+	}
+
+	return nil
+}
+
 func (q *qemu) createQmpSocket() ([]govmmQemu.QMPSocket, error) {
 	monitorSockPath, err := q.qmpSocketPath(q.id)
 	if err != nil {
@@ -361,14 +669,30 @@ func (q *qemu) createQmpSocket() ([]govmmQemu.QMPSocket, error) {
 		path: monitorSockPath,
 	}
 
-	return []govmmQemu.QMPSocket{
+	sockets := []govmmQemu.QMPSocket{
 		{
 			Type:   "unix",
 			Name:   q.qmpMonitorCh.path,
 			Server: true,
 			NoWait: true,
 		},
-	}, nil
+	}
+
+	if q.config.MemoryReclaim.Enable {
+		statsSockPath, err := q.qmpStatsSocketPath(q.id)
+		if err != nil {
+			return nil, err
+		}
+
+		sockets = append(sockets, govmmQemu.QMPSocket{
+			Type:   "unix",
+			Name:   statsSockPath,
+			Server: true,
+			NoWait: true,
+		})
+	}
+
+	return sockets, nil
 }
 
 func (q *qemu) buildDevices(initrdPath string) ([]govmmQemu.Device, *govmmQemu.IOThread, error) {
@@ -379,10 +703,34 @@ func (q *qemu) buildDevices(initrdPath string) ([]govmmQemu.Device, *govmmQemu.I
 		return nil, nil, err
 	}
 
+	if q.arch.largeGuest(q.config.DefaultMaxVCPUs) {
+		devices, err = q.arch.appendIOMMU(devices)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Add bridges before any other devices. This way we make sure that
 	// bridge gets the first available PCI address i.e bridgePCIStartAddr
 	devices = q.arch.appendBridges(devices, q.state.Bridges)
 
+	devices, err = q.arch.appendPCIHotplug(devices, q.config.PCIHotplug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if q.config.EnableIOMMU {
+		if q.arch.largeGuest(q.config.DefaultMaxVCPUs) {
+			return nil, nil, fmt.Errorf("virtio-iommu is not supported with %d vCPUs, which need interrupt remapping",
+				q.config.DefaultMaxVCPUs)
+		}
+
+		devices, err = q.arch.appendVirtioIOMMU(devices)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	devices = q.arch.appendConsole(devices, console)
 
 	if initrdPath == "" {
@@ -433,6 +781,12 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 		return err
 	}
 
+	accelerator, err := q.selectAccelerator()
+	if err != nil {
+		return err
+	}
+	q.accelerator = accelerator
+
 	machine, err := q.getQemuMachine()
 	if err != nil {
 		return err
@@ -461,6 +815,10 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 		return err
 	}
 
+	if err := checkKernelPCIHotplug(kernelPath, q.config.PCIHotplug); err != nil {
+		return err
+	}
+
 	initrdPath, err := q.config.InitrdAssetPath()
 	if err != nil {
 		return err
@@ -474,6 +832,14 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 
 	incoming := q.setupTemplate(&knobs, &memory)
 
+	// The vhost user backends access the guest memory directly, huge
+	// pages are always shared.
+	if q.config.usesVhostUser() && !q.config.HugePages {
+		knobs.FileBackedMem = true
+		knobs.FileBackedMemShared = true
+		memory.Path = vhostUserSharedMemoryPath
+	}
+
 	rtc := govmmQemu.RTC{
 		Base:     "utc",
 		DriftFix: "slew",
@@ -493,13 +859,21 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 		return err
 	}
 
-	cpuModel := q.arch.cpuModel()
+	cpuModel := q.cpuModel()
 
 	firmwarePath, err := q.config.FirmwareAssetPath()
 	if err != nil {
 		return err
 	}
 
+	if q.config.FirmwareVarsPath != "" {
+		devices, err = q.appendFirmwareFlash(devices, machine, firmwarePath)
+		if err != nil {
+			return err
+		}
+		firmwarePath = ""
+	}
+
 	qemuPath, err := q.qemuPath()
 	if err != nil {
 		return err
@@ -528,6 +902,11 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 		PidFile:     pidFile,
 	}
 
+	if q.accelerator != "" && q.accelerator != kvmAccelerator {
+		// the PIT is emulated by QEMU, not KVM
+		qemuConfig.GlobalParam = ""
+	}
+
 	if ioThread != nil {
 		qemuConfig.IOThreads = []govmmQemu.IOThread{*ioThread}
 	}
@@ -538,11 +917,55 @@ func (q *qemu) createSandbox(ctx context.Context, id string, hypervisorConfig *H
 	}
 	qemuConfig.Devices = q.arch.appendRNGDevice(qemuConfig.Devices, rngDev)
 
+	if q.config.MemoryReclaim.Enable {
+		qemuConfig.Devices = q.arch.appendBalloonDevice(qemuConfig.Devices, balloonID)
+	}
+
 	q.qemuConfig = qemuConfig
 
 	return nil
 }
 
+// qemuParamsLogger catches the parameters govmm logs right before it
+// executes qemu.
+type qemuParamsLogger struct {
+	qmpLogger
+	params []string
+}
+
+func (l *qemuParamsLogger) Infof(format string, v ...interface{}) {
+	if strings.HasPrefix(format, "launching ") && len(v) == 2 {
+		if params, ok := v[1].([]string); ok {
+			l.params = params
+		}
+	}
+}
+
+func (l *qemuParamsLogger) Errorf(format string, v ...interface{}) {
+}
+
+// commandLine returns the command line the VM is started with. It goes
+// through the same govmm launch path as startSandbox, with a canceled
+// context so that qemu is not executed.
+func (q *qemu) commandLine() ([]string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	qemuConfig := q.qemuConfig
+	qemuConfig.Ctx = ctx
+
+	logger := &qemuParamsLogger{qmpLogger: newQMPLogger()}
+	if _, err := govmmQemu.LaunchQemu(qemuConfig, logger); err != nil && err != context.Canceled {
+		return nil, err
+	}
+
+	if logger.params == nil {
+		return nil, errors.New("Could not get the qemu command line")
+	}
+
+	return append([]string{qemuConfig.Path}, logger.params...), nil
+}
+
 // startSandbox will start the Sandbox's VM.
 func (q *qemu) startSandbox(timeout int) error {
 	span, _ := q.trace("startSandbox")
@@ -582,6 +1005,10 @@ func (q *qemu) startSandbox(timeout int) error {
 		}
 	}()
 
+	if err = q.copyFirmwareVars(); err != nil {
+		return err
+	}
+
 	var strErr string
 	strErr, err = govmmQemu.LaunchQemu(q.qemuConfig, newQMPLogger())
 	if err != nil {
@@ -715,6 +1142,21 @@ func (q *qemu) togglePauseSandbox(pause bool) error {
 	return nil
 }
 
+func (q *qemu) guestMemory() uint32 {
+	return q.config.MemorySize + uint32(q.state.HotpluggedMemory)
+}
+
+func (q *qemu) setBalloonTarget(targetMB uint32) error {
+	span, _ := q.trace("setBalloonTarget")
+	defer span.Finish()
+
+	if err := q.qmpSetup(); err != nil {
+		return err
+	}
+
+	return q.qmpMonitorCh.qmp.ExecuteBalloon(q.qmpMonitorCh.ctx, uint64(targetMB)<<utils.MibToBytesShift)
+}
+
 func (q *qemu) qmpSetup() error {
 	if q.qmpMonitorCh.qmp != nil {
 		return nil
@@ -807,6 +1249,12 @@ func (q *qemu) hotplugAddBlockDevice(drive *config.BlockDrive, op operation, dev
 		return nil
 	}
 
+	// The drives are added as raw, other formats would follow the
+	// backing file their image names.
+	if drive.Format != "" && drive.Format != diskImageRaw {
+		return fmt.Errorf("Cannot hotplug the %s drive %s, only raw drives are supported", drive.Format, drive.File)
+	}
+
 	if q.config.BlockDeviceCacheSet {
 		err = q.qmpMonitorCh.qmp.ExecuteBlockdevAddWithCache(q.qmpMonitorCh.ctx, drive.File, drive.ID, q.config.BlockDeviceCacheDirect, q.config.BlockDeviceCacheNoflush)
 	} else {
@@ -1110,7 +1558,7 @@ func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
 
 		// CPU type, i.e host-x86_64-cpu
 		driver := hc.Type
-		cpuID := fmt.Sprintf("cpu-%d", len(q.state.HotpluggedVCPUs))
+		cpuID := q.newCPUIDs(1)[0]
 		socketID := fmt.Sprintf("%d", hc.Properties.Socket)
 		coreID := fmt.Sprintf("%d", hc.Properties.Core)
 		threadID := fmt.Sprintf("%d", hc.Properties.Thread)
@@ -1123,18 +1571,23 @@ func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
 
 		if err := q.qmpMonitorCh.qmp.ExecuteCPUDeviceAdd(q.qmpMonitorCh.ctx, driver, cpuID, socketID, coreID, threadID, romFile); err != nil {
 			// don't fail, let's try with other CPU
+			q.Logger().WithError(err).WithField("cpu", cpuID).Debug("failed to hot add vCPU")
 			continue
 		}
 
 		// a new vCPU was added, update list of hotplugged vCPUs and check if all vCPUs were added
-		q.state.HotpluggedVCPUs = append(q.state.HotpluggedVCPUs, CPUDevice{cpuID})
+		q.state.HotpluggedVCPUs = append(q.state.HotpluggedVCPUs, CPUDevice{ID: cpuID})
 		hotpluggedVCPUs++
 		if hotpluggedVCPUs == amount {
-			// All vCPUs were hotplugged
-			return amount, q.store.Store(store.Hypervisor, q.state)
+			break
 		}
 	}
 
+	if hotpluggedVCPUs == amount {
+		// All vCPUs were hotplugged
+		return amount, q.store.Store(store.Hypervisor, q.state)
+	}
+
 	// All vCPUs were NOT hotplugged
 	if err := q.store.Store(store.Hypervisor, q.state); err != nil {
 		q.Logger().Errorf("failed to save hypervisor state after hotplug %d vCPUs: %v", hotpluggedVCPUs, err)
@@ -1143,30 +1596,160 @@ func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
 	return hotpluggedVCPUs, fmt.Errorf("failed to hot add vCPUs: only %d vCPUs of %d were added", hotpluggedVCPUs, amount)
 }
 
+// newCPUIDs returns number vCPU IDs not used by the hotplugged vCPUs.
+func (q *qemu) newCPUIDs(number int) []string {
+	used := make(map[string]bool)
+	for _, cpu := range q.state.HotpluggedVCPUs {
+		used[cpu.ID] = true
+	}
+
+	var ids []string
+	for i := 0; len(ids) < number; i++ {
+		if id := fmt.Sprintf("cpu-%d", i); !used[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
 // try to  hot remove an amount of vCPUs, returns the number of vCPUs removed
 func (q *qemu) hotplugRemoveCPUs(amount uint32) (uint32, error) {
-	hotpluggedVCPUs := uint32(len(q.state.HotpluggedVCPUs))
+	// we can only remove hotplugged vCPUs, the vCPUs dedicated to a
+	// container are only removed with it
+	var removable []CPUDevice
+	var remaining []CPUDevice
+	for i := len(q.state.HotpluggedVCPUs) - 1; i >= 0; i-- {
+		cpu := q.state.HotpluggedVCPUs[i]
+		if cpu.Owner == "" && uint32(len(removable)) < amount {
+			removable = append([]CPUDevice{cpu}, removable...)
+			continue
+		}
+		remaining = append([]CPUDevice{cpu}, remaining...)
+	}
 
-	// we can only remove hotplugged vCPUs
-	if amount > hotpluggedVCPUs {
-		return 0, fmt.Errorf("Unable to remove %d CPUs, currently there are only %d hotplugged CPUs", amount, hotpluggedVCPUs)
+	if uint32(len(removable)) < amount {
+		return 0, fmt.Errorf("Unable to remove %d CPUs, currently there are only %d hotplugged CPUs not dedicated to a container",
+			amount, len(removable))
 	}
 
-	for i := uint32(0); i < amount; i++ {
-		// get the last vCPUs and try to remove it
-		cpu := q.state.HotpluggedVCPUs[len(q.state.HotpluggedVCPUs)-1]
-		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, cpu.ID); err != nil {
+	// remove the last vCPUs, one at a time: each device_del waits for
+	// the guest to eject the vCPU
+	for i := len(removable) - 1; i >= 0; i-- {
+		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, removable[i].ID); err != nil {
+			// keep the vCPUs that could not be hotunplugged
+			removed := len(removable) - 1 - i
+			q.state.HotpluggedVCPUs = append(remaining, removable[:i+1]...)
 			_ = q.store.Store(store.Hypervisor, q.state)
-			return i, fmt.Errorf("failed to hotunplug CPUs, only %d CPUs were hotunplugged: %v", i, err)
+			return uint32(removed), fmt.Errorf("failed to hotunplug CPUs, only %d CPUs were hotunplugged: %v", removed, err)
 		}
-
-		// remove from the list the vCPU hotunplugged
-		q.state.HotpluggedVCPUs = q.state.HotpluggedVCPUs[:len(q.state.HotpluggedVCPUs)-1]
 	}
+	q.state.HotpluggedVCPUs = remaining
 
 	return amount, q.store.Store(store.Hypervisor, q.state)
 }
 
+// hotpluggedCPUIndexes returns the QEMU CPU index, which is the guest CPU
+// number, of the hotplugged vCPUs by device ID.
+func (q *qemu) hotpluggedCPUIndexes() (map[string]int, error) {
+	cpuInfos, err := q.qmpMonitorCh.qmp.ExecQueryCpusFast(q.qmpMonitorCh.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CPUs: %v", err)
+	}
+
+	indexes := make(map[string]int, len(cpuInfos))
+	for _, i := range cpuInfos {
+		if strings.HasPrefix(i.QomPath, qomPeripheralPath) {
+			indexes[strings.TrimPrefix(i.QomPath, qomPeripheralPath)] = i.CPUIndex
+		}
+	}
+
+	return indexes, nil
+}
+
+// dedicateVCPUs dedicates to owner the last count hotplugged vCPUs not
+// dedicated yet, the ones just hot added for it, and returns their guest CPU
+// numbers.
+func (q *qemu) dedicateVCPUs(owner string, count uint32) ([]int, error) {
+	if owner == "" {
+		return nil, fmt.Errorf("Cannot dedicate vCPUs without an owner")
+	}
+
+	var free []int
+	for i := len(q.state.HotpluggedVCPUs) - 1; i >= 0 && uint32(len(free)) < count; i-- {
+		if q.state.HotpluggedVCPUs[i].Owner == "" {
+			free = append(free, i)
+		}
+	}
+
+	if uint32(len(free)) < count {
+		return nil, fmt.Errorf("Unable to dedicate %d vCPUs, only %d hotplugged vCPUs are not dedicated", count, len(free))
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return nil, err
+	}
+
+	indexes, err := q.hotpluggedCPUIndexes()
+	if err != nil {
+		return nil, err
+	}
+
+	guestCPUs := make([]int, 0, len(free))
+	for _, i := range free {
+		cpu := q.state.HotpluggedVCPUs[i]
+		index, ok := indexes[cpu.ID]
+		if !ok {
+			return nil, fmt.Errorf("Could not find the guest CPU of vCPU %s", cpu.ID)
+		}
+		guestCPUs = append(guestCPUs, index)
+	}
+
+	for _, i := range free {
+		q.state.HotpluggedVCPUs[i].Owner = owner
+	}
+	sort.Ints(guestCPUs)
+
+	return guestCPUs, q.store.Store(store.Hypervisor, q.state)
+}
+
+// releaseVCPUs hot removes the vCPUs dedicated to owner, returns the number
+// of vCPUs removed.
+func (q *qemu) releaseVCPUs(owner string) (uint32, error) {
+	if owner == "" {
+		return 0, nil
+	}
+
+	if err := q.qmpSetup(); err != nil {
+		return 0, err
+	}
+
+	var remaining []CPUDevice
+	var removed uint32
+	var delErr error
+	for _, cpu := range q.state.HotpluggedVCPUs {
+		if cpu.Owner != owner {
+			remaining = append(remaining, cpu)
+			continue
+		}
+
+		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, cpu.ID); err != nil {
+			remaining = append(remaining, cpu)
+			delErr = err
+			continue
+		}
+		removed++
+	}
+	q.state.HotpluggedVCPUs = remaining
+
+	if delErr != nil {
+		_ = q.store.Store(store.Hypervisor, q.state)
+		return removed, fmt.Errorf("failed to hotunplug the vCPUs of %s, only %d vCPUs were hotunplugged: %v", owner, removed, delErr)
+	}
+
+	return removed, q.store.Store(store.Hypervisor, q.state)
+}
+
 func (q *qemu) hotplugMemory(memDev *memoryDevice, op operation) (int, error) {
 
 	if !q.arch.supportGuestMemoryHotplug() {
@@ -1198,7 +1781,7 @@ func (q *qemu) hotplugMemory(memDev *memoryDevice, op operation) (int, error) {
 		return 0, nil
 	case addDevice:
 		memLog.WithField("operation", "add").Debugf("Requested to add memory: %d MB", memDev.sizeMB)
-		maxMem, err := q.hostMemMB()
+		maxMem, err := q.maxMemMB()
 		if err != nil {
 			return 0, err
 		}
@@ -1235,6 +1818,10 @@ func (q *qemu) hotplugAddMemory(memDev *memoryDevice) (int, error) {
 		}
 		memDev.slot = maxSlot + 1
 	}
+	if memDev.slot >= int(q.config.MemSlots) {
+		return 0, fmt.Errorf("Unable to hotplug %d MiB memory, all the %d memory slots are used",
+			memDev.sizeMB, q.config.MemSlots)
+	}
 	err = q.qmpMonitorCh.qmp.ExecHotplugMemory(q.qmpMonitorCh.ctx, "memory-backend-ram", "mem"+strconv.Itoa(memDev.slot), "", memDev.sizeMB)
 	if err != nil {
 		q.Logger().WithError(err).Error("hotplug memory")
@@ -1378,6 +1965,20 @@ func (q *qemu) resizeMemory(reqMemMB uint32, memoryBlockSizeMB uint32) (uint32,
 		return 0, err
 	}
 	switch {
+	case currentMemory < reqMemMB && !q.arch.supportGuestMemoryHotplug():
+		// Do not fail the container update, memory limits are still
+		// enforced by the guest cgroups, but the VM cannot grow.
+		q.Logger().WithFields(logrus.Fields{
+			"current-memory-mb":   currentMemory,
+			"requested-memory-mb": reqMemMB,
+		}).Warn("guest memory hotplug not supported, not resizing VM memory")
+	case currentMemory < reqMemMB && q.config.usesVhostUser():
+		// The hotplugged memory would not be shared with the vhost
+		// user backends, which could not access it.
+		q.Logger().WithFields(logrus.Fields{
+			"current-memory-mb":   currentMemory,
+			"requested-memory-mb": reqMemMB,
+		}).Warn("guest memory not shared with the vhost user backends when hotplugged, not resizing VM memory")
 	case currentMemory < reqMemMB:
 		//hotplug
 		addMemMB := reqMemMB - currentMemory
@@ -1430,9 +2031,10 @@ func (q *qemu) resizeMemory(reqMemMB uint32, memoryBlockSizeMB uint32) (uint32,
 	return currentMemory, nil
 }
 
-// genericAppendBridges appends to devices the given bridges
+// genericAppendBridges appends to devices the given bridges, with their
+// standard hot plug controller if shpc is true
 // nolint: unused, deadcode
-func genericAppendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge, machineType string) []govmmQemu.Device {
+func genericAppendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge, machineType string, shpc bool) []govmmQemu.Device {
 	bus := defaultPCBridgeBus
 	switch machineType {
 	case QemuQ35, QemuVirt:
@@ -1447,6 +2049,18 @@ func genericAppendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge,
 
 		bridges[idx].Addr = bridgePCIStartAddr + idx
 
+		if b.Type == types.PCIERootPort {
+			devices = append(devices,
+				pcieRootPort{
+					ID:      b.ID,
+					Bus:     bus,
+					Chassis: idx + 1,
+					Addr:    bridges[idx].Addr,
+				},
+			)
+			continue
+		}
+
 		devices = append(devices,
 			govmmQemu.BridgeDevice{
 				Type: t,
@@ -1454,7 +2068,7 @@ func genericAppendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge,
 				ID:   b.ID,
 				// Each bridge is required to be assigned a unique chassis id > 0
 				Chassis: idx + 1,
-				SHPC:    true,
+				SHPC:    shpc,
 				Addr:    strconv.FormatInt(int64(bridges[idx].Addr), 10),
 			},
 		)
@@ -6,6 +6,7 @@
 package virtcontainers
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/kata-containers/runtime/virtcontainers/types"
@@ -16,6 +17,9 @@ import (
 type qemuAmd64 struct {
 	// inherit from qemuArchBase, overwrite methods if needed
 	qemuArchBase
+
+	pciHotplug    string
+	pcieRootPorts uint32
 }
 
 const defaultQemuPath = "/usr/bin/qemu-system-x86_64"
@@ -73,11 +77,41 @@ var supportedQemuMachines = []govmmQemu.Machine{
 	},
 }
 
+// The xAPIC addresses up to 255 vCPUs, larger guests use the x2APIC, whose
+// interrupts must be remapped by an IOMMU. Only the q35 machine emulates
+// one, it runs up to 288 vCPUs.
+const (
+	maxXAPICVCPUs = 255
+	maxQ35VCPUs   = 288
+)
+
 // MaxQemuVCPUs returns the maximum number of vCPUs supported
 func MaxQemuVCPUs() uint32 {
 	return uint32(240)
 }
 
+// MaxQemuMachineVCPUs returns the maximum number of vCPUs supported by the
+// given machine type
+func MaxQemuMachineVCPUs(machineType string) uint32 {
+	if machineType == QemuQ35 {
+		return maxQ35VCPUs
+	}
+
+	return MaxQemuVCPUs()
+}
+
+// intelIOMMU is the emulated Intel IOMMU, remapping the interrupts of the
+// guest to the extended APIC IDs of the x2APIC.
+type intelIOMMU struct{}
+
+func (iommu intelIOMMU) Valid() bool {
+	return true
+}
+
+func (iommu intelIOMMU) QemuParams(config *govmmQemu.Config) []string {
+	return []string{"-device", "intel-iommu,intremap=on,eim=on"}
+}
+
 func newQemuArch(config HypervisorConfig) qemuArch {
 	machineType := config.HypervisorMachineType
 	if machineType == "" {
@@ -94,6 +128,18 @@ func newQemuArch(config HypervisorConfig) qemuArch {
 			kernelParamsDebug:     kernelParamsDebug,
 			kernelParams:          kernelParams,
 		},
+		config.PCIHotplug,
+		config.PCIeRootPorts,
+	}
+
+	if config.EnableIOMMU {
+		// the guest must not turn its IOMMU support off
+		q.kernelParams = nil
+		for _, p := range kernelParams {
+			if p.Key != "iommu" {
+				q.kernelParams = append(q.kernelParams, p)
+			}
+		}
 	}
 
 	q.handleImagePath(config)
@@ -115,8 +161,35 @@ func (q *qemuAmd64) capabilities() types.Capabilities {
 	return caps
 }
 
+func (q *qemuAmd64) largeGuest(maxvcpus uint32) bool {
+	return maxvcpus > maxXAPICVCPUs
+}
+
+func (q *qemuAmd64) appendIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	if q.machineType != QemuQ35 {
+		return nil, fmt.Errorf("IOMMU is not supported by the %s machine", q.machineType)
+	}
+
+	// QEMU wants the IOMMU created before the devices it translates for
+	return append([]govmmQemu.Device{intelIOMMU{}}, devices...), nil
+}
+
 func (q *qemuAmd64) bridges(number uint32) []types.PCIBridge {
-	return genericBridges(number, q.machineType)
+	if q.pciHotplug != PCIHotplugNative {
+		return genericBridges(number, q.machineType)
+	}
+
+	// the devices are hot plugged on root ports instead, one per port
+	var ports []types.PCIBridge
+	for i := uint32(0); i < q.pcieRootPorts; i++ {
+		ports = append(ports, types.PCIBridge{
+			Type:    types.PCIERootPort,
+			ID:      fmt.Sprintf("%s-%d", types.PCIERootPort, i),
+			Address: make(map[uint32]string),
+		})
+	}
+
+	return ports
 }
 
 func (q *qemuAmd64) cpuModel() string {
@@ -159,5 +232,33 @@ func (q *qemuAmd64) appendImage(devices []govmmQemu.Device, path string) ([]govm
 
 // appendBridges appends to devices the given bridges
 func (q *qemuAmd64) appendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge) []govmmQemu.Device {
-	return genericAppendBridges(devices, bridges, q.machineType)
+	// Without SHPC the guest only sees the ACPI hotplug of the bridges.
+	return genericAppendBridges(devices, bridges, q.machineType, q.pciHotplug != PCIHotplugACPI)
+}
+
+func (q *qemuAmd64) appendPCIHotplug(devices []govmmQemu.Device, mode string) ([]govmmQemu.Device, error) {
+	var pm string
+
+	switch q.machineType {
+	case QemuPC, QemuPCLite:
+		pm = "PIIX4_PM"
+		if mode == PCIHotplugNative {
+			return nil, fmt.Errorf("native PCI hotplug is not supported by the %s machine", q.machineType)
+		}
+	case QemuQ35:
+		pm = "ICH9-LPC"
+	default:
+		return q.qemuArchBase.appendPCIHotplug(devices, mode)
+	}
+
+	switch mode {
+	case "":
+		return devices, nil
+	case PCIHotplugACPI:
+		return append(devices, qemuGlobal(pm+".acpi-pci-hotplug-with-bridge-support=on")), nil
+	default:
+		// the firmware must not take the hotplug over from the SHPC or
+		// from the root ports
+		return append(devices, qemuGlobal(pm+".acpi-pci-hotplug-with-bridge-support=off")), nil
+	}
 }
@@ -66,6 +66,22 @@ func TestQemuAmd64Bridges(t *testing.T) {
 	assert.Nil(bridges)
 }
 
+func TestQemuAmd64KernelParametersIOMMU(t *testing.T) {
+	assert := assert.New(t)
+
+	amd64 := newTestQemu(QemuQ35)
+	assert.Contains(amd64.kernelParameters(false), Param{"iommu", "off"})
+
+	amd64 = newQemuArch(HypervisorConfig{
+		HypervisorMachineType: QemuQ35,
+		EnableIOMMU:           true,
+	})
+	assert.NotContains(amd64.kernelParameters(false), Param{"iommu", "off"})
+
+	// the default parameters are left untouched
+	assert.Contains(kernelParams, Param{"iommu", "off"})
+}
+
 func TestQemuAmd64CPUModel(t *testing.T) {
 	assert := assert.New(t)
 	amd64 := newTestQemu(QemuPC)
@@ -78,6 +94,15 @@ func TestQemuAmd64CPUModel(t *testing.T) {
 	expectedOut = defaultCPUModel + ",pmu=off"
 	model = amd64.cpuModel()
 	assert.Equal(expectedOut, model)
+
+	// the configured model keeps the nesting options
+	q := &qemu{
+		arch: amd64,
+		config: HypervisorConfig{
+			CPUModel: "Skylake-Server",
+		},
+	}
+	assert.Equal("Skylake-Server,pmu=off", q.cpuModel())
 }
 
 func TestQemuAmd64MemoryTopology(t *testing.T) {
@@ -177,3 +202,111 @@ func TestQemuAmd64AppendBridges(t *testing.T) {
 
 	assert.Equal(expectedOut, devices)
 }
+
+func TestQemuAmd64PCIHotplug(t *testing.T) {
+	assert := assert.New(t)
+
+	newArch := func(machineType string, mode string) qemuArch {
+		return newQemuArch(HypervisorConfig{
+			HypervisorMachineType: machineType,
+			PCIHotplug:            mode,
+			PCIeRootPorts:         2,
+		})
+	}
+
+	// ACPI only, the bridges have no SHPC
+	amd64 := newArch(QemuPC, PCIHotplugACPI)
+	devices := amd64.appendBridges(nil, amd64.bridges(1))
+	assert.False(devices[0].(govmmQemu.BridgeDevice).SHPC)
+
+	devices, err := amd64.appendPCIHotplug(nil, PCIHotplugACPI)
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{qemuGlobal("PIIX4_PM.acpi-pci-hotplug-with-bridge-support=on")}, devices)
+
+	amd64 = newArch(QemuQ35, PCIHotplugSHPC)
+	devices = amd64.appendBridges(nil, amd64.bridges(1))
+	assert.True(devices[0].(govmmQemu.BridgeDevice).SHPC)
+
+	devices, err = amd64.appendPCIHotplug(nil, PCIHotplugSHPC)
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{qemuGlobal("ICH9-LPC.acpi-pci-hotplug-with-bridge-support=off")}, devices)
+
+	// native, the devices are hot plugged on root ports
+	amd64 = newArch(QemuQ35, PCIHotplugNative)
+	bridges := amd64.bridges(1)
+	assert.Len(bridges, 2)
+	assert.Equal(types.PCIERootPort, bridges[0].Type)
+
+	devices = amd64.appendBridges(nil, bridges)
+	assert.Equal([]govmmQemu.Device{
+		pcieRootPort{ID: bridges[0].ID, Bus: defaultBridgeBus, Chassis: 1, Addr: 2},
+		pcieRootPort{ID: bridges[1].ID, Bus: defaultBridgeBus, Chassis: 2, Addr: 3},
+	}, devices)
+	assert.Equal([]string{"-device", "pcie-root-port,id=pcie-root-port-1,bus=pcie.0,chassis=2,slot=2,addr=3"},
+		devices[1].QemuParams(nil))
+
+	addr, err := bridges[0].AddDevice("dev0")
+	assert.NoError(err)
+	assert.Equal(uint32(0), addr)
+
+	_, err = newArch(QemuPC, PCIHotplugNative).appendPCIHotplug(nil, PCIHotplugNative)
+	assert.Error(err)
+
+	// the default of the machine is kept
+	devices, err = newTestQemu(QemuPC).appendPCIHotplug(nil, "")
+	assert.NoError(err)
+	assert.Empty(devices)
+}
+
+func TestQemuAmd64LargeGuest(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint32(maxQ35VCPUs), MaxQemuMachineVCPUs(QemuQ35))
+	assert.Equal(MaxQemuVCPUs(), MaxQemuMachineVCPUs(QemuPC))
+
+	q := &qemu{
+		arch: newTestQemu(QemuQ35),
+		config: HypervisorConfig{
+			NumVCPUs:        4,
+			DefaultMaxVCPUs: maxXAPICVCPUs,
+		},
+	}
+
+	// up to 255 vCPUs, nothing changes
+	assert.False(q.arch.largeGuest(q.config.DefaultMaxVCPUs))
+	machine, err := q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal(defaultQemuMachineOptions, machine.Options)
+
+	q.config.DefaultMaxVCPUs = maxQ35VCPUs
+	assert.True(q.arch.largeGuest(q.config.DefaultMaxVCPUs))
+	machine, err = q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal("accel=kvm,nvdimm,kernel_irqchip=split", machine.Options)
+
+	smp := q.cpuTopology()
+	assert.Equal(govmmQemu.SMP{CPUs: 4, Sockets: maxQ35VCPUs, Cores: 1, Threads: 1, MaxCPUs: maxQ35VCPUs}, smp)
+
+	devices, err := q.arch.appendIOMMU([]govmmQemu.Device{govmmQemu.BalloonDevice{ID: "balloon0"}})
+	assert.NoError(err)
+	assert.Len(devices, 2)
+	assert.Equal([]string{"-device", "intel-iommu,intremap=on,eim=on"}, devices[0].QemuParams(nil))
+
+	// the interrupts cannot be remapped by the in-kernel IOAPIC
+	q.config.KernelIRQChip = "on"
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+
+	q.config.KernelIRQChip = ""
+	q.config.DefaultMaxVCPUs = maxQ35VCPUs + 1
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+
+	// only q35 emulates an IOMMU
+	q.arch = newTestQemu(QemuPC)
+	q.config.DefaultMaxVCPUs = maxXAPICVCPUs + 1
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+	_, err = q.arch.appendIOMMU(nil)
+	assert.Error(err)
+}
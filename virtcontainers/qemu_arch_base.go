@@ -95,11 +95,132 @@ type qemuArch interface {
 	// appendRNGDevice appends a RNG device to devices
 	appendRNGDevice(devices []govmmQemu.Device, rngDevice config.RNGDev) []govmmQemu.Device
 
+	// appendBalloonDevice appends a virtio-balloon device to devices
+	appendBalloonDevice(devices []govmmQemu.Device, id string) []govmmQemu.Device
+
 	// handleImagePath handles the Hypervisor Config image path
 	handleImagePath(config HypervisorConfig)
 
 	// supportGuestMemoryHotplug returns if the guest supports memory hotplug
 	supportGuestMemoryHotplug() bool
+
+	// largeGuest returns if maxvcpus are more than the interrupt controller
+	// of the guest addresses without interrupt remapping
+	largeGuest(maxvcpus uint32) bool
+
+	// appendIOMMU appends an IOMMU remapping the guest interrupts to devices
+	appendIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error)
+
+	// appendVirtioIOMMU appends a virtio-iommu device to devices
+	appendVirtioIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error)
+
+	// appendPCIHotplug appends to devices the settings selecting the given
+	// PCI hotplug mechanism
+	appendPCIHotplug(devices []govmmQemu.Device, mode string) ([]govmmQemu.Device, error)
+}
+
+// virtioIOMMUDrivers are the virtio devices translating their DMA through
+// the virtio-iommu. The vhost-vsock and vhost-user devices are left out,
+// their backends do not all support the device IOTLB.
+var virtioIOMMUDrivers = []string{
+	"virtio-net-pci",
+	"virtio-blk-pci",
+	"virtio-scsi-pci",
+	"virtio-serial-pci",
+	"virtio-9p-pci",
+	"virtio-rng-pci",
+	"virtio-balloon-pci",
+}
+
+// virtioIOMMU is the virtio-iommu device. Its parameters also set the
+// iommu_platform property of the virtio devices, whether they are cold or
+// hot plugged.
+type virtioIOMMU struct{}
+
+func (iommu virtioIOMMU) Valid() bool {
+	return true
+}
+
+func (iommu virtioIOMMU) QemuParams(config *govmmQemu.Config) []string {
+	params := []string{"-device", "virtio-iommu-pci"}
+
+	for _, driver := range virtioIOMMUDrivers {
+		params = append(params,
+			"-global", driver+".disable-legacy=on",
+			"-global", driver+".iommu_platform=on")
+	}
+
+	return params
+}
+
+// qemuGlobal sets a property of all the devices of a driver, in the
+// driver.property=value format.
+type qemuGlobal string
+
+func (g qemuGlobal) Valid() bool {
+	return g != ""
+}
+
+func (g qemuGlobal) QemuParams(config *govmmQemu.Config) []string {
+	return []string{"-global", string(g)}
+}
+
+// pflash is a flash memory device mapping a UEFI firmware volume, unit 0
+// being the firmware code and unit 1 its variable store.
+type pflash struct {
+	Unit     int
+	Path     string
+	ReadOnly bool
+}
+
+func (p pflash) Valid() bool {
+	return p.Path != ""
+}
+
+func (p pflash) QemuParams(config *govmmQemu.Config) []string {
+	drive := fmt.Sprintf("if=pflash,format=raw,unit=%d,file=%s", p.Unit, p.Path)
+	if p.ReadOnly {
+		drive += ",readonly=on"
+	}
+
+	return []string{"-drive", drive}
+}
+
+// pcieRootPort is a PCIe root port of the native PCIe hotplug.
+type pcieRootPort struct {
+	ID      string
+	Bus     string
+	Chassis int
+	Addr    int
+}
+
+func (p pcieRootPort) Valid() bool {
+	return p.ID != "" && p.Bus != ""
+}
+
+func (p pcieRootPort) QemuParams(config *govmmQemu.Config) []string {
+	return []string{"-device", fmt.Sprintf("pcie-root-port,id=%s,bus=%s,chassis=%d,slot=%d,addr=%x",
+		p.ID, p.Bus, p.Chassis, p.Chassis, p.Addr)}
+}
+
+// serialBlockDevice is a block device the guest looks up by its serial
+// number.
+type serialBlockDevice struct {
+	govmmQemu.BlockDevice
+	Serial string
+}
+
+func (d serialBlockDevice) QemuParams(config *govmmQemu.Config) []string {
+	params := d.BlockDevice.QemuParams(config)
+
+	for i := 0; i+1 < len(params); i++ {
+		if params[i] == "-device" {
+			params[i+1] += ",serial=" + d.Serial
+			break
+		}
+	}
+
+	return params
 }
 
 type qemuArchBase struct {
@@ -123,6 +244,9 @@ const (
 	defaultPCBridgeBus        = "pci.0"
 	maxDevIDSize              = 31
 	defaultMsize9p            = 8192
+	minMsize9p                = 4096
+	defaultCache9p            = "mmap"
+	defaultVersion9p          = "9p2000.L"
 )
 
 // This is the PCI start address assigned to the first bridge that
@@ -498,19 +622,24 @@ func (q *qemuArchBase) appendBlockDevice(devices []govmmQemu.Device, drive confi
 		drive.ID = drive.ID[:maxDevIDSize]
 	}
 
-	devices = append(devices,
-		govmmQemu.BlockDevice{
-			Driver:        govmmQemu.VirtioBlock,
-			ID:            drive.ID,
-			File:          drive.File,
-			AIO:           govmmQemu.Threads,
-			Format:        govmmQemu.BlockDeviceFormat(drive.Format),
-			Interface:     "none",
-			DisableModern: q.nestedRun,
-		},
-	)
+	blockDevice := govmmQemu.BlockDevice{
+		Driver:        govmmQemu.VirtioBlock,
+		ID:            drive.ID,
+		File:          drive.File,
+		AIO:           govmmQemu.Threads,
+		Format:        govmmQemu.BlockDeviceFormat(drive.Format),
+		Interface:     "none",
+		DisableModern: q.nestedRun,
+	}
 
-	return devices
+	if drive.Serial != "" {
+		return append(devices, serialBlockDevice{
+			BlockDevice: blockDevice,
+			Serial:      drive.Serial,
+		})
+	}
+
+	return append(devices, blockDevice)
 }
 
 func (q *qemuArchBase) appendVhostUserDevice(devices []govmmQemu.Device, attr config.VhostUserDeviceAttrs) ([]govmmQemu.Device, error) {
@@ -559,6 +688,48 @@ func (q *qemuArchBase) appendRNGDevice(devices []govmmQemu.Device, rngDev config
 	return devices
 }
 
+func (q *qemuArchBase) appendBalloonDevice(devices []govmmQemu.Device, id string) []govmmQemu.Device {
+	devices = append(devices,
+		govmmQemu.BalloonDevice{
+			ID: id,
+			// the guest gets the memory back instead of being OOM killed
+			DeflateOnOOM:  true,
+			DisableModern: q.nestedRun,
+		},
+	)
+
+	return devices
+}
+
+func (q *qemuArchBase) largeGuest(maxvcpus uint32) bool {
+	return false
+}
+
+func (q *qemuArchBase) appendIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	return nil, fmt.Errorf("IOMMU is not supported by the %s machine", q.machineType)
+}
+
+func (q *qemuArchBase) appendVirtioIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	if q.machineType != QemuQ35 && q.machineType != QemuVirt {
+		return nil, fmt.Errorf("virtio-iommu is not supported by the %s machine", q.machineType)
+	}
+
+	// Going through the IOMMU is a feature of the modern devices only.
+	if q.nestedRun {
+		return nil, fmt.Errorf("virtio-iommu needs the modern virtio devices, disabled when running nested")
+	}
+
+	return append(devices, virtioIOMMU{}), nil
+}
+
+func (q *qemuArchBase) appendPCIHotplug(devices []govmmQemu.Device, mode string) ([]govmmQemu.Device, error) {
+	if mode != "" {
+		return nil, fmt.Errorf("%s PCI hotplug is not supported by the %s machine", mode, q.machineType)
+	}
+
+	return devices, nil
+}
+
 func (q *qemuArchBase) handleImagePath(config HypervisorConfig) {
 	if config.ImagePath != "" {
 		q.kernelParams = append(q.kernelParams, kernelRootParams...)
@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	govmmQemu "github.com/intel/govmm/qemu"
@@ -376,6 +377,27 @@ func TestQemuArchBaseAppendBlockDevice(t *testing.T) {
 	}
 
 	testQemuArchBaseAppend(t, drive, expectedOut)
+
+	// the guest looks up some drives by their serial
+	drive.Serial = "kdump"
+	serialDevice := serialBlockDevice{
+		BlockDevice: govmmQemu.BlockDevice{
+			Driver:    govmmQemu.VirtioBlock,
+			ID:        id,
+			File:      "/root",
+			AIO:       govmmQemu.Threads,
+			Format:    govmmQemu.BlockDeviceFormat(format),
+			Interface: "none",
+		},
+		Serial: "kdump",
+	}
+	expectedOut[0] = serialDevice
+
+	testQemuArchBaseAppend(t, drive, expectedOut)
+
+	params := serialDevice.QemuParams(&govmmQemu.Config{})
+	assert.Equal(t, "-device", params[0])
+	assert.True(t, strings.HasSuffix(params[1], ",serial=kdump"), params[1])
 }
 
 func TestQemuArchBaseAppendVhostUserDevice(t *testing.T) {
@@ -419,6 +441,43 @@ func TestQemuArchBaseAppendVFIODevice(t *testing.T) {
 	testQemuArchBaseAppend(t, vfDevice, expectedOut)
 }
 
+func TestQemuArchBaseAppendBalloonDevice(t *testing.T) {
+	assert := assert.New(t)
+	qemuArchBase := newQemuArchBase()
+
+	expectedOut := []govmmQemu.Device{
+		govmmQemu.BalloonDevice{
+			ID:           balloonID,
+			DeflateOnOOM: true,
+		},
+	}
+
+	devices := qemuArchBase.appendBalloonDevice(nil, balloonID)
+	assert.Equal(expectedOut, devices)
+}
+
+func TestQemuArchBaseAppendVirtioIOMMU(t *testing.T) {
+	assert := assert.New(t)
+	qemuArchBase := newQemuArchBase()
+
+	_, err := qemuArchBase.appendVirtioIOMMU(nil)
+	assert.Error(err)
+
+	qemuArchBase.machineType = QemuVirt
+	devices, err := qemuArchBase.appendVirtioIOMMU(nil)
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{virtioIOMMU{}}, devices)
+
+	params := devices[0].QemuParams(nil)
+	assert.Equal([]string{"-device", "virtio-iommu-pci"}, params[:2])
+	assert.Contains(params, "virtio-net-pci.iommu_platform=on")
+	assert.Contains(params, "virtio-net-pci.disable-legacy=on")
+
+	qemuArchBase.nestedRun = true
+	_, err = qemuArchBase.appendVirtioIOMMU(nil)
+	assert.Error(err)
+}
+
 func TestQemuArchBaseAppendSCSIController(t *testing.T) {
 	var devices []govmmQemu.Device
 	assert := assert.New(t)
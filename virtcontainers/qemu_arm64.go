@@ -19,6 +19,10 @@ import (
 type qemuArm64 struct {
 	// inherit from qemuArchBase, overwrite methods if needed
 	qemuArchBase
+
+	// acpi is true when the guest boots with UEFI firmware, which is
+	// the only way for the virt machine to expose ACPI tables.
+	acpi bool
 }
 
 const defaultQemuPath = "/usr/bin/qemu-system-aarch64"
@@ -37,6 +41,18 @@ var kernelParams = []Param{
 	{"iommu.passthrough", "0"},
 }
 
+// kernelACPIParams forces the guest kernel to use ACPI rather than the
+// device tree, so that ACPI based memory hotplug is available.
+var kernelACPIParams = []Param{
+	{"acpi", "force"},
+}
+
+// ACPI memory hotplug is supported by the virt machine since QEMU 4.2
+const (
+	qemuMemHotplugMajorVersion = 4
+	qemuMemHotplugMinorVersion = 2
+)
+
 var kernelRootParams = []Param{
 	{"root", "/dev/pmem0p1"},
 	{"rootflags", "data=ordered,errors=remount-ro rw"},
@@ -128,6 +144,12 @@ func MaxQemuVCPUs() uint32 {
 	return uint32(runtime.NumCPU())
 }
 
+// MaxQemuMachineVCPUs returns the maximum number of vCPUs supported by the
+// given machine type
+func MaxQemuMachineVCPUs(machineType string) uint32 {
+	return MaxQemuVCPUs()
+}
+
 func newQemuArch(config HypervisorConfig) qemuArch {
 	machineType := config.HypervisorMachineType
 	if machineType == "" {
@@ -144,6 +166,11 @@ func newQemuArch(config HypervisorConfig) qemuArch {
 			kernelParamsDebug:     kernelParamsDebug,
 			kernelParams:          kernelParams,
 		},
+		config.FirmwarePath != "",
+	}
+
+	if q.acpi {
+		q.kernelParams = append(q.kernelParams, kernelACPIParams...)
 	}
 
 	if config.ImagePath != "" {
@@ -161,7 +188,7 @@ func (q *qemuArm64) bridges(number uint32) []types.PCIBridge {
 
 // appendBridges appends to devices the given bridges
 func (q *qemuArm64) appendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge) []govmmQemu.Device {
-	return genericAppendBridges(devices, bridges, q.machineType)
+	return genericAppendBridges(devices, bridges, q.machineType, true)
 }
 
 func (q *qemuArm64) appendImage(devices []govmmQemu.Device, path string) ([]govmmQemu.Device, error) {
@@ -189,3 +216,19 @@ func (q *qemuArm64) appendImage(devices []govmmQemu.Device, path string) ([]govm
 
 	return devices, nil
 }
+
+// supportGuestMemoryHotplug returns true only when memory can actually be
+// hotplugged into the guest. Unlike x86, the virt machine has no legacy
+// DIMM hotplug: pc-dimm devices are only announced to the guest through
+// ACPI, which requires both UEFI firmware and a recent enough QEMU.
+func (q *qemuArm64) supportGuestMemoryHotplug() bool {
+	if !q.acpi {
+		return false
+	}
+
+	if qemuMajorVersion != qemuMemHotplugMajorVersion {
+		return qemuMajorVersion > qemuMemHotplugMajorVersion
+	}
+
+	return qemuMinorVersion >= qemuMemHotplugMinorVersion
+}
@@ -155,3 +155,41 @@ func TestQemuArm64AppendImage(t *testing.T) {
 
 	assert.Equal(expectedOut, devices)
 }
+
+func TestQemuArm64SupportGuestMemoryHotplug(t *testing.T) {
+	assert := assert.New(t)
+
+	savedMajor, savedMinor := qemuMajorVersion, qemuMinorVersion
+	defer func() {
+		qemuMajorVersion, qemuMinorVersion = savedMajor, savedMinor
+	}()
+
+	// no firmware, no ACPI
+	arm64 := newTestQemu(QemuVirt)
+	qemuMajorVersion, qemuMinorVersion = 4, 2
+	assert.False(arm64.supportGuestMemoryHotplug())
+
+	arm64 = newQemuArch(HypervisorConfig{
+		HypervisorMachineType: QemuVirt,
+		FirmwarePath:          "/usr/share/qemu-efi-aarch64/QEMU_EFI.fd",
+	})
+	assert.Contains(arm64.kernelParameters(false), Param{"acpi", "force"})
+
+	type testData struct {
+		major    int
+		minor    int
+		expected bool
+	}
+
+	data := []testData{
+		{3, 1, false},
+		{4, 1, false},
+		{4, 2, true},
+		{5, 0, true},
+	}
+
+	for _, d := range data {
+		qemuMajorVersion, qemuMinorVersion = d.major, d.minor
+		assert.Equal(d.expected, arm64.supportGuestMemoryHotplug(), "%+v", d)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/kata-containers/runtime/virtcontainers/utils"
+)
+
+const (
+	// qmpStatsSocket is the QMP monitor the balloon stats are read from,
+	// apart from the main one for the monitor checks not to wait behind
+	// the hotplug operations.
+	qmpStatsSocket = "qmp-stats.sock"
+
+	qmpStatsTimeout = 5 * time.Second
+
+	// balloonStatsPollInterval is the number of seconds the balloon
+	// driver reports the guest memory stats every.
+	balloonStatsPollInterval = 5
+
+	balloonStatAvailable = "stat-available-memory"
+)
+
+type qmpStatsCommand struct {
+	Execute   string                 `json:"execute"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+type qmpStatsResponse struct {
+	Return json.RawMessage `json:"return"`
+	Event  string          `json:"event"`
+	Error  *struct {
+		Class string `json:"class"`
+		Desc  string `json:"desc"`
+	} `json:"error"`
+}
+
+// balloonStats are the guest memory stats, in bytes, reported by the balloon
+// driver, last-update is 0 until it first reports them.
+type balloonStats struct {
+	Stats      map[string]int64 `json:"stats"`
+	LastUpdate int64            `json:"last-update"`
+}
+
+func (q *qemu) qmpStatsSocketPath(id string) (string, error) {
+	return utils.BuildSocketPath(store.RunVMStoragePath, id, qmpStatsSocket)
+}
+
+// qmpStatsExecute runs a QMP command on the stats monitor, ret is set to its
+// result when not nil.
+func qmpStatsExecute(path string, cmd qmpStatsCommand, ret interface{}) error {
+	conn, err := net.DialTimeout("unix", path, qmpStatsTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(qmpStatsTimeout)); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	// the greeting
+	var greeting map[string]interface{}
+	if err := dec.Decode(&greeting); err != nil {
+		return err
+	}
+
+	for _, c := range []qmpStatsCommand{{Execute: "qmp_capabilities"}, cmd} {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+
+		result, err := qmpStatsResult(dec)
+		if err != nil {
+			return fmt.Errorf("QMP %s failed: %v", c.Execute, err)
+		}
+
+		if c.Execute == cmd.Execute && ret != nil {
+			return json.Unmarshal(result, ret)
+		}
+	}
+
+	return nil
+}
+
+// qmpStatsResult returns the result of the command sent, skipping the
+// events.
+func qmpStatsResult(dec *json.Decoder) (json.RawMessage, error) {
+	for {
+		var resp qmpStatsResponse
+		if err := dec.Decode(&resp); err != nil {
+			return nil, err
+		}
+
+		if resp.Event != "" {
+			continue
+		}
+
+		if resp.Error != nil {
+			return nil, fmt.Errorf("%s: %s", resp.Error.Class, resp.Error.Desc)
+		}
+
+		return resp.Return, nil
+	}
+}
+
+// guestMemAvailable returns the memory in MiB available to the guest, its
+// MemAvailable as reported by the balloon driver. The agent is not involved.
+func (q *qemu) guestMemAvailable() (uint32, error) {
+	path, err := q.qmpStatsSocketPath(q.id)
+	if err != nil {
+		return 0, err
+	}
+
+	balloonPath := qomPeripheralPath + balloonID
+
+	// The driver only reports the stats once asked to poll them.
+	if !q.balloonStatsPolled {
+		if err := qmpStatsExecute(path, qmpStatsCommand{
+			Execute: "qom-set",
+			Arguments: map[string]interface{}{
+				"path":     balloonPath,
+				"property": "guest-stats-polling-interval",
+				"value":    balloonStatsPollInterval,
+			},
+		}, nil); err != nil {
+			return 0, err
+		}
+		q.balloonStatsPolled = true
+	}
+
+	var stats balloonStats
+	if err := qmpStatsExecute(path, qmpStatsCommand{
+		Execute: "qom-get",
+		Arguments: map[string]interface{}{
+			"path":     balloonPath,
+			"property": "guest-stats",
+		},
+	}, &stats); err != nil {
+		return 0, err
+	}
+
+	if stats.LastUpdate == 0 {
+		return 0, fmt.Errorf("No balloon stats reported yet")
+	}
+
+	// -1 when the guest does not report it, before linux 4.6
+	available, ok := stats.Stats[balloonStatAvailable]
+	if !ok || available < 0 {
+		return 0, fmt.Errorf("The guest does not report its available memory")
+	}
+
+	return uint32(available >> utils.MibToBytesShift), nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveQMPStats answers the QMP commands with the balloon stats, and
+// records them.
+func serveQMPStats(l net.Listener, stats *balloonStats, commands chan<- qmpStatsCommand) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			dec := json.NewDecoder(conn)
+			enc := json.NewEncoder(conn)
+			enc.Encode(map[string]interface{}{"QMP": map[string]interface{}{}})
+
+			for {
+				var cmd qmpStatsCommand
+				if err := dec.Decode(&cmd); err != nil {
+					return
+				}
+				commands <- cmd
+
+				var ret interface{} = map[string]interface{}{}
+				if cmd.Execute == "qom-get" {
+					// an event before the response
+					enc.Encode(map[string]interface{}{"event": "BALLOON_CHANGE"})
+					ret = stats
+				}
+				enc.Encode(map[string]interface{}{"return": ret})
+			}
+		}(conn)
+	}
+}
+
+func TestQemuGuestMemAvailable(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{id: "qemu-balloon-test"}
+	dir := filepath.Join(store.RunVMStoragePath, q.id)
+	assert.NoError(os.MkdirAll(dir, store.DirMode))
+	defer os.RemoveAll(dir)
+
+	path, err := q.qmpStatsSocketPath(q.id)
+	assert.NoError(err)
+
+	// no monitor
+	_, err = q.guestMemAvailable()
+	assert.Error(err)
+
+	l, err := net.Listen("unix", path)
+	assert.NoError(err)
+	defer l.Close()
+
+	stats := &balloonStats{}
+	commands := make(chan qmpStatsCommand, 16)
+	go serveQMPStats(l, stats, commands)
+
+	// not reported yet, the polling is enabled first
+	_, err = q.guestMemAvailable()
+	assert.Error(err)
+	assert.True(q.balloonStatsPolled)
+	for _, name := range []string{"qmp_capabilities", "qom-set", "qmp_capabilities", "qom-get"} {
+		cmd := <-commands
+		assert.Equal(name, cmd.Execute)
+	}
+
+	stats.LastUpdate = 1
+	stats.Stats = map[string]int64{balloonStatAvailable: -1}
+	_, err = q.guestMemAvailable()
+	assert.Error(err)
+
+	stats.Stats[balloonStatAvailable] = 512 << 20
+	available, err := q.guestMemAvailable()
+	assert.NoError(err)
+	assert.Equal(uint32(512), available)
+}
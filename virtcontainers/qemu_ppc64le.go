@@ -8,6 +8,7 @@ package virtcontainers
 import (
 	"encoding/hex"
 	"os"
+	"strings"
 
 	govmmQemu "github.com/intel/govmm/qemu"
 	deviceConfig "github.com/kata-containers/runtime/virtcontainers/device/config"
@@ -29,6 +30,8 @@ const defaultQemuMachineOptions = "accel=kvm,usb=off"
 
 const defaultMemMaxPPC64le = 32256 // Restrict MemMax to 32Gb on PPC64le
 
+const defaultSocketsPPC64le uint32 = 1
+
 var qemuPaths = map[string]string{
 	QemuPseries: defaultQemuPath,
 }
@@ -54,16 +57,59 @@ var supportedQemuMachines = []govmmQemu.Machine{
 	},
 }
 
+const (
+	interruptControllerXICS = utils.InterruptControllerXICS
+	interruptControllerXIVE = utils.InterruptControllerXIVE
+
+	// dual lets the guest negotiate XICS or XIVE with the hypervisor.
+	interruptControllerDual = "dual"
+)
+
+// We will access this file on host to detect the interrupt controller
+var icProfile = "/proc/interrupts"
+
 // Logger returns a logrus logger appropriate for logging qemu messages
 func (q *qemuPPC64le) Logger() *logrus.Entry {
 	return virtLog.WithField("subsystem", "qemu")
 }
 
+// getHostInterruptController returns the interrupt controller used by the
+// host, or an empty string if it cannot be detected.
+func getHostInterruptController() string {
+	ic, err := utils.PowerInterruptController(icProfile)
+	if err != nil {
+		virtLog.WithField("subsystem", "qemu").WithField("interrupt profile", icProfile).WithError(err).Warn("Failed to parse interrupt profile")
+	}
+
+	return ic
+}
+
+// guestInterruptControllerMode returns the pseries "ic-mode" to use for the
+// given host interrupt controller. A XIVE capable host can run both XIVE
+// and XICS guests, so let the guest kernel choose, while a XICS host can
+// only emulate XICS.
+func guestInterruptControllerMode(hostIC string) string {
+	switch hostIC {
+	case interruptControllerXIVE:
+		return interruptControllerDual
+	case interruptControllerXICS:
+		return interruptControllerXICS
+	}
+
+	return ""
+}
+
 // MaxQemuVCPUs returns the maximum number of vCPUs supported
 func MaxQemuVCPUs() uint32 {
 	return uint32(128)
 }
 
+// MaxQemuMachineVCPUs returns the maximum number of vCPUs supported by the
+// given machine type
+func MaxQemuMachineVCPUs(machineType string) uint32 {
+	return MaxQemuVCPUs()
+}
+
 func newQemuArch(config HypervisorConfig) qemuArch {
 	machineType := config.HypervisorMachineType
 	if machineType == "" {
@@ -89,6 +135,25 @@ func newQemuArch(config HypervisorConfig) qemuArch {
 	return q
 }
 
+// machine returns the pseries machine, selecting the interrupt controller
+// mode from the host one unless it has been set explicitly.
+func (q *qemuPPC64le) machine() (govmmQemu.Machine, error) {
+	m, err := q.qemuArchBase.machine()
+	if err != nil {
+		return m, err
+	}
+
+	if m.Type != QemuPseries || strings.Contains(m.Options, "ic-mode=") {
+		return m, nil
+	}
+
+	if mode := guestInterruptControllerMode(getHostInterruptController()); mode != "" {
+		m.Options += ",ic-mode=" + mode
+	}
+
+	return m, nil
+}
+
 func (q *qemuPPC64le) capabilities() types.Capabilities {
 	var caps types.Capabilities
 
@@ -148,7 +213,41 @@ func (q *qemuPPC64le) appendImage(devices []govmmQemu.Device, path string) ([]go
 	return q.appendBlockDevice(devices, drive), nil
 }
 
-// appendBridges appends to devices the given bridges
+// cpuTopology returns the CPU topology for the given amount of vcpus.
+// pseries hotplugs whole cores and expects the topology to describe all
+// the possible vCPUs, so expose maxvcpus single threaded cores in one
+// socket, which keeps the hotplug granularity to one vCPU.
+func (q *qemuPPC64le) cpuTopology(vcpus, maxvcpus uint32) govmmQemu.SMP {
+	if maxvcpus < vcpus {
+		maxvcpus = vcpus
+	}
+
+	return govmmQemu.SMP{
+		CPUs:    vcpus,
+		Sockets: defaultSocketsPPC64le,
+		Cores:   maxvcpus,
+		Threads: defaultThreads,
+		MaxCPUs: maxvcpus,
+	}
+}
+
+// appendBridges appends to devices the given bridges.
+// Devices behind a pseries PCI bridge are hotplugged through the PAPR
+// dynamic reconfiguration connectors, the standard hot plug controller
+// is not used.
 func (q *qemuPPC64le) appendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge) []govmmQemu.Device {
-	return genericAppendBridges(devices, bridges, q.machineType)
+	devices = genericAppendBridges(devices, bridges, q.machineType, true)
+
+	if q.machineType != QemuPseries {
+		return devices
+	}
+
+	for i, d := range devices {
+		if b, ok := d.(govmmQemu.BridgeDevice); ok {
+			b.SHPC = false
+			devices[i] = b
+		}
+	}
+
+	return devices
 }
@@ -7,6 +7,9 @@ package virtcontainers
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	govmmQemu "github.com/intel/govmm/qemu"
@@ -51,3 +54,75 @@ func TestQemuPPC64leMemoryTopology(t *testing.T) {
 	m := ppc64le.memoryTopology(mem, hostMem, slots)
 	assert.Equal(expectedMemory, m)
 }
+
+func TestQemuPPC64leMachineInterruptController(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	savedIcProfile := icProfile
+	defer func() {
+		icProfile = savedIcProfile
+	}()
+
+	icProfile = filepath.Join(tmpdir, "interrupts")
+
+	type testData struct {
+		contents        string
+		expectedOptions string
+	}
+
+	data := []testData{
+		{"", defaultQemuMachineOptions},
+		{" 16:          0   XIVE-IPI   0 Edge      IPI\n", defaultQemuMachineOptions + ",ic-mode=dual"},
+		{" 16:          0   XICS   0 Edge      IPI\n", defaultQemuMachineOptions + ",ic-mode=xics"},
+	}
+
+	ppc64le := newTestQemu(QemuPseries)
+
+	for _, d := range data {
+		err := ioutil.WriteFile(icProfile, []byte(d.contents), os.FileMode(0640))
+		assert.NoError(err)
+
+		m, err := ppc64le.machine()
+		assert.NoError(err)
+		assert.Equal(d.expectedOptions, m.Options)
+	}
+}
+
+func TestQemuPPC64leAppendBridges(t *testing.T) {
+	assert := assert.New(t)
+	ppc64le := newTestQemu(QemuPseries)
+
+	bridges := ppc64le.bridges(2)
+	assert.Len(bridges, 2)
+
+	devices := ppc64le.appendBridges([]govmmQemu.Device{}, bridges)
+	assert.Len(devices, 2)
+
+	for _, d := range devices {
+		b, ok := d.(govmmQemu.BridgeDevice)
+		assert.True(ok)
+		assert.False(b.SHPC)
+	}
+}
+
+func TestQemuPPC64leCPUTopology(t *testing.T) {
+	assert := assert.New(t)
+	ppc64le := newTestQemu(QemuPseries)
+
+	smp := ppc64le.cpuTopology(2, 8)
+	assert.Equal(govmmQemu.SMP{
+		CPUs:    2,
+		Sockets: 1,
+		Cores:   8,
+		Threads: 1,
+		MaxCPUs: 8,
+	}, smp)
+
+	smp = ppc64le.cpuTopology(4, 0)
+	assert.Equal(uint32(4), smp.Cores)
+	assert.Equal(uint32(4), smp.MaxCPUs)
+}
@@ -53,6 +53,12 @@ func MaxQemuVCPUs() uint32 {
 	return uint32(248)
 }
 
+// MaxQemuMachineVCPUs returns the maximum number of vCPUs supported by the
+// given machine type
+func MaxQemuMachineVCPUs(machineType string) uint32 {
+	return MaxQemuVCPUs()
+}
+
 func newQemuArch(config HypervisorConfig) qemuArch {
 	machineType := config.HypervisorMachineType
 	if machineType == "" {
@@ -86,7 +92,7 @@ func (q *qemuS390x) bridges(number uint32) []types.PCIBridge {
 
 // appendBridges appends to devices the given bridges
 func (q *qemuS390x) appendBridges(devices []govmmQemu.Device, bridges []types.PCIBridge) []govmmQemu.Device {
-	return genericAppendBridges(devices, bridges, q.machineType)
+	return genericAppendBridges(devices, bridges, q.machineType, true)
 }
 
 // appendConsole appends a console to devices.
@@ -13,9 +13,11 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	govmmQemu "github.com/intel/govmm/qemu"
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/runtime/virtcontainers/store"
 	"github.com/kata-containers/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,8 @@ func newQemuConfig() HypervisorConfig {
 		BlockDeviceDriver: defaultBlockDriver,
 		DefaultMaxVCPUs:   defaultMaxQemuVCPUs,
 		Msize9p:           defaultMsize9p,
+		Cache9p:           defaultCache9p,
+		Version9p:         defaultVersion9p,
 	}
 }
 
@@ -72,6 +76,67 @@ func TestQemuKernelParameters(t *testing.T) {
 	testQemuKernelParameters(t, params, expectedOut, false)
 }
 
+func TestQemuKernelParametersPTPKVM(t *testing.T) {
+	assert := assert.New(t)
+
+	qemuConfig := newQemuConfig()
+	qemuConfig.EnablePTPKVM = true
+
+	q := &qemu{
+		config: qemuConfig,
+		arch:   &qemuArchBase{},
+	}
+
+	expected := fmt.Sprintf("panic=1 nr_cpus=%d agent.use_vsock=false agent.ptp_kvm=true", MaxQemuVCPUs())
+	assert.Equal(expected, q.kernelParameters())
+}
+
+func TestQemuNewCPUIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{}
+	assert.Equal([]string{"cpu-0", "cpu-1"}, q.newCPUIDs(2))
+
+	// the IDs of the vCPUs that failed to be added are reused
+	q.state.HotpluggedVCPUs = []CPUDevice{{ID: "cpu-0"}, {ID: "cpu-2"}}
+	assert.Equal([]string{"cpu-1", "cpu-3", "cpu-4"}, q.newCPUIDs(3))
+}
+
+func TestQemuDedicatedVCPUs(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{}
+	q.state.HotpluggedVCPUs = []CPUDevice{{ID: "cpu-0", Owner: "ctr"}, {ID: "cpu-1"}}
+
+	// The vCPUs dedicated to a container are not hot removed to shrink
+	// the VM, nor dedicated again.
+	_, err := q.hotplugRemoveCPUs(2)
+	assert.Error(err)
+
+	_, err = q.dedicateVCPUs("other", 2)
+	assert.Error(err)
+
+	_, err = q.dedicateVCPUs("", 1)
+	assert.Error(err)
+
+	assert.Equal([]CPUDevice{{ID: "cpu-0", Owner: "ctr"}, {ID: "cpu-1"}}, q.state.HotpluggedVCPUs)
+}
+
+func TestQemuKernelParametersIOMMU(t *testing.T) {
+	assert := assert.New(t)
+
+	qemuConfig := newQemuConfig()
+	qemuConfig.EnableIOMMU = true
+
+	q := &qemu{
+		config: qemuConfig,
+		arch:   &qemuArchBase{},
+	}
+
+	expected := fmt.Sprintf("panic=1 nr_cpus=%d agent.use_vsock=false agent.hotplug_timeout=10s", MaxQemuVCPUs())
+	assert.Equal(expected, q.kernelParameters())
+}
+
 func TestQemuCreateSandbox(t *testing.T) {
 	qemuConfig := newQemuConfig()
 	q := &qemu{}
@@ -178,6 +243,32 @@ func TestQemuCPUTopology(t *testing.T) {
 	}
 }
 
+func TestQemuExplicitCPUTopology(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{},
+		config: HypervisorConfig{
+			NumVCPUs:        2,
+			DefaultMaxVCPUs: 10,
+			CPUCores:        2,
+			CPUThreads:      2,
+		},
+	}
+
+	// the maximum is rounded down to whole sockets
+	assert.Equal(govmmQemu.SMP{CPUs: 2, Sockets: 2, Cores: 2, Threads: 2, MaxCPUs: 8}, q.cpuTopology())
+
+	// but still holds the boot vCPUs
+	q.config.NumVCPUs = 6
+	q.config.DefaultMaxVCPUs = 6
+	q.config.CPUCores = 4
+	assert.Equal(govmmQemu.SMP{CPUs: 6, Sockets: 1, Cores: 4, Threads: 2, MaxCPUs: 8}, q.cpuTopology())
+
+	q.config.CPUCores = 0
+	assert.Equal(govmmQemu.SMP{CPUs: 6, Sockets: 3, Cores: 1, Threads: 2, MaxCPUs: 6}, q.cpuTopology())
+}
+
 func TestQemuMemoryTopology(t *testing.T) {
 	mem := uint32(1000)
 	slots := uint32(8)
@@ -212,6 +303,218 @@ func TestQemuMemoryTopology(t *testing.T) {
 	}
 }
 
+func TestQemuMemoryTopologyMaxMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	hostMemKb, err := getHostMemorySizeKb(procMemInfo)
+	assert.NoError(err)
+	hostMemMb := hostMemKb / 1024
+
+	q := &qemu{
+		arch: &qemuArchBase{},
+		config: HypervisorConfig{
+			MemorySize:           128,
+			MemSlots:             8,
+			DefaultMaxMemorySize: uint32(hostMemMb / 2),
+		},
+	}
+
+	memory, err := q.memoryTopology()
+	assert.NoError(err)
+	assert.Equal(fmt.Sprintf("%dM", hostMemMb/2), memory.MaxMem)
+
+	// the maximum memory is capped to the host memory
+	q.config.DefaultMaxMemorySize = uint32(hostMemMb * 2)
+	memory, err = q.memoryTopology()
+	assert.NoError(err)
+	assert.Equal(fmt.Sprintf("%dM", hostMemMb), memory.MaxMem)
+}
+
+func TestQemuCPUModel(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{},
+	}
+	assert.Equal(defaultCPUModel, q.cpuModel())
+
+	q.config.CPUFeatures = "-vmx,+avx512f"
+	assert.Equal(defaultCPUModel+",-vmx,+avx512f", q.cpuModel())
+
+	q.config.CPUModel = "Skylake-Server"
+	assert.Equal("Skylake-Server,-vmx,+avx512f", q.cpuModel())
+}
+
+func TestQemuSelectAccelerator(t *testing.T) {
+	assert := assert.New(t)
+
+	savedKVMDevice := kvmDevice
+	defer func() {
+		kvmDevice = savedKVMDevice
+	}()
+	kvmDevice = filepath.Join(testDir, "no-kvm")
+
+	q := &qemu{}
+
+	// default machine options are kept
+	accelerator, err := q.selectAccelerator()
+	assert.NoError(err)
+	assert.Empty(accelerator)
+
+	q.config.Accelerator = kvmAccelerator
+	_, err = q.selectAccelerator()
+	assert.Error(err)
+
+	q.config.AcceleratorTCGFallback = true
+	accelerator, err = q.selectAccelerator()
+	assert.NoError(err)
+	assert.Equal(tcgAccelerator, accelerator)
+
+	q.config.Accelerator = ""
+	accelerator, err = q.selectAccelerator()
+	assert.NoError(err)
+	assert.Equal(tcgAccelerator, accelerator)
+
+	f, err := os.Create(kvmDevice)
+	assert.NoError(err)
+	f.Close()
+	defer os.Remove(kvmDevice)
+
+	accelerator, err = q.selectAccelerator()
+	assert.NoError(err)
+	assert.Equal(kvmAccelerator, accelerator)
+}
+
+func TestQemuTCGAccelerator(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{
+			machineType: QemuPC,
+			supportedQemuMachines: []govmmQemu.Machine{
+				{Type: QemuPC, Options: "accel=kvm,nvdimm"},
+			},
+		},
+		accelerator: tcgAccelerator,
+	}
+
+	machine, err := q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal("accel=tcg,nvdimm", machine.Options)
+
+	assert.Equal(tcgCPUModel, q.cpuModel())
+}
+
+func TestQemuMachineKnobs(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{
+			machineType: QemuPC,
+			supportedQemuMachines: []govmmQemu.Machine{
+				{Type: QemuPC, Options: "accel=kvm,kernel_irqchip,nvdimm"},
+				{Type: QemuVirt, Options: "accel=kvm"},
+			},
+		},
+		config: HypervisorConfig{
+			KernelIRQChip: "split",
+			DisableVMPort: true,
+			DisablePMU:    true,
+		},
+	}
+
+	machine, err := q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal("accel=kvm,nvdimm,kernel_irqchip=split,vmport=off", machine.Options)
+	assert.Equal(defaultCPUModel+",pmu=off", q.cpuModel())
+
+	q.arch.(*qemuArchBase).machineType = QemuVirt
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+
+	q.config.KernelIRQChip = "off"
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+
+	q.config.DisableVMPort = false
+	machine, err = q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal("accel=kvm,kernel_irqchip=off", machine.Options)
+}
+
+func TestQemuSecureBoot(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir(testDir, "firmware-")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	template := filepath.Join(dir, "OVMF_VARS.fd")
+	assert.NoError(ioutil.WriteFile(template, []byte("vars"), 0644))
+
+	q := &qemu{
+		id: "secure-boot",
+		arch: &qemuArchBase{
+			machineType: QemuQ35,
+			supportedQemuMachines: []govmmQemu.Machine{
+				{Type: QemuQ35, Options: "accel=kvm"},
+				{Type: QemuPC, Options: "accel=kvm"},
+			},
+		},
+		config: HypervisorConfig{
+			FirmwareVarsPath: template,
+			SecureBoot:       true,
+		},
+	}
+	defer q.cleanupVM()
+
+	machine, err := q.getQemuMachine()
+	assert.NoError(err)
+	assert.Equal("accel=kvm,smm=on", machine.Options)
+
+	_, err = q.appendFirmwareFlash(nil, machine, "")
+	assert.Error(err)
+
+	vars := filepath.Join(store.RunVMStoragePath, q.id, firmwareVarsFile)
+	devices, err := q.appendFirmwareFlash(nil, machine, "/usr/share/OVMF/OVMF_CODE.fd")
+	assert.NoError(err)
+	assert.Equal([]govmmQemu.Device{
+		pflash{Unit: 0, Path: "/usr/share/OVMF/OVMF_CODE.fd", ReadOnly: true},
+		pflash{Unit: 1, Path: vars},
+		qemuGlobal("cfi.pflash01.secure=on"),
+	}, devices)
+
+	// the template is copied to the VM directory, removed with the VM
+	assert.NoError(os.MkdirAll(filepath.Dir(vars), store.DirMode))
+	assert.NoError(q.copyFirmwareVars())
+	content, err := ioutil.ReadFile(vars)
+	assert.NoError(err)
+	assert.Equal("vars", string(content))
+
+	assert.NoError(q.cleanupVM())
+	_, err = os.Stat(vars)
+	assert.True(os.IsNotExist(err))
+
+	q.config.FirmwareVarsReadOnly = true
+	devices, err = q.appendFirmwareFlash(nil, machine, "/usr/share/OVMF/OVMF_CODE.fd")
+	assert.NoError(err)
+	assert.Equal(pflash{Unit: 1, Path: template, ReadOnly: true}, devices[1])
+
+	assert.Equal([]string{"-drive", "if=pflash,format=raw,unit=0,file=OVMF_CODE.fd,readonly=on"},
+		pflash{Unit: 0, Path: "OVMF_CODE.fd", ReadOnly: true}.QemuParams(nil))
+
+	q.arch.(*qemuArchBase).machineType = QemuPC
+	_, err = q.getQemuMachine()
+	assert.Error(err)
+}
+
+func TestSetMachineOption(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("vmport=off", setMachineOption("", "vmport", "off"))
+	assert.Equal("accel=kvm,kernel_irqchip=on", setMachineOption("accel=kvm,kernel-irqchip=split", "kernel_irqchip", "on"))
+}
+
 func testQemuAddDevice(t *testing.T, devInfo interface{}, devType deviceType, expected []govmmQemu.Device) {
 	q := &qemu{
 		ctx:  context.Background(),
@@ -482,3 +785,47 @@ func TestQemuAddDeviceToBridge(t *testing.T) {
 	exceptErr = errors.New("failed to get available address from bridges")
 	assert.Equal(exceptErr, err)
 }
+
+func TestQemuHotplugAddBlockDeviceFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		config: newQemuConfig(),
+	}
+	q.config.BlockDeviceDriver = config.VirtioBlock
+
+	// A qcow2 image could name any host file as its backing file.
+	drive := &config.BlockDrive{
+		File:   "/var/lib/images/a.qcow2",
+		Format: "qcow2",
+		ID:     "drive-a",
+	}
+	assert.Error(q.hotplugAddBlockDevice(drive, addDevice, "virtio-drive-a"))
+}
+
+func TestHypervisorCommandLine(t *testing.T) {
+	assert := assert.New(t)
+
+	sandboxConfig := SandboxConfig{
+		HypervisorType:   QemuHypervisor,
+		HypervisorConfig: newQemuConfig(),
+	}
+
+	cmdline, err := HypervisorCommandLine(context.Background(), sandboxConfig)
+	assert.NoError(err)
+	assert.Equal(sandboxConfig.HypervisorConfig.HypervisorPath, cmdline[0])
+	assert.Contains(cmdline, "-kernel")
+	assert.Contains(cmdline, "-append")
+
+	// the throw-away sandbox store is removed
+	files, err := ioutil.ReadDir(store.RunStoragePath)
+	if err == nil {
+		for _, f := range files {
+			assert.False(strings.HasPrefix(f.Name(), "dry-run-"), f.Name())
+		}
+	}
+
+	sandboxConfig.HypervisorType = FirecrackerHypervisor
+	_, err = HypervisorCommandLine(context.Background(), sandboxConfig)
+	assert.Error(err)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rbdDevicePrefix is the name prefix of the block devices the kernel RBD
+// client maps, eg. rbd0 or rbd0p1 for a partition.
+const rbdDevicePrefix = "rbd"
+
+var sysDevBlockTemplate = "/sys/dev/block/%d:%d"
+
+var checkRBDDevice = isRBDDevice
+
+// isRBDDevice checks if the device with the major and minor numbers is a
+// block device mapped by the kernel RBD client.
+func isRBDDevice(major, minor int) (bool, error) {
+	target, err := filepath.EvalSymlinks(fmt.Sprintf(sysDevBlockTemplate, major, minor))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return strings.HasPrefix(filepath.Base(target), rbdDevicePrefix), nil
+}
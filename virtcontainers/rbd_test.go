@@ -0,0 +1,53 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRBDDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "sys-dev-block")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedTemplate := sysDevBlockTemplate
+	defer func() {
+		sysDevBlockTemplate = savedTemplate
+	}()
+	sysDevBlockTemplate = filepath.Join(dir, "%d:%d")
+
+	for name, dev := range map[string]string{
+		"rbd0":   "252:0",
+		"rbd0p1": "252:1",
+		"sda":    "8:0",
+	} {
+		target := filepath.Join(dir, "devices", name)
+		assert.NoError(os.MkdirAll(target, 0755))
+		assert.NoError(os.Symlink(target, filepath.Join(dir, dev)))
+	}
+
+	for _, d := range []struct {
+		major, minor int
+		expected     bool
+	}{
+		{252, 0, true},
+		{252, 1, true},
+		{8, 0, false},
+		{8, 16, false},
+	} {
+		isRBD, err := isRBDDevice(d.major, d.minor)
+		assert.NoError(err)
+		assert.Equal(d.expected, isRBD, "%d:%d", d.major, d.minor)
+	}
+}
@@ -0,0 +1,534 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/kata-containers/runtime/protocols/hypervisor"
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+)
+
+// remoteTimeout bounds the calls to the driver, on top of the boot timeout
+// for StartVM.
+const remoteTimeout = 30 * time.Second
+
+// RemoteHypervisorInfo contains the information about the VM of a remote
+// hypervisor that is stored on disk.
+type RemoteHypervisorInfo struct {
+	PID int
+}
+
+// remoteHypervisor is a hypervisor implementation delegating the management
+// of the VM to an out of tree driver, through the HypervisorDriver gRPC
+// protocol served on a unix socket.
+type remoteHypervisor struct {
+	id     string
+	config HypervisorConfig
+	store  *store.VCStore
+	info   RemoteHypervisorInfo
+	caps   types.Capabilities
+
+	conn   *grpc.ClientConn
+	client pb.HypervisorDriverClient
+
+	ctx context.Context
+}
+
+// Logger returns a logrus logger appropriate for logging remote hypervisor
+// messages.
+func (r *remoteHypervisor) Logger() *logrus.Entry {
+	return virtLog.WithFields(logrus.Fields{
+		"subsystem": "remote-hypervisor",
+		"socket":    r.config.RemoteHypervisorSocket,
+	})
+}
+
+func (r *remoteHypervisor) trace(name string) (opentracing.Span, context.Context) {
+	if r.ctx == nil {
+		r.Logger().WithField("type", "bug").Error("trace called before context set")
+		r.ctx = context.Background()
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(r.ctx, name)
+
+	span.SetTag("subsystem", "hypervisor")
+	span.SetTag("type", "remote")
+
+	return span, ctx
+}
+
+func (r *remoteHypervisor) connect() (pb.HypervisorDriverClient, error) {
+	if r.client != nil {
+		return r.client, nil
+	}
+
+	conn, err := grpc.Dial(r.config.RemoteHypervisorSocket, grpc.WithInsecure(),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout("unix", addr, timeout)
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to the hypervisor driver %q: %v", r.config.RemoteHypervisorSocket, err)
+	}
+
+	r.conn = conn
+	r.client = pb.NewHypervisorDriverClient(conn)
+
+	return r.client, nil
+}
+
+// call runs a driver call, timing it out after remoteTimeout plus extra.
+func (r *remoteHypervisor) call(name string, extra time.Duration, fn func(context.Context, pb.HypervisorDriverClient) error) error {
+	span, ctx := r.trace(name)
+	defer span.Finish()
+
+	client, err := r.connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout+extra)
+	defer cancel()
+
+	if err := fn(ctx, client); err != nil {
+		return fmt.Errorf("hypervisor driver %s failed: %v", name, err)
+	}
+
+	return nil
+}
+
+// currentNetNS returns the path of the network namespace of the calling
+// thread, valid as long as the thread does not leave it.
+func currentNetNS() string {
+	return fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid())
+}
+
+func (r *remoteHypervisor) createSandbox(ctx context.Context, id string, hypervisorConfig *HypervisorConfig, vcStore *store.VCStore) error {
+	r.ctx = ctx
+
+	if hypervisorConfig.RemoteHypervisorSocket == "" {
+		return errors.New("Missing remote hypervisor socket")
+	}
+
+	if err := hypervisorConfig.selectGuestBoot(); err != nil {
+		return err
+	}
+
+	r.id = id
+	r.store = vcStore
+	r.config = *hypervisorConfig
+
+	// Nothing is stored yet for a new sandbox.
+	if err := r.store.Load(store.Hypervisor, &r.info); err != nil {
+		r.Logger().WithError(err).Info("No info could be fetched")
+	}
+
+	var caps *pb.CapabilitiesResponse
+	err := r.call("Capabilities", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		caps, err = c.Capabilities(ctx, &pb.CapabilitiesRequest{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if caps.Version != pb.Version {
+		return fmt.Errorf("Hypervisor driver protocol version %d is not supported, expecting %d", caps.Version, pb.Version)
+	}
+
+	r.caps = types.Capabilities{}
+	if caps.BlockDevice {
+		r.caps.SetBlockDeviceSupport()
+	}
+	if caps.BlockDeviceHotplug {
+		r.caps.SetBlockDeviceHotplugSupport()
+	}
+	if caps.MultiQueue {
+		r.caps.SetMultiQueueSupport()
+	}
+	if !caps.FsSharing {
+		r.caps.SetFsSharingUnsupported()
+	}
+
+	vmConfig, err := r.vmConfig()
+	if err != nil {
+		return err
+	}
+
+	return r.call("CreateVM", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.CreateVM(ctx, &pb.CreateVMRequest{
+			Id:     r.id,
+			Config: vmConfig,
+		})
+		return err
+	})
+}
+
+func (r *remoteHypervisor) vmConfig() (*pb.VMConfig, error) {
+	kernel, err := r.config.KernelAssetPath()
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := r.config.ImageAssetPath()
+	if err != nil {
+		return nil, err
+	}
+
+	initrd, err := r.config.InitrdAssetPath()
+	if err != nil {
+		return nil, err
+	}
+
+	firmware, err := r.config.FirmwareAssetPath()
+	if err != nil {
+		return nil, err
+	}
+
+	params := r.config.KernelParams
+
+	return &pb.VMConfig{
+		Kernel:            kernel,
+		KernelParams:      strings.Join(SerializeParams(params, "="), " "),
+		Image:             image,
+		Initrd:            initrd,
+		Firmware:          firmware,
+		Vcpus:             r.config.NumVCPUs,
+		MaxVcpus:          r.config.DefaultMaxVCPUs,
+		MemoryMb:          r.config.MemorySize,
+		MaxMemoryMb:       r.config.DefaultMaxMemorySize,
+		BlockDeviceDriver: r.config.BlockDeviceDriver,
+		Hugepages:         r.config.HugePages,
+		Debug:             r.config.Debug,
+	}, nil
+}
+
+func (r *remoteHypervisor) vmRequest() *pb.VMRequest {
+	return &pb.VMRequest{Id: r.id}
+}
+
+func (r *remoteHypervisor) startSandbox(timeout int) error {
+	var resp *pb.StartVMResponse
+	err := r.call("StartVM", time.Duration(timeout)*time.Second, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.StartVM(ctx, &pb.StartVMRequest{
+			Id:      r.id,
+			Timeout: uint32(timeout),
+			// The VM is started from the sandbox network namespace.
+			Netns: currentNetNS(),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.info.PID = int(resp.Pid)
+
+	return r.store.Store(store.Hypervisor, r.info)
+}
+
+func (r *remoteHypervisor) stopSandbox() error {
+	err := r.call("StopVM", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.StopVM(ctx, r.vmRequest())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.info.PID = 0
+
+	return r.store.Store(store.Hypervisor, r.info)
+}
+
+func (r *remoteHypervisor) pauseSandbox() error {
+	return r.call("PauseVM", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.PauseVM(ctx, r.vmRequest())
+		return err
+	})
+}
+
+func (r *remoteHypervisor) saveSandbox() error {
+	return r.call("SaveVM", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.SaveVM(ctx, r.vmRequest())
+		return err
+	})
+}
+
+func (r *remoteHypervisor) resumeSandbox() error {
+	return r.call("ResumeVM", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.ResumeVM(ctx, r.vmRequest())
+		return err
+	})
+}
+
+// remoteDevice converts a device to its protocol description.
+func remoteDevice(devInfo interface{}) (*pb.Device, error) {
+	switch v := devInfo.(type) {
+	case config.BlockDrive:
+		return remoteDevice(&v)
+	case *config.BlockDrive:
+		return &pb.Device{Block: &pb.BlockDevice{
+			Id:     v.ID,
+			File:   v.File,
+			Format: v.Format,
+			Index:  int64(v.Index),
+		}}, nil
+	case config.VFIODev:
+		return remoteDevice(&v)
+	case *config.VFIODev:
+		return &pb.Device{Vfio: &pb.VFIODevice{
+			Id:       v.ID,
+			Bdf:      v.BDF,
+			SysfsDev: v.SysfsDev,
+		}}, nil
+	case Endpoint:
+		netDev := &pb.NetDevice{
+			Name:   v.Name(),
+			HwAddr: v.HardwareAddr(),
+			Type:   string(v.Type()),
+		}
+		if pair := v.NetworkPair(); pair != nil {
+			netDev.TapName = pair.TAPIface.Name
+		}
+		return &pb.Device{Net: netDev}, nil
+	case types.Volume:
+		return &pb.Device{Fs: &pb.SharedFS{
+			MountTag: v.MountTag,
+			HostPath: v.HostPath,
+		}}, nil
+	case types.Socket:
+		return &pb.Device{Serial: &pb.SerialPort{
+			Id:       v.ID,
+			Name:     v.Name,
+			HostPath: v.HostPath,
+		}}, nil
+	case kataVSOCK:
+		// The vhost-vsock file descriptor reserving the context ID
+		// cannot be handed over to the driver, the agent is reached
+		// through its serial port.
+		return nil, errors.New("vhost-vsock is not supported by the remote hypervisor")
+	case uint32:
+		return &pb.Device{Vcpus: v}, nil
+	case *memoryDevice:
+		return &pb.Device{Memory: &pb.MemoryDevice{
+			Slot:   int64(v.slot),
+			SizeMb: int64(v.sizeMB),
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported device %T for the remote hypervisor", devInfo)
+}
+
+func (r *remoteHypervisor) deviceRequest(devInfo interface{}) (*pb.DeviceRequest, error) {
+	dev, err := remoteDevice(devInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.DeviceRequest{
+		Id:     r.id,
+		Device: dev,
+	}
+
+	// The network endpoints are attached from the sandbox network
+	// namespace.
+	if dev.Net != nil {
+		req.Netns = currentNetNS()
+	}
+
+	return req, nil
+}
+
+func (r *remoteHypervisor) addDevice(devInfo interface{}, devType deviceType) error {
+	req, err := r.deviceRequest(devInfo)
+	if err != nil {
+		return err
+	}
+
+	return r.call("AddDevice", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.AddDevice(ctx, req)
+		return err
+	})
+}
+
+// hotplugResult returns what the other hypervisors return from the
+// hotplug of the device types.
+func hotplugResult(resp *pb.HotplugResponse, devType deviceType) interface{} {
+	switch devType {
+	case cpuDev:
+		return resp.Vcpus
+	case memoryDev:
+		return int(resp.MemoryMb)
+	}
+
+	return nil
+}
+
+func (r *remoteHypervisor) hotplugAddDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
+	req, err := r.deviceRequest(devInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *pb.HotplugResponse
+	err = r.call("HotplugAddDevice", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.HotplugAddDevice(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hotplugResult(resp, devType), nil
+}
+
+func (r *remoteHypervisor) hotplugRemoveDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
+	req, err := r.deviceRequest(devInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *pb.HotplugResponse
+	err = r.call("HotplugRemoveDevice", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.HotplugRemoveDevice(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hotplugResult(resp, devType), nil
+}
+
+func (r *remoteHypervisor) resizeMemory(memMB uint32, memoryBlockSizeMB uint32) (uint32, error) {
+	var resp *pb.ResizeMemoryResponse
+	err := r.call("ResizeMemory", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.ResizeMemory(ctx, &pb.ResizeMemoryRequest{
+			Id:                r.id,
+			MemoryMb:          memMB,
+			MemoryBlockSizeMb: memoryBlockSizeMB,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.MemoryMb, nil
+}
+
+func (r *remoteHypervisor) resizeVCPUs(vcpus uint32) (uint32, uint32, error) {
+	var resp *pb.ResizeVCPUsResponse
+	err := r.call("ResizeVCPUs", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.ResizeVCPUs(ctx, &pb.ResizeVCPUsRequest{
+			Id:    r.id,
+			Vcpus: vcpus,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return resp.OldVcpus, resp.NewVcpus, nil
+}
+
+func (r *remoteHypervisor) dedicateVCPUs(owner string, count uint32) ([]int, error) {
+	return nil, fmt.Errorf("the remote hypervisor does not support dedicating vCPUs")
+}
+
+func (r *remoteHypervisor) releaseVCPUs(owner string) (uint32, error) {
+	return 0, nil
+}
+
+func (r *remoteHypervisor) getSandboxConsole(sandboxID string) (string, error) {
+	var resp *pb.GetConsoleResponse
+	err := r.call("GetConsole", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.GetConsole(ctx, r.vmRequest())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Url, nil
+}
+
+func (r *remoteHypervisor) disconnect() {
+	if r.conn == nil {
+		return
+	}
+
+	if err := r.conn.Close(); err != nil {
+		r.Logger().WithError(err).Warn("Could not close the driver connection")
+	}
+
+	r.conn = nil
+	r.client = nil
+}
+
+func (r *remoteHypervisor) capabilities() types.Capabilities {
+	return r.caps
+}
+
+func (r *remoteHypervisor) hypervisorConfig() HypervisorConfig {
+	return r.config
+}
+
+func (r *remoteHypervisor) getThreadIDs() (vcpuThreadIDs, error) {
+	vcpuInfo := vcpuThreadIDs{
+		vcpus: make(map[int]int),
+	}
+
+	var resp *pb.GetThreadIDsResponse
+	err := r.call("GetThreadIDs", 0, func(ctx context.Context, c pb.HypervisorDriverClient) (err error) {
+		resp, err = c.GetThreadIDs(ctx, r.vmRequest())
+		return err
+	})
+	if err != nil {
+		return vcpuInfo, err
+	}
+
+	for vcpu, tid := range resp.Vcpus {
+		vcpuInfo.vcpus[int(vcpu)] = int(tid)
+	}
+
+	return vcpuInfo, nil
+}
+
+func (r *remoteHypervisor) cleanup() error {
+	defer r.disconnect()
+
+	return r.call("Cleanup", 0, func(ctx context.Context, c pb.HypervisorDriverClient) error {
+		_, err := c.Cleanup(ctx, r.vmRequest())
+		return err
+	})
+}
+
+func (r *remoteHypervisor) pid() int {
+	return r.info.PID
+}
+
+func (r *remoteHypervisor) fromGrpc(ctx context.Context, hypervisorConfig *HypervisorConfig, store *store.VCStore, j []byte) error {
+	return errors.New("remote hypervisor is not supported by VM cache")
+}
+
+func (r *remoteHypervisor) toGrpc() ([]byte, error) {
+	return nil, errors.New("remote hypervisor is not supported by VM cache")
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/kata-containers/runtime/protocols/hypervisor"
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/kata-containers/runtime/virtcontainers/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// mockDriver is a HypervisorDriver recording the requests it is sent.
+type mockDriver struct {
+	version uint32
+	config  *pb.VMConfig
+	started bool
+	devices []*pb.DeviceRequest
+	vcpus   uint32
+}
+
+func (d *mockDriver) Capabilities(ctx context.Context, req *pb.CapabilitiesRequest) (*pb.CapabilitiesResponse, error) {
+	return &pb.CapabilitiesResponse{
+		Version:     d.version,
+		BlockDevice: true,
+		FsSharing:   true,
+	}, nil
+}
+
+func (d *mockDriver) CreateVM(ctx context.Context, req *pb.CreateVMRequest) (*pb.VMResponse, error) {
+	d.config = req.Config
+	return &pb.VMResponse{}, nil
+}
+
+func (d *mockDriver) StartVM(ctx context.Context, req *pb.StartVMRequest) (*pb.StartVMResponse, error) {
+	d.started = true
+	return &pb.StartVMResponse{Pid: 1234}, nil
+}
+
+func (d *mockDriver) StopVM(ctx context.Context, req *pb.VMRequest) (*pb.VMResponse, error) {
+	d.started = false
+	return &pb.VMResponse{}, nil
+}
+
+func (d *mockDriver) PauseVM(ctx context.Context, req *pb.VMRequest) (*pb.VMResponse, error) {
+	return &pb.VMResponse{}, nil
+}
+
+func (d *mockDriver) ResumeVM(ctx context.Context, req *pb.VMRequest) (*pb.VMResponse, error) {
+	return &pb.VMResponse{}, nil
+}
+
+func (d *mockDriver) SaveVM(ctx context.Context, req *pb.VMRequest) (*pb.VMResponse, error) {
+	return nil, errors.New("not supported")
+}
+
+func (d *mockDriver) AddDevice(ctx context.Context, req *pb.DeviceRequest) (*pb.VMResponse, error) {
+	d.devices = append(d.devices, req)
+	return &pb.VMResponse{}, nil
+}
+
+func (d *mockDriver) HotplugAddDevice(ctx context.Context, req *pb.DeviceRequest) (*pb.HotplugResponse, error) {
+	d.devices = append(d.devices, req)
+	return &pb.HotplugResponse{Vcpus: req.Device.Vcpus}, nil
+}
+
+func (d *mockDriver) HotplugRemoveDevice(ctx context.Context, req *pb.DeviceRequest) (*pb.HotplugResponse, error) {
+	return &pb.HotplugResponse{Vcpus: req.Device.Vcpus}, nil
+}
+
+func (d *mockDriver) ResizeMemory(ctx context.Context, req *pb.ResizeMemoryRequest) (*pb.ResizeMemoryResponse, error) {
+	return &pb.ResizeMemoryResponse{MemoryMb: req.MemoryMb}, nil
+}
+
+func (d *mockDriver) ResizeVCPUs(ctx context.Context, req *pb.ResizeVCPUsRequest) (*pb.ResizeVCPUsResponse, error) {
+	old := d.vcpus
+	d.vcpus = req.Vcpus
+	return &pb.ResizeVCPUsResponse{OldVcpus: old, NewVcpus: req.Vcpus}, nil
+}
+
+func (d *mockDriver) GetConsole(ctx context.Context, req *pb.VMRequest) (*pb.GetConsoleResponse, error) {
+	return &pb.GetConsoleResponse{Url: "unix:///run/vc/vm/" + req.Id + "/console.sock"}, nil
+}
+
+func (d *mockDriver) GetThreadIDs(ctx context.Context, req *pb.VMRequest) (*pb.GetThreadIDsResponse, error) {
+	return &pb.GetThreadIDsResponse{Vcpus: map[uint32]int64{0: 100, 1: 101}}, nil
+}
+
+func (d *mockDriver) Cleanup(ctx context.Context, req *pb.VMRequest) (*pb.VMResponse, error) {
+	return &pb.VMResponse{}, nil
+}
+
+func startMockDriver(t *testing.T, driver *mockDriver) (string, func()) {
+	dir, err := ioutil.TempDir("", "remote-hypervisor")
+	assert.NoError(t, err)
+
+	socket := filepath.Join(dir, "driver.sock")
+	l, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterHypervisorDriverServer(server, driver)
+	go server.Serve(l)
+
+	return socket, func() {
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func newRemoteTestHypervisor(t *testing.T, driver *mockDriver) (*remoteHypervisor, *HypervisorConfig, *store.VCStore, func()) {
+	socket, stop := startMockDriver(t, driver)
+
+	hConfig := newQemuConfig()
+	hConfig.RemoteHypervisorSocket = socket
+	hConfig.HypervisorPath = ""
+
+	vcStore, err := store.NewVCSandboxStore(context.Background(), testSandboxID)
+	assert.NoError(t, err)
+
+	r := &remoteHypervisor{}
+
+	return r, &hConfig, vcStore, func() {
+		r.disconnect()
+		stop()
+		vcStore.Delete()
+	}
+}
+
+func TestRemoteHypervisorCreateSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	driver := &mockDriver{version: pb.Version}
+	r, hConfig, vcStore, cleanup := newRemoteTestHypervisor(t, driver)
+	defer cleanup()
+
+	err := r.createSandbox(context.Background(), testSandboxID, hConfig, vcStore)
+	assert.NoError(err)
+
+	assert.NotNil(driver.config)
+	assert.Equal(testQemuKernelPath, driver.config.Kernel)
+	assert.Equal(hConfig.NumVCPUs, driver.config.Vcpus)
+	assert.Equal(hConfig.MemorySize, driver.config.MemoryMb)
+
+	caps := r.capabilities()
+	assert.True(caps.IsBlockDeviceSupported())
+	assert.False(caps.IsBlockDeviceHotplugSupported())
+	assert.True(caps.IsFsSharingSupported())
+
+	// A driver implementing another version of the protocol is refused.
+	driver.version = pb.Version + 1
+	r.disconnect()
+	err = r.createSandbox(context.Background(), testSandboxID, hConfig, vcStore)
+	assert.Error(err)
+
+	hConfig.RemoteHypervisorSocket = ""
+	err = r.createSandbox(context.Background(), testSandboxID, hConfig, vcStore)
+	assert.Error(err)
+}
+
+func TestRemoteHypervisorLifecycle(t *testing.T) {
+	assert := assert.New(t)
+
+	driver := &mockDriver{version: pb.Version, vcpus: 1}
+	r, hConfig, vcStore, cleanup := newRemoteTestHypervisor(t, driver)
+	defer cleanup()
+
+	assert.NoError(r.createSandbox(context.Background(), testSandboxID, hConfig, vcStore))
+
+	assert.NoError(r.startSandbox(vmStartTimeout))
+	assert.True(driver.started)
+	assert.Equal(1234, r.pid())
+
+	assert.NoError(r.pauseSandbox())
+	assert.NoError(r.resumeSandbox())
+	assert.Error(r.saveSandbox())
+
+	oldVCPUs, newVCPUs, err := r.resizeVCPUs(4)
+	assert.NoError(err)
+	assert.Equal(uint32(1), oldVCPUs)
+	assert.Equal(uint32(4), newVCPUs)
+
+	mem, err := r.resizeMemory(4096, 128)
+	assert.NoError(err)
+	assert.Equal(uint32(4096), mem)
+
+	added, err := r.hotplugAddDevice(uint32(2), cpuDev)
+	assert.NoError(err)
+	assert.Equal(uint32(2), added)
+
+	drive := &config.BlockDrive{ID: "drive-1", File: "/dev/loop0", Format: "raw"}
+	_, err = r.hotplugAddDevice(drive, blockDev)
+	assert.NoError(err)
+	assert.Equal("/dev/loop0", driver.devices[len(driver.devices)-1].Device.Block.File)
+
+	console, err := r.getSandboxConsole(testSandboxID)
+	assert.NoError(err)
+	assert.Equal("unix:///run/vc/vm/"+testSandboxID+"/console.sock", console)
+
+	tids, err := r.getThreadIDs()
+	assert.NoError(err)
+	assert.Equal(map[int]int{0: 100, 1: 101}, tids.vcpus)
+
+	assert.NoError(r.stopSandbox())
+	assert.False(driver.started)
+	assert.Equal(0, r.pid())
+
+	assert.NoError(r.cleanup())
+}
+
+func TestRemoteDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	dev, err := remoteDevice(config.BlockDrive{ID: "drive-1", File: "/dev/sda", Index: 2})
+	assert.NoError(err)
+	assert.Equal("drive-1", dev.Block.Id)
+	assert.Equal(int64(2), dev.Block.Index)
+
+	dev, err = remoteDevice(config.VFIODev{BDF: "02:10.0"})
+	assert.NoError(err)
+	assert.Equal("02:10.0", dev.Vfio.Bdf)
+
+	dev, err = remoteDevice(types.Volume{MountTag: "kataShared", HostPath: "/run/shared"})
+	assert.NoError(err)
+	assert.Equal("kataShared", dev.Fs.MountTag)
+
+	dev, err = remoteDevice(&memoryDevice{slot: 1, sizeMB: 256})
+	assert.NoError(err)
+	assert.Equal(int64(256), dev.Memory.SizeMb)
+
+	_, err = remoteDevice(kataVSOCK{})
+	assert.Error(err)
+
+	_, err = remoteDevice("foo")
+	assert.Error(err)
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -53,6 +54,9 @@ type SandboxStatus struct {
 	// for example to add additional status values required
 	// to support particular specifications.
 	Annotations map[string]string
+
+	// Labels are the key/value pairs the sandbox was tagged with.
+	Labels map[string]string
 }
 
 // SandboxConfig is a Sandbox configuration.
@@ -88,6 +92,10 @@ type SandboxConfig struct {
 	// with e.g. reverse domain notation (org.clearlinux.key).
 	Annotations map[string]string
 
+	// Labels are arbitrary key/value pairs tagging the sandbox, eg. with
+	// its tenant, for the tools listing the sandboxes to select them.
+	Labels map[string]string
+
 	ShmSize uint64
 
 	// SharePidNs sets all containers to share the same sandbox level pid namespace.
@@ -102,8 +110,56 @@ type SandboxConfig struct {
 
 	DisableGuestSeccomp bool
 
+	// IdlePauseTimeout is the number of seconds after which an idle
+	// sandbox VM is paused, until the next agent request. The VM is
+	// never paused when it is 0.
+	IdlePauseTimeout uint32
+
+	// KSM is the host KSM policy applied when the sandbox is created.
+	KSM KSMConfig
+
+	// SlowOperationThreshold is the number of seconds after which a
+	// container creation, a device attach or an agent request still
+	// running is logged, along with the goroutine stacks. Nothing is
+	// logged when it is 0.
+	SlowOperationThreshold uint32
+
+	// VolumeSourceTimeout is the number of seconds a missing container
+	// volume source is waited for, eg. a CSI ephemeral volume not yet
+	// published by the kubelet. The container creation fails right away
+	// when it is 0.
+	VolumeSourceTimeout uint32
+
+	// Audit is where the privileged host operations of the sandbox are
+	// recorded.
+	Audit AuditConfig
+
+	// HostHooks are the host binaries run at the points of the sandbox
+	// lifecycle.
+	HostHooks HostHooksConfig
+
+	// PrivilegedWithoutHostDevices keeps the host devices a privileged
+	// container lists away from the VM, the container remains privileged
+	// in the guest.
+	PrivilegedWithoutHostDevices bool
+
+	// StrictOCISpec fails the creation of the containers whose spec has
+	// fields the guest does not apply, instead of dropping them.
+	StrictOCISpec bool
+
+	// EnableAnnotations are the regular expressions the names of the
+	// virtcontainers annotations of the containers must match, without
+	// the annotations prefix. The other ones are ignored. Only the guest
+	// asset annotations are enabled when it is nil.
+	EnableAnnotations []string
+
 	// Experimental features enabled
 	Experimental []exp.Feature
+
+	// MockHooks script the mock hypervisor and the noop agent of the
+	// sandbox. They are not stored, so they only apply to the sandbox
+	// kept in memory by the caller, eg. a stateful one.
+	MockHooks *MockHooks `json:"-"`
 }
 
 func (s *Sandbox) trace(name string) (opentracing.Span, context.Context) {
@@ -186,6 +242,23 @@ type Sandbox struct {
 	stateful         bool
 	seccompSupported bool
 
+	// scratchDisk is the block drive backing the guest local storage,
+	// only set by the process starting the VM. The device itself is
+	// tracked by the device manager, see scratchDiskDevice().
+	scratchDisk *config.BlockDrive
+
+	idle *idleTracker
+
+	reclaim *memoryReclaimer
+
+	watchdog *slowOpWatchdog
+
+	audit *auditLog
+
+	// hypervisorPid is the hypervisor process started by startVM, kept
+	// since QEMU removes its pid file when it exits.
+	hypervisorPid int
+
 	ctx context.Context
 }
 
@@ -202,6 +275,22 @@ func (s *Sandbox) Logger() *logrus.Entry {
 	})
 }
 
+// containerAnnotation returns the value of an annotation of a container spec,
+// unless it is a virtcontainers annotation the sandbox does not enable.
+func (s *Sandbox) containerAnnotation(spec *specs.Spec, key string) (string, bool) {
+	value, ok := spec.Annotations[key]
+	if !ok {
+		return "", false
+	}
+
+	if !annotations.Enabled(s.config.EnableAnnotations, key) {
+		s.Logger().WithField("annotation", key).Warn("annotation not enabled by the runtime configuration, ignoring it")
+		return "", false
+	}
+
+	return value, true
+}
+
 // Annotations returns any annotation that a user could have stored through the sandbox.
 func (s *Sandbox) Annotations(key string) (string, error) {
 	value, exist := s.config.Annotations[key]
@@ -307,6 +396,7 @@ func (s *Sandbox) Status() SandboxStatus {
 		Agent:            s.config.AgentType,
 		ContainersStatus: contStatusList,
 		Annotations:      s.config.Annotations,
+		Labels:           s.config.Labels,
 	}
 }
 
@@ -325,6 +415,22 @@ func (s *Sandbox) Monitor() (chan error, error) {
 	return s.monitor.newWatcher()
 }
 
+// HypervisorExited tells whether the hypervisor process of the running
+// sandbox is gone. It is false when the hypervisor pid is unknown, which
+// is the case of the sandboxes fetched from their storage.
+func (s *Sandbox) HypervisorExited() bool {
+	if s.state.State != types.StateRunning && s.state.State != types.StatePaused {
+		return false
+	}
+
+	pid := s.hypervisorPid
+	if pid <= 0 {
+		return false
+	}
+
+	return syscall.Kill(pid, syscall.Signal(0)) == syscall.ESRCH
+}
+
 // WaitProcess waits on a container process and return its exit code
 func (s *Sandbox) WaitProcess(containerID, processID string) (int32, error) {
 	if s.state.State != types.StateRunning {
@@ -445,6 +551,8 @@ func createSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Fac
 	span, ctx := trace(ctx, "createSandbox")
 	defer span.Finish()
 
+	createStart := time.Now()
+
 	if err := createAssets(ctx, &sandboxConfig); err != nil {
 		return nil, err
 	}
@@ -485,6 +593,16 @@ func createSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Fac
 		return nil, err
 	}
 
+	if err := s.addKdumpDisk(); err != nil {
+		return nil, err
+	}
+
+	if err := s.addVhostUserSCSIController(); err != nil {
+		return nil, err
+	}
+
+	s.state.BootTimeline.Record(types.BootPhaseCreate, createStart)
+
 	// Set sandbox state
 	if err := s.setSandboxState(types.StateReady); err != nil {
 		return nil, err
@@ -508,6 +626,10 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		return nil, err
 	}
 
+	if m, ok := hypervisor.(*mockHypervisor); ok {
+		m.hooks = sandboxConfig.MockHooks
+	}
+
 	s := &Sandbox{
 		id:              sandboxConfig.ID,
 		factory:         factory,
@@ -534,6 +656,16 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 
 	s.store = vcStore
 
+	if sandboxConfig.IdlePauseTimeout > 0 {
+		s.idle = newIdleTracker(s, time.Duration(sandboxConfig.IdlePauseTimeout)*time.Second)
+	}
+
+	if sandboxConfig.SlowOperationThreshold > 0 {
+		s.watchdog = newSlowOpWatchdog(s, time.Duration(sandboxConfig.SlowOperationThreshold)*time.Second)
+	}
+
+	s.audit = newAuditLog(s, sandboxConfig.Audit)
+
 	if err = globalSandboxList.addSandbox(s); err != nil {
 		return nil, err
 	}
@@ -555,6 +687,9 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		return nil, err
 	}
 
+	// the hypervisor has set the policy defaults
+	s.reclaim = newMemoryReclaimer(s, sandboxConfig.HypervisorConfig.MemoryReclaim)
+
 	agentConfig := newAgentConfig(sandboxConfig.AgentType, sandboxConfig.AgentConfig)
 	if err = s.agent.init(ctx, s, agentConfig); err != nil {
 		return nil, err
@@ -710,6 +845,12 @@ func (s *Sandbox) Delete() error {
 		return fmt.Errorf("Sandbox not ready, paused or stopped, impossible to delete")
 	}
 
+	// The delete hooks failures are only logged, see
+	// HostHookPreSandboxDelete.
+	if err := s.runHostHooks(HostHookPreSandboxDelete); err != nil {
+		s.Logger().WithError(err).Warn("Host hooks failed")
+	}
+
 	for _, c := range s.containers {
 		if err := c.delete(); err != nil {
 			return err
@@ -732,6 +873,14 @@ func (s *Sandbox) Delete() error {
 
 	s.agent.cleanup(s.id)
 
+	if err := s.collectKdump(); err != nil {
+		s.Logger().WithError(err).Warn("failed to collect guest kernel crash dump")
+	}
+
+	if err := s.config.KSM.throttleKSM(time.Now()); err != nil {
+		s.Logger().WithError(err).Warn("failed to throttle KSM")
+	}
+
 	return s.store.Delete()
 }
 
@@ -788,6 +937,7 @@ func (s *Sandbox) createNetwork() error {
 	if s.factory == nil {
 		// Add the network
 		endpoints, err := s.network.Add(s.ctx, &s.config.NetworkConfig, s.hypervisor, false)
+		s.auditEndpoints("attach-endpoint", endpoints, err)
 		if err != nil {
 			return err
 		}
@@ -815,7 +965,10 @@ func (s *Sandbox) removeNetwork() error {
 		}
 	}
 
-	return s.network.Remove(s.ctx, &s.networkNS, s.hypervisor, s.factory != nil)
+	err := s.network.Remove(s.ctx, &s.networkNS, s.hypervisor, s.factory != nil)
+	s.auditEndpoints("detach-endpoint", s.networkNS.Endpoints, err)
+
+	return err
 }
 
 func (s *Sandbox) generateNetInfo(inf *vcTypes.Interface) (NetworkInfo, error) {
@@ -861,10 +1014,12 @@ func (s *Sandbox) AddInterface(inf *vcTypes.Interface) (*vcTypes.Interface, erro
 	}
 
 	endpoint.SetProperties(netInfo)
-	if err := doNetNS(s.networkNS.NetNsPath, func(_ ns.NetNS) error {
+	err = doNetNS(s.networkNS.NetNsPath, func(_ ns.NetNS) error {
 		s.Logger().WithField("endpoint-type", endpoint.Type()).Info("Hot attaching endpoint")
 		return endpoint.HotAttach(s.hypervisor)
-	}); err != nil {
+	})
+	s.auditEndpoints("hot-attach-endpoint", []Endpoint{endpoint}, err)
+	if err != nil {
 		return nil, err
 	}
 
@@ -883,8 +1038,17 @@ func (s *Sandbox) AddInterface(inf *vcTypes.Interface) (*vcTypes.Interface, erro
 func (s *Sandbox) RemoveInterface(inf *vcTypes.Interface) (*vcTypes.Interface, error) {
 	for i, endpoint := range s.networkNS.Endpoints {
 		if endpoint.HardwareAddr() == inf.HwAddr {
+			// Remove the nic configuration from the guest before
+			// unplugging it. A failure does not prevent the hot detach,
+			// which releases the host TAP and bridge.
+			if err := s.agent.removeInterface(inf); err != nil {
+				s.Logger().WithError(err).Warn("Could not remove the interface from the guest")
+			}
+
 			s.Logger().WithField("endpoint-type", endpoint.Type()).Info("Hot detaching endpoint")
-			if err := endpoint.HotDetach(s.hypervisor, s.networkNS.NetNsCreated, s.networkNS.NetNsPath); err != nil {
+			err := endpoint.HotDetach(s.hypervisor, s.networkNS.NetNsCreated, s.networkNS.NetNsPath)
+			s.auditEndpoints("hot-detach-endpoint", []Endpoint{endpoint}, err)
+			if err != nil {
 				return inf, err
 			}
 			s.networkNS.Endpoints = append(s.networkNS.Endpoints[:i], s.networkNS.Endpoints[i+1:]...)
@@ -919,6 +1083,10 @@ func (s *Sandbox) startVM() (err error) {
 
 	s.Logger().Info("Starting VM")
 
+	if err := s.runHostHooks(HostHookPreVMStart); err != nil {
+		return err
+	}
+
 	if err := s.network.Run(s.networkNS.NetNsPath, func() error {
 		if s.factory != nil {
 			vm, err := s.factory.GetVM(ctx, VMConfig{
@@ -939,21 +1107,29 @@ func (s *Sandbox) startVM() (err error) {
 			return nil
 		}
 
-		return s.hypervisor.startSandbox(vmStartTimeout)
+		return classifyError(ErrorClassHypervisorLaunch, s.hypervisor.startSandbox(vmStartTimeout))
 	}); err != nil {
 		return err
 	}
 
+	s.recordBootPhase(types.BootPhaseHypervisor)
+	s.hypervisorPid = s.hypervisor.pid()
+
 	defer func() {
 		if err != nil {
 			s.hypervisor.stopSandbox()
 		}
 	}()
 
+	if err := s.setupCoreScheduling(); err != nil {
+		return err
+	}
+
 	// In case of vm factory, network interfaces are hotplugged
 	// after vm is started.
 	if s.factory != nil {
 		endpoints, err := s.network.Add(s.ctx, &s.config.NetworkConfig, s.hypervisor, true)
+		s.auditEndpoints("hot-attach-endpoint", endpoints, err)
 		if err != nil {
 			return err
 		}
@@ -972,6 +1148,14 @@ func (s *Sandbox) startVM() (err error) {
 
 	s.Logger().Info("VM started")
 
+	if err := s.config.KSM.boostKSM(); err != nil {
+		s.Logger().WithError(err).Warn("failed to boost KSM")
+	}
+
+	if err := s.attachScratchDisk(); err != nil {
+		return err
+	}
+
 	// Once the hypervisor is done starting the sandbox,
 	// we want to guarantee that it is manageable.
 	// For that we need to ask the agent to start the
@@ -982,7 +1166,9 @@ func (s *Sandbox) startVM() (err error) {
 
 	s.Logger().Info("Agent started in the sandbox")
 
-	return nil
+	s.recordBootPhase(types.BootPhaseAgent)
+
+	return s.runHostHooks(HostHookPostVMStart)
 }
 
 // stopVM: stop the sandbox's VM
@@ -996,6 +1182,8 @@ func (s *Sandbox) stopVM() error {
 	}
 
 	s.Logger().Info("Stopping VM")
+	// The hypervisor is not expected to run anymore.
+	s.hypervisorPid = 0
 	return s.hypervisor.stopSandbox()
 }
 
@@ -1038,6 +1226,9 @@ func (s *Sandbox) fetchContainers() error {
 // This should be called only when the sandbox is already created.
 // It will add new container config to sandbox.config.Containers
 func (s *Sandbox) CreateContainer(contConfig ContainerConfig) (VCContainer, error) {
+	done := s.watchdog.watch("CreateContainer", nil)
+	defer done()
+
 	// Create the container.
 	c, err := newContainer(s, contConfig)
 	if err != nil {
@@ -1048,11 +1239,15 @@ func (s *Sandbox) CreateContainer(contConfig ContainerConfig) (VCContainer, erro
 	s.config.Containers = append(s.config.Containers, contConfig)
 
 	// Sandbox is reponsable to update VM resources needed by Containers
-	err = s.updateResources()
+	oldCPUs, newCPUs, err := s.resizeResources()
 	if err != nil {
 		return nil, err
 	}
 
+	if err = c.dedicateVCPUs(oldCPUs, newCPUs); err != nil {
+		return nil, err
+	}
+
 	err = c.create()
 	if err != nil {
 		return nil, err
@@ -1093,6 +1288,9 @@ func (s *Sandbox) StartContainer(containerID string) (VCContainer, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	s.recordBootPhase(types.BootPhaseContainer)
+
 	//Fixme Container delete from sandbox, need to update resources
 
 	return c, nil
@@ -1144,6 +1342,9 @@ func (s *Sandbox) DeleteContainer(containerID string) (VCContainer, error) {
 		return nil, err
 	}
 
+	// The vCPUs dedicated to the container go with it.
+	c.releaseVCPUs()
+
 	// Update sandbox config
 	for idx, contConfig := range s.config.Containers {
 		if contConfig.ID == containerID {
@@ -1253,6 +1454,55 @@ func (s *Sandbox) StatsContainer(containerID string) (ContainerStats, error) {
 	return *stats, nil
 }
 
+// hypervisorStats reads the host resources used by the hypervisor process
+// and its vCPU threads.
+func (s *Sandbox) hypervisorStats() (*HypervisorStats, error) {
+	pid := s.hypervisor.pid()
+	if pid <= 0 {
+		return nil, fmt.Errorf("Invalid hypervisor PID: %d", pid)
+	}
+
+	proc, err := utils.NewProc(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := proc.NewStat()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &HypervisorStats{
+		RSS:     uint64(stat.ResidentMemory()),
+		CPUTime: uint64(stat.CPUTime() * float64(time.Second)),
+	}
+
+	tids, err := s.hypervisor.getThreadIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	// A vCPU thread can be gone by now, hot removed or with the hypervisor
+	// exiting, it does not fail the stats of the others.
+	for _, tid := range tids.vcpus {
+		thread, err := utils.NewProc(tid)
+		if err != nil {
+			s.Logger().WithError(err).WithField("vcpu-thread", tid).Debug("Could not read the vCPU thread")
+			continue
+		}
+
+		threadStat, err := thread.NewStat()
+		if err != nil {
+			s.Logger().WithError(err).WithField("vcpu-thread", tid).Debug("Could not read the vCPU thread stats")
+			continue
+		}
+
+		stats.VCPUTime += uint64(threadStat.CPUTime() * float64(time.Second))
+	}
+
+	return stats, nil
+}
+
 // PauseContainer pauses a running container.
 func (s *Sandbox) PauseContainer(containerID string) error {
 	// Fetch the container.
@@ -1326,6 +1576,10 @@ func (s *Sandbox) Start() error {
 		}
 	}
 
+	if len(s.containers) > 0 {
+		s.recordBootPhase(types.BootPhaseContainer)
+	}
+
 	s.Logger().Info("Sandbox is started")
 
 	return nil
@@ -1352,6 +1606,10 @@ func (s *Sandbox) Stop() error {
 		}
 	}
 
+	if err := s.detachScratchDisk(); err != nil {
+		s.Logger().WithError(err).Warn("Could not detach the scratch disk")
+	}
+
 	if err := s.stopVM(); err != nil {
 		return err
 	}
@@ -1387,9 +1645,24 @@ func (s *Sandbox) Resume() error {
 		return err
 	}
 
+	// The guest clock did not move while the VM was paused.
+	s.syncGuestTime()
+
 	return s.resumeSetStates()
 }
 
+// syncGuestTime sets the guest clock to the host time. A failure is only
+// logged since the guest keeps running with a skewed clock.
+func (s *Sandbox) syncGuestTime() {
+	now := time.Now()
+	if err := s.agent.setGuestDateTime(now); err != nil {
+		s.Logger().WithError(err).Warn("failed to sync guest time")
+		return
+	}
+
+	s.Logger().WithField("time", now).Info("synced guest time")
+}
+
 // list lists all sandbox running on the host.
 func (s *Sandbox) list() ([]Sandbox, error) {
 	return nil, nil
@@ -1400,6 +1673,32 @@ func (s *Sandbox) enter(args []string) error {
 	return nil
 }
 
+// recordBootPhase records that the sandbox reached a startup phase and
+// stores the updated timeline. Failing to store it is not fatal as the
+// timeline is only used for reporting.
+func (s *Sandbox) recordBootPhase(phase types.BootPhase) {
+	if !s.state.BootTimeline.Record(phase, time.Now()) {
+		return
+	}
+
+	if err := s.store.Store(store.State, s.state); err != nil {
+		s.Logger().WithError(err).WithField("phase", phase).Warn("failed to store boot timeline")
+	}
+
+	if phase != types.BootPhaseContainer {
+		return
+	}
+
+	fields := logrus.Fields{
+		"total-ms": s.state.BootTimeline.Total().Nanoseconds() / int64(time.Millisecond),
+	}
+	for p, d := range s.state.BootTimeline.Durations() {
+		fields[string(p)+"-ms"] = d.Nanoseconds() / int64(time.Millisecond)
+	}
+
+	s.Logger().WithFields(fields).Info("sandbox boot timeline")
+}
+
 // setSandboxState sets both the in-memory and on-disk state of the
 // sandbox.
 func (s *Sandbox) setSandboxState(state types.StateString) error {
@@ -1528,14 +1827,16 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 
 		// adding a group of VFIO devices
 		for _, dev := range vfioDevices {
-			if _, err := s.hypervisor.hotplugAddDevice(dev, vfioDev); err != nil {
+			_, err := s.hypervisor.hotplugAddDevice(dev, vfioDev)
+			s.audit.record("hotplug-device", vfioAuditArgs(dev), err)
+			if err != nil {
 				s.Logger().
 					WithFields(logrus.Fields{
 						"sandbox":         s.id,
 						"vfio-device-ID":  dev.ID,
 						"vfio-device-BDF": dev.BDF,
 					}).WithError(err).Error("failed to hotplug VFIO device")
-				return err
+				return classifyError(ErrorClassHotplug, err)
 			}
 		}
 		return nil
@@ -1545,7 +1846,8 @@ func (s *Sandbox) HotplugAddDevice(device api.Device, devType config.DeviceType)
 			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
 		}
 		_, err := s.hypervisor.hotplugAddDevice(blockDevice.BlockDrive, blockDev)
-		return err
+		s.audit.record("hotplug-device", blockAuditArgs(blockDevice.BlockDrive), err)
+		return classifyError(ErrorClassHotplug, err)
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -1565,14 +1867,16 @@ func (s *Sandbox) HotplugRemoveDevice(device api.Device, devType config.DeviceTy
 
 		// remove a group of VFIO devices
 		for _, dev := range vfioDevices {
-			if _, err := s.hypervisor.hotplugRemoveDevice(dev, vfioDev); err != nil {
+			_, err := s.hypervisor.hotplugRemoveDevice(dev, vfioDev)
+			s.audit.record("hotunplug-device", vfioAuditArgs(dev), err)
+			if err != nil {
 				s.Logger().WithError(err).
 					WithFields(logrus.Fields{
 						"sandbox":         s.id,
 						"vfio-device-ID":  dev.ID,
 						"vfio-device-BDF": dev.BDF,
 					}).Error("failed to hot unplug VFIO device")
-				return err
+				return classifyError(ErrorClassHotplug, err)
 			}
 		}
 		return nil
@@ -1582,7 +1886,8 @@ func (s *Sandbox) HotplugRemoveDevice(device api.Device, devType config.DeviceTy
 			return fmt.Errorf("device type mismatch, expect device type to be %s", devType)
 		}
 		_, err := s.hypervisor.hotplugRemoveDevice(blockDrive, blockDev)
-		return err
+		s.audit.record("hotunplug-device", blockAuditArgs(blockDrive), err)
+		return classifyError(ErrorClassHotplug, err)
 	case config.DeviceGeneric:
 		// TODO: what?
 		return nil
@@ -1636,15 +1941,22 @@ func (s *Sandbox) AddDevice(info config.DeviceInfo) (api.Device, error) {
 }
 
 func (s *Sandbox) updateResources() error {
+	_, _, err := s.resizeResources()
+	return err
+}
+
+// resizeResources resizes the VM for the resources needed by the containers,
+// returning the number of vCPUs before and after the resize.
+func (s *Sandbox) resizeResources() (uint32, uint32, error) {
 	// the hypervisor.MemorySize is the amount of memory reserved for
 	// the VM and contaniners without memory limit
 
 	if s == nil {
-		return errors.New("sandbox is nil")
+		return 0, 0, errors.New("sandbox is nil")
 	}
 
 	if s.config == nil {
-		return fmt.Errorf("sandbox config is nil")
+		return 0, 0, fmt.Errorf("sandbox config is nil")
 	}
 
 	sandboxVCPUs := s.calculateSandboxCPUs()
@@ -1657,29 +1969,45 @@ func (s *Sandbox) updateResources() error {
 	// Update VCPUs
 	s.Logger().WithField("cpus-sandbox", sandboxVCPUs).Debugf("Request to hypervisor to update vCPUs")
 	oldCPUs, newCPUs, err := s.hypervisor.resizeVCPUs(sandboxVCPUs)
+	s.audit.record("resize-vcpus", map[string]string{
+		"requested": fmt.Sprint(sandboxVCPUs),
+		"old":       fmt.Sprint(oldCPUs),
+		"new":       fmt.Sprint(newCPUs),
+	}, err)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
-	// The CPUs were increased, ask agent to online them
+	// The CPUs were increased, the agent onlines them together with the
+	// memory, in a single pass.
+	var vcpusAdded uint32
 	if oldCPUs < newCPUs {
-		vcpusAdded := newCPUs - oldCPUs
-		if err := s.agent.onlineCPUMem(vcpusAdded, true); err != nil {
-			return err
-		}
+		vcpusAdded = newCPUs - oldCPUs
 	}
 	s.Logger().Debugf("Sandbox CPUs: %d", newCPUs)
 
 	// Update Memory
 	s.Logger().WithField("memory-sandbox-size-byte", sandboxMemoryByte).Debugf("Request to hypervisor to update memory")
 	newMemory, err := s.hypervisor.resizeMemory(uint32(sandboxMemoryByte>>utils.MibToBytesShift), s.state.GuestMemoryBlockSizeMB)
+	s.audit.record("resize-memory", map[string]string{
+		"requested-mb": fmt.Sprint(sandboxMemoryByte >> utils.MibToBytesShift),
+		"new-mb":       fmt.Sprint(newMemory),
+	}, err)
 	if err != nil {
-		return err
+		if vcpusAdded > 0 {
+			// the added vCPUs are still onlined
+			if err := s.agent.onlineCPUMem(vcpusAdded, true); err != nil {
+				s.Logger().WithError(err).Warn("failed to online the added vCPUs")
+			}
+		}
+		return 0, 0, err
 	}
 	s.Logger().Debugf("Sandbox memory size: %d Byte", newMemory)
-	if err := s.agent.onlineCPUMem(0, false); err != nil {
-		return err
+	// leave the containers the whole memory until the next check
+	s.reclaim.reset()
+	if err := s.agent.onlineCPUMem(vcpusAdded, false); err != nil {
+		return 0, 0, err
 	}
-	return nil
+	return oldCPUs, newCPUs, nil
 }
 
 func (s *Sandbox) calculateSandboxMemory() int64 {
@@ -1067,6 +1067,50 @@ func TestSandboxCreateAssets(t *testing.T) {
 	assert.NotNil(err)
 }
 
+func TestSandboxCreateAssetsValidPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpfile, err := ioutil.TempFile("", "virtcontainers-test-")
+	assert.Nil(err)
+
+	defer func() {
+		tmpfile.Close()
+		os.Remove(tmpfile.Name()) // clean up
+	}()
+
+	hc := HypervisorConfig{
+		KernelPath:       filepath.Join(testDir, testKernel),
+		ImagePath:        filepath.Join(testDir, testImage),
+		ValidKernelPaths: []string{filepath.Join(testDir, "*")},
+	}
+
+	p := &SandboxConfig{
+		Annotations: map[string]string{
+			annotations.KernelPath: tmpfile.Name(),
+		},
+
+		HypervisorConfig: hc,
+	}
+
+	// not a permitted kernel
+	err = createAssets(context.Background(), p)
+	assert.Error(err)
+
+	p.HypervisorConfig.ValidKernelPaths = append(p.HypervisorConfig.ValidKernelPaths, filepath.Join(filepath.Dir(tmpfile.Name()), "virtcontainers-test-*"))
+	err = createAssets(context.Background(), p)
+	assert.NoError(err)
+
+	a, ok := p.HypervisorConfig.customAssets[types.KernelAsset]
+	assert.True(ok)
+	assert.Equal(a.Path(), tmpfile.Name())
+
+	// the path is cleaned before being matched
+	p.Annotations[annotations.KernelPath] = filepath.Join(testDir, "..", filepath.Base(tmpfile.Name()))
+	p.HypervisorConfig.ValidKernelPaths = []string{filepath.Join(testDir, "*")}
+	err = createAssets(context.Background(), p)
+	assert.Error(err)
+}
+
 func testFindContainerFailure(t *testing.T, sandbox *Sandbox, cid string) {
 	c, err := sandbox.findContainer(cid)
 	assert.Nil(t, c, "Container pointer should be nil")
@@ -1246,6 +1290,28 @@ func TestMonitor(t *testing.T) {
 	s.monitor.stop()
 }
 
+func TestSandboxHypervisorExited(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		state: types.State{State: types.StateRunning},
+	}
+	assert.False(s.HypervisorExited(), "unknown hypervisor pid")
+
+	cmd := exec.Command("true")
+	assert.NoError(cmd.Run())
+
+	s.hypervisorPid = cmd.Process.Pid
+	assert.True(s.HypervisorExited())
+
+	s.state.State = types.StateStopped
+	assert.False(s.HypervisorExited(), "stopped sandbox")
+
+	s.state.State = types.StatePaused
+	s.hypervisorPid = os.Getpid()
+	assert.False(s.HypervisorExited())
+}
+
 func TestWaitProcess(t *testing.T) {
 	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NoopAgentType, NetworkConfig{}, nil, nil)
 	assert.Nil(t, err, "VirtContainers should not allow empty sandboxes")
@@ -1700,3 +1766,31 @@ func TestSandboxExperimentalFeature(t *testing.T) {
 	assert.NotNil(t, exp.Get(testFeature.Name))
 	assert.True(t, sconfig.valid())
 }
+
+func TestSandboxHypervisorStats(t *testing.T) {
+	assert := assert.New(t)
+
+	h := &mockHypervisor{}
+	s := &Sandbox{
+		hypervisor: h,
+	}
+
+	_, err := s.hypervisorStats()
+	assert.Error(err)
+
+	// The mock reports the test process as the hypervisor, with a
+	// single vCPU thread.
+	h.mockPid = os.Getpid()
+	stats, err := s.hypervisorStats()
+	assert.NoError(err)
+	assert.NotZero(stats.RSS)
+
+	// A vCPU thread gone does not fail the stats.
+	s.hypervisor = &vcpuThreadsHypervisor{
+		mockHypervisor: mockHypervisor{mockPid: os.Getpid()},
+		vcpus:          map[int]int{0: os.Getpid(), 1: -1},
+	}
+	stats, err = s.hypervisorStats()
+	assert.NoError(err)
+	assert.NotZero(stats.RSS)
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/store"
+)
+
+const (
+	scratchDiskID     = "scratch"
+	scratchDiskFile   = "scratch.img"
+	scratchDiskFstype = "ext4"
+)
+
+// mkfsExt4 is the host tool used to format the scratch disk.
+var mkfsExt4 = "mkfs.ext4"
+
+// createScratchDisk creates a sparse raw image of sizeMB MiB at path and
+// formats it. Inode tables and journal are initialized lazily by the guest
+// so that the image stays thin provisioned.
+func createScratchDisk(path string, sizeMB uint32) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	err = f.Truncate(int64(sizeMB) << 20)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(mkfsExt4, "-q", "-F", "-E", "lazy_itable_init=1,lazy_journal_init=1", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to format scratch disk %s: %v: %s", path, err, output)
+	}
+
+	return nil
+}
+
+// scratchDiskEnabled returns true if a scratch disk is attached to the
+// sandbox VM.
+func (s *Sandbox) scratchDiskEnabled() bool {
+	if s.config == nil || s.config.HypervisorConfig.ScratchDiskSize == 0 {
+		return false
+	}
+
+	caps := s.hypervisor.capabilities()
+	return caps.IsBlockDeviceHotplugSupported()
+}
+
+// scratchDiskPath returns the path of the sandbox scratch disk image.
+func (s *Sandbox) scratchDiskPath() string {
+	return filepath.Join(store.SandboxConfigurationRootPath(s.id), scratchDiskFile)
+}
+
+// scratchDiskDevice returns the device manager device of the sandbox scratch
+// disk, nil if there is none.
+func (s *Sandbox) scratchDiskDevice() api.Device {
+	if s.devManager == nil {
+		return nil
+	}
+
+	path := s.scratchDiskPath()
+	for _, dev := range s.devManager.GetAllDevices() {
+		if dev.DeviceType() != config.DeviceBlock {
+			continue
+		}
+		if drive, ok := dev.GetDeviceInfo().(*config.BlockDrive); ok && drive != nil && drive.File == path {
+			return dev
+		}
+	}
+
+	return nil
+}
+
+// attachScratchDisk creates the sandbox scratch disk and hotplugs it into
+// the VM through the device manager.
+func (s *Sandbox) attachScratchDisk() (err error) {
+	if s.config.HypervisorConfig.ScratchDiskSize == 0 {
+		return nil
+	}
+
+	if !s.scratchDiskEnabled() {
+		s.Logger().Warn("block device hotplug not supported, not attaching scratch disk")
+		return nil
+	}
+
+	path := s.scratchDiskPath()
+	if err := createScratchDisk(path, s.config.HypervisorConfig.ScratchDiskSize); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	device, err := s.devManager.NewDevice(config.DeviceInfo{
+		HostPath:      path,
+		DevType:       "b",
+		DriverOptions: map[string]string{config.ImageFormatOption: diskImageRaw},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.devManager.AttachDevice(device.DeviceID(), s); err != nil {
+		s.devManager.RemoveDevice(device.DeviceID())
+		return err
+	}
+
+	drive, ok := device.GetDeviceInfo().(*config.BlockDrive)
+	if !ok || drive == nil {
+		return fmt.Errorf("scratch disk device %s has no block drive", device.DeviceID())
+	}
+	s.scratchDisk = drive
+
+	return s.storeSandboxDevices()
+}
+
+// detachScratchDisk hot unplugs the sandbox scratch disk from the VM and
+// removes it from the device manager and the host.
+func (s *Sandbox) detachScratchDisk() error {
+	device := s.scratchDiskDevice()
+	if device == nil {
+		return nil
+	}
+
+	if device.GetAttachCount() > 0 {
+		if err := s.devManager.DetachDevice(device.DeviceID(), s); err != nil {
+			return err
+		}
+	}
+
+	if err := s.devManager.RemoveDevice(device.DeviceID()); err != nil {
+		return err
+	}
+	s.scratchDisk = nil
+
+	if err := os.Remove(s.scratchDiskPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return s.storeSandboxDevices()
+}
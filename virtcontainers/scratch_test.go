@@ -0,0 +1,118 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/runtime/virtcontainers/device/manager"
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateScratchDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	if _, err := exec.LookPath(mkfsExt4); err != nil {
+		t.Skipf("%s not found", mkfsExt4)
+	}
+
+	dir, err := ioutil.TempDir("", "scratch")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, scratchDiskFile)
+	err = createScratchDisk(path, 16)
+	assert.NoError(err)
+
+	fi, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(int64(16<<20), fi.Size())
+
+	// the image is not overwritten
+	err = createScratchDisk(path, 16)
+	assert.Error(err)
+	_, err = os.Stat(path)
+	assert.NoError(err)
+}
+
+func TestCreateScratchDiskFormatFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	savedMkfs := mkfsExt4
+	defer func() {
+		mkfsExt4 = savedMkfs
+	}()
+	mkfsExt4 = "false"
+
+	dir, err := ioutil.TempDir("", "scratch")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, scratchDiskFile)
+	err = createScratchDisk(path, 16)
+	assert.Error(err)
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestAttachScratchDiskDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		config:     &SandboxConfig{},
+		hypervisor: &mockHypervisor{},
+	}
+
+	assert.False(s.scratchDiskEnabled())
+	assert.NoError(s.attachScratchDisk())
+	assert.Nil(s.scratchDisk)
+}
+
+func TestDetachScratchDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		config:     &SandboxConfig{},
+		hypervisor: &mockHypervisor{},
+		devManager: manager.NewDeviceManager(config.VirtioSCSI, nil),
+		ctx:        context.Background(),
+	}
+
+	vcStore, err := store.NewVCSandboxStore(s.ctx, s.id)
+	assert.NoError(err)
+	s.store = vcStore
+	defer os.RemoveAll(store.SandboxConfigurationRootPath(s.id))
+
+	// no scratch disk
+	assert.Nil(s.scratchDiskDevice())
+	assert.NoError(s.detachScratchDisk())
+
+	path := s.scratchDiskPath()
+	assert.NoError(ioutil.WriteFile(path, nil, 0600))
+
+	device, err := s.devManager.NewDevice(config.DeviceInfo{
+		HostPath:      path,
+		DevType:       "b",
+		DriverOptions: map[string]string{config.ImageFormatOption: diskImageRaw},
+	})
+	assert.NoError(err)
+	assert.NoError(s.devManager.AttachDevice(device.DeviceID(), s))
+	assert.Equal(device, s.scratchDiskDevice())
+
+	assert.NoError(s.detachScratchDisk())
+	assert.Nil(s.scratchDiskDevice())
+	assert.Nil(s.devManager.GetDeviceByID(device.DeviceID()))
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+}
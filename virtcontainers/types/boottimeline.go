@@ -0,0 +1,90 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import "time"
+
+// BootPhase identifies a sandbox startup phase.
+type BootPhase string
+
+const (
+	// BootPhaseCreate marks the start of the sandbox creation.
+	BootPhaseCreate BootPhase = "create"
+
+	// BootPhaseNetwork marks the end of the network namespace setup.
+	BootPhaseNetwork BootPhase = "network-ready"
+
+	// BootPhaseHypervisor marks the end of the hypervisor start.
+	BootPhaseHypervisor BootPhase = "hypervisor-started"
+
+	// BootPhaseAgent marks the moment the agent started the sandbox.
+	BootPhaseAgent BootPhase = "agent-ready"
+
+	// BootPhaseContainer marks the moment the first container is running.
+	BootPhaseContainer BootPhase = "container-running"
+)
+
+// BootEvent is the time at which a sandbox startup phase was reached.
+type BootEvent struct {
+	Phase BootPhase `json:"phase"`
+	Time  time.Time `json:"time"`
+}
+
+// BootTimeline is the ordered list of startup phases reached by a sandbox.
+type BootTimeline []BootEvent
+
+// Record adds phase to the timeline. Only the first occurrence of a
+// phase is kept, so that later restarts do not hide the initial boot.
+// It returns false if the phase had already been recorded.
+func (t *BootTimeline) Record(phase BootPhase, at time.Time) bool {
+	for _, e := range *t {
+		if e.Phase == phase {
+			return false
+		}
+	}
+
+	*t = append(*t, BootEvent{Phase: phase, Time: at})
+
+	return true
+}
+
+// Get returns the time at which phase was reached.
+func (t BootTimeline) Get(phase BootPhase) (time.Time, bool) {
+	for _, e := range t {
+		if e.Phase == phase {
+			return e.Time, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Durations returns, for each recorded phase, the time elapsed since the
+// previous phase. The first phase always has a zero duration.
+func (t BootTimeline) Durations() map[BootPhase]time.Duration {
+	durations := make(map[BootPhase]time.Duration, len(t))
+
+	for i, e := range t {
+		if i == 0 {
+			durations[e.Phase] = 0
+			continue
+		}
+
+		durations[e.Phase] = e.Time.Sub(t[i-1].Time)
+	}
+
+	return durations
+}
+
+// Total returns the time elapsed between the first and the last
+// recorded phases.
+func (t BootTimeline) Total() time.Duration {
+	if len(t) == 0 {
+		return 0
+	}
+
+	return t[len(t)-1].Time.Sub(t[0].Time)
+}
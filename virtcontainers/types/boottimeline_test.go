@@ -0,0 +1,45 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBootTimeline(t *testing.T) {
+	assert := assert.New(t)
+
+	var timeline BootTimeline
+
+	assert.Equal(time.Duration(0), timeline.Total())
+
+	start := time.Now()
+
+	assert.True(timeline.Record(BootPhaseCreate, start))
+	assert.True(timeline.Record(BootPhaseNetwork, start.Add(10*time.Millisecond)))
+	assert.True(timeline.Record(BootPhaseHypervisor, start.Add(50*time.Millisecond)))
+
+	// phases are only recorded once
+	assert.False(timeline.Record(BootPhaseCreate, start.Add(time.Second)))
+	assert.Len(timeline, 3)
+
+	at, ok := timeline.Get(BootPhaseCreate)
+	assert.True(ok)
+	assert.Equal(start, at)
+
+	_, ok = timeline.Get(BootPhaseAgent)
+	assert.False(ok)
+
+	durations := timeline.Durations()
+	assert.Equal(time.Duration(0), durations[BootPhaseCreate])
+	assert.Equal(10*time.Millisecond, durations[BootPhaseNetwork])
+	assert.Equal(40*time.Millisecond, durations[BootPhaseHypervisor])
+
+	assert.Equal(50*time.Millisecond, timeline.Total())
+}
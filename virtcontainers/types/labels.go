@@ -0,0 +1,46 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseLabelSelector parses a comma separated list of key=value pairs,
+// eg. "team=payments,env=prod", selecting the sandboxes carrying all these
+// labels.
+func ParseLabelSelector(selector string) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for _, pair := range strings.Split(selector, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("Invalid label selector %q, expecting key=value", pair)
+		}
+
+		labels[kv[0]] = kv[1]
+	}
+
+	return labels, nil
+}
+
+// MatchLabels returns true if labels has every pair of selector. An empty
+// selector matches any labels.
+func MatchLabels(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	selector, err := ParseLabelSelector("")
+	assert.NoError(err)
+	assert.Empty(selector)
+
+	selector, err = ParseLabelSelector("team=payments, env=prod,empty=")
+	assert.NoError(err)
+	assert.Equal(map[string]string{
+		"team":  "payments",
+		"env":   "prod",
+		"empty": "",
+	}, selector)
+
+	for _, s := range []string{"team", "=payments", "team=payments,env"} {
+		_, err := ParseLabelSelector(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	labels := map[string]string{
+		"team": "payments",
+		"env":  "prod",
+	}
+
+	assert.True(MatchLabels(labels, nil))
+	assert.True(MatchLabels(nil, nil))
+	assert.True(MatchLabels(labels, map[string]string{"team": "payments"}))
+	assert.True(MatchLabels(labels, labels))
+	assert.False(MatchLabels(labels, map[string]string{"team": "search"}))
+	assert.False(MatchLabels(labels, map[string]string{"tenant": "a"}))
+	assert.False(MatchLabels(nil, map[string]string{"team": "payments"}))
+}
@@ -16,6 +16,10 @@ const (
 
 	// PCIE represents a PCIe bus and bridge
 	PCIE PCIType = "pcie"
+
+	// PCIERootPort represents a PCIe root port, where a single device
+	// is hot plugged, at address 0, through the native PCIe hotplug.
+	PCIERootPort PCIType = "pcie-root-port"
 )
 
 const pciBridgeMaxCapacity = 30
@@ -35,20 +39,32 @@ type PCIBridge struct {
 	Addr int
 }
 
+func (b *PCIBridge) addrRange() (uint32, uint32) {
+	if b.Type == PCIERootPort {
+		return 0, 0
+	}
+
+	return 1, pciBridgeMaxCapacity
+}
+
 // AddDevice on success adds the device ID to the PCI bridge and returns
 // the address where the device was added.
 func (b *PCIBridge) AddDevice(ID string) (uint32, error) {
 	var addr uint32
+	found := false
+
+	first, last := b.addrRange()
 
 	// looking for the first available address
-	for i := uint32(1); i <= pciBridgeMaxCapacity; i++ {
+	for i := first; i <= last; i++ {
 		if _, ok := b.Address[i]; !ok {
 			addr = i
+			found = true
 			break
 		}
 	}
 
-	if addr == 0 {
+	if !found {
 		return 0, fmt.Errorf("Unable to hot plug device on bridge: there are not empty slots")
 	}
 
@@ -45,3 +45,22 @@ func TestAddRemoveDevice(t *testing.T) {
 		assert.Fail("address should be 0")
 	}
 }
+
+func TestAddRemoveRootPortDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	b := &PCIBridge{make(map[uint32]string), PCIERootPort, "pcie-root-port-0", 2}
+
+	addr, err := b.AddDevice("abc123")
+	assert.NoError(err)
+	assert.Equal(uint32(0), addr)
+
+	// a root port has a single slot
+	_, err = b.AddDevice("def456")
+	assert.Error(err)
+
+	assert.NoError(b.RemoveDevice("abc123"))
+	addr, err = b.AddDevice("def456")
+	assert.NoError(err)
+	assert.Equal(uint32(0), addr)
+}
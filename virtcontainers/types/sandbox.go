@@ -48,6 +48,13 @@ type State struct {
 	// CgroupPath is the cgroup hierarchy where sandbox's processes
 	// including the hypervisor are placed.
 	CgroupPath string `json:"cgroupPath,omitempty"`
+
+	// GuestCPUs is the cpuset of the guest vCPUs dedicated to a container
+	// with exclusive CPUs.
+	GuestCPUs string `json:"guestCPUs,omitempty"`
+
+	// BootTimeline records when each sandbox startup phase was reached.
+	BootTimeline BootTimeline `json:"bootTimeline,omitempty"`
 }
 
 // Valid checks that the sandbox state is valid.
@@ -266,6 +273,10 @@ type Cmd struct {
 	Console      string
 	Capabilities LinuxCapabilities
 
+	// ApparmorProfile is the profile the process is confined with, in
+	// the guest.
+	ApparmorProfile string
+
 	Interactive     bool
 	Detach          bool
 	NoNewPrivileges bool
@@ -0,0 +1,42 @@
+// Copyright (c) 2019 IBM
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// InterruptControllerXICS is the interrupt controller of POWER8 and
+	// older processors.
+	InterruptControllerXICS = "xics"
+
+	// InterruptControllerXIVE is the interrupt controller introduced with
+	// POWER9.
+	InterruptControllerXIVE = "xive"
+)
+
+// PowerInterruptController returns the POWER interrupt controller listed in
+// interruptsFile, usually /proc/interrupts, or an empty string if the file
+// lists none.
+func PowerInterruptController(interruptsFile string) (string, error) {
+	bytes, err := ioutil.ReadFile(interruptsFile)
+	if err != nil {
+		return "", err
+	}
+
+	s := string(bytes)
+	if strings.Contains(s, "XIVE") {
+		return InterruptControllerXIVE, nil
+	}
+
+	if strings.Contains(s, "XICS") {
+		return InterruptControllerXICS, nil
+	}
+
+	return "", nil
+}
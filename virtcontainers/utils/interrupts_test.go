@@ -0,0 +1,45 @@
+// Copyright (c) 2019 IBM
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerInterruptController(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "interrupts")
+
+	_, err = PowerInterruptController(file)
+	assert.Error(err)
+
+	data := []struct {
+		contents string
+		expected string
+	}{
+		{"", ""},
+		{"  16:          0   XICS 4096 Edge      IPI", InterruptControllerXICS},
+		{"  16:          0   XIVE-IPI   0 Edge      IPI", InterruptControllerXIVE},
+	}
+
+	for _, d := range data {
+		assert.NoError(ioutil.WriteFile(file, []byte(d.contents), 0640))
+
+		ic, err := PowerInterruptController(file)
+		assert.NoError(err)
+		assert.Equal(d.expected, ic)
+	}
+}
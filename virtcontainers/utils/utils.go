@@ -12,6 +12,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // DefaultCgroupPath runtime-determined location in the cgroups hierarchy.
@@ -142,6 +145,44 @@ func ConstraintsToVCPUs(quota int64, period uint64) uint {
 	return 0
 }
 
+// ParseCPUSet parses a cpuset list, eg. "0-3,6", into the sorted list of
+// the CPUs it holds.
+func ParseCPUSet(cpuset string) ([]int, error) {
+	cpus := make(map[int]bool)
+
+	for _, r := range strings.Split(cpuset, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("Invalid cpuset %q", cpuset)
+		}
+
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil || last < first {
+				return nil, fmt.Errorf("Invalid cpuset %q", cpuset)
+			}
+		}
+
+		for cpu := first; cpu <= last; cpu++ {
+			cpus[cpu] = true
+		}
+	}
+
+	var list []int
+	for cpu := range cpus {
+		list = append(list, cpu)
+	}
+	sort.Ints(list)
+
+	return list, nil
+}
+
 // GetVirtDriveName returns the disk name format for virtio-blk
 // Reference: https://github.com/torvalds/linux/blob/master/drivers/block/virtio_blk.c @c0aa3e0916d7e531e69b02e426f7162dfb1c6c0
 func GetVirtDriveName(index int) (string, error) {
@@ -40,12 +40,56 @@ func ioctl(fd uintptr, request int, arg1 uint64) error {
 	return nil
 }
 
+// from <linux/prctl.h> and <linux/pid.h>, not in x/sys/unix yet
+const (
+	prSchedCore       = 62
+	prSchedCoreCreate = 1
+	pidTypeTGID       = 1
+)
+
+// CreateCoreSchedCookie gives all the threads of process pid a new core
+// scheduling cookie: they never share a physical core with the threads
+// of another cookie. The threads the process creates later inherit it.
+func CreateCoreSchedCookie(pid int) error {
+	if err := unix.Prctl(prSchedCore, prSchedCoreCreate, uintptr(pid), pidTypeTGID, 0); err != nil {
+		return os.NewSyscallError("prctl(PR_SCHED_CORE)", err)
+	}
+
+	return nil
+}
+
+// SetThreadAffinity restricts the thread tid to run on the given CPUs.
+func SetThreadAffinity(tid int, cpus []int) error {
+	if len(cpus) == 0 {
+		return fmt.Errorf("No CPU to set the affinity of thread %d to", tid)
+	}
+
+	mask := make([]uint64, cpus[len(cpus)-1]/64+1)
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << uint(cpu%64)
+	}
+
+	if _, _, errno := unix.RawSyscall(
+		unix.SYS_SCHED_SETAFFINITY,
+		uintptr(tid),
+		uintptr(len(mask)*8),
+		uintptr(unsafe.Pointer(&mask[0])),
+	); errno != 0 {
+		return os.NewSyscallError("sched_setaffinity", errno)
+	}
+
+	return nil
+}
+
 // FindContextID finds a unique context ID by generating a random number between 3 and max unsigned int (maxUint).
 // Using the ioctl VHOST_VSOCK_SET_GUEST_CID, findContextID asks to the kernel if the given
 // context ID (N) is available, when the context ID is not available, incrementing by 1 findContextID
 // iterates from N to maxUint until an available context ID is found, otherwise decrementing by 1
 // findContextID iterates from N to 3 until an available context ID is found, this is the last chance
 // to find a context ID available.
+// A context ID accepted by the kernel is only returned if reserve, when not nil, succeeds
+// for it too. This allows the caller to detect collisions with context IDs allocated to
+// VMs that do not hold a vhost-vsock file descriptor, or from a previous boot.
 // On success vhost file and a context ID greater or equal than 3 are returned, otherwise 0 and an error are returned.
 // vhost file can be used to send vhost file decriptor to QEMU. It's the caller's responsibility to
 // close vhost file descriptor.
@@ -54,7 +98,7 @@ func ioctl(fd uintptr, request int, arg1 uint64) error {
 // - Reduce the probability of a *DoS attack*, since other processes don't know whatis the initial context ID
 //   used by findContextID to find a context ID available
 //
-func FindContextID() (*os.File, uint64, error) {
+func FindContextID(reserve func(cid uint64) error) (*os.File, uint64, error) {
 	// context IDs 0x0, 0x1 and 0x2 are reserved, 0x3 is the first context ID usable.
 	var firstContextID uint64 = 0x3
 	var contextID = firstContextID
@@ -75,14 +119,14 @@ func FindContextID() (*os.File, uint64, error) {
 
 	// Looking for the first available context ID.
 	for cid := contextID; cid <= maxUInt; cid++ {
-		if err := ioctlFunc(vsockFd.Fd(), ioctlVhostVsockSetGuestCid, cid); err == nil {
+		if err := ioctlFunc(vsockFd.Fd(), ioctlVhostVsockSetGuestCid, cid); err == nil && reserveContextID(reserve, cid) {
 			return vsockFd, cid, nil
 		}
 	}
 
 	// Last chance to get a free context ID.
 	for cid := contextID - 1; cid >= firstContextID; cid-- {
-		if err := ioctlFunc(vsockFd.Fd(), ioctlVhostVsockSetGuestCid, cid); err == nil {
+		if err := ioctlFunc(vsockFd.Fd(), ioctlVhostVsockSetGuestCid, cid); err == nil && reserveContextID(reserve, cid) {
 			return vsockFd, cid, nil
 		}
 	}
@@ -90,3 +134,7 @@ func FindContextID() (*os.File, uint64, error) {
 	vsockFd.Close()
 	return nil, 0, fmt.Errorf("Could not get a unique context ID for the vsock")
 }
+
+func reserveContextID(reserve func(cid uint64) error, cid uint64) bool {
+	return reserve == nil || reserve(cid) == nil
+}
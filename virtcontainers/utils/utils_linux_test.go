@@ -7,9 +7,11 @@ package utils
 
 import (
 	"errors"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 )
 
 func TestFindContextID(t *testing.T) {
@@ -28,8 +30,68 @@ func TestFindContextID(t *testing.T) {
 	VHostVSockDevicePath = "/dev/null"
 	maxUInt = uint64(1000000)
 
-	f, cid, err := FindContextID()
+	f, cid, err := FindContextID(nil)
 	assert.Nil(f)
 	assert.Zero(cid)
 	assert.Error(err)
 }
+
+func TestFindContextIDReserve(t *testing.T) {
+	assert := assert.New(t)
+
+	orgIoctlFunc := ioctlFunc
+	orgVHostVSockDevicePath := VHostVSockDevicePath
+	orgMaxUInt := maxUInt
+	defer func() {
+		ioctlFunc = orgIoctlFunc
+		VHostVSockDevicePath = orgVHostVSockDevicePath
+		maxUInt = orgMaxUInt
+	}()
+	VHostVSockDevicePath = "/dev/null"
+	maxUInt = uint64(10)
+
+	ioctlFunc = func(fd uintptr, request int, arg1 uint64) error {
+		return nil
+	}
+
+	// context IDs the kernel accepts but that are already reserved
+	// are skipped
+	reserved := map[uint64]bool{}
+	reserve := func(cid uint64) error {
+		if cid%2 == 0 {
+			return errors.New("reserved")
+		}
+		reserved[cid] = true
+		return nil
+	}
+
+	f, cid, err := FindContextID(reserve)
+	assert.NoError(err)
+	assert.NotNil(f)
+	defer f.Close()
+	assert.True(cid%2 == 1)
+	assert.True(reserved[cid])
+	assert.Len(reserved, 1)
+
+	// no context ID can be reserved
+	f, cid, err = FindContextID(func(cid uint64) error {
+		return errors.New("reserved")
+	})
+	assert.Nil(f)
+	assert.Zero(cid)
+	assert.Error(err)
+}
+
+func TestSetThreadAffinity(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Error(SetThreadAffinity(unix.Gettid(), nil))
+
+	errCh := make(chan error)
+	go func() {
+		// The thread exits with the goroutine, along with its affinity.
+		runtime.LockOSThread()
+		errCh <- SetThreadAffinity(unix.Gettid(), []int{0})
+	}()
+	assert.NoError(<-errCh)
+}
@@ -171,6 +171,23 @@ func TestConstraintsToVCPUs(t *testing.T) {
 	assert.Equal(expectedVCPUs, vcpus)
 }
 
+func TestParseCPUSet(t *testing.T) {
+	assert := assert.New(t)
+
+	cpus, err := ParseCPUSet("")
+	assert.NoError(err)
+	assert.Empty(cpus)
+
+	cpus, err = ParseCPUSet("6, 0-3,2")
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2, 3, 6}, cpus)
+
+	for _, cpuset := range []string{"a", "1-", "3-1", "-1", "1-2-3"} {
+		_, err = ParseCPUSet(cpuset)
+		assert.Error(err, cpuset)
+	}
+}
+
 func TestGetVirtDriveNameInvalidIndex(t *testing.T) {
 	_, err := GetVirtDriveName(-1)
 
@@ -123,12 +123,11 @@ func (endpoint *VethEndpoint) HotAttach(h hypervisor) error {
 	return nil
 }
 
-// HotDetach for the veth endpoint uses hot pull device
+// HotDetach for the veth endpoint uses hot pull device. Unlike Detach, the
+// network namespace is still there when the endpoint is hot detached, even
+// if it has not been created by virtcontainers, so that the TAP and bridge
+// are always removed along with the guest device.
 func (endpoint *VethEndpoint) HotDetach(h hypervisor, netNsCreated bool, netNsPath string) error {
-	if !netNsCreated {
-		return nil
-	}
-
 	if err := doNetNS(netNsPath, func(_ ns.NetNS) error {
 		return xDisconnectVMNetwork(endpoint)
 	}); err != nil {
@@ -7,6 +7,7 @@ package virtcontainers
 
 import (
 	"net"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -104,3 +105,43 @@ func TestCreateVethNetworkEndpointInvalidArgs(t *testing.T) {
 		}
 	}
 }
+
+type netDetachHypervisor struct {
+	mockHypervisor
+	detached []interface{}
+}
+
+func (h *netDetachHypervisor) hotplugRemoveDevice(devInfo interface{}, devType deviceType) (interface{}, error) {
+	if devType == netDev {
+		h.detached = append(h.detached, devInfo)
+	}
+	return nil, nil
+}
+
+func TestVethEndpointHotDetach(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	netNSPath, err := createNetNS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteNetNS(netNSPath)
+
+	endpoint, err := createVethNetworkEndpoint(0, "eth0", DefaultNetInterworkingModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The device is unplugged even from a network namespace not created
+	// by virtcontainers, and whose interfaces are gone already.
+	h := &netDetachHypervisor{}
+	if err := endpoint.HotDetach(h, false, netNSPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.detached) != 1 || h.detached[0] != endpoint {
+		t.Fatalf("Expected the endpoint to be unplugged, got %+v", h.detached)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+)
+
+const (
+	// vhostUserSCSIMountType is the type of the container mounts backed by
+	// a LUN of the vhost-user-scsi controller of the sandbox. The source of
+	// such a mount is the SCSI address of the LUN as target:lun.
+	vhostUserSCSIMountType = "vhost-user-scsi"
+
+	// vhostUserSCSIFstypeOption is the mount option giving the filesystem
+	// of a vhost-user-scsi mount.
+	vhostUserSCSIFstypeOption = "fstype="
+
+	vhostUserSCSIDefaultFstype = "ext4"
+
+	vhostUserSCSIDevID = "vhostuserscsi0"
+
+	// vhostUserSCSISocketsDir is the directory of the vhost user store
+	// holding the sockets of the vhost-user-scsi controllers, in a
+	// directory per sandbox named after the sandbox ID.
+	vhostUserSCSISocketsDir = "scsi"
+
+	// vhostUserSharedMemoryPath is the file backing the guest memory shared
+	// with the vhost user backends, when the memory is not backed by huge
+	// pages.
+	vhostUserSharedMemoryPath = "/dev/shm"
+)
+
+func (conf *HypervisorConfig) checkVhostUserSCSIConfig() error {
+	name := conf.VhostUserSCSIController
+	if name == "" {
+		return nil
+	}
+
+	if !conf.EnableVhostUserStore {
+		return fmt.Errorf("vhost-user-scsi controller %s set but the vhost user store is disabled", name)
+	}
+
+	if !filepath.IsAbs(conf.VhostUserStorePath) {
+		return fmt.Errorf("Invalid vhost user store path %s, expecting an absolute path", conf.VhostUserStorePath)
+	}
+
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return fmt.Errorf("Invalid vhost-user-scsi controller name %s", name)
+	}
+
+	// The agent looks for the SCSI LUNs on the first SCSI host of the
+	// guest, which has to be the vhost-user-scsi controller.
+	if conf.BlockDeviceDriver == config.VirtioSCSI {
+		return fmt.Errorf("vhost-user-scsi controller %s cannot be used with the %s block device driver",
+			name, config.VirtioSCSI)
+	}
+
+	// The template memory is not shared with the vhost user backends.
+	if conf.BootToBeTemplate || conf.BootFromTemplate {
+		return fmt.Errorf("vhost-user-scsi controller %s cannot be used with a VM template", name)
+	}
+
+	return nil
+}
+
+// usesVhostUser returns true if the guest memory has to be shared with a
+// vhost user backend.
+func (conf *HypervisorConfig) usesVhostUser() bool {
+	return conf.VhostUserSCSIController != ""
+}
+
+// vhostUserSCSISocketPath returns the socket of the vhost-user-scsi
+// controller of the sandbox. A sandbox only reaches the controllers
+// provisioned for it, in the directory named after its ID.
+func vhostUserSCSISocketPath(conf HypervisorConfig, sandboxID string) string {
+	return filepath.Join(conf.VhostUserStorePath, vhostUserSCSISocketsDir, sandboxID, conf.VhostUserSCSIController)
+}
+
+// addVhostUserSCSIController cold plugs the vhost-user-scsi controller of
+// the sandbox, eg. served by SPDK, into the VM.
+func (s *Sandbox) addVhostUserSCSIController() error {
+	conf := s.config.HypervisorConfig
+	if conf.VhostUserSCSIController == "" {
+		return nil
+	}
+
+	if s.factory != nil {
+		return fmt.Errorf("vhost-user-scsi controller %s cannot be used with the VM factory", conf.VhostUserSCSIController)
+	}
+
+	if s.id != filepath.Base(s.id) || s.id == "." || s.id == ".." {
+		return fmt.Errorf("Invalid sandbox ID %s for a vhost-user-scsi controller", s.id)
+	}
+
+	// The socket itself must be provisioned for the sandbox, not link
+	// to the controller of another sandbox.
+	path := vhostUserSCSISocketPath(conf, s.id)
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("vhost-user-scsi controller %s is not a socket", path)
+	}
+
+	return s.hypervisor.addDevice(&config.VhostUserDeviceAttrs{
+		DevID:      vhostUserSCSIDevID,
+		SocketPath: path,
+		Type:       config.VhostUserSCSI,
+	}, vhostuserDev)
+}
+
+// vhostUserSCSIAddr checks the source of a vhost-user-scsi mount, and
+// returns the SCSI address of its LUN as expected by the agent.
+func vhostUserSCSIAddr(source string) (string, error) {
+	fields := strings.Split(source, ":")
+	if len(fields) != 2 {
+		return "", fmt.Errorf("Invalid vhost-user-scsi LUN %q, expecting target:lun", source)
+	}
+
+	for _, f := range fields {
+		if _, err := strconv.ParseUint(f, 10, 16); err != nil {
+			return "", fmt.Errorf("Invalid vhost-user-scsi LUN %q, expecting target:lun", source)
+		}
+	}
+
+	return source, nil
+}
+
+// vhostUserSCSIMountOptions splits the options of a vhost-user-scsi mount
+// into the filesystem of the LUN and its mount options.
+func vhostUserSCSIMountOptions(options []string) (string, []string) {
+	fstype := vhostUserSCSIDefaultFstype
+	var mountOptions []string
+
+	for _, o := range options {
+		if strings.HasPrefix(o, vhostUserSCSIFstypeOption) {
+			fstype = strings.TrimPrefix(o, vhostUserSCSIFstypeOption)
+			continue
+		}
+		mountOptions = append(mountOptions, o)
+	}
+
+	return fstype, mountOptions
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/device/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVhostUserSCSIConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, d := range []struct {
+		name        string
+		enabled     bool
+		store       string
+		blockDriver string
+		template    bool
+		valid       bool
+	}{
+		{"", false, "", config.VirtioSCSI, false, true},
+		{"spdk0", true, "/var/run/kata-containers/vhost-user", config.VirtioBlock, false, true},
+		{"spdk0", false, "/var/run/kata-containers/vhost-user", config.VirtioBlock, false, false},
+		{"spdk0", true, "vhost-user", config.VirtioBlock, false, false},
+		{"../spdk0", true, "/var/run/kata-containers/vhost-user", config.VirtioBlock, false, false},
+		{"..", true, "/var/run/kata-containers/vhost-user", config.VirtioBlock, false, false},
+		{"spdk0", true, "/var/run/kata-containers/vhost-user", config.VirtioSCSI, false, false},
+		{"spdk0", true, "/var/run/kata-containers/vhost-user", config.VirtioBlock, true, false},
+	} {
+		conf := HypervisorConfig{
+			VhostUserSCSIController: d.name,
+			EnableVhostUserStore:    d.enabled,
+			VhostUserStorePath:      d.store,
+			BlockDeviceDriver:       d.blockDriver,
+			BootToBeTemplate:        d.template,
+		}
+
+		err := conf.checkVhostUserSCSIConfig()
+		if d.valid {
+			assert.NoError(err, "%+v", d)
+		} else {
+			assert.Error(err, "%+v", d)
+		}
+	}
+
+	conf := HypervisorConfig{
+		VhostUserStorePath:      "/var/run/kata-containers/vhost-user",
+		VhostUserSCSIController: "spdk0",
+	}
+	assert.True(conf.usesVhostUser())
+	assert.Equal("/var/run/kata-containers/vhost-user/scsi/sandbox1/spdk0", vhostUserSCSISocketPath(conf, "sandbox1"))
+}
+
+func TestAddVhostUserSCSIController(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(store)
+
+	s := &Sandbox{
+		id:         "sandbox1",
+		hypervisor: &mockHypervisor{},
+		config: &SandboxConfig{
+			HypervisorConfig: HypervisorConfig{
+				EnableVhostUserStore:    true,
+				VhostUserStorePath:      store,
+				VhostUserSCSIController: "spdk0",
+			},
+		},
+	}
+
+	// The controller of another sandbox is not reachable.
+	other := filepath.Join(store, vhostUserSCSISocketsDir, "sandbox2")
+	assert.NoError(os.MkdirAll(other, 0700))
+	l, err := net.Listen("unix", filepath.Join(other, "spdk0"))
+	assert.NoError(err)
+	defer l.Close()
+
+	assert.Error(s.addVhostUserSCSIController())
+
+	// Nor through a symlink.
+	dir := filepath.Join(store, vhostUserSCSISocketsDir, "sandbox1")
+	assert.NoError(os.MkdirAll(dir, 0700))
+	assert.NoError(os.Symlink(filepath.Join(other, "spdk0"), filepath.Join(dir, "spdk0")))
+	assert.Error(s.addVhostUserSCSIController())
+
+	assert.NoError(os.Remove(filepath.Join(dir, "spdk0")))
+	l2, err := net.Listen("unix", filepath.Join(dir, "spdk0"))
+	assert.NoError(err)
+	defer l2.Close()
+	assert.NoError(s.addVhostUserSCSIController())
+
+	s.id = ".."
+	assert.Error(s.addVhostUserSCSIController())
+}
+
+func TestVhostUserSCSIAddr(t *testing.T) {
+	assert := assert.New(t)
+
+	addr, err := vhostUserSCSIAddr("1:0")
+	assert.NoError(err)
+	assert.Equal("1:0", addr)
+
+	for _, source := range []string{"", "1", "1:0:0", "a:0", "1:-1", "/dev/sdb"} {
+		_, err := vhostUserSCSIAddr(source)
+		assert.Error(err, source)
+	}
+}
+
+func TestVhostUserSCSIMountOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	fstype, options := vhostUserSCSIMountOptions(nil)
+	assert.Equal(vhostUserSCSIDefaultFstype, fstype)
+	assert.Empty(options)
+
+	fstype, options = vhostUserSCSIMountOptions([]string{"ro", "fstype=xfs", "noatime"})
+	assert.Equal("xfs", fstype)
+	assert.Equal([]string{"ro", "noatime"}, options)
+}
+
+func TestHandleVhostUserSCSIVolumes(t *testing.T) {
+	assert := assert.New(t)
+
+	k := &kataAgent{}
+	c := &Container{
+		sandbox: &Sandbox{
+			config: &SandboxConfig{},
+		},
+	}
+
+	spec := &specs.Spec{
+		Mounts: []specs.Mount{
+			{
+				Type:        "bind",
+				Source:      "/tmp",
+				Destination: "/tmp",
+			},
+			{
+				Type:        vhostUserSCSIMountType,
+				Source:      "2:0",
+				Destination: "/data",
+				Options:     []string{"fstype=xfs", "ro"},
+			},
+		},
+	}
+
+	// No controller attached to the sandbox.
+	_, err := k.handleVhostUserSCSIVolumes(c, spec)
+	assert.Error(err)
+
+	c.sandbox.config.HypervisorConfig.VhostUserSCSIController = "spdk0"
+
+	storages, err := k.handleVhostUserSCSIVolumes(c, spec)
+	assert.NoError(err)
+	assert.Len(storages, 1)
+
+	assert.Equal(kataSCSIDevType, storages[0].Driver)
+	assert.Equal("2:0", storages[0].Source)
+	assert.Equal("xfs", storages[0].Fstype)
+	assert.Equal([]string{"ro"}, storages[0].Options)
+	assert.Equal("/data", storages[0].MountPoint)
+
+	assert.Equal("bind", spec.Mounts[1].Type)
+	assert.Equal([]string{"rbind", "ro"}, spec.Mounts[1].Options)
+	assert.Equal("bind", spec.Mounts[0].Type)
+}
@@ -352,6 +352,10 @@ func (v *VM) Stop() error {
 		return err
 	}
 
+	if err := releaseVSockCIDs(v.id); err != nil {
+		v.logger().WithError(err).Warn("failed to release vsock context IDs")
+	}
+
 	return v.store.Delete()
 }
 
@@ -447,6 +451,10 @@ func (v *VM) assignSandbox(s *Sandbox) error {
 		return err
 	}
 
+	if err := transferVSockCIDs(v.id, s.id); err != nil {
+		return err
+	}
+
 	// First make sure the symlinks do not exist
 	os.RemoveAll(sbSharePath)
 	os.RemoveAll(sbSockDir)
@@ -0,0 +1,131 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+)
+
+// vsockCIDStoragePath holds one file per allocated vsock context ID. Each
+// file is named after the context ID and contains the ID of its owner,
+// either a sandbox or a factory VM. It is kept across host reboots so that
+// a context ID is never handed out twice while it may still be in use.
+var vsockCIDStoragePath = filepath.Join("/var/lib", store.StoragePathSuffix, "vsock")
+
+// vsockCIDOwnerRunning returns true if the owner of a context ID still
+// exists. Runtime directories do not survive a host reboot, which makes
+// any context ID allocated before it stale.
+func vsockCIDOwnerRunning(owner string) bool {
+	_, err := os.Stat(store.SandboxRuntimeRootPath(owner))
+	return err == nil
+}
+
+// reserveVSockCID records the context ID as allocated to owner. It fails if
+// the context ID is already allocated to a running sandbox or VM.
+func reserveVSockCID(owner string, cid uint64) error {
+	if err := os.MkdirAll(vsockCIDStoragePath, store.DirMode); err != nil {
+		return err
+	}
+
+	path := filepath.Join(vsockCIDStoragePath, strconv.FormatUint(cid, 10))
+
+	for retry := true; ; retry = false {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = f.WriteString(owner)
+			f.Close()
+			if err != nil {
+				os.Remove(path)
+			}
+			return err
+		}
+
+		if !os.IsExist(err) || !retry {
+			return err
+		}
+
+		// The context ID may have been left behind by an owner
+		// that is gone, reclaim it.
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if prev := strings.TrimSpace(string(data)); prev != "" && vsockCIDOwnerRunning(prev) {
+			return os.ErrExist
+		}
+
+		virtLog.WithField("vsock-cid", cid).Info("Removing stale vsock context ID")
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+}
+
+// transferVSockCIDs hands the context IDs allocated to a factory VM over to
+// the sandbox the VM is assigned to.
+func transferVSockCIDs(from, to string) error {
+	files, err := ioutil.ReadDir(vsockCIDStoragePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(vsockCIDStoragePath, f.Name())
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil || strings.TrimSpace(string(data)) != from {
+			continue
+		}
+
+		if err := ioutil.WriteFile(path, []byte(to), 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseVSockCIDs releases all the context IDs allocated to owner, along
+// with the stale context IDs found.
+func releaseVSockCIDs(owner string) error {
+	files, err := ioutil.ReadDir(vsockCIDStoragePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(vsockCIDStoragePath, f.Name())
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		prev := strings.TrimSpace(string(data))
+		if prev != owner && vsockCIDOwnerRunning(prev) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/runtime/virtcontainers/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func readVSockCIDOwner(cid string) string {
+	data, err := ioutil.ReadFile(filepath.Join(vsockCIDStoragePath, cid))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func TestReserveVSockCID(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.RemoveAll(vsockCIDStoragePath)
+
+	running := "running-sandbox"
+	err := os.MkdirAll(store.SandboxRuntimeRootPath(running), store.DirMode)
+	assert.NoError(err)
+	defer os.RemoveAll(store.SandboxRuntimeRootPath(running))
+
+	err = reserveVSockCID(running, 3)
+	assert.NoError(err)
+	assert.Equal(running, readVSockCIDOwner("3"))
+
+	// the context ID is in use
+	err = reserveVSockCID("foo", 3)
+	assert.Error(err)
+	assert.Equal(running, readVSockCIDOwner("3"))
+
+	// a context ID left behind by a sandbox that is gone is reclaimed
+	err = reserveVSockCID("gone-sandbox", 4)
+	assert.NoError(err)
+	err = reserveVSockCID("foo", 4)
+	assert.NoError(err)
+	assert.Equal("foo", readVSockCIDOwner("4"))
+}
+
+func TestReleaseVSockCIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.RemoveAll(vsockCIDStoragePath)
+
+	// nothing allocated yet
+	assert.NoError(releaseVSockCIDs("foo"))
+
+	owners := []string{"vm", "sandbox", "other"}
+	for _, owner := range owners {
+		err := os.MkdirAll(store.SandboxRuntimeRootPath(owner), store.DirMode)
+		assert.NoError(err)
+		defer os.RemoveAll(store.SandboxRuntimeRootPath(owner))
+	}
+
+	assert.NoError(reserveVSockCID("vm", 3))
+	assert.NoError(reserveVSockCID("other", 4))
+	assert.NoError(reserveVSockCID("gone", 5))
+
+	// the VM context ID goes to the sandbox it is assigned to
+	assert.NoError(transferVSockCIDs("vm", "sandbox"))
+	assert.Equal("sandbox", readVSockCIDOwner("3"))
+	assert.Equal("other", readVSockCIDOwner("4"))
+
+	// stale context IDs are released too
+	assert.NoError(releaseVSockCIDs("sandbox"))
+	assert.Empty(readVSockCIDOwner("3"))
+	assert.Equal("other", readVSockCIDOwner("4"))
+	assert.Empty(readVSockCIDOwner("5"))
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+)
+
+// maxStackDumpSize bounds the size of the goroutine stacks logged for a slow
+// operation.
+const maxStackDumpSize = 1024 * 1024
+
+// slowOpWatchdog warns about the sandbox operations still running after a
+// threshold, usually because of a stuck QMP or agent call, so that they show
+// in the logs before the caller times out.
+type slowOpWatchdog struct {
+	threshold time.Duration
+	logger    *logrus.Entry
+}
+
+func newSlowOpWatchdog(s *Sandbox, threshold time.Duration) *slowOpWatchdog {
+	return &slowOpWatchdog{
+		threshold: threshold,
+		logger:    s.Logger(),
+	}
+}
+
+// stackDump returns the stacks of all the goroutines.
+func stackDump() string {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackDumpSize {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// watch starts watching an operation, and returns the function to call once
+// it is done. span is the trace span of the operation, if any.
+func (w *slowOpWatchdog) watch(operation string, span opentracing.Span) func() {
+	if w == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	var (
+		lock  sync.Mutex
+		fired bool
+	)
+
+	timer := time.AfterFunc(w.threshold, func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		fired = true
+
+		fields := logrus.Fields{
+			"operation": operation,
+			"threshold": w.threshold,
+			"stacks":    stackDump(),
+		}
+
+		if span != nil {
+			if sc, ok := span.Context().(fmt.Stringer); ok {
+				fields["span"] = sc.String()
+			}
+		}
+
+		w.logger.WithFields(fields).Warn("slow sandbox operation")
+	})
+
+	return func() {
+		if timer.Stop() {
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		if fired {
+			w.logger.WithFields(logrus.Fields{
+				"operation": operation,
+				"duration":  time.Since(start),
+			}).Info("slow sandbox operation completed")
+		}
+	}
+}
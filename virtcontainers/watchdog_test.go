@@ -0,0 +1,85 @@
+// Copyright (c) 2019 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntriesHook struct {
+	sync.Mutex
+	entries []logrus.Entry
+}
+
+func (h *logEntriesHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *logEntriesHook) Fire(e *logrus.Entry) error {
+	h.Lock()
+	defer h.Unlock()
+
+	h.entries = append(h.entries, *e)
+	return nil
+}
+
+func (h *logEntriesHook) all() []logrus.Entry {
+	h.Lock()
+	defer h.Unlock()
+
+	return append([]logrus.Entry{}, h.entries...)
+}
+
+func TestSlowOpWatchdogNil(t *testing.T) {
+	var w *slowOpWatchdog
+
+	done := w.watch("foo", nil)
+	done()
+}
+
+func TestSlowOpWatchdog(t *testing.T) {
+	assert := assert.New(t)
+
+	hook := &logEntriesHook{}
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.Level = logrus.DebugLevel
+	logger.Hooks.Add(hook)
+
+	w := &slowOpWatchdog{
+		threshold: 10 * time.Millisecond,
+		logger:    logrus.NewEntry(logger),
+	}
+
+	// a fast operation is not logged
+	done := w.watch("fast", nil)
+	done()
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(hook.all())
+
+	done = w.watch("slow", nil)
+	for i := 0; i < 100 && len(hook.all()) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	done()
+
+	entries := hook.all()
+	assert.Len(entries, 2)
+
+	assert.Equal(logrus.WarnLevel, entries[0].Level)
+	assert.Equal("slow", entries[0].Data["operation"])
+	assert.True(strings.Contains(entries[0].Data["stacks"].(string), "TestSlowOpWatchdog"))
+
+	assert.Equal(logrus.InfoLevel, entries[1].Level)
+	assert.Equal("slow", entries[1].Data["operation"])
+}